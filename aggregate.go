@@ -0,0 +1,100 @@
+package redis_bloom_go
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// CmsQueryAcross - Returns, for each item, the sum of its counts in the sketches stored at keys, each count
+// multiplied by the weight of its sketch. weights is aligned with keys, every weight being 1 if it is nil.
+// The sketches are queried with pipelined CMS.QUERY commands, which unlike CMS.MERGE needs no destination key
+// and leaves the sketches untouched.
+func (client *Client) CmsQueryAcross(keys []string, items []string, weights []int64) ([]int64, error) {
+	if weights != nil && len(weights) != len(keys) {
+		return nil, errors.New("CmsQueryAcross expects as many weights as keys")
+	}
+	return client.cmsQueryAcross(keys, items, weights, false)
+}
+
+// cmsQueryAcross implements CmsQueryAcross, counting sketches that do not exist as zero if skipMissing is set
+func (client *Client) cmsQueryAcross(keys []string, items []string, weights []int64, skipMissing bool) ([]int64, error) {
+	conn := client.Pool.Get()
+	defer conn.Close()
+	for _, key := range keys {
+		if err := conn.Send("CMS.QUERY", redis.Args{client.key(key)}.AddFlat(items)...); err != nil {
+			return nil, err
+		}
+	}
+	if err := conn.Flush(); err != nil {
+		return nil, err
+	}
+	totals := make([]int64, len(items))
+	var outErr error
+	for i := range keys {
+		counts, err := redis.Int64s(conn.Receive())
+		if err != nil {
+			if outErr == nil && !(skipMissing && strings.Contains(err.Error(), cmsMissingKeyError)) {
+				outErr = err
+			}
+			continue
+		}
+		weight := int64(1)
+		if weights != nil {
+			weight = weights[i]
+		}
+		for j, count := range counts {
+			totals[j] += weight * count
+		}
+	}
+	if outErr != nil {
+		return nil, outErr
+	}
+	return totals, nil
+}
+
+// TopkListMerged - Returns the items listed by the top-k sketches stored at keys, e.g. one per shard or region,
+// with their counts summed across the sketches, from the most to the least frequent. The sketches are read with
+// pipelined TOPK.LIST WITHCOUNT commands. An item misses the occurrences it had in the sketches where it was not
+// among the top items.
+func (client *Client) TopkListMerged(keys ...string) ([]HeavyHitter, error) {
+	counts, err := client.topkListMerged(keys, false)
+	if err != nil {
+		return nil, err
+	}
+	return topHeavyHitters(counts, int64(len(counts))), nil
+}
+
+// topkListMerged returns the counts of the items listed by the sketches at keys summed across the sketches,
+// ignoring sketches that do not exist if skipMissing is set
+func (client *Client) topkListMerged(keys []string, skipMissing bool) (map[string]int64, error) {
+	conn := client.Pool.Get()
+	defer conn.Close()
+	for _, key := range keys {
+		if err := conn.Send("TOPK.LIST", client.key(key), "WITHCOUNT"); err != nil {
+			return nil, err
+		}
+	}
+	if err := conn.Flush(); err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int64)
+	var outErr error
+	for range keys {
+		list, err := ParseInfoReply(redis.Values(conn.Receive()))
+		if err != nil {
+			if outErr == nil && !(skipMissing && strings.Contains(err.Error(), topkMissingKeyError)) {
+				outErr = err
+			}
+			continue
+		}
+		for item, count := range list {
+			counts[item] += count
+		}
+	}
+	if outErr != nil {
+		return nil, outErr
+	}
+	return counts, nil
+}
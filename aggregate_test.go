@@ -0,0 +1,42 @@
+package redis_bloom_go
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_CmsQueryAcross(t *testing.T) {
+	client.FlushAll()
+	client.CmsInitByDim("test_across_a", 1000, 5)
+	client.CmsInitByDim("test_across_b", 1000, 5)
+	client.CmsIncrByItems("test_across_a", []CmsIncrement{{Item: "x", Count: 2}, {Item: "y", Count: 1}})
+	client.CmsIncrByItems("test_across_b", []CmsIncrement{{Item: "x", Count: 3}})
+
+	totals, err := client.CmsQueryAcross([]string{"test_across_a", "test_across_b"}, []string{"x", "y", "z"}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{5, 1, 0}, totals)
+	totals, err = client.CmsQueryAcross([]string{"test_across_a", "test_across_b"}, []string{"x", "y"}, []int64{10, -1})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{17, 10}, totals)
+
+	_, err = client.CmsQueryAcross([]string{"test_across_a"}, []string{"x"}, []int64{1, 2})
+	assert.NotNil(t, err)
+	_, err = client.CmsQueryAcross([]string{"test_across_a", "test_across_missing"}, []string{"x"}, nil)
+	assert.NotNil(t, err)
+}
+
+func TestClient_TopkListMerged(t *testing.T) {
+	client.FlushAll()
+	client.TopkReserve("test_topk_merged_a", 3, 50, 5, 0.9)
+	client.TopkReserve("test_topk_merged_b", 3, 50, 5, 0.9)
+	client.TopkAdd("test_topk_merged_a", []string{"x", "x", "y"})
+	client.TopkAdd("test_topk_merged_b", []string{"y", "y", "z"})
+
+	merged, err := client.TopkListMerged("test_topk_merged_a", "test_topk_merged_b")
+	assert.Nil(t, err)
+	assert.Equal(t, []HeavyHitter{{"y", 3}, {"x", 2}, {"z", 1}}, merged)
+
+	_, err = client.TopkListMerged("test_topk_merged_a", "test_topk_merged_missing")
+	assert.NotNil(t, err)
+}
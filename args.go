@@ -0,0 +1,60 @@
+package redis_bloom_go
+
+import "sync"
+
+// maxPooledArgs bounds the capacity of the argument slices kept for reuse, so that a single huge call
+// does not pin a huge slice
+const maxPooledArgs = 4096
+
+var argsPool = sync.Pool{New: func() interface{} { return &commandArgs{} }}
+
+// commandArgs is a reusable argument slice for the multi-item commands. Building their arguments with
+// redis.Args{}.AddFlat goes through reflection and grows the slice several times; commandArgs is sized once
+// and recycled, leaving only the boxing of each item in an interface{}.
+// The slice must not be used once released, so it must not be released before every command it was
+// passed to has been replied to.
+type commandArgs struct {
+	values []interface{}
+}
+
+// newCommandArgs returns an empty argument slice with room for capacity arguments
+func newCommandArgs(capacity int) *commandArgs {
+	args := argsPool.Get().(*commandArgs)
+	if cap(args.values) < capacity {
+		args.values = make([]interface{}, 0, capacity)
+	}
+	return args
+}
+
+// keyArgs returns an argument slice holding the prefixed key, with room for capacity more arguments
+func (client *Client) keyArgs(key string, capacity int) *commandArgs {
+	args := newCommandArgs(capacity + 1)
+	args.values = append(args.values, client.key(key))
+	return args
+}
+
+// stringArgs returns an argument slice holding items
+func stringArgs(items []string) *commandArgs {
+	args := newCommandArgs(len(items))
+	args.addStrings(items)
+	return args
+}
+
+// addStrings appends items
+func (args *commandArgs) addStrings(items []string) {
+	for _, item := range items {
+		args.values = append(args.values, item)
+	}
+}
+
+// release returns the slice to the pool, clearing it so that it does not keep its arguments alive
+func (args *commandArgs) release() {
+	if cap(args.values) > maxPooledArgs {
+		return
+	}
+	for i := range args.values {
+		args.values[i] = nil
+	}
+	args.values = args.values[:0]
+	argsPool.Put(args)
+}
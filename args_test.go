@@ -0,0 +1,128 @@
+package redis_bloom_go
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+// replyConn replies to every command with the same reply
+type replyConn struct {
+	fakeConn
+	reply interface{}
+}
+
+func (c *replyConn) Do(string, ...interface{}) (interface{}, error) {
+	return c.reply, nil
+}
+
+func TestCommandArgs(t *testing.T) {
+	c := NewClientFromPool(nil, "test", WithKeyPrefix("app:"))
+	args := c.keyArgs("key", 2)
+	args.addStrings([]string{"a", "b"})
+	assert.Equal(t, []interface{}{"app:key", "a", "b"}, args.values)
+	args.release()
+	assert.Empty(t, args.values)
+	assert.Equal(t, []interface{}{nil, nil, nil}, args.values[:3])
+
+	items := stringArgs([]string{"x"})
+	assert.Equal(t, []interface{}{"x"}, items.values)
+	items.release()
+}
+
+func TestCommandArgs_Reuse(t *testing.T) {
+	conn := &argsConn{fakeConn: &fakeConn{replies: []interface{}{
+		[]interface{}{int64(1), int64(0)}, []interface{}{nil},
+	}}}
+	c := NewClientFromPool(nil, "test")
+	c.Pool = &fakePool{conn: conn}
+	added, err := c.BfAddMulti("bf", []string{"a", "b"})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1, 0}, added)
+	_, err = c.TopkAdd("topk", []string{"c"})
+	assert.Nil(t, err)
+	assert.Equal(t, [][]interface{}{{"bf", "a", "b"}, {"topk", "c"}}, conn.args)
+}
+
+func benchmarkItems(n int) []string {
+	items := make([]string, n)
+	for i := range items {
+		items[i] = "item" + strconv.Itoa(i)
+	}
+	return items
+}
+
+func BenchmarkArgs_AddFlat(b *testing.B) {
+	items := benchmarkItems(100)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = append(redis.Args{"key"}, redis.Args{}.AddFlat(items)...)
+	}
+}
+
+func BenchmarkArgs_Pooled(b *testing.B) {
+	c := NewClientFromPool(nil, "test")
+	items := benchmarkItems(100)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		args := c.keyArgs("key", len(items))
+		args.addStrings(items)
+		args.release()
+	}
+}
+
+func benchmarkClient(reply interface{}) *Client {
+	c := NewClientFromPool(nil, "test")
+	c.Pool = &fakePool{conn: &replyConn{reply: reply}}
+	return c
+}
+
+func int64sReply(n int) []interface{} {
+	reply := make([]interface{}, n)
+	for i := range reply {
+		reply[i] = int64(1)
+	}
+	return reply
+}
+
+func BenchmarkBfAddMulti(b *testing.B) {
+	c := benchmarkClient(int64sReply(100))
+	items := benchmarkItems(100)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		c.BfAddMulti("bf", items)
+	}
+}
+
+func BenchmarkBfExistsMulti(b *testing.B) {
+	c := benchmarkClient(int64sReply(100))
+	items := benchmarkItems(100)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		c.BfExistsMulti("bf", items)
+	}
+}
+
+func BenchmarkCmsIncrBy(b *testing.B) {
+	c := benchmarkClient(int64sReply(100))
+	increments := make(map[string]int64, 100)
+	for _, item := range benchmarkItems(100) {
+		increments[item] = 1
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		c.CmsIncrBy("cms", increments)
+	}
+}
+
+func BenchmarkTopkAdd(b *testing.B) {
+	reply := make([]interface{}, 100)
+	c := benchmarkClient(reply)
+	items := benchmarkItems(100)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		c.TopkAdd("topk", items)
+	}
+}
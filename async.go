@@ -0,0 +1,92 @@
+package redis_bloom_go
+
+// AsyncClient runs the commands of a Client in the background, returning futures instead of blocking,
+// so that callers can overlap the latency of many commands. Each command runs on its own goroutine;
+// the number of commands actually in flight is bounded by the connection pool.
+type AsyncClient struct {
+	client *Client
+}
+
+// Async - Returns the asynchronous view of the client
+func (client *Client) Async() *AsyncClient {
+	return &AsyncClient{client: client}
+}
+
+func (a *AsyncClient) boolFuture(fn func() (bool, error)) *BoolFuture {
+	future := newBoolFuture()
+	go func() {
+		future.resolve(fn())
+	}()
+	return future
+}
+
+func (a *AsyncClient) int64sFuture(fn func() ([]int64, error)) *Int64sFuture {
+	future := newInt64sFuture()
+	go func() {
+		future.resolve(fn())
+	}()
+	return future
+}
+
+// BfAdd - Asynchronous form of Client.Add
+func (a *AsyncClient) BfAdd(key string, item string) *BoolFuture {
+	return a.boolFuture(func() (bool, error) {
+		return a.client.Add(key, item)
+	})
+}
+
+// BfExists - Asynchronous form of Client.Exists
+func (a *AsyncClient) BfExists(key string, item string) *BoolFuture {
+	return a.boolFuture(func() (bool, error) {
+		return a.client.Exists(key, item)
+	})
+}
+
+// BfAddMulti - Asynchronous form of Client.BfAddMulti
+func (a *AsyncClient) BfAddMulti(key string, items []string) *Int64sFuture {
+	return a.int64sFuture(func() ([]int64, error) {
+		return a.client.BfAddMulti(key, items)
+	})
+}
+
+// BfExistsMulti - Asynchronous form of Client.BfExistsMulti
+func (a *AsyncClient) BfExistsMulti(key string, items []string) *Int64sFuture {
+	return a.int64sFuture(func() ([]int64, error) {
+		return a.client.BfExistsMulti(key, items)
+	})
+}
+
+// CfAdd - Asynchronous form of Client.CfAdd
+func (a *AsyncClient) CfAdd(key string, item string) *BoolFuture {
+	return a.boolFuture(func() (bool, error) {
+		return a.client.CfAdd(key, item)
+	})
+}
+
+// CfExists - Asynchronous form of Client.CfExists
+func (a *AsyncClient) CfExists(key string, item string) *BoolFuture {
+	return a.boolFuture(func() (bool, error) {
+		return a.client.CfExists(key, item)
+	})
+}
+
+// CmsIncrByItems - Asynchronous form of Client.CmsIncrByItems
+func (a *AsyncClient) CmsIncrByItems(key string, increments []CmsIncrement) *Int64sFuture {
+	return a.int64sFuture(func() ([]int64, error) {
+		return a.client.CmsIncrByItems(key, increments)
+	})
+}
+
+// CmsQuery - Asynchronous form of Client.CmsQuery
+func (a *AsyncClient) CmsQuery(key string, items []string) *Int64sFuture {
+	return a.int64sFuture(func() ([]int64, error) {
+		return a.client.CmsQuery(key, items)
+	})
+}
+
+// TopkCount - Asynchronous form of Client.TopkCount
+func (a *AsyncClient) TopkCount(key string, items []string) *Int64sFuture {
+	return a.int64sFuture(func() ([]int64, error) {
+		return a.client.TopkCount(key, items)
+	})
+}
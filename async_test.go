@@ -0,0 +1,39 @@
+package redis_bloom_go
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAsyncClient(t *testing.T) {
+	client.FlushAll()
+	async := client.Async()
+
+	futures := make([]*BoolFuture, 20)
+	for i := range futures {
+		futures[i] = async.BfAdd("test_async", fmt.Sprintf("item-%d", i))
+	}
+	for _, future := range futures {
+		<-future.Done()
+		ok, err := future.Get()
+		assert.Nil(t, err)
+		assert.True(t, ok)
+	}
+
+	exists, err := async.BfExists("test_async", "item-1").Get()
+	assert.Nil(t, err)
+	assert.True(t, exists)
+	values, err := async.BfExistsMulti("test_async", []string{"item-1", "missing"}).Get()
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1, 0}, values)
+
+	client.CmsInitByDim("test_async_cms", 1000, 5)
+	counts, err := async.CmsIncrByItems("test_async_cms", []CmsIncrement{{"a", 3}, {"b", 1}}).Get()
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{3, 1}, counts)
+
+	_, err = async.CfExists("test_async", "item-1").Get()
+	assert.NotNil(t, err)
+}
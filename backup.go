@@ -0,0 +1,197 @@
+package redis_bloom_go
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// backupMagic identifies a stream written by Backup
+const backupMagic = "RBBACKUP"
+
+// backupFormatVersion is the version of the backup stream layout:
+// magic | version (1 byte) | { kind (1 byte) | key length (uint32) | key | pttl (int64) | payload }* | kind 0
+// where the payload of bloom and cuckoo filters is a dump stream as written by BfDumpToWriter,
+// and the payload of other types is a length prefixed (uint32) DUMP serialization
+const backupFormatVersion = 1
+
+// maxBackupKeySize bounds the size of a key name read back from a backup stream
+const maxBackupKeySize = 512 << 20
+
+// Kinds of records in a backup stream
+const (
+	backupEnd byte = iota
+	backupBloom
+	backupCuckoo
+	backupDump
+)
+
+// Backup - Writes every RedisBloom key matching pattern (bloom, cuckoo, count-min sketch, top-k and t-digest)
+// to w, along with its remaining time to live. Bloom and cuckoo filters are streamed with SCANDUMP, other types
+// are serialized with DUMP and can therefore only be restored on a server with a compatible RDB version.
+// Returns the number of keys written.
+func (client *Client) Backup(ctx context.Context, w io.Writer, pattern string) (int, error) {
+	bw := bufio.NewWriter(w)
+	if _, err := bw.WriteString(backupMagic); err != nil {
+		return 0, err
+	}
+	if err := bw.WriteByte(backupFormatVersion); err != nil {
+		return 0, err
+	}
+	count := 0
+	err := client.scanKeys(pattern, func(key string) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		keyType, err := client.keyType(key)
+		if err != nil {
+			return err
+		}
+		var kind byte
+		switch keyType {
+		case bloomTypeName:
+			kind = backupBloom
+		case cuckooTypeName:
+			kind = backupCuckoo
+		case cmsTypeName, topkTypeName, tdigestTypeName:
+			kind = backupDump
+		default:
+			return nil
+		}
+		if err = client.backupKey(bw, kind, key); err != nil {
+			return err
+		}
+		count++
+		return nil
+	})
+	if err != nil {
+		return count, err
+	}
+	if err = bw.WriteByte(backupEnd); err != nil {
+		return count, err
+	}
+	return count, bw.Flush()
+}
+
+func (client *Client) backupKey(bw *bufio.Writer, kind byte, key string) error {
+	conn := client.Pool.Get()
+	pttl, err := redis.Int64(conn.Do("PTTL", client.key(key)))
+	conn.Close()
+	if err != nil {
+		return err
+	}
+	if err = bw.WriteByte(kind); err != nil {
+		return err
+	}
+	if err = binary.Write(bw, binary.BigEndian, uint32(len(key))); err != nil {
+		return err
+	}
+	if _, err = bw.WriteString(key); err != nil {
+		return err
+	}
+	if err = binary.Write(bw, binary.BigEndian, pttl); err != nil {
+		return err
+	}
+	switch kind {
+	case backupBloom:
+		return client.BfDumpToWriter(key, bw)
+	case backupCuckoo:
+		return client.CfDumpToWriter(key, bw)
+	default:
+		conn := client.Pool.Get()
+		payload, err := redis.Bytes(conn.Do("DUMP", client.key(key)))
+		conn.Close()
+		if err != nil {
+			return err
+		}
+		if err = binary.Write(bw, binary.BigEndian, uint32(len(payload))); err != nil {
+			return err
+		}
+		_, err = bw.Write(payload)
+		return err
+	}
+}
+
+// Restore - Restores the keys of a stream written by Backup, which must not already exist.
+// Returns the number of keys restored.
+func (client *Client) Restore(ctx context.Context, r io.Reader) (int, error) {
+	br := bufio.NewReader(r)
+	header := make([]byte, len(backupMagic)+1)
+	if _, err := io.ReadFull(br, header); err != nil || string(header[:len(backupMagic)]) != backupMagic {
+		return 0, ErrInvalidDump
+	}
+	if version := header[len(backupMagic)]; version != backupFormatVersion {
+		return 0, fmt.Errorf("redisbloom: unsupported backup format version %d", version)
+	}
+	count := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return count, err
+		}
+		kind, err := br.ReadByte()
+		if err != nil {
+			return count, fmt.Errorf("redisbloom: reading backup record: %v", err)
+		}
+		if kind == backupEnd {
+			return count, nil
+		}
+		key, err := readBackupBytes(br, maxBackupKeySize)
+		if err != nil {
+			return count, err
+		}
+		var pttl int64
+		if err = binary.Read(br, binary.BigEndian, &pttl); err != nil {
+			return count, fmt.Errorf("redisbloom: reading backup record: %v", err)
+		}
+		if err = client.restoreKey(br, kind, string(key), pttl); err != nil {
+			return count, err
+		}
+		count++
+	}
+}
+
+func (client *Client) restoreKey(br *bufio.Reader, kind byte, key string, pttl int64) error {
+	var err error
+	switch kind {
+	case backupBloom:
+		err = client.BfLoadFromReader(key, br)
+	case backupCuckoo:
+		err = client.CfLoadFromReader(key, br)
+	case backupDump:
+		var payload []byte
+		if payload, err = readBackupBytes(br, maxDumpChunkSize); err != nil {
+			return err
+		}
+		conn := client.Pool.Get()
+		_, err = conn.Do("RESTORE", client.key(key), 0, payload)
+		conn.Close()
+	default:
+		return ErrInvalidDump
+	}
+	if err != nil || pttl <= 0 {
+		return err
+	}
+	conn := client.Pool.Get()
+	defer conn.Close()
+	_, err = conn.Do("PEXPIRE", client.key(key), pttl)
+	return err
+}
+
+func readBackupBytes(br *bufio.Reader, max uint32) ([]byte, error) {
+	var length uint32
+	if err := binary.Read(br, binary.BigEndian, &length); err != nil {
+		return nil, fmt.Errorf("redisbloom: reading backup record: %v", err)
+	}
+	if length > max {
+		return nil, ErrInvalidDump
+	}
+	data := make([]byte, length)
+	if _, err := io.ReadFull(br, data); err != nil {
+		return nil, fmt.Errorf("redisbloom: reading backup record: %v", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,63 @@
+package redis_bloom_go
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_BackupRestore(t *testing.T) {
+	client.FlushAll()
+	client.Add("backup:bf", "a")
+	client.CfAdd("backup:cf", "b")
+	client.CmsInitByDim("backup:cms", 1000, 5)
+	client.CmsIncrByItems("backup:cms", []CmsIncrement{{"c", 3}})
+	client.TopkReserve("backup:topk", 10, 2000, 7, 0.925)
+	client.TopkAdd("backup:topk", []string{"d"})
+	client.TdCreate("backup:td", 100)
+	client.TdAddValues("backup:td", 1.0, 2.0)
+	client.Add("other:bf", "e")
+	conn := client.Pool.Get()
+	defer conn.Close()
+	conn.Do("SET", "backup:plain", "value")
+	conn.Do("EXPIRE", "backup:bf", 3600)
+
+	var buf bytes.Buffer
+	count, err := client.Backup(context.Background(), &buf, "backup:*")
+	assert.Nil(t, err)
+	assert.Equal(t, 5, count)
+
+	client.FlushAll()
+	count, err = client.Restore(context.Background(), &buf)
+	assert.Nil(t, err)
+	assert.Equal(t, 5, count)
+
+	exists, err := client.Exists("backup:bf", "a")
+	assert.Nil(t, err)
+	assert.True(t, exists)
+	exists, err = client.CfExists("backup:cf", "b")
+	assert.Nil(t, err)
+	assert.True(t, exists)
+	counts, err := client.CmsQuery("backup:cms", []string{"c"})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{3}, counts)
+	query, err := client.TopkQuery("backup:topk", []string{"d"})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1}, query)
+	max, err := client.TdMax("backup:td")
+	assert.Nil(t, err)
+	assert.Equal(t, 2.0, max)
+	ttl, err := conn.Do("TTL", "backup:bf")
+	assert.Nil(t, err)
+	assert.Less(t, int64(0), ttl.(int64))
+
+	_, err = client.Restore(context.Background(), bytes.NewReader([]byte("garbage")))
+	assert.Equal(t, ErrInvalidDump, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = client.Backup(ctx, &buf, "backup:*")
+	assert.Equal(t, context.Canceled, err)
+}
@@ -0,0 +1,237 @@
+package redis_bloom_go
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// ErrBatcherClosed is returned by the futures of commands queued on a closed Batcher
+var ErrBatcherClosed = errors.New("redisbloom: batcher is closed")
+
+// BatcherConfig tunes when a Batcher flushes its queue
+type BatcherConfig struct {
+	// MaxDelay is the longest a queued command waits before being sent, 1ms by default
+	MaxDelay time.Duration
+	// MaxItems is the number of queued commands that triggers a flush, 1000 by default
+	MaxItems int
+}
+
+const (
+	defaultBatcherMaxDelay = time.Millisecond
+	defaultBatcherMaxItems = 1000
+)
+
+// Batcher queues single item Add and Exists calls and sends them in the background as pipelined
+// BF.MADD and BF.MEXISTS commands, one per key, giving pipeline throughput to callers issuing
+// one item at a time. Results are delivered through futures.
+type Batcher struct {
+	client  *Client
+	config  BatcherConfig
+	mutex   sync.Mutex
+	closed  bool
+	queue   chan batchRequest
+	flushed chan struct{}
+}
+
+type batchRequest struct {
+	command string
+	key     string
+	item    string
+	future  *BoolFuture
+}
+
+// batchGroup gathers the queued requests sent as a single command
+type batchGroup struct {
+	command  string
+	key      string
+	items    []string
+	requests []batchRequest
+}
+
+// NewBatcher - Returns a batcher sending its commands through client, and starts its flushing goroutine
+func NewBatcher(client *Client, config BatcherConfig) *Batcher {
+	if config.MaxDelay <= 0 {
+		config.MaxDelay = defaultBatcherMaxDelay
+	}
+	if config.MaxItems <= 0 {
+		config.MaxItems = defaultBatcherMaxItems
+	}
+	b := &Batcher{
+		client:  client,
+		config:  config,
+		queue:   make(chan batchRequest, config.MaxItems),
+		flushed: make(chan struct{}),
+	}
+	go b.run()
+	client.register(b, b.Close)
+	return b
+}
+
+// Add - Queues the addition of item to the bloom filter stored at key. The future resolves to
+// true if the item was newly added.
+func (b *Batcher) Add(key string, item string) *BoolFuture {
+	return b.enqueue("BF.MADD", key, item)
+}
+
+// Exists - Queues a lookup of item in the bloom filter stored at key
+func (b *Batcher) Exists(key string, item string) *BoolFuture {
+	return b.enqueue("BF.MEXISTS", key, item)
+}
+
+func (b *Batcher) enqueue(command string, key string, item string) *BoolFuture {
+	future := newBoolFuture()
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.closed {
+		future.resolve(false, ErrBatcherClosed)
+		return future
+	}
+	b.queue <- batchRequest{command: command, key: key, item: item, future: future}
+	return future
+}
+
+// Close - Sends the commands still queued, waits for their results and stops the batcher
+func (b *Batcher) Close() {
+	b.mutex.Lock()
+	if b.closed {
+		b.mutex.Unlock()
+		<-b.flushed
+		return
+	}
+	b.closed = true
+	close(b.queue)
+	b.mutex.Unlock()
+	<-b.flushed
+	b.client.unregister(b)
+}
+
+func (b *Batcher) run() {
+	defer close(b.flushed)
+	pending := make([]batchRequest, 0, b.config.MaxItems)
+	timer := time.NewTimer(b.config.MaxDelay)
+	timer.Stop()
+	for {
+		select {
+		case request, ok := <-b.queue:
+			if !ok {
+				b.flush(pending)
+				return
+			}
+			if len(pending) == 0 {
+				timer.Reset(b.config.MaxDelay)
+			}
+			pending = append(pending, request)
+			if len(pending) >= b.config.MaxItems {
+				if !timer.Stop() {
+					<-timer.C
+				}
+				b.flush(pending)
+				pending = pending[:0]
+			}
+		case <-timer.C:
+			b.flush(pending)
+			pending = pending[:0]
+		}
+	}
+}
+
+// flush sends the pending requests in a single pipeline and resolves their futures
+func (b *Batcher) flush(pending []batchRequest) {
+	if len(pending) == 0 {
+		return
+	}
+	var groups []*batchGroup
+	index := make(map[[2]string]*batchGroup)
+	for _, request := range pending {
+		id := [2]string{request.command, request.key}
+		group, ok := index[id]
+		if ok && b.expires(group) && len(group.items)+1 >= maxExpireOnSuccessArgs {
+			// the arguments of a write setting the TTL must fit in a script
+			ok = false
+		}
+		if !ok {
+			group = &batchGroup{command: request.command, key: request.key}
+			index[id] = group
+			groups = append(groups, group)
+		}
+		group.items = append(group.items, request.item)
+		group.requests = append(group.requests, request)
+	}
+
+	conn := b.client.Pool.Get()
+	defer conn.Close()
+	replies, err := b.send(conn, groups)
+	for i, group := range groups {
+		var values []int64
+		groupErr := err
+		if groupErr == nil {
+			if replyErr, ok := replies[i].(error); ok {
+				groupErr = replyErr
+			} else {
+				values, groupErr = redis.Int64s(replies[i], nil)
+			}
+		}
+		if groupErr == nil && len(values) != len(group.requests) {
+			groupErr = errors.New("redisbloom: unexpected number of results in batch reply")
+		}
+		for j, request := range group.requests {
+			if groupErr != nil {
+				request.future.resolve(false, groupErr)
+			} else {
+				request.future.resolve(values[j] == 1, nil)
+			}
+		}
+	}
+}
+
+// expires reports whether the command of group sets the write TTL of the client
+func (b *Batcher) expires(group *batchGroup) bool {
+	return group.command == "BF.MADD" && b.client.writeTTL > 0
+}
+
+// send pipelines one command per group, the writes setting the TTL of their key on success in the same script
+// when the client has a write TTL, and returns the reply of each group's command
+func (b *Batcher) send(conn redis.Conn, groups []*batchGroup) ([]interface{}, error) {
+	ttl := b.client.writeTTL
+	for _, group := range groups {
+		args := redis.Args{b.client.key(group.key)}.AddFlat(group.items)
+		var err error
+		switch {
+		case !b.expires(group):
+			err = conn.Send(group.command, args...)
+		case ttl < time.Millisecond:
+			// PEXPIRE 0 would delete the key, the write fails with ErrInvalidTTL without being sent
+			continue
+		default:
+			scriptArgs := redis.Args{b.client.key(group.key), int64(ttl / time.Millisecond), group.command}
+			err = expireOnSuccessScript.Send(conn, append(scriptArgs, args...)...)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := conn.Flush(); err != nil {
+		return nil, err
+	}
+	replies := make([]interface{}, 0, len(groups))
+	var outErr error
+	// drain every reply, even after a failure, so the connection is returned to the pool clean
+	for _, group := range groups {
+		if b.expires(group) && ttl < time.Millisecond {
+			replies = append(replies, ErrInvalidTTL)
+			continue
+		}
+		reply, err := conn.Receive()
+		if err != nil {
+			if _, ok := err.(redis.Error); !ok && outErr == nil {
+				outErr = err
+			}
+			reply = err
+		}
+		replies = append(replies, reply)
+	}
+	return replies, outErr
+}
@@ -0,0 +1,94 @@
+package redis_bloom_go
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatcher(t *testing.T) {
+	client.FlushAll()
+	batcher := NewBatcher(client, BatcherConfig{MaxDelay: time.Millisecond, MaxItems: 10})
+
+	added := make([]*BoolFuture, 25)
+	for i := range added {
+		added[i] = batcher.Add("test_batcher", fmt.Sprintf("item-%d", i))
+	}
+	for _, future := range added {
+		ok, err := future.Get()
+		assert.Nil(t, err)
+		assert.True(t, ok)
+	}
+
+	present := batcher.Exists("test_batcher", "item-3")
+	missing := batcher.Exists("test_batcher", "missing")
+	again := batcher.Add("test_batcher", "item-3")
+	other := batcher.Add("test_batcher_other", "item-3")
+	batcher.Close()
+
+	for _, future := range []*BoolFuture{present, other} {
+		ok, err := future.Get()
+		assert.Nil(t, err)
+		assert.True(t, ok)
+	}
+	for _, future := range []*BoolFuture{missing, again} {
+		ok, err := future.Get()
+		assert.Nil(t, err)
+		assert.False(t, ok)
+	}
+
+	_, err := batcher.Exists("test_batcher", "item-3").Get()
+	assert.Equal(t, ErrBatcherClosed, err)
+}
+
+func TestBatcher_WrongType(t *testing.T) {
+	client.FlushAll()
+	client.CfReserve("test_batcher_cuckoo", 1000, 0, 0, 0)
+	batcher := NewBatcher(client, BatcherConfig{})
+	defer batcher.Close()
+
+	wrong := batcher.Exists("test_batcher_cuckoo", "a")
+	right := batcher.Add("test_batcher", "a")
+	_, err := wrong.Get()
+	assert.NotNil(t, err)
+	ok, err := right.Get()
+	assert.Nil(t, err)
+	assert.True(t, ok)
+}
+
+func TestBatcher_WriteTTL(t *testing.T) {
+	conn := &fakeConn{replies: []interface{}{
+		[]interface{}{int64(1), int64(0)},
+		redis.Error("WRONGTYPE Operation against a key holding the wrong kind of value"),
+	}}
+	c := NewClientFromPool(nil, "test", WithWriteTTL(time.Minute))
+	c.Pool = &fakePool{conn: conn}
+	batcher := NewBatcher(c, BatcherConfig{MaxDelay: time.Hour, MaxItems: 3})
+	added := batcher.Add("bf", "a")
+	again := batcher.Add("bf", "b")
+	wrong := batcher.Add("plain", "a")
+
+	ok, err := added.Get()
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	ok, err = again.Get()
+	assert.Nil(t, err)
+	assert.False(t, ok)
+	// the TTL is set by the script running each write, and only if it succeeds
+	_, err = wrong.Get()
+	assert.Equal(t, redis.Error("WRONGTYPE Operation against a key holding the wrong kind of value"), err)
+	assert.Equal(t, []string{"EVAL", "EVAL"}, conn.commands)
+	batcher.Close()
+
+	conn = &fakeConn{}
+	c = NewClientFromPool(nil, "test", WithWriteTTL(time.Microsecond))
+	c.Pool = &fakePool{conn: conn}
+	batcher = NewBatcher(c, BatcherConfig{})
+	defer batcher.Close()
+	_, err = batcher.Add("bf", "a").Get()
+	assert.Equal(t, ErrInvalidTTL, err)
+	assert.Nil(t, conn.commands)
+}
@@ -0,0 +1,90 @@
+package redis_bloom_go
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+)
+
+// mergeSuffix is appended to the destination of MergeBloom to build the union before renaming it
+const mergeSuffix = ":merge"
+
+// ErrIncompatibleFilters is returned by MergeBloom when the source filters do not share their parameters
+var ErrIncompatibleFilters = errors.New("redisbloom: bloom filters do not share the same parameters and sub-filters")
+
+// MergeBloom - Stores at destKey the union of the bloom filters stored at srcKeys, overwriting destKey if it
+// exists. RedisBloom has no command to merge bloom filters, so the filters are fetched with BF.SCANDUMP, their
+// bit arrays are ORed in process, and the result is loaded with BF.LOADCHUNK under a temporary key renamed to
+// destKey. The filters must have been created with the same error rate, capacity and expansion, and must have
+// the same number of sub-filters, otherwise ErrIncompatibleFilters is returned.
+// The item count of the union is the sum of the counts of the sources, which overestimates it when they share
+// items, so the union adds a sub-filter sooner than a filter holding the same items would.
+func (client *Client) MergeBloom(destKey string, srcKeys ...string) error {
+	if len(srcKeys) == 0 {
+		return errors.New("MergeBloom expects at least one source filter")
+	}
+	union, err := client.BfExportLocal(srcKeys[0])
+	if err != nil {
+		return err
+	}
+	for _, key := range srcKeys[1:] {
+		local, err := client.BfExportLocal(key)
+		if err != nil {
+			return err
+		}
+		if err = union.merge(local); err != nil {
+			return err
+		}
+	}
+	tmpKey := destKey + mergeSuffix
+	if _, err = client.DeleteFilter(tmpKey); err != nil {
+		return err
+	}
+	if err = client.BfImportLocal(tmpKey, union); err == nil {
+		err = client.RenameFilter(tmpKey, destKey)
+	}
+	if err != nil {
+		client.DeleteFilter(tmpKey)
+	}
+	return err
+}
+
+// merge ORs the bit arrays of other into the filter and adds up their item counts
+func (b *LocalBloom) merge(other *LocalBloom) error {
+	if len(b.header) != bloomDumpHeaderSize+len(b.filter.links)*bloomDumpLinkSize {
+		return ErrUnsupportedDump
+	}
+	if !bytes.Equal(bloomDumpShape(b.header), bloomDumpShape(other.header)) || len(b.lengths) != len(other.lengths) {
+		return ErrIncompatibleFilters
+	}
+	for i, length := range b.lengths {
+		if other.lengths[i] != length || other.iters[i+1] != b.iters[i+1] {
+			return ErrIncompatibleFilters
+		}
+	}
+	for i, link := range b.filter.links {
+		for j, octet := range other.filter.links[i].data {
+			link.data[j] |= octet
+		}
+	}
+	addUint64 := func(offset int) {
+		sum := binary.LittleEndian.Uint64(b.header[offset:]) + binary.LittleEndian.Uint64(other.header[offset:])
+		binary.LittleEndian.PutUint64(b.header[offset:], sum)
+	}
+	addUint64(0)
+	for i := range b.filter.links {
+		addUint64(bloomDumpHeaderSize + i*bloomDumpLinkSize + 16)
+	}
+	return nil
+}
+
+// bloomDumpShape returns a copy of a BF.SCANDUMP header with the item counts cleared, leaving the parameters
+// which must match for the bit arrays of two filters to be combined
+func bloomDumpShape(header []byte) []byte {
+	shape := append([]byte(nil), header...)
+	binary.LittleEndian.PutUint64(shape[0:8], 0)
+	for offset := bloomDumpHeaderSize; offset+bloomDumpLinkSize <= len(shape); offset += bloomDumpLinkSize {
+		binary.LittleEndian.PutUint64(shape[offset+16:offset+24], 0)
+	}
+	return shape
+}
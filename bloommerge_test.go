@@ -0,0 +1,47 @@
+package redis_bloom_go
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalBloom_merge(t *testing.T) {
+	a, b := testLocalBloom(10), testLocalBloom(10)
+	a.Add("a")
+	b.Add("b")
+	b.Add("c")
+	assert.Nil(t, a.merge(b))
+	assert.True(t, a.Exists("a"))
+	assert.True(t, a.Exists("b"))
+	assert.True(t, a.Exists("c"))
+	assert.False(t, a.Exists("d"))
+	assert.Equal(t, int64(3), a.Count())
+
+	assert.Equal(t, ErrIncompatibleFilters, a.merge(testLocalBloom(20)))
+}
+
+func TestClient_MergeBloom(t *testing.T) {
+	client.FlushAll()
+	assert.Nil(t, client.Reserve("test_merge_a", 0.01, 1000))
+	assert.Nil(t, client.Reserve("test_merge_b", 0.01, 1000))
+	assert.Nil(t, client.Reserve("test_merge_other", 0.01, 2000))
+	client.BfAddMulti("test_merge_a", []string{"a", "b"})
+	client.BfAddMulti("test_merge_b", []string{"c"})
+
+	assert.Nil(t, client.MergeBloom("test_merge_union", "test_merge_a", "test_merge_b"))
+	exists, err := client.BfExistsMulti("test_merge_union", []string{"a", "b", "c", "d"})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1, 1, 1, 0}, exists)
+	info, err := client.Info("test_merge_union")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), info["Number of items inserted"])
+
+	// the destination may be one of the sources
+	assert.Nil(t, client.MergeBloom("test_merge_a", "test_merge_a", "test_merge_b"))
+	exists, err = client.BfExistsMulti("test_merge_a", []string{"c"})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1}, exists)
+
+	assert.Equal(t, ErrIncompatibleFilters, client.MergeBloom("test_merge_union", "test_merge_a", "test_merge_other"))
+}
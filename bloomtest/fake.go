@@ -0,0 +1,678 @@
+// Package bloomtest provides an in-memory fake of the RedisBloom client, so that code using
+// the client can be unit tested without a Redis server running the RedisBloom module.
+//
+// The fake keeps exact data structures: its filters never report false positives and its
+// sketches return exact counts, which makes test expectations deterministic.
+package bloomtest
+
+import (
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/gomodule/redigo/redis"
+	redisbloom "github.com/mohit-doubtnut/redisbloom-go"
+)
+
+// the errors replied by RedisBloom, returned by the fake in the same circumstances
+var (
+	errWrongType      = redis.Error("WRONGTYPE Operation against a key holding the wrong kind of value")
+	errItemExists     = redis.Error("ERR item exists")
+	errNotFound       = redis.Error("ERR not found")
+	errCMSMissing     = redis.Error("CMS: key does not exist")
+	errCMSExists      = redis.Error("CMS: key already exists")
+	errTopKMissing    = redis.Error("TopK: key does not exist")
+	errTopKExists     = redis.Error("TopK: key already exists")
+	errTDigestExists  = redis.Error("ERR T-Digest: key already exists")
+	errTDigestMissing = redis.Error("ERR T-Digest: key does not exist")
+)
+
+type bloom struct {
+	capacity  uint64
+	errorRate float64
+	items     map[string]bool
+}
+
+type cuckoo struct {
+	capacity int64
+	items    map[string]int64
+	inserted int64
+	deleted  int64
+}
+
+type sketch struct {
+	width  int64
+	depth  int64
+	counts map[string]int64
+	total  int64
+}
+
+type topK struct {
+	k      int64
+	width  int64
+	depth  int64
+	decay  float64
+	counts map[string]int64
+}
+
+type tdigest struct {
+	compression int64
+	values      []float64
+	sorted      bool
+}
+
+// Fake is an in-memory stand-in for *redis_bloom_go.Client implementing redis_bloom_go.Commands
+type Fake struct {
+	mutex sync.Mutex
+	keys  map[string]interface{}
+}
+
+// New - Returns an empty fake
+func New() *Fake {
+	return &Fake{keys: make(map[string]interface{})}
+}
+
+// FlushAll - Deletes every key
+func (f *Fake) FlushAll() error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.keys = make(map[string]interface{})
+	return nil
+}
+
+// DeleteFilter - Deletes key, returning false if it did not exist
+func (f *Fake) DeleteFilter(key string) (bool, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	_, ok := f.keys[key]
+	delete(f.keys, key)
+	return ok, nil
+}
+
+// KeyExists - Reports whether key exists
+func (f *Fake) KeyExists(key string) (bool, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	_, ok := f.keys[key]
+	return ok, nil
+}
+
+func (f *Fake) bloom(key string, create bool) (*bloom, error) {
+	value, ok := f.keys[key]
+	if !ok {
+		if !create {
+			return nil, nil
+		}
+		filter := &bloom{capacity: 100, errorRate: 0.01, items: make(map[string]bool)}
+		f.keys[key] = filter
+		return filter, nil
+	}
+	filter, ok := value.(*bloom)
+	if !ok {
+		return nil, errWrongType
+	}
+	return filter, nil
+}
+
+// Reserve - Creates an empty bloom filter
+func (f *Fake) Reserve(key string, errorRate float64, capacity uint64) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if _, ok := f.keys[key]; ok {
+		return errItemExists
+	}
+	f.keys[key] = &bloom{capacity: capacity, errorRate: errorRate, items: make(map[string]bool)}
+	return nil
+}
+
+// Add - Adds item to the bloom filter, creating it if needed
+func (f *Fake) Add(key string, item string) (bool, error) {
+	added, err := f.BfAddMulti(key, []string{item})
+	if err != nil {
+		return false, err
+	}
+	return added[0] == 1, nil
+}
+
+// Exists - Determines whether item is in the bloom filter
+func (f *Fake) Exists(key string, item string) (bool, error) {
+	exists, err := f.BfExistsMulti(key, []string{item})
+	if err != nil {
+		return false, err
+	}
+	return exists[0] == 1, nil
+}
+
+// BfAddMulti - Adds items to the bloom filter, creating it if needed
+func (f *Fake) BfAddMulti(key string, items []string) ([]int64, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	filter, err := f.bloom(key, true)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]int64, len(items))
+	for i, item := range items {
+		if !filter.items[item] {
+			filter.items[item] = true
+			results[i] = 1
+		}
+	}
+	return results, nil
+}
+
+// BfExistsMulti - Determines whether each item is in the bloom filter
+func (f *Fake) BfExistsMulti(key string, items []string) ([]int64, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	filter, err := f.bloom(key, false)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]int64, len(items))
+	for i, item := range items {
+		if filter != nil && filter.items[item] {
+			results[i] = 1
+		}
+	}
+	return results, nil
+}
+
+// Info - Returns the BF.INFO fields of the bloom filter
+func (f *Fake) Info(key string) (map[string]int64, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	filter, err := f.bloom(key, false)
+	if err != nil {
+		return nil, err
+	}
+	if filter == nil {
+		return nil, errNotFound
+	}
+	return map[string]int64{
+		"Capacity":                 int64(filter.capacity),
+		"Size":                     int64(filter.capacity),
+		"Number of filters":        1,
+		"Number of items inserted": int64(len(filter.items)),
+		"Expansion rate":           2,
+	}, nil
+}
+
+func (f *Fake) cuckoo(key string, create bool) (*cuckoo, error) {
+	value, ok := f.keys[key]
+	if !ok {
+		if !create {
+			return nil, nil
+		}
+		filter := &cuckoo{capacity: 1024, items: make(map[string]int64)}
+		f.keys[key] = filter
+		return filter, nil
+	}
+	filter, ok := value.(*cuckoo)
+	if !ok {
+		return nil, errWrongType
+	}
+	return filter, nil
+}
+
+// CfReserve - Creates an empty cuckoo filter
+func (f *Fake) CfReserve(key string, capacity int64, bucketSize int64, maxIterations int64, expansion int64) (string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if _, ok := f.keys[key]; ok {
+		return "", errItemExists
+	}
+	f.keys[key] = &cuckoo{capacity: capacity, items: make(map[string]int64)}
+	return "OK", nil
+}
+
+// CfAdd - Adds item to the cuckoo filter, creating it if needed
+func (f *Fake) CfAdd(key string, item string) (bool, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	filter, err := f.cuckoo(key, true)
+	if err != nil {
+		return false, err
+	}
+	filter.items[item]++
+	filter.inserted++
+	return true, nil
+}
+
+// CfAddNx - Adds item to the cuckoo filter unless it is already in it
+func (f *Fake) CfAddNx(key string, item string) (bool, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	filter, err := f.cuckoo(key, true)
+	if err != nil {
+		return false, err
+	}
+	if filter.items[item] > 0 {
+		return false, nil
+	}
+	filter.items[item]++
+	filter.inserted++
+	return true, nil
+}
+
+// CfExists - Determines whether item is in the cuckoo filter
+func (f *Fake) CfExists(key string, item string) (bool, error) {
+	count, err := f.CfCount(key, item)
+	return count > 0, err
+}
+
+// CfExistsMulti - Determines whether each item is in the cuckoo filter
+func (f *Fake) CfExistsMulti(key string, items ...string) ([]int64, error) {
+	results := make([]int64, len(items))
+	for i, item := range items {
+		exists, err := f.CfExists(key, item)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			results[i] = 1
+		}
+	}
+	return results, nil
+}
+
+// CfDel - Deletes item once from the cuckoo filter
+func (f *Fake) CfDel(key string, item string) (bool, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	filter, err := f.cuckoo(key, false)
+	if err != nil {
+		return false, err
+	}
+	if filter == nil {
+		return false, errNotFound
+	}
+	if filter.items[item] == 0 {
+		return false, nil
+	}
+	filter.items[item]--
+	filter.deleted++
+	return true, nil
+}
+
+// CfCount - Returns the number of times item is in the cuckoo filter
+func (f *Fake) CfCount(key string, item string) (int64, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	filter, err := f.cuckoo(key, false)
+	if err != nil || filter == nil {
+		return 0, err
+	}
+	return filter.items[item], nil
+}
+
+// CfInfo - Returns the CF.INFO fields of the cuckoo filter
+func (f *Fake) CfInfo(key string) (map[string]int64, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	filter, err := f.cuckoo(key, false)
+	if err != nil {
+		return nil, err
+	}
+	if filter == nil {
+		return nil, errNotFound
+	}
+	return map[string]int64{
+		"Size":                     filter.capacity,
+		"Number of buckets":        filter.capacity / 2,
+		"Number of filters":        1,
+		"Number of items inserted": filter.inserted - filter.deleted,
+		"Number of items deleted":  filter.deleted,
+		"Bucket size":              2,
+		"Expansion rate":           1,
+		"Max iterations":           20,
+	}, nil
+}
+
+func (f *Fake) sketch(key string) (*sketch, error) {
+	value, ok := f.keys[key]
+	if !ok {
+		return nil, errCMSMissing
+	}
+	s, ok := value.(*sketch)
+	if !ok {
+		return nil, errWrongType
+	}
+	return s, nil
+}
+
+// CmsInitByDim - Creates an empty count-min sketch
+func (f *Fake) CmsInitByDim(key string, width int64, depth int64) (string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if _, ok := f.keys[key]; ok {
+		return "", errCMSExists
+	}
+	f.keys[key] = &sketch{width: width, depth: depth, counts: make(map[string]int64)}
+	return "OK", nil
+}
+
+// CmsInitByProb - Creates an empty count-min sketch sized for the given error and probability
+func (f *Fake) CmsInitByProb(key string, error float64, probability float64) (string, error) {
+	return f.CmsInitByDim(key, int64(math.Ceil(math.E/error)), int64(math.Ceil(math.Log(1/probability))))
+}
+
+// CmsIncrBy - Increases the counts of items
+func (f *Fake) CmsIncrBy(key string, itemIncrements map[string]int64) ([]int64, error) {
+	increments := make([]redisbloom.CmsIncrement, 0, len(itemIncrements))
+	for item, increment := range itemIncrements {
+		increments = append(increments, redisbloom.CmsIncrement{Item: item, Count: increment})
+	}
+	return f.CmsIncrByItems(key, increments)
+}
+
+// CmsIncrByItems - Increases the count of each item by its increment
+func (f *Fake) CmsIncrByItems(key string, increments []redisbloom.CmsIncrement) ([]int64, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	s, err := f.sketch(key)
+	if err != nil {
+		return nil, err
+	}
+	counts := make([]int64, len(increments))
+	for i, increment := range increments {
+		s.counts[increment.Item] += increment.Count
+		s.total += increment.Count
+		counts[i] = s.counts[increment.Item]
+	}
+	return counts, nil
+}
+
+// CmsQuery - Returns the counts of items
+func (f *Fake) CmsQuery(key string, items []string) ([]int64, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	s, err := f.sketch(key)
+	if err != nil {
+		return nil, err
+	}
+	counts := make([]int64, len(items))
+	for i, item := range items {
+		counts[i] = s.counts[item]
+	}
+	return counts, nil
+}
+
+// CmsInfo - Returns the width, depth and total count of the sketch
+func (f *Fake) CmsInfo(key string) (map[string]int64, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	s, err := f.sketch(key)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]int64{"width": s.width, "depth": s.depth, "count": s.total}, nil
+}
+
+func (f *Fake) topK(key string) (*topK, error) {
+	value, ok := f.keys[key]
+	if !ok {
+		return nil, errTopKMissing
+	}
+	t, ok := value.(*topK)
+	if !ok {
+		return nil, errWrongType
+	}
+	return t, nil
+}
+
+// top returns the k items with the highest counts, ties broken by item
+func (t *topK) top() []string {
+	items := make([]string, 0, len(t.counts))
+	for item := range t.counts {
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if t.counts[items[i]] != t.counts[items[j]] {
+			return t.counts[items[i]] > t.counts[items[j]]
+		}
+		return items[i] < items[j]
+	})
+	if int64(len(items)) > t.k {
+		items = items[:t.k]
+	}
+	return items
+}
+
+func (t *topK) contains(item string) bool {
+	for _, top := range t.top() {
+		if top == item {
+			return true
+		}
+	}
+	return false
+}
+
+// TopkReserve - Creates an empty top-k sketch
+func (f *Fake) TopkReserve(key string, topk int64, width int64, depth int64, decay float64) (string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if _, ok := f.keys[key]; ok {
+		return "", errTopKExists
+	}
+	f.keys[key] = &topK{k: topk, width: width, depth: depth, decay: decay, counts: make(map[string]int64)}
+	return "OK", nil
+}
+
+// TopkAdd - Adds items to the sketch, returning for each item the item it expelled from the top-k, if any
+func (f *Fake) TopkAdd(key string, items []string) ([]string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	t, err := f.topK(key)
+	if err != nil {
+		return nil, err
+	}
+	expelled := make([]string, len(items))
+	for i, item := range items {
+		before := t.top()
+		t.counts[item]++
+		if !t.contains(item) {
+			continue
+		}
+		for _, previous := range before {
+			if !t.contains(previous) {
+				expelled[i] = previous
+			}
+		}
+	}
+	return expelled, nil
+}
+
+// TopkQuery - Returns 1 for each item in the top-k, 0 otherwise
+func (f *Fake) TopkQuery(key string, items []string) ([]int64, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	t, err := f.topK(key)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]int64, len(items))
+	for i, item := range items {
+		if t.contains(item) {
+			results[i] = 1
+		}
+	}
+	return results, nil
+}
+
+// TopkCount - Returns the counts of items
+func (f *Fake) TopkCount(key string, items []string) ([]int64, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	t, err := f.topK(key)
+	if err != nil {
+		return nil, err
+	}
+	counts := make([]int64, len(items))
+	for i, item := range items {
+		counts[i] = t.counts[item]
+	}
+	return counts, nil
+}
+
+// TopkList - Returns the top-k items, from the most to the least frequent
+func (f *Fake) TopkList(key string) ([]string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	t, err := f.topK(key)
+	if err != nil {
+		return nil, err
+	}
+	return t.top(), nil
+}
+
+// TopkListWithCount - Returns the top-k items with their counts
+func (f *Fake) TopkListWithCount(key string) (map[string]int64, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	t, err := f.topK(key)
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int64, t.k)
+	for _, item := range t.top() {
+		counts[item] = t.counts[item]
+	}
+	return counts, nil
+}
+
+func (f *Fake) tdigest(key string) (*tdigest, error) {
+	value, ok := f.keys[key]
+	if !ok {
+		return nil, errTDigestMissing
+	}
+	t, ok := value.(*tdigest)
+	if !ok {
+		return nil, errWrongType
+	}
+	if !t.sorted {
+		sort.Float64s(t.values)
+		t.sorted = true
+	}
+	return t, nil
+}
+
+// TdCreate - Creates an empty t-digest sketch
+func (f *Fake) TdCreate(key string, compression int64) (string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if _, ok := f.keys[key]; ok {
+		return "", errTDigestExists
+	}
+	f.keys[key] = &tdigest{compression: compression, sorted: true}
+	return "OK", nil
+}
+
+// TdReset - Empties the sketch
+func (f *Fake) TdReset(key string) (string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	t, err := f.tdigest(key)
+	if err != nil {
+		return "", err
+	}
+	t.values = nil
+	return "OK", nil
+}
+
+// TdAddValues - Adds values to the sketch
+func (f *Fake) TdAddValues(key string, values ...float64) (string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	t, err := f.tdigest(key)
+	if err != nil {
+		return "", err
+	}
+	t.values = append(t.values, values...)
+	t.sorted = false
+	return "OK", nil
+}
+
+// TdAdd - Adds each value of samples as many times as its integral weight
+func (f *Fake) TdAdd(key string, samples map[float64]float64) (string, error) {
+	var values []float64
+	for value, weight := range samples {
+		for i := 0; i < int(weight); i++ {
+			values = append(values, value)
+		}
+	}
+	return f.TdAddValues(key, values...)
+}
+
+// TdMin - Returns the smallest value of the sketch, NaN and redisbloom.ErrEmptySketch if it is empty
+func (f *Fake) TdMin(key string) (float64, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	t, err := f.tdigest(key)
+	if err != nil {
+		return 0, err
+	}
+	if len(t.values) == 0 {
+		return math.NaN(), redisbloom.ErrEmptySketch
+	}
+	return t.values[0], nil
+}
+
+// TdMax - Returns the largest value of the sketch, NaN and redisbloom.ErrEmptySketch if it is empty
+func (f *Fake) TdMax(key string) (float64, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	t, err := f.tdigest(key)
+	if err != nil {
+		return 0, err
+	}
+	if len(t.values) == 0 {
+		return math.NaN(), redisbloom.ErrEmptySketch
+	}
+	return t.values[len(t.values)-1], nil
+}
+
+// TdQuantile - Returns the value at quantile, by nearest rank
+func (f *Fake) TdQuantile(key string, quantile float64) (float64, error) {
+	quantiles, err := f.TdQuantiles(key, quantile)
+	if err != nil {
+		return 0, err
+	}
+	return quantiles[0], nil
+}
+
+// TdQuantiles - Returns the values at quantiles, by nearest rank
+func (f *Fake) TdQuantiles(key string, quantiles ...float64) ([]float64, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	t, err := f.tdigest(key)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]float64, len(quantiles))
+	for i, quantile := range quantiles {
+		if len(t.values) == 0 {
+			values[i] = math.NaN()
+			continue
+		}
+		values[i] = t.values[int(math.Round(quantile*float64(len(t.values)-1)))]
+	}
+	return values, nil
+}
+
+// TdCdf - Returns the fraction of the values of the sketch smaller than or equal to value
+func (f *Fake) TdCdf(key string, value float64) (float64, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	t, err := f.tdigest(key)
+	if err != nil {
+		return 0, err
+	}
+	if len(t.values) == 0 {
+		return math.NaN(), nil
+	}
+	n := sort.Search(len(t.values), func(i int) bool { return t.values[i] > value })
+	return float64(n) / float64(len(t.values)), nil
+}
+
+var _ redisbloom.Commands = (*Fake)(nil)
@@ -0,0 +1,134 @@
+package bloomtest
+
+import (
+	"math"
+	"testing"
+
+	redisbloom "github.com/mohit-doubtnut/redisbloom-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFake_Bloom(t *testing.T) {
+	f := New()
+	assert.Nil(t, f.Reserve("bf", 0.01, 1000))
+	assert.Equal(t, errItemExists, f.Reserve("bf", 0.01, 1000))
+	added, err := f.Add("bf", "a")
+	assert.Nil(t, err)
+	assert.True(t, added)
+	results, err := f.BfAddMulti("bf", []string{"a", "b"})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{0, 1}, results)
+	results, err = f.BfExistsMulti("bf", []string{"b", "c"})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1, 0}, results)
+	exists, err := f.Exists("missing", "a")
+	assert.Nil(t, err)
+	assert.False(t, exists)
+	info, err := f.Info("bf")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), info["Number of items inserted"])
+	assert.Equal(t, int64(1000), info["Capacity"])
+	_, err = f.Info("missing")
+	assert.Equal(t, errNotFound, err)
+
+	f.CfAdd("cf", "a")
+	_, err = f.Add("cf", "a")
+	assert.Equal(t, errWrongType, err)
+}
+
+func TestFake_Cuckoo(t *testing.T) {
+	f := New()
+	added, err := f.CfAddNx("cf", "a")
+	assert.Nil(t, err)
+	assert.True(t, added)
+	added, err = f.CfAddNx("cf", "a")
+	assert.Nil(t, err)
+	assert.False(t, added)
+	f.CfAdd("cf", "a")
+	count, err := f.CfCount("cf", "a")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), count)
+	deleted, err := f.CfDel("cf", "a")
+	assert.Nil(t, err)
+	assert.True(t, deleted)
+	results, err := f.CfExistsMulti("cf", "a", "b")
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1, 0}, results)
+	info, err := f.CfInfo("cf")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), info["Number of items inserted"])
+	assert.Equal(t, int64(1), info["Number of items deleted"])
+}
+
+func TestFake_CMS(t *testing.T) {
+	f := New()
+	_, err := f.CmsQuery("cms", []string{"a"})
+	assert.Equal(t, errCMSMissing, err)
+	f.CmsInitByDim("cms", 1000, 5)
+	counts, err := f.CmsIncrByItems("cms", []redisbloom.CmsIncrement{{Item: "a", Count: 2}, {Item: "a", Count: 3}})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{2, 5}, counts)
+	f.CmsIncrBy("cms", map[string]int64{"b": 1})
+	counts, err = f.CmsQuery("cms", []string{"a", "b", "c"})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{5, 1, 0}, counts)
+	info, err := f.CmsInfo("cms")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]int64{"width": 1000, "depth": 5, "count": 6}, info)
+}
+
+func TestFake_TopK(t *testing.T) {
+	f := New()
+	f.TopkReserve("topk", 2, 50, 3, 0.9)
+	expelled, err := f.TopkAdd("topk", []string{"a", "b", "c", "c"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"", "", "", "b"}, expelled)
+	list, err := f.TopkList("topk")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"c", "a"}, list)
+	results, err := f.TopkQuery("topk", []string{"a", "b"})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1, 0}, results)
+	counts, err := f.TopkCount("topk", []string{"b", "c"})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1, 2}, counts)
+	withCount, err := f.TopkListWithCount("topk")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]int64{"c": 2, "a": 1}, withCount)
+}
+
+func TestFake_TDigest(t *testing.T) {
+	f := New()
+	f.TdCreate("td", 100)
+	min, err := f.TdMin("td")
+	assert.Equal(t, redisbloom.ErrEmptySketch, err)
+	assert.True(t, math.IsNaN(min))
+	f.TdAddValues("td", 5, 1, 3, 2, 4)
+	f.TdAdd("td", map[float64]float64{6: 2})
+	max, err := f.TdMax("td")
+	assert.Nil(t, err)
+	assert.Equal(t, 6.0, max)
+	quantiles, err := f.TdQuantiles("td", 0, 0.5, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{1, 4, 6}, quantiles)
+	cdf, err := f.TdCdf("td", 3)
+	assert.Nil(t, err)
+	assert.InDelta(t, 3.0/7, cdf, 1e-9)
+	f.TdReset("td")
+	median, err := f.TdQuantile("td", 0.5)
+	assert.Nil(t, err)
+	assert.True(t, math.IsNaN(median))
+}
+
+func TestFake_Keys(t *testing.T) {
+	f := New()
+	f.Add("bf", "a")
+	exists, _ := f.KeyExists("bf")
+	assert.True(t, exists)
+	deleted, _ := f.DeleteFilter("bf")
+	assert.True(t, deleted)
+	f.Add("bf", "a")
+	f.FlushAll()
+	exists, _ = f.KeyExists("bf")
+	assert.False(t, exists)
+}
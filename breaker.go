@@ -0,0 +1,290 @@
+package redis_bloom_go
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// ErrCircuitOpen is returned instead of sending commands while the circuit breaker is open
+var ErrCircuitOpen = errors.New("redisbloom: circuit breaker is open")
+
+// CircuitState is the state of a circuit breaker
+type CircuitState int
+
+const (
+	// CircuitClosed lets every command through
+	CircuitClosed CircuitState = iota
+	// CircuitOpen fails every command fast with ErrCircuitOpen
+	CircuitOpen
+	// CircuitHalfOpen lets a few probe commands through to find out whether the server recovered
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// CircuitBreakerConfig describes when a circuit breaker trips and recovers.
+// Only connection failures count as failures: error replies prove that the server is up.
+type CircuitBreakerConfig struct {
+	// FailureRatio is the ratio of failed commands within Window that trips the breaker, 0.5 by default
+	FailureRatio float64
+	// MinCommands is the number of commands within Window below which the breaker never trips, 20 by default
+	MinCommands int
+	// Window is the period over which failures are counted, 10s by default
+	Window time.Duration
+	// OpenTimeout is how long the breaker stays open before probing the server, 5s by default
+	OpenTimeout time.Duration
+	// Probes is the number of concurrent commands let through while half-open, 1 by default.
+	// The breaker closes on the first successful probe and opens again on the first failed one.
+	Probes int
+	// OnStateChange, if set, is called on every state change
+	OnStateChange func(from CircuitState, to CircuitState)
+}
+
+// CircuitBreaker stops sending commands to a failing server for a while, so that callers fail fast
+// instead of piling up on the connection pool during an outage
+type CircuitBreaker struct {
+	config CircuitBreakerConfig
+	now    func() time.Time
+
+	mutex       sync.Mutex
+	state       CircuitState
+	windowStart time.Time
+	commands    int
+	failures    int
+	openedAt    time.Time
+	probes      int
+}
+
+// NewCircuitBreaker - Returns a closed circuit breaker
+func NewCircuitBreaker(config CircuitBreakerConfig) *CircuitBreaker {
+	if config.FailureRatio <= 0 {
+		config.FailureRatio = 0.5
+	}
+	if config.MinCommands <= 0 {
+		config.MinCommands = 20
+	}
+	if config.Window <= 0 {
+		config.Window = 10 * time.Second
+	}
+	if config.OpenTimeout <= 0 {
+		config.OpenTimeout = 5 * time.Second
+	}
+	if config.Probes <= 0 {
+		config.Probes = 1
+	}
+	return &CircuitBreaker{config: config, now: time.Now}
+}
+
+// WithCircuitBreaker sends the commands of the client through breaker
+func WithCircuitBreaker(breaker *CircuitBreaker) ClientOption {
+	return func(client *Client) {
+		client.Pool = &breakerPool{ConnPool: client.Pool, breaker: breaker}
+	}
+}
+
+// State - Returns the current state of the breaker
+func (b *CircuitBreaker) State() CircuitState {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.refresh()
+	return b.state
+}
+
+// refresh moves an open breaker whose timeout elapsed to half-open. The mutex must be held.
+func (b *CircuitBreaker) refresh() {
+	if b.state == CircuitOpen && b.now().Sub(b.openedAt) >= b.config.OpenTimeout {
+		b.setState(CircuitHalfOpen)
+	}
+}
+
+// setState changes the state and resets the counters. The mutex must be held.
+func (b *CircuitBreaker) setState(state CircuitState) {
+	from := b.state
+	b.state = state
+	b.commands, b.failures, b.probes = 0, 0, 0
+	b.windowStart = b.now()
+	if state == CircuitOpen {
+		b.openedAt = b.now()
+	}
+	if b.config.OnStateChange != nil && from != state {
+		b.config.OnStateChange(from, state)
+	}
+}
+
+// allow reports whether a command may be sent, and whether it is a probe
+func (b *CircuitBreaker) allow() (bool, bool) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.refresh()
+	switch b.state {
+	case CircuitOpen:
+		return false, false
+	case CircuitHalfOpen:
+		if b.probes >= b.config.Probes {
+			return false, false
+		}
+		b.probes++
+		return true, true
+	}
+	return true, false
+}
+
+// record accounts for the outcome of a command let through by allow
+func (b *CircuitBreaker) record(probe bool, err error) {
+	failed := err != nil && err != redis.ErrNil
+	if _, ok := err.(redis.Error); ok {
+		failed = false
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if probe {
+		if b.state != CircuitHalfOpen {
+			return
+		}
+		if failed {
+			b.setState(CircuitOpen)
+		} else {
+			b.setState(CircuitClosed)
+		}
+		return
+	}
+	if b.state != CircuitClosed {
+		return
+	}
+	if b.now().Sub(b.windowStart) >= b.config.Window {
+		b.windowStart = b.now()
+		b.commands, b.failures = 0, 0
+	}
+	b.commands++
+	if failed {
+		b.failures++
+	}
+	if b.commands >= b.config.MinCommands && float64(b.failures) >= b.config.FailureRatio*float64(b.commands) {
+		b.setState(CircuitOpen)
+	}
+}
+
+// breakerPool gates the connections of a pool with a circuit breaker
+type breakerPool struct {
+	ConnPool
+	breaker *CircuitBreaker
+}
+
+func (p *breakerPool) unwrap() ConnPool {
+	return p.ConnPool
+}
+
+// Get returns a connection failing fast, without borrowing one from the pool, while the breaker is open
+func (p *breakerPool) Get() redis.Conn {
+	if p.breaker.State() == CircuitOpen {
+		return errorConn{err: ErrCircuitOpen}
+	}
+	return &breakerConn{Conn: p.ConnPool.Get(), breaker: p.breaker}
+}
+
+// breakerConn passes the commands of a connection through a circuit breaker.
+// Pipelined commands are accounted for when their reply is received.
+type breakerConn struct {
+	redis.Conn
+	breaker *CircuitBreaker
+	pending []bool
+}
+
+func (c *breakerConn) Do(command string, args ...interface{}) (interface{}, error) {
+	return c.do(command, doCall(command, args))
+}
+
+func (c *breakerConn) DoWithTimeout(timeout time.Duration, command string, args ...interface{}) (interface{}, error) {
+	return c.do(command, doWithTimeoutCall(timeout, command, args))
+}
+
+func (c *breakerConn) do(command string, call connCall) (interface{}, error) {
+	if command == "" {
+		reply, err := call(c.Conn)
+		c.recordPending(err)
+		return reply, err
+	}
+	allowed, probe := c.breaker.allow()
+	if !allowed {
+		return nil, ErrCircuitOpen
+	}
+	reply, err := call(c.Conn)
+	c.recordPending(err)
+	c.breaker.record(probe, err)
+	return reply, err
+}
+
+// recordPending accounts for the pending pipelined commands whose replies were consumed by Do
+func (c *breakerConn) recordPending(err error) {
+	if _, ok := err.(redis.Error); ok {
+		err = nil
+	}
+	for _, probe := range c.pending {
+		c.breaker.record(probe, err)
+	}
+	c.pending = nil
+}
+
+func (c *breakerConn) Send(command string, args ...interface{}) error {
+	allowed, probe := c.breaker.allow()
+	if !allowed {
+		return ErrCircuitOpen
+	}
+	if err := c.Conn.Send(command, args...); err != nil {
+		c.breaker.record(probe, err)
+		return err
+	}
+	c.pending = append(c.pending, probe)
+	return nil
+}
+
+func (c *breakerConn) Receive() (interface{}, error) {
+	return c.receive(receiveCall)
+}
+
+func (c *breakerConn) ReceiveWithTimeout(timeout time.Duration) (interface{}, error) {
+	return c.receive(receiveWithTimeoutCall(timeout))
+}
+
+func (c *breakerConn) receive(call connCall) (interface{}, error) {
+	reply, err := call(c.Conn)
+	if len(c.pending) > 0 {
+		probe := c.pending[0]
+		c.pending = c.pending[1:]
+		c.breaker.record(probe, err)
+	}
+	return reply, err
+}
+
+// errorConn is a connection whose every operation fails with err
+type errorConn struct {
+	err error
+}
+
+func (c errorConn) Do(string, ...interface{}) (interface{}, error) { return nil, c.err }
+func (c errorConn) Send(string, ...interface{}) error              { return c.err }
+func (c errorConn) Err() error                                     { return c.err }
+func (c errorConn) Close() error                                   { return nil }
+func (c errorConn) Flush() error                                   { return c.err }
+func (c errorConn) Receive() (interface{}, error)                  { return nil, c.err }
+
+func (c errorConn) DoWithTimeout(time.Duration, string, ...interface{}) (interface{}, error) {
+	return nil, c.err
+}
+
+func (c errorConn) ReceiveWithTimeout(time.Duration) (interface{}, error) {
+	return nil, c.err
+}
@@ -0,0 +1,69 @@
+package redis_bloom_go
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Unix(0, 0)
+	var changes []CircuitState
+	b := NewCircuitBreaker(CircuitBreakerConfig{MinCommands: 4, OnStateChange: func(from CircuitState, to CircuitState) {
+		changes = append(changes, to)
+	}})
+	b.now = func() time.Time { return now }
+
+	// error replies do not count as failures
+	for i := 0; i < 4; i++ {
+		allowed, probe := b.allow()
+		assert.True(t, allowed)
+		assert.False(t, probe)
+		b.record(probe, redis.Error("ERR wrong type"))
+	}
+	assert.Equal(t, CircuitClosed, b.State())
+
+	for i := 0; i < 4; i++ {
+		b.allow()
+		b.record(false, io.EOF)
+	}
+	assert.Equal(t, CircuitOpen, b.State())
+	allowed, _ := b.allow()
+	assert.False(t, allowed)
+
+	now = now.Add(5 * time.Second)
+	assert.Equal(t, CircuitHalfOpen, b.State())
+	allowed, probe := b.allow()
+	assert.True(t, allowed)
+	assert.True(t, probe)
+	allowed, _ = b.allow()
+	assert.False(t, allowed)
+	b.record(true, io.EOF)
+	assert.Equal(t, CircuitOpen, b.State())
+
+	now = now.Add(5 * time.Second)
+	allowed, probe = b.allow()
+	assert.True(t, allowed)
+	b.record(probe, nil)
+	assert.Equal(t, CircuitClosed, b.State())
+	assert.Equal(t, []CircuitState{CircuitOpen, CircuitHalfOpen, CircuitOpen, CircuitHalfOpen, CircuitClosed}, changes)
+}
+
+func TestWithCircuitBreaker(t *testing.T) {
+	conn := &fakeConn{replies: []interface{}{io.EOF, io.EOF}}
+	c := NewClientFromPool(nil, "test")
+	c.Pool = &fakePool{conn: conn}
+	breaker := NewCircuitBreaker(CircuitBreakerConfig{MinCommands: 2})
+	WithCircuitBreaker(breaker)(c)
+
+	_, err := c.Exists("key", "item")
+	assert.Equal(t, io.EOF, err)
+	_, err = c.Exists("key", "item")
+	assert.Equal(t, io.EOF, err)
+	_, err = c.Exists("key", "item")
+	assert.Equal(t, ErrCircuitOpen, err)
+	assert.Equal(t, []string{"BF.EXISTS", "BF.EXISTS"}, conn.commands)
+}
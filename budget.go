@@ -0,0 +1,107 @@
+package redis_bloom_go
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// topkBucketBytes is the size of a top-k counter and its fingerprint
+const topkBucketBytes = 8
+
+// ErrBudgetExceeded is the error a *BudgetExceededError unwraps to
+var ErrBudgetExceeded = errors.New("redisbloom: memory budget exceeded")
+
+// BudgetExceededError is returned when creating a data structure whose estimated size exceeds
+// the memory budget set with WithMemoryBudget. The command is not sent to the server.
+type BudgetExceededError struct {
+	Key string
+	// Prefix is the key or key prefix the budget was set for
+	Prefix string
+	// Estimated is the estimated size of the data structure, in bytes
+	Estimated int64
+	// Budget is the maximum size allowed, in bytes
+	Budget int64
+}
+
+func (e *BudgetExceededError) Error() string {
+	return fmt.Sprintf("redisbloom: creating %s needs an estimated %d bytes, over the memory budget of %d bytes for %q; "+
+		"use WithoutMemoryBudget to override", e.Key, e.Estimated, e.Budget, e.Prefix)
+}
+
+// Unwrap returns ErrBudgetExceeded
+func (e *BudgetExceededError) Unwrap() error {
+	return ErrBudgetExceeded
+}
+
+// WithMemoryBudget rejects the creation of data structures whose estimated size exceeds maxBytes when their key
+// starts with prefix, a key or key prefix relative to WithKeyPrefix, or any key for an empty prefix. It can be
+// given several times, the longest matching prefix applying. Reserve, ReserveWithTTL, EnsureBfReserved,
+// CfReserve, CfReserveWithOptions, CfReserveWithTTL, EnsureCfReserved, CmsInitByDim, CmsInitByProb and
+// TopkReserve return a *BudgetExceededError instead of sending their command. Sizes are estimated from the
+// parameters of the data structure, the initial sub-filter for scalable filters.
+func WithMemoryBudget(prefix string, maxBytes int64) ClientOption {
+	return func(client *Client) {
+		budgets := make(map[string]int64, len(client.memoryBudgets)+1)
+		for p, b := range client.memoryBudgets {
+			budgets[p] = b
+		}
+		budgets[prefix] = maxBytes
+		client.memoryBudgets = budgets
+	}
+}
+
+// WithoutMemoryBudget - Returns a view of the client which ignores the memory budgets set with WithMemoryBudget,
+// to deliberately create a data structure over budget. The view shares the connection pool of the client.
+func (client *Client) WithoutMemoryBudget() *Client {
+	unbounded := *client
+	unbounded.memoryBudgets = nil
+	return &unbounded
+}
+
+// checkBudget returns a *BudgetExceededError if estimated exceeds the memory budget of key
+func (client *Client) checkBudget(key string, estimated int64) error {
+	prefix, budget, found := "", int64(0), false
+	for p, b := range client.memoryBudgets {
+		if strings.HasPrefix(key, p) && (!found || len(p) > len(prefix)) {
+			prefix, budget, found = p, b, true
+		}
+	}
+	if !found || estimated <= budget {
+		return nil
+	}
+	return &BudgetExceededError{Key: key, Prefix: prefix, Estimated: estimated, Budget: budget}
+}
+
+// checkBloomBudget checks the estimated size of a bloom filter against the memory budget of key
+func (client *Client) checkBloomBudget(key string, errorRate float64, capacity uint64) error {
+	plan, err := PlanBloom(capacity, errorRate)
+	if client.memoryBudgets == nil || err != nil {
+		// invalid parameters are left for the server to reject
+		return nil
+	}
+	return client.checkBudget(key, int64(plan.MemoryBytes))
+}
+
+// checkCuckooBudget checks the estimated size of a cuckoo filter against the memory budget of key,
+// a byte per fingerprint
+func (client *Client) checkCuckooBudget(key string, capacity int64, opts []CallOption) error {
+	if client.memoryBudgets == nil {
+		return nil
+	}
+	bucketSize := int64(defaultCuckooBucketSize)
+	if o := newCallOptions(opts); o.bucketSize != nil && *o.bucketSize > 0 {
+		bucketSize = *o.bucketSize
+	}
+	return client.checkBudget(key, cuckooBuckets(capacity, bucketSize)*bucketSize)
+}
+
+// cuckooBuckets returns the number of buckets of the first sub-filter of a cuckoo filter, which CF.RESERVE
+// rounds up to a power of 2
+func cuckooBuckets(capacity int64, bucketSize int64) int64 {
+	buckets := int64(1)
+	for buckets < capacity/bucketSize {
+		buckets <<= 1
+	}
+	return buckets
+}
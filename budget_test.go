@@ -0,0 +1,40 @@
+package redis_bloom_go
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_MemoryBudget(t *testing.T) {
+	conn := &fakeConn{replies: []interface{}{"OK", "OK", "OK"}}
+	c := NewClientFromPool(nil, "test", WithMemoryBudget("", 1<<20), WithMemoryBudget("big:", 1<<30))
+	c.Pool = &fakePool{conn: conn}
+
+	// 10M items at 1% take about 12MB
+	err := c.Reserve("filter", 0.01, 10000000)
+	exceeded, ok := err.(*BudgetExceededError)
+	assert.True(t, ok)
+	assert.Equal(t, "filter", exceeded.Key)
+	assert.Equal(t, "", exceeded.Prefix)
+	assert.Equal(t, int64(1<<20), exceeded.Budget)
+	assert.Equal(t, int64(11981323), exceeded.Estimated)
+	assert.True(t, errors.Is(err, ErrBudgetExceeded))
+
+	_, err = c.CmsInitByDim("sketch", 1000000, 10)
+	assert.IsType(t, &BudgetExceededError{}, err)
+	_, err = c.TopkReserve("top", 10, 100000, 10, 0.9)
+	assert.IsType(t, &BudgetExceededError{}, err)
+	// 4M buckets of 2 fingerprints
+	_, err = c.CfReserveWithOptions("cuckoo", 8000000)
+	assert.IsType(t, &BudgetExceededError{}, err)
+	assert.Empty(t, conn.commands)
+
+	// the longest prefix applies
+	assert.Nil(t, c.Reserve("big:filter", 0.01, 10000000))
+	assert.Nil(t, c.WithoutMemoryBudget().Reserve("filter", 0.01, 10000000))
+	_, err = c.CmsInitByDim("sketch", 1000, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"BF.RESERVE", "BF.RESERVE", "CMS.INITBYDIM"}, conn.commands)
+}
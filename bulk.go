@@ -0,0 +1,179 @@
+package redis_bloom_go
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// BulkOptions tunes BulkLoad
+type BulkOptions struct {
+	// Workers is the number of connections loading in parallel, 4 by default
+	Workers int
+	// BatchSize is the number of items of each BF.MADD, 1000 by default
+	BatchSize int
+	// Pipeline is the number of BF.MADD each worker sends per round trip, 8 by default
+	Pipeline int
+	// Progress, if set, is called after each round trip with the total number of items sent so far.
+	// It is called from the worker goroutines and must be safe for concurrent use.
+	Progress func(sent int64)
+}
+
+const (
+	defaultBulkWorkers   = 4
+	defaultBulkBatchSize = 1000
+	defaultBulkPipeline  = 8
+	// maxBulkLoadErrors is the number of errors a BulkLoadError keeps, the others being only counted
+	maxBulkLoadErrors = 16
+)
+
+// BulkLoadError gathers the errors of the round trips that failed during a BulkLoad
+type BulkLoadError struct {
+	// Errors are the first errors, up to 16
+	Errors []error
+	// Omitted is the number of errors left out of Errors
+	Omitted int
+}
+
+func (e *BulkLoadError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+	if e.Omitted > 0 {
+		messages = append(messages, fmt.Sprintf("%d more", e.Omitted))
+	}
+	return fmt.Sprintf("redisbloom: %d bulk load errors: %s", len(e.Errors)+e.Omitted, strings.Join(messages, "; "))
+}
+
+// add keeps err, or only counts it once enough errors are kept
+func (e *BulkLoadError) add(err error) {
+	if len(e.Errors) < maxBulkLoadErrors {
+		e.Errors = append(e.Errors, err)
+	} else {
+		e.Omitted++
+	}
+}
+
+// BulkLoad - Adds every item received from items to the bloom filter stored at key, until items is closed.
+// Items are sent as pipelined BF.MADD batches over several connections in parallel, so it is much faster
+// than adding them one by one. Failed round trips do not stop the load: their errors are returned together
+// as a *BulkLoadError. A worker whose connection fails carries on with a new one. Returns the number of items newly added to the filter.
+// Reserve the filter beforehand to control its capacity and error rate.
+func (client *Client) BulkLoad(key string, items <-chan string, opts BulkOptions) (int64, error) {
+	if opts.Workers <= 0 {
+		opts.Workers = defaultBulkWorkers
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaultBulkBatchSize
+	}
+	if opts.Pipeline <= 0 {
+		opts.Pipeline = defaultBulkPipeline
+	}
+	var added, sent int64
+	var mutex sync.Mutex
+	var errs BulkLoadError
+	var wg sync.WaitGroup
+	for i := 0; i < opts.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			conn := client.Pool.Get()
+			defer func() { conn.Close() }()
+			for {
+				batches := readBulkBatches(items, opts.BatchSize, opts.Pipeline)
+				if len(batches) == 0 {
+					return
+				}
+				n, err := client.sendBulkBatches(conn, key, batches)
+				atomic.AddInt64(&added, n)
+				if err != nil {
+					mutex.Lock()
+					errs.add(err)
+					mutex.Unlock()
+					if _, ok := err.(redis.Error); !ok {
+						// the connection may be broken or out of step with its replies
+						conn.Close()
+						conn = client.Pool.Get()
+					}
+				}
+				total := atomic.AddInt64(&sent, bulkBatchesLen(batches))
+				if opts.Progress != nil {
+					opts.Progress(total)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if client.writeTTL > 0 {
+		conn := client.Pool.Get()
+		defer conn.Close()
+		if _, err := conn.Do("PEXPIRE", client.key(key), int64(client.writeTTL/time.Millisecond)); err != nil {
+			errs.add(err)
+		}
+	}
+	if len(errs.Errors) > 0 {
+		return added, &errs
+	}
+	return added, nil
+}
+
+// readBulkBatches reads up to pipeline batches of up to batchSize items, returning fewer once items is closed
+func readBulkBatches(items <-chan string, batchSize int, pipeline int) [][]string {
+	var batches [][]string
+	for len(batches) < pipeline {
+		batch := make([]string, 0, batchSize)
+		for len(batch) < batchSize {
+			item, ok := <-items
+			if !ok {
+				if len(batch) > 0 {
+					batches = append(batches, batch)
+				}
+				return batches
+			}
+			batch = append(batch, item)
+		}
+		batches = append(batches, batch)
+	}
+	return batches
+}
+
+func bulkBatchesLen(batches [][]string) int64 {
+	var n int64
+	for _, batch := range batches {
+		n += int64(len(batch))
+	}
+	return n
+}
+
+// sendBulkBatches pipelines one BF.MADD per batch and returns the number of items newly added
+func (client *Client) sendBulkBatches(conn redis.Conn, key string, batches [][]string) (int64, error) {
+	for _, batch := range batches {
+		if err := conn.Send("BF.MADD", redis.Args{client.key(key)}.AddFlat(batch)...); err != nil {
+			return 0, err
+		}
+	}
+	if err := conn.Flush(); err != nil {
+		return 0, err
+	}
+	var added int64
+	var outErr error
+	// drain every reply, even after a failure, so the connection can be reused
+	for range batches {
+		values, err := redis.Int64s(conn.Receive())
+		if err != nil {
+			if outErr == nil {
+				outErr = err
+			}
+			continue
+		}
+		for _, value := range values {
+			added += value
+		}
+	}
+	return added, outErr
+}
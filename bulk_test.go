@@ -0,0 +1,107 @@
+package redis_bloom_go
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+// sequencePool hands out its connections in turn, counting the connections taken
+type sequencePool struct {
+	conns []redis.Conn
+	gets  int
+}
+
+func (p *sequencePool) Get() redis.Conn {
+	conn := p.conns[p.gets]
+	p.gets++
+	return conn
+}
+
+func (p *sequencePool) Close() error { return nil }
+
+func TestReadBulkBatches(t *testing.T) {
+	items := make(chan string, 10)
+	for i := 0; i < 7; i++ {
+		items <- fmt.Sprint(i)
+	}
+	close(items)
+	assert.Equal(t, [][]string{{"0", "1", "2"}, {"3", "4", "5"}}, readBulkBatches(items, 3, 2))
+	assert.Equal(t, [][]string{{"6"}}, readBulkBatches(items, 3, 2))
+	assert.Nil(t, readBulkBatches(items, 3, 2))
+}
+
+func TestBulkLoadError(t *testing.T) {
+	err := &BulkLoadError{Errors: []error{errors.New("a"), errors.New("b")}}
+	assert.Equal(t, "redisbloom: 2 bulk load errors: a; b", err.Error())
+	err.Omitted = 3
+	assert.Equal(t, "redisbloom: 5 bulk load errors: a; b; 3 more", err.Error())
+}
+
+func TestClient_BulkLoad_Errors(t *testing.T) {
+	broken := &fakeConn{replies: []interface{}{errors.New("EOF")}}
+	var replies []interface{}
+	for i := 0; i < 20; i++ {
+		replies = append(replies, redis.Error("WRONGTYPE Operation against a key holding the wrong kind of value"))
+	}
+	replies = append(replies, []interface{}{int64(1)})
+	fresh := &fakeConn{replies: replies}
+	pool := &sequencePool{conns: []redis.Conn{broken, fresh}}
+	c := NewClientFromPool(nil, "test")
+	c.Pool = pool
+	items := make(chan string, 22)
+	for i := 0; i < 22; i++ {
+		items <- fmt.Sprint(i)
+	}
+	close(items)
+	added, err := c.BulkLoad("bf", items, BulkOptions{Workers: 1, BatchSize: 1, Pipeline: 1})
+	assert.Equal(t, int64(1), added)
+	// the broken connection is replaced, while the server errors keep the connection
+	assert.Equal(t, 2, pool.gets)
+	assert.Len(t, fresh.commands, 21)
+	bulkErr, ok := err.(*BulkLoadError)
+	assert.True(t, ok)
+	assert.Len(t, bulkErr.Errors, maxBulkLoadErrors)
+	assert.Equal(t, 21-maxBulkLoadErrors, bulkErr.Omitted)
+	assert.EqualError(t, bulkErr.Errors[0], "EOF")
+}
+
+func TestClient_BulkLoad(t *testing.T) {
+	client.FlushAll()
+	key := "test_bulk_load"
+	assert.Nil(t, client.Reserve(key, 0.001, 10000))
+	items := make(chan string)
+	go func() {
+		for i := 0; i < 5000; i++ {
+			items <- fmt.Sprintf("item-%d", i)
+		}
+		close(items)
+	}()
+	var progress int64
+	added, err := client.BulkLoad(key, items, BulkOptions{Workers: 3, BatchSize: 100, Pipeline: 4, Progress: func(sent int64) {
+		// workers report concurrently, so keep the highest total seen
+		for {
+			last := atomic.LoadInt64(&progress)
+			if sent <= last || atomic.CompareAndSwapInt64(&progress, last, sent) {
+				return
+			}
+		}
+	}})
+	assert.Nil(t, err)
+	assert.True(t, added > 4990)
+	assert.Equal(t, int64(5000), atomic.LoadInt64(&progress))
+	exists, err := client.Exists(key, "item-4999")
+	assert.Nil(t, err)
+	assert.True(t, exists)
+
+	client.CfReserve("test_bulk_load_cuckoo", 1000, 0, 0, 0)
+	items = make(chan string, 1)
+	items <- "a"
+	close(items)
+	_, err = client.BulkLoad("test_bulk_load_cuckoo", items, BulkOptions{})
+	assert.IsType(t, &BulkLoadError{}, err)
+}
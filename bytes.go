@@ -0,0 +1,84 @@
+package redis_bloom_go
+
+import (
+	"github.com/gomodule/redigo/redis"
+)
+
+// bytesArgs returns items as command arguments, sent as is without string conversion
+func bytesArgs(items [][]byte) redis.Args {
+	args := make(redis.Args, len(items))
+	for i, item := range items {
+		args[i] = item
+	}
+	return args
+}
+
+// AddBytes - Same as Add, for a binary item
+func (client *Client) AddBytes(key string, item []byte) (bool, error) {
+	conn := client.Pool.Get()
+	defer conn.Close()
+	return redis.Bool(client.doWithTTL(conn, key, client.writeTTL, "BF.ADD", client.key(key), item))
+}
+
+// ExistsBytes - Same as Exists, for a binary item
+func (client *Client) ExistsBytes(key string, item []byte) (bool, error) {
+	if client.existsCache != nil || client.flights != nil {
+		return client.Exists(key, string(item))
+	}
+	conn := client.Pool.Get()
+	defer conn.Close()
+	return redis.Bool(conn.Do("BF.EXISTS", client.key(key), item))
+}
+
+// BfAddMultiBytes - Same as BfAddMulti, for binary items
+func (client *Client) BfAddMultiBytes(key string, items [][]byte) ([]int64, error) {
+	return client.batchedInt64s("BF.MADD", key, bytesArgs(items), true)
+}
+
+// BfExistsMultiBytes - Same as BfExistsMulti, for binary items
+func (client *Client) BfExistsMultiBytes(key string, items [][]byte) ([]int64, error) {
+	return client.batchedInt64s("BF.MEXISTS", key, bytesArgs(items), false)
+}
+
+// CfAddBytes - Same as CfAdd, for a binary item
+func (client *Client) CfAddBytes(key string, item []byte) (bool, error) {
+	conn := client.Pool.Get()
+	defer conn.Close()
+	return redis.Bool(client.doWithTTL(conn, key, client.writeTTL, "CF.ADD", client.key(key), item))
+}
+
+// CfAddNxBytes - Same as CfAddNx, for a binary item
+func (client *Client) CfAddNxBytes(key string, item []byte) (bool, error) {
+	conn := client.Pool.Get()
+	defer conn.Close()
+	return redis.Bool(client.doWithTTL(conn, key, client.writeTTL, "CF.ADDNX", client.key(key), item))
+}
+
+// CfExistsBytes - Same as CfExists, for a binary item
+func (client *Client) CfExistsBytes(key string, item []byte) (bool, error) {
+	if client.flights != nil {
+		return client.CfExists(key, string(item))
+	}
+	conn := client.Pool.Get()
+	defer conn.Close()
+	return redis.Bool(conn.Do("CF.EXISTS", client.key(key), item))
+}
+
+// CfDelBytes - Same as CfDel, for a binary item
+func (client *Client) CfDelBytes(key string, item []byte) (bool, error) {
+	conn := client.Pool.Get()
+	defer conn.Close()
+	return redis.Bool(conn.Do("CF.DEL", client.key(key), item))
+}
+
+// CfCountBytes - Same as CfCount, for a binary item
+func (client *Client) CfCountBytes(key string, item []byte) (int64, error) {
+	conn := client.Pool.Get()
+	defer conn.Close()
+	return redis.Int64(conn.Do("CF.COUNT", client.key(key), item))
+}
+
+// CmsQueryBytes - Same as CmsQuery, for binary items
+func (client *Client) CmsQueryBytes(key string, items [][]byte) ([]int64, error) {
+	return client.batchedInt64s("CMS.QUERY", key, bytesArgs(items), false)
+}
@@ -0,0 +1,56 @@
+package redis_bloom_go
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBytesArgs(t *testing.T) {
+	args := bytesArgs([][]byte{{0xff}, {}})
+	assert.Equal(t, 2, len(args))
+	assert.Equal(t, []byte{0xff}, args[0])
+}
+
+func TestClient_BytesItems(t *testing.T) {
+	client.FlushAll()
+	invalid := []byte{0xff, 0xfe, 0x00}
+
+	added, err := client.AddBytes("test_bytes_bloom", invalid)
+	assert.Nil(t, err)
+	assert.True(t, added)
+	exists, err := client.Exists("test_bytes_bloom", string(invalid))
+	assert.Nil(t, err)
+	assert.True(t, exists)
+	results, err := client.BfAddMultiBytes("test_bytes_bloom", [][]byte{invalid, {0x01}})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{0, 1}, results)
+	results, err = client.BfExistsMultiBytes("test_bytes_bloom", [][]byte{{0x01}, {0x02}})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1, 0}, results)
+	exists, err = client.ExistsBytes("test_bytes_bloom", []byte{0x02})
+	assert.Nil(t, err)
+	assert.False(t, exists)
+
+	added, err = client.CfAddNxBytes("test_bytes_cuckoo", invalid)
+	assert.Nil(t, err)
+	assert.True(t, added)
+	added, err = client.CfAddBytes("test_bytes_cuckoo", invalid)
+	assert.Nil(t, err)
+	assert.True(t, added)
+	count, err := client.CfCountBytes("test_bytes_cuckoo", invalid)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), count)
+	deleted, err := client.CfDelBytes("test_bytes_cuckoo", invalid)
+	assert.Nil(t, err)
+	assert.True(t, deleted)
+	exists, err = client.CfExistsBytes("test_bytes_cuckoo", invalid)
+	assert.Nil(t, err)
+	assert.True(t, exists)
+
+	client.CmsInitByDim("test_bytes_cms", 1000, 5)
+	client.CmsIncrByItems("test_bytes_cms", []CmsIncrement{{string(invalid), 3}})
+	counts, err := client.CmsQueryBytes("test_bytes_cms", [][]byte{invalid, {0x01}})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{3, 0}, counts)
+}
@@ -0,0 +1,87 @@
+package redis_bloom_go
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// positiveCache is a bounded LRU set of (key, item) pairs known to be present in a bloom filter.
+// Bloom filters never forget an item, so a positive answer stays valid until the key is deleted.
+type positiveCache struct {
+	mutex   sync.Mutex
+	size    int
+	ttl     time.Duration
+	entries map[positiveCacheKey]*list.Element
+	lru     *list.List
+	now     func() time.Time
+}
+
+type positiveCacheKey struct {
+	key  string
+	item string
+}
+
+type positiveCacheEntry struct {
+	id      positiveCacheKey
+	expires time.Time
+}
+
+func newPositiveCache(size int, ttl time.Duration) *positiveCache {
+	return &positiveCache{
+		size:    size,
+		ttl:     ttl,
+		entries: make(map[positiveCacheKey]*list.Element, size),
+		lru:     list.New(),
+		now:     time.Now,
+	}
+}
+
+// contains reports whether item was recorded for key and has not expired
+func (c *positiveCache) contains(key string, item string) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	element, ok := c.entries[positiveCacheKey{key, item}]
+	if !ok {
+		return false
+	}
+	entry := element.Value.(*positiveCacheEntry)
+	if c.ttl > 0 && c.now().After(entry.expires) {
+		c.lru.Remove(element)
+		delete(c.entries, entry.id)
+		return false
+	}
+	c.lru.MoveToFront(element)
+	return true
+}
+
+// add records item as present in key, evicting the least recently used entry when full
+func (c *positiveCache) add(key string, item string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	id := positiveCacheKey{key, item}
+	expires := c.now().Add(c.ttl)
+	if element, ok := c.entries[id]; ok {
+		element.Value.(*positiveCacheEntry).expires = expires
+		c.lru.MoveToFront(element)
+		return
+	}
+	c.entries[id] = c.lru.PushFront(&positiveCacheEntry{id: id, expires: expires})
+	for c.lru.Len() > c.size {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*positiveCacheEntry).id)
+	}
+}
+
+// forget drops every item recorded for key
+func (c *positiveCache) forget(key string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for id, element := range c.entries {
+		if id.key == key {
+			c.lru.Remove(element)
+			delete(c.entries, id)
+		}
+	}
+}
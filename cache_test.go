@@ -0,0 +1,65 @@
+package redis_bloom_go
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPositiveCache(t *testing.T) {
+	now := time.Unix(0, 0)
+	c := newPositiveCache(2, time.Minute)
+	c.now = func() time.Time { return now }
+
+	assert.False(t, c.contains("k", "a"))
+	c.add("k", "a")
+	c.add("k", "b")
+	assert.True(t, c.contains("k", "a"))
+	assert.False(t, c.contains("other", "a"))
+
+	// b is the least recently used entry
+	c.add("k", "c")
+	assert.False(t, c.contains("k", "b"))
+	assert.True(t, c.contains("k", "a"))
+	assert.True(t, c.contains("k", "c"))
+
+	now = now.Add(2 * time.Minute)
+	assert.False(t, c.contains("k", "a"))
+
+	c.add("k", "a")
+	c.add("j", "a")
+	c.forget("k")
+	assert.False(t, c.contains("k", "a"))
+	assert.True(t, c.contains("j", "a"))
+}
+
+func TestClient_ExistsCache(t *testing.T) {
+	client.FlushAll()
+	host, password := getTestConnectionDetails()
+	pool := &redis.Pool{Dial: func() (redis.Conn, error) {
+		return redis.Dial("tcp", host, redis.DialPassword(password))
+	}, MaxIdle: maxConns}
+	cached := NewClientFromPool(pool, "bloom-client-cache", WithExistsCache(100, time.Minute))
+	defer cached.Pool.Close()
+
+	cached.Add("test_exists_cache", "a")
+	exists, err := cached.Exists("test_exists_cache", "a")
+	assert.Nil(t, err)
+	assert.True(t, exists)
+	exists, err = cached.Exists("test_exists_cache", "b")
+	assert.Nil(t, err)
+	assert.False(t, exists)
+
+	// served from the cache even though the key vanished behind the client
+	client.DeleteFilter("test_exists_cache")
+	exists, err = cached.Exists("test_exists_cache", "a")
+	assert.Nil(t, err)
+	assert.True(t, exists)
+
+	cached.DeleteFilter("test_exists_cache")
+	exists, err = cached.Exists("test_exists_cache", "a")
+	assert.Nil(t, err)
+	assert.False(t, exists)
+}
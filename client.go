@@ -4,18 +4,42 @@ import (
 	"errors"
 	"fmt"
 	"github.com/gomodule/redigo/redis"
+	"math"
+	"net/url"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // TODO: refactor this hard limit and revise client locking
 // Client Max Connections
 var maxConns = 500
 
+// defaultTDigestCompression is the compression RedisBloom applies when none is given
+const defaultTDigestCompression = 100
+
+// ErrEmptySketch is returned by the t-digest commands which have no meaningful reply for a sketch without
+// observations, such as TdMin, TdMax and TdSummary
+var ErrEmptySketch = errors.New("redisbloom: t-digest sketch is empty")
+
+// ErrFilterFull is returned by CfInsertBool when the cuckoo filter had no room left for an item
+var ErrFilterFull = errors.New("redisbloom: cuckoo filter is full")
+
 // Client is an interface to RedisBloom redis commands
 type Client struct {
-	Pool ConnPool
-	Name string
+	Pool          ConnPool
+	Name          string
+	versionCache  *moduleVersionCache
+	tdigestSyntax TDigestSyntax
+	maxBatchSize  int
+	keyPrefix     string
+	writeTTL      time.Duration
+	existsCache   *positiveCache
+	flights       *flightGroup
+	// memoryBudgets are the maximum sizes of the data structures created under each key prefix
+	memoryBudgets map[string]int64
+	lifecycle     *clientLifecycle
+	connect       *connectChain
 }
 
 // TDigestInfo is a struct that represents T-Digest properties
@@ -27,6 +51,7 @@ type TDigestInfo struct {
 	mergedWeight      float64
 	unmergedWeight    float64
 	totalCompressions int64
+	extra             map[string]interface{}
 }
 
 // Compression - returns the compression of TDigestInfo instance
@@ -64,11 +89,17 @@ func (info *TDigestInfo) TotalCompressions() int64 {
 	return info.totalCompressions
 }
 
+// Extra - returns the fields of TDIGEST.INFO this client does not know about, e.g. added by a
+// newer module version, with bulk strings converted to strings. Nil when there are none.
+func (info *TDigestInfo) Extra() map[string]interface{} {
+	return info.extra
+}
+
 // NewClient creates a new client connecting to the redis host, and using the given name as key prefix.
 // Addr can be a single host:port pair, or a comma separated list of host:port,host:port...
 // In the case of multiple hosts we create a multi-pool and select connections at random
 // Deprecated: Please use NewClientFromPool() instead
-func NewClient(addr, name string, authPass *string) *Client {
+func NewClient(addr, name string, authPass *string, opts ...ClientOption) *Client {
 	addrs := strings.Split(addr, ",")
 	var pool ConnPool
 	if len(addrs) == 1 {
@@ -77,30 +108,76 @@ func NewClient(addr, name string, authPass *string) *Client {
 		pool = NewMultiHostPool(addrs, authPass)
 	}
 	ret := &Client{
-		Pool: pool,
-		Name: name,
+		Pool:         pool,
+		Name:         name,
+		versionCache: &moduleVersionCache{},
+		lifecycle:    newClientLifecycle(),
+	}
+	for _, opt := range opts {
+		opt(ret)
 	}
 	return ret
 }
 
 // NewClientFromPool creates a new Client with the given pool and client name
-func NewClientFromPool(pool *redis.Pool, name string) *Client {
+func NewClientFromPool(pool *redis.Pool, name string, opts ...ClientOption) *Client {
 	ret := &Client{
-		Pool: pool,
-		Name: name,
+		Pool:         pool,
+		Name:         name,
+		versionCache: &moduleVersionCache{},
+		lifecycle:    newClientLifecycle(),
+	}
+	for _, opt := range opts {
+		opt(ret)
 	}
 	return ret
 }
 
+// NewClientFromURL creates a new Client connecting to the server at rawurl, in the
+// redis://[user:password@]host[:port][/db] form, or rediss:// for TLS
+func NewClientFromURL(rawurl string, name string, opts ...ClientOption) (*Client, error) {
+	if _, err := url.Parse(rawurl); err != nil {
+		return nil, err
+	}
+	pool := &redis.Pool{
+		Dial: func() (redis.Conn, error) {
+			return redis.DialURL(rawurl)
+		},
+		TestOnBorrow: testOnBorrow,
+		MaxIdle:      maxConns,
+	}
+	return NewClientFromPool(pool, name, opts...), nil
+}
+
+// key returns the name under which key is stored on the server
+func (client *Client) key(key string) string {
+	return client.keyPrefix + key
+}
+
+// keys returns the names under which keys are stored on the server
+func (client *Client) keys(keys []string) []string {
+	if client.keyPrefix == "" {
+		return keys
+	}
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = client.key(key)
+	}
+	return prefixed
+}
+
 // Reserve - Creates an empty Bloom Filter with a given desired error ratio and initial capacity.
 // args:
 // key - the name of the filter
 // error_rate - the desired probability for false positives
 // capacity - the number of entries you intend to add to the filter
 func (client *Client) Reserve(key string, error_rate float64, capacity uint64) (err error) {
+	if err = client.checkBloomBudget(key, error_rate, capacity); err != nil {
+		return err
+	}
 	conn := client.Pool.Get()
 	defer conn.Close()
-	_, err = conn.Do("BF.RESERVE", key, strconv.FormatFloat(error_rate, 'g', 16, 64), capacity)
+	_, err = conn.Do("BF.RESERVE", client.key(key), strconv.FormatFloat(error_rate, 'g', 16, 64), capacity)
 	return err
 }
 
@@ -111,7 +188,7 @@ func (client *Client) Reserve(key string, error_rate float64, capacity uint64) (
 func (client *Client) Add(key string, item string) (exists bool, err error) {
 	conn := client.Pool.Get()
 	defer conn.Close()
-	return redis.Bool(conn.Do("BF.ADD", key, item))
+	return redis.Bool(client.doWithTTL(conn, key, client.writeTTL, "BF.ADD", client.key(key), item))
 }
 
 // Exists - Determines whether an item may exist in the Bloom Filter or not.
@@ -119,41 +196,28 @@ func (client *Client) Add(key string, item string) (exists bool, err error) {
 // key - the name of the filter
 // item - the item to check for
 func (client *Client) Exists(key string, item string) (exists bool, err error) {
-	conn := client.Pool.Get()
-	defer conn.Close()
-	return redis.Bool(conn.Do("BF.EXISTS", key, item))
+	if client.existsCache != nil && client.existsCache.contains(client.key(key), item) {
+		return true, nil
+	}
+	exists, err = client.coalesceBool("BF.EXISTS", client.key(key), item, func() (bool, error) {
+		conn := client.Pool.Get()
+		defer conn.Close()
+		return redis.Bool(conn.Do("BF.EXISTS", client.key(key), item))
+	})
+	if exists && client.existsCache != nil {
+		client.existsCache.add(client.key(key), item)
+	}
+	return exists, err
 }
 
 // Info - Return information about key
+// Fields which are not integers, e.g. added by a newer module version, are left out: use BfInfoTyped to get them.
 // args:
 // key - the name of the filter
 func (client *Client) Info(key string) (info map[string]int64, err error) {
 	conn := client.Pool.Get()
 	defer conn.Close()
-	result, err := conn.Do("BF.INFO", key)
-	if err != nil {
-		return nil, err
-	}
-
-	values, err := redis.Values(result, nil)
-	if err != nil {
-		return nil, err
-	}
-	if len(values)%2 != 0 {
-		return nil, errors.New("Info expects even number of values result")
-	}
-	info = map[string]int64{}
-	for i := 0; i < len(values); i += 2 {
-		key, err = redis.String(values[i], nil)
-		if err != nil {
-			return nil, err
-		}
-		info[key], err = redis.Int64(values[i+1], nil)
-		if err != nil {
-			return nil, err
-		}
-	}
-	return info, nil
+	return ParseInfoReply(redis.Values(conn.Do("BF.INFO", client.key(key))))
 }
 
 // BfAddMulti - Adds one or more items to the Bloom Filter, creating the filter if it does not yet exist.
@@ -161,11 +225,9 @@ func (client *Client) Info(key string) (info map[string]int64, err error) {
 // key - the name of the filter
 // item - One or more items to add
 func (client *Client) BfAddMulti(key string, items []string) ([]int64, error) {
-	conn := client.Pool.Get()
-	defer conn.Close()
-	args := redis.Args{key}.AddFlat(items)
-	result, err := conn.Do("BF.MADD", args...)
-	return redis.Int64s(result, err)
+	args := stringArgs(items)
+	defer args.release()
+	return client.batchedInt64s("BF.MADD", key, args.values, true)
 }
 
 // BfExistsMulti - Determines if one or more items may exist in the filter or not.
@@ -173,18 +235,139 @@ func (client *Client) BfAddMulti(key string, items []string) ([]int64, error) {
 // key - the name of the filter
 // item - one or more items to check
 func (client *Client) BfExistsMulti(key string, items []string) ([]int64, error) {
+	args := stringArgs(items)
+	defer args.release()
+	return client.batchedInt64s("BF.MEXISTS", key, args.values, false)
+}
+
+// batchedInt64s issues a multi-item command returning one integer per item, splitting the items
+// into pipelined commands of at most maxBatchSize items and stitching the replies back in order.
+// write commands refresh the write-through TTL of key
+func (client *Client) batchedInt64s(command string, key string, items []interface{}, write bool) ([]int64, error) {
 	conn := client.Pool.Get()
 	defer conn.Close()
-	args := redis.Args{key}.AddFlat(items)
-	result, err := conn.Do("BF.MEXISTS", args...)
-	return redis.Int64s(result, err)
+	ttl := time.Duration(0)
+	if write {
+		ttl = client.writeTTL
+	}
+	batchSize := client.maxBatchSize
+	if batchSize <= 0 || len(items) <= batchSize {
+		args := client.keyArgs(key, len(items))
+		defer args.release()
+		args.values = append(args.values, items...)
+		return parseInt64s(client.doWithTTL(conn, key, ttl, command, args.values...))
+	}
+	batches := 0
+	for start := 0; start < len(items); start += batchSize {
+		end := start + batchSize
+		if end > len(items) {
+			end = len(items)
+		}
+		// released once every reply is received, as hooks may hold on to the arguments of pipelined commands
+		args := client.keyArgs(key, end-start)
+		defer args.release()
+		args.values = append(args.values, items[start:end]...)
+		if err := conn.Send(command, args.values...); err != nil {
+			return nil, err
+		}
+		batches++
+	}
+	if err := conn.Flush(); err != nil {
+		return nil, err
+	}
+	result := make([]int64, 0, len(items))
+	var outErr error
+	// drain every reply, even after a failure, so the connection is returned to the pool clean
+	for i := 0; i < batches; i++ {
+		values, err := parseInt64s(conn.Receive())
+		if err != nil {
+			if outErr == nil {
+				outErr = err
+			}
+			continue
+		}
+		result = append(result, values...)
+	}
+	if outErr != nil {
+		return nil, outErr
+	}
+	if ttl > 0 {
+		if _, err := conn.Do("PEXPIRE", client.key(key), int64(ttl/time.Millisecond)); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// BfAdd - Variadic form of BfAddMulti
+func (client *Client) BfAdd(key string, items ...string) ([]int64, error) {
+	return client.BfAddMulti(key, items)
+}
+
+// BfExists - Variadic form of BfExistsMulti
+func (client *Client) BfExists(key string, items ...string) ([]int64, error) {
+	return client.BfExistsMulti(key, items)
+}
+
+// BfAddMultiBool - Adds one or more items to the Bloom Filter, creating the filter if it does not yet exist.
+// Each result is true if the corresponding item was newly added.
+func (client *Client) BfAddMultiBool(key string, items []string) ([]bool, error) {
+	return Int64sToBools(client.BfAddMulti(key, items))
+}
+
+// BfExistsMultiBool - Determines if one or more items may exist in the filter or not.
+// Each result is true if the corresponding item may exist.
+func (client *Client) BfExistsMultiBool(key string, items []string) ([]bool, error) {
+	return Int64sToBools(client.BfExistsMulti(key, items))
+}
+
+// BfExistsManyKeys - Determines, for each filter of itemsByKey, whether its items may exist in it, with one
+// pipelined BF.MEXISTS per filter. The results of each filter are aligned with its items.
+func (client *Client) BfExistsManyKeys(itemsByKey map[string][]string) (map[string][]bool, error) {
+	conn := client.Pool.Get()
+	defer conn.Close()
+	result := make(map[string][]bool, len(itemsByKey))
+	keys := make([]string, 0, len(itemsByKey))
+	for key, items := range itemsByKey {
+		if len(items) == 0 {
+			result[key] = []bool{}
+			continue
+		}
+		// released once every reply is received, as hooks may hold on to the arguments of pipelined commands
+		args := client.keyArgs(key, len(items))
+		defer args.release()
+		args.addStrings(items)
+		if err := conn.Send("BF.MEXISTS", args.values...); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	if err := conn.Flush(); err != nil {
+		return nil, err
+	}
+	var outErr error
+	// drain every reply, even after a failure, so the connection is returned to the pool clean
+	for _, key := range keys {
+		exists, err := Int64sToBools(parseInt64s(conn.Receive()))
+		if err != nil {
+			if outErr == nil {
+				outErr = err
+			}
+			continue
+		}
+		result[key] = exists
+	}
+	if outErr != nil {
+		return nil, outErr
+	}
+	return result, nil
 }
 
 // Begins an incremental save of the bloom filter.
 func (client *Client) BfScanDump(key string, iter int64) (int64, []byte, error) {
 	conn := client.Pool.Get()
 	defer conn.Close()
-	reply, err := redis.Values(conn.Do("BF.SCANDUMP", key, iter))
+	reply, err := redis.Values(conn.Do("BF.SCANDUMP", client.key(key), iter))
 	if err != nil || len(reply) != 2 {
 		return 0, nil, err
 	}
@@ -199,33 +382,62 @@ func (client *Client) BfScanDump(key string, iter int64) (int64, []byte, error)
 func (client *Client) BfLoadChunk(key string, iter int64, data []byte) (string, error) {
 	conn := client.Pool.Get()
 	defer conn.Close()
-	return redis.String(conn.Do("BF.LOADCHUNK", key, iter, data))
+	return redis.String(conn.Do("BF.LOADCHUNK", client.key(key), iter, data))
 }
 
 // This command will add one or more items to the bloom filter, by default creating it if it does not yet exist.
+// Non-positive cap, errorRatio and expansion values are omitted from the command.
+// Deprecated: Please use BfInsertWithOptions() instead
 func (client *Client) BfInsert(key string, cap int64, errorRatio float64, expansion int64, noCreate bool, nonScaling bool, items []string) (res []int64, err error) {
-	conn := client.Pool.Get()
-	defer conn.Close()
-	args := redis.Args{key}
+	opts := make([]CallOption, 0, 5)
 	if cap > 0 {
-		args = args.Add("CAPACITY", cap)
+		opts = append(opts, WithCapacity(cap))
 	}
 	if errorRatio > 0 {
-		args = args.Add("ERROR", errorRatio)
+		opts = append(opts, WithErrorRate(errorRatio))
 	}
 	if expansion > 0 {
-		args = args.Add("EXPANSION", expansion)
+		opts = append(opts, WithExpansion(expansion))
 	}
 	if noCreate {
-		args = args.Add("NOCREATE")
+		opts = append(opts, WithNoCreate())
 	}
 	if nonScaling {
+		opts = append(opts, WithNonScaling())
+	}
+	return client.BfInsertWithOptions(key, items, opts...)
+}
+
+// BfInsertWithOptions - Adds one or more items to the bloom filter, by default creating it if it does not yet exist.
+// args:
+// key - the name of the filter
+// items - the items to add
+// opts - WithCapacity, WithErrorRate, WithExpansion, WithNoCreate, WithNonScaling and WithExtraArgs
+func (client *Client) BfInsertWithOptions(key string, items []string, opts ...CallOption) (res []int64, err error) {
+	conn := client.Pool.Get()
+	defer conn.Close()
+	o := newCallOptions(opts)
+	args := redis.Args{client.key(key)}
+	if o.capacity != nil {
+		args = args.Add("CAPACITY", *o.capacity)
+	}
+	if o.errorRate != nil {
+		args = args.Add("ERROR", *o.errorRate)
+	}
+	if o.expansion != nil {
+		args = args.Add("EXPANSION", *o.expansion)
+	}
+	if o.noCreate {
+		args = args.Add("NOCREATE")
+	}
+	if o.nonScaling {
 		args = args.Add("NONSCALING")
 	}
+	args = append(args, o.extraArgs...)
 	args = args.Add("ITEMS").AddFlat(items)
 	var resp []interface{}
 	var innerRes int64
-	resp, err = redis.Values(conn.Do("BF.INSERT", args...))
+	resp, err = redis.Values(client.doWithTTL(conn, key, client.writeTTL, "BF.INSERT", args...))
 	if err != nil {
 		return
 	}
@@ -240,11 +452,21 @@ func (client *Client) BfInsert(key string, cap int64, errorRatio float64, expans
 	return
 }
 
+// BfInsertBool - Same as BfInsert, each result being true if the corresponding item was newly added.
+func (client *Client) BfInsertBool(key string, cap int64, errorRatio float64, expansion int64, noCreate bool, nonScaling bool, items []string) ([]bool, error) {
+	return Int64sToBools(client.BfInsert(key, cap, errorRatio, expansion, noCreate, nonScaling, items))
+}
+
 // Initializes a TopK with specified parameters.
 func (client *Client) TopkReserve(key string, topk int64, width int64, depth int64, decay float64) (string, error) {
+	if client.memoryBudgets != nil {
+		if err := client.checkBudget(key, width*depth*topkBucketBytes); err != nil {
+			return "", err
+		}
+	}
 	conn := client.Pool.Get()
 	defer conn.Close()
-	result, err := conn.Do("TOPK.RESERVE", key, topk, width, depth, strconv.FormatFloat(decay, 'g', 16, 64))
+	result, err := conn.Do("TOPK.RESERVE", client.key(key), topk, width, depth, strconv.FormatFloat(decay, 'g', 16, 64))
 	return redis.String(result, err)
 }
 
@@ -252,17 +474,24 @@ func (client *Client) TopkReserve(key string, topk int64, width int64, depth int
 func (client *Client) TopkAdd(key string, items []string) ([]string, error) {
 	conn := client.Pool.Get()
 	defer conn.Close()
-	args := redis.Args{key}.AddFlat(items)
-	result, err := conn.Do("TOPK.ADD", args...)
-	return redis.Strings(result, err)
+	args := client.keyArgs(key, len(items))
+	defer args.release()
+	args.addStrings(items)
+	result, err := conn.Do("TOPK.ADD", args.values...)
+	return parseStrings(result, err)
+}
+
+// TopkAddItems - Variadic form of TopkAdd
+func (client *Client) TopkAddItems(key string, items ...string) ([]string, error) {
+	return client.TopkAdd(key, items)
 }
 
 // Returns count for an item.
 func (client *Client) TopkCount(key string, items []string) (result []int64, err error) {
 	conn := client.Pool.Get()
 	defer conn.Close()
-	args := redis.Args{key}.AddFlat(items)
-	result, err = redis.Int64s(conn.Do("TOPK.COUNT", args...))
+	args := redis.Args{client.key(key)}.AddFlat(items)
+	result, err = parseInt64s(conn.Do("TOPK.COUNT", args...))
 	return
 }
 
@@ -270,207 +499,356 @@ func (client *Client) TopkCount(key string, items []string) (result []int64, err
 func (client *Client) TopkQuery(key string, items []string) ([]int64, error) {
 	conn := client.Pool.Get()
 	defer conn.Close()
-	args := redis.Args{key}.AddFlat(items)
+	args := redis.Args{client.key(key)}.AddFlat(items)
 	result, err := conn.Do("TOPK.QUERY", args...)
-	return redis.Int64s(result, err)
+	return parseInt64s(result, err)
+}
+
+// TopkCountItems - Variadic form of TopkCount
+func (client *Client) TopkCountItems(key string, items ...string) ([]int64, error) {
+	return client.TopkCount(key, items)
+}
+
+// TopkQueryItems - Variadic form of TopkQuery
+func (client *Client) TopkQueryItems(key string, items ...string) ([]int64, error) {
+	return client.TopkQuery(key, items)
+}
+
+// TopkQueryBool - Checks whether each item is one of Top-K items.
+func (client *Client) TopkQueryBool(key string, items []string) ([]bool, error) {
+	return Int64sToBools(client.TopkQuery(key, items))
 }
 
 // Return full list of items in Top K list.
 func (client *Client) TopkListWithCount(key string) (map[string]int64, error) {
 	conn := client.Pool.Get()
 	defer conn.Close()
-	return ParseInfoReply(redis.Values(conn.Do("TOPK.LIST", key, "WITHCOUNT")))
+	return parseStringInt64Pairs(conn.Do("TOPK.LIST", client.key(key), "WITHCOUNT"))
 }
 
 func (client *Client) TopkList(key string) ([]string, error) {
 	conn := client.Pool.Get()
 	defer conn.Close()
-	result, err := conn.Do("TOPK.LIST", key)
-	return redis.Strings(result, err)
+	result, err := conn.Do("TOPK.LIST", client.key(key))
+	return parseStrings(result, err)
 }
 
 // Returns number of required items (k), width, depth and decay values.
+// Fields added by newer module versions are formatted with fmt.Sprint.
 func (client *Client) TopkInfo(key string) (map[string]string, error) {
 	conn := client.Pool.Get()
 	defer conn.Close()
-	reply, err := conn.Do("TOPK.INFO", key)
-	values, err := redis.Values(reply, err)
+	fields, err := parseInfoFields(conn.Do("TOPK.INFO", client.key(key)))
 	if err != nil {
 		return nil, err
 	}
-	if len(values)%2 != 0 {
-		return nil, errors.New("expects even number of values result")
+	m := make(map[string]string, len(fields))
+	for k, v := range fields {
+		m[k] = infoString(v)
 	}
-
-	m := make(map[string]string, len(values)/2)
-	for i := 0; i < len(values); i += 2 {
-		k := values[i].(string)
-		switch v := values[i+1].(type) {
-		case []byte:
-			m[k] = string(values[i+1].([]byte))
-			break
-		case int64:
-			m[k] = strconv.FormatInt(values[i+1].(int64), 10)
-		default:
-			return nil, fmt.Errorf("unexpected element type for (Ints,String), got type %T", v)
-		}
-	}
-	return m, err
+	return m, nil
 }
 
 // Increase the score of an item in the data structure by increment.
+// The order of the returned items is not deterministic, use TopkIncrByItems to correlate them with items.
 func (client *Client) TopkIncrBy(key string, itemIncrements map[string]int64) ([]string, error) {
 	conn := client.Pool.Get()
 	defer conn.Close()
-	args := redis.Args{key}
+	args := redis.Args{client.key(key)}
 	for k, v := range itemIncrements {
 		args = args.Add(k, v)
 	}
 	reply, err := conn.Do("TOPK.INCRBY", args...)
-	return redis.Strings(reply, err)
+	return parseStrings(reply, err)
+}
+
+// TopkIncrement is an item and the value to increase its score by
+type TopkIncrement struct {
+	Item      string
+	Increment int64
+}
+
+// TopkIncrByItems - Increase the score of each item by its increment.
+// The returned expelled items are aligned with the order of increments, an empty string meaning no item was expelled.
+func (client *Client) TopkIncrByItems(key string, increments []TopkIncrement) ([]string, error) {
+	conn := client.Pool.Get()
+	defer conn.Close()
+	args := redis.Args{client.key(key)}
+	for _, increment := range increments {
+		args = args.Add(increment.Item, increment.Increment)
+	}
+	reply, err := conn.Do("TOPK.INCRBY", args...)
+	return parseStrings(reply, err)
 }
 
 // Initializes a Count-Min Sketch to dimensions specified by user.
 func (client *Client) CmsInitByDim(key string, width int64, depth int64) (string, error) {
+	if client.memoryBudgets != nil {
+		if err := client.checkBudget(key, width*depth*cmsCounterBytes); err != nil {
+			return "", err
+		}
+	}
 	conn := client.Pool.Get()
 	defer conn.Close()
-	result, err := conn.Do("CMS.INITBYDIM", key, width, depth)
+	result, err := conn.Do("CMS.INITBYDIM", client.key(key), width, depth)
 	return redis.String(result, err)
 }
 
 // Initializes a Count-Min Sketch to accommodate requested capacity.
 func (client *Client) CmsInitByProb(key string, error float64, probability float64) (string, error) {
+	if plan, err := PlanCMS(error, probability); client.memoryBudgets != nil && err == nil {
+		if err = client.checkBudget(key, plan.MemoryBytes); err != nil {
+			return "", err
+		}
+	}
 	conn := client.Pool.Get()
 	defer conn.Close()
-	result, err := conn.Do("CMS.INITBYPROB", key, error, probability)
+	result, err := conn.Do("CMS.INITBYPROB", client.key(key), error, probability)
 	return redis.String(result, err)
 }
 
 // Increases the count of item by increment. Multiple items can be increased with one call.
+// The order of the returned counts is not deterministic, use CmsIncrByItems to correlate them with items.
 func (client *Client) CmsIncrBy(key string, itemIncrements map[string]int64) ([]int64, error) {
 	conn := client.Pool.Get()
 	defer conn.Close()
-	args := redis.Args{key}
+	args := client.keyArgs(key, 2*len(itemIncrements))
+	defer args.release()
 	for k, v := range itemIncrements {
-		args = args.Add(k, v)
+		args.values = append(args.values, k, v)
+	}
+	result, err := conn.Do("CMS.INCRBY", args.values...)
+	return parseInt64s(result, err)
+}
+
+// CmsIncrement is an item and the value to increase its count by
+type CmsIncrement struct {
+	Item  string
+	Count int64
+}
+
+// CmsIncrByItems - Increases the count of each item by its increment.
+// The returned counts are aligned with the order of increments.
+func (client *Client) CmsIncrByItems(key string, increments []CmsIncrement) ([]int64, error) {
+	conn := client.Pool.Get()
+	defer conn.Close()
+	args := redis.Args{client.key(key)}
+	for _, increment := range increments {
+		args = args.Add(increment.Item, increment.Count)
 	}
 	result, err := conn.Do("CMS.INCRBY", args...)
-	return redis.Int64s(result, err)
+	return parseInt64s(result, err)
 }
 
 // Returns count for item.
 func (client *Client) CmsQuery(key string, items []string) ([]int64, error) {
-	conn := client.Pool.Get()
-	defer conn.Close()
-	args := redis.Args{key}.AddFlat(items)
-	result, err := conn.Do("CMS.QUERY", args...)
-	return redis.Int64s(result, err)
+	return client.coalesceInt64s("CMS.QUERY", client.key(key), items, func() ([]int64, error) {
+		args := stringArgs(items)
+		defer args.release()
+		return client.batchedInt64s("CMS.QUERY", key, args.values, false)
+	})
+}
+
+// CmsQueryItems - Variadic form of CmsQuery
+func (client *Client) CmsQueryItems(key string, items ...string) ([]int64, error) {
+	return client.CmsQuery(key, items)
 }
 
 // Merges several sketches into one sketch, stored at dest key
 // All sketches must have identical width and depth.
+// On a Redis Cluster, returns a *CrossSlotError if the keys map to different slots.
 func (client *Client) CmsMerge(dest string, srcs []string, weights []int64) (string, error) {
 	conn := client.Pool.Get()
 	defer conn.Close()
-	args := redis.Args{dest}.Add(len(srcs)).AddFlat(srcs)
+	args := redis.Args{client.key(dest)}.Add(len(srcs)).AddFlat(client.keys(srcs))
 	if weights != nil && len(weights) > 0 {
 		args = args.Add("WEIGHTS").AddFlat(weights)
 	}
-	return redis.String(conn.Do("CMS.MERGE", args...))
+	reply, err := redis.String(conn.Do("CMS.MERGE", args...))
+	return reply, client.crossSlotError(append([]string{dest}, srcs...), err)
 }
 
 // Returns width, depth and total count of the sketch.
 func (client *Client) CmsInfo(key string) (map[string]int64, error) {
 	conn := client.Pool.Get()
 	defer conn.Close()
-	return ParseInfoReply(redis.Values(conn.Do("CMS.INFO", key)))
+	return ParseInfoReply(redis.Values(conn.Do("CMS.INFO", client.key(key))))
 }
 
 // Create an empty cuckoo filter with an initial capacity of {capacity} items.
+// Non-positive bucketSize, maxIterations and expansion values are omitted from the command.
+// Deprecated: Please use CfReserveWithOptions() instead
 func (client *Client) CfReserve(key string, capacity int64, bucketSize int64, maxIterations int64, expansion int64) (string, error) {
-	conn := client.Pool.Get()
-	defer conn.Close()
-	args := redis.Args{key}.Add(capacity)
+	opts := make([]CallOption, 0, 3)
 	if bucketSize > 0 {
-		args = args.Add("BUCKETSIZE", bucketSize)
+		opts = append(opts, WithBucketSize(bucketSize))
 	}
 	if maxIterations > 0 {
-		args = args.Add("MAXITERATIONS", maxIterations)
+		opts = append(opts, WithMaxIterations(maxIterations))
 	}
 	if expansion > 0 {
-		args = args.Add("EXPANSION", expansion)
+		opts = append(opts, WithExpansion(expansion))
+	}
+	return client.CfReserveWithOptions(key, capacity, opts...)
+}
+
+// CfReserveWithOptions - Create an empty cuckoo filter with an initial capacity of {capacity} items.
+// args:
+// key - the name of the filter
+// capacity - the number of entries you intend to add to the filter
+// opts - WithBucketSize, WithMaxIterations, WithExpansion and WithExtraArgs
+func (client *Client) CfReserveWithOptions(key string, capacity int64, opts ...CallOption) (string, error) {
+	if err := client.checkCuckooBudget(key, capacity, opts); err != nil {
+		return "", err
+	}
+	conn := client.Pool.Get()
+	defer conn.Close()
+	return redis.String(conn.Do("CF.RESERVE", client.cfReserveArgs(key, capacity, opts)...))
+}
+
+func (client *Client) cfReserveArgs(key string, capacity int64, opts []CallOption) redis.Args {
+	o := newCallOptions(opts)
+	args := redis.Args{client.key(key)}.Add(capacity)
+	if o.bucketSize != nil {
+		args = args.Add("BUCKETSIZE", *o.bucketSize)
+	}
+	if o.maxIterations != nil {
+		args = args.Add("MAXITERATIONS", *o.maxIterations)
 	}
-	return redis.String(conn.Do("CF.RESERVE", args...))
+	if o.expansion != nil {
+		args = args.Add("EXPANSION", *o.expansion)
+	}
+	return append(args, o.extraArgs...)
 }
 
 // Adds an item to the cuckoo filter, creating the filter if it does not exist.
 func (client *Client) CfAdd(key string, item string) (bool, error) {
 	conn := client.Pool.Get()
 	defer conn.Close()
-	return redis.Bool(conn.Do("CF.ADD", key, item))
+	return redis.Bool(client.doWithTTL(conn, key, client.writeTTL, "CF.ADD", client.key(key), item))
 }
 
 // Adds an item to a cuckoo filter if the item did not exist previously.
 func (client *Client) CfAddNx(key string, item string) (bool, error) {
 	conn := client.Pool.Get()
 	defer conn.Close()
-	return redis.Bool(conn.Do("CF.ADDNX", key, item))
+	return redis.Bool(client.doWithTTL(conn, key, client.writeTTL, "CF.ADDNX", client.key(key), item))
 }
 
 // Adds one or more items to a cuckoo filter, allowing the filter to be created with a custom capacity if it does not yet exist.
+// Deprecated: Please use CfInsertWithOptions() instead
 func (client *Client) CfInsert(key string, cap int64, noCreate bool, items []string) ([]int64, error) {
+	return client.CfInsertWithOptions(key, items, insertOptions(cap, noCreate)...)
+}
+
+// CfInsertWithOptions - Adds one or more items to a cuckoo filter, by default creating it if it does not yet exist.
+// args:
+// key - the name of the filter
+// items - the items to add
+// opts - WithCapacity, WithNoCreate and WithExtraArgs
+func (client *Client) CfInsertWithOptions(key string, items []string, opts ...CallOption) ([]int64, error) {
 	conn := client.Pool.Get()
 	defer conn.Close()
-	args := GetInsertArgs(key, cap, noCreate, items)
-	return redis.Int64s(conn.Do("CF.INSERT", args...))
+	return parseInt64s(client.doWithTTL(conn, key, client.writeTTL, "CF.INSERT", cfInsertArgs(client.key(key), items, opts)...))
+}
+
+// CfInsertBool - Same as CfInsert, each result being true if the corresponding item was added.
+// If the filter was full for some items, the results are returned along with ErrFilterFull,
+// the items the filter had no room for being false.
+func (client *Client) CfInsertBool(key string, cap int64, noCreate bool, items []string) ([]bool, error) {
+	values, err := client.CfInsert(key, cap, noCreate, items)
+	result, err := Int64sToBools(values, err)
+	if err != nil {
+		return nil, err
+	}
+	for _, value := range values {
+		if value == -1 {
+			return result, ErrFilterFull
+		}
+	}
+	return result, nil
 }
 
 // Adds one or more items to a cuckoo filter, allowing the filter to be created with a custom capacity if it does not yet exist.
+// Deprecated: Please use CfInsertNxWithOptions() instead
 func (client *Client) CfInsertNx(key string, cap int64, noCreate bool, items []string) ([]int64, error) {
+	return client.CfInsertNxWithOptions(key, items, insertOptions(cap, noCreate)...)
+}
+
+// CfInsertNxWithOptions - Adds one or more items to a cuckoo filter if they did not exist previously,
+// by default creating it if it does not yet exist.
+// opts - WithCapacity, WithNoCreate and WithExtraArgs
+func (client *Client) CfInsertNxWithOptions(key string, items []string, opts ...CallOption) ([]int64, error) {
 	conn := client.Pool.Get()
 	defer conn.Close()
-	args := GetInsertArgs(key, cap, noCreate, items)
-	return redis.Int64s(conn.Do("CF.INSERTNX", args...))
+	return parseInt64s(client.doWithTTL(conn, key, client.writeTTL, "CF.INSERTNX", cfInsertArgs(client.key(key), items, opts)...))
 }
 
-func GetInsertArgs(key string, cap int64, noCreate bool, items []string) redis.Args {
-	args := redis.Args{key}
+// insertOptions converts the positional arguments of CfInsert and CfInsertNx into options
+func insertOptions(cap int64, noCreate bool) []CallOption {
+	opts := make([]CallOption, 0, 2)
 	if cap > 0 {
-		args = args.Add("CAPACITY", cap)
+		opts = append(opts, WithCapacity(cap))
 	}
 	if noCreate {
+		opts = append(opts, WithNoCreate())
+	}
+	return opts
+}
+
+func cfInsertArgs(key string, items []string, opts []CallOption) redis.Args {
+	o := newCallOptions(opts)
+	args := redis.Args{key}
+	if o.capacity != nil {
+		args = args.Add("CAPACITY", *o.capacity)
+	}
+	if o.noCreate {
 		args = args.Add("NOCREATE")
 	}
-	args = args.Add("ITEMS").AddFlat(items)
-	return args
+	args = append(args, o.extraArgs...)
+	return args.Add("ITEMS").AddFlat(items)
+}
+
+func GetInsertArgs(key string, cap int64, noCreate bool, items []string) redis.Args {
+	return cfInsertArgs(key, items, insertOptions(cap, noCreate))
 }
 
 // Check if an item exists in a Cuckoo Filter
 func (client *Client) CfExists(key string, item string) (bool, error) {
+	return client.coalesceBool("CF.EXISTS", client.key(key), item, func() (bool, error) {
+		conn := client.Pool.Get()
+		defer conn.Close()
+		return redis.Bool(conn.Do("CF.EXISTS", client.key(key), item))
+	})
+}
+
+// CfExistsMulti - Check if one or more items exist in a Cuckoo Filter
+func (client *Client) CfExistsMulti(key string, items ...string) ([]int64, error) {
 	conn := client.Pool.Get()
 	defer conn.Close()
-	return redis.Bool(conn.Do("CF.EXISTS", key, item))
+	args := redis.Args{client.key(key)}.AddFlat(items)
+	return parseInt64s(conn.Do("CF.MEXISTS", args...))
 }
 
 // Deletes an item once from the filter.
 func (client *Client) CfDel(key string, item string) (bool, error) {
 	conn := client.Pool.Get()
 	defer conn.Close()
-	return redis.Bool(conn.Do("CF.DEL", key, item))
+	return redis.Bool(conn.Do("CF.DEL", client.key(key), item))
 }
 
 // Returns the number of times an item may be in the filter.
 func (client *Client) CfCount(key string, item string) (int64, error) {
 	conn := client.Pool.Get()
 	defer conn.Close()
-	return redis.Int64(conn.Do("CF.COUNT", key, item))
+	return redis.Int64(conn.Do("CF.COUNT", client.key(key), item))
 }
 
 // Begins an incremental save of the cuckoo filter.
 func (client *Client) CfScanDump(key string, iter int64) (int64, []byte, error) {
 	conn := client.Pool.Get()
 	defer conn.Close()
-	reply, err := redis.Values(conn.Do("CF.SCANDUMP", key, iter))
+	reply, err := redis.Values(conn.Do("CF.SCANDUMP", client.key(key), iter))
 	if err != nil || len(reply) != 2 {
 		return 0, nil, err
 	}
@@ -485,76 +863,219 @@ func (client *Client) CfScanDump(key string, iter int64) (int64, []byte, error)
 func (client *Client) CfLoadChunk(key string, iter int64, data []byte) (string, error) {
 	conn := client.Pool.Get()
 	defer conn.Close()
-	return redis.String(conn.Do("CF.LOADCHUNK", key, iter, data))
+	return redis.String(conn.Do("CF.LOADCHUNK", client.key(key), iter, data))
 }
 
 // Return information about key
 func (client *Client) CfInfo(key string) (map[string]int64, error) {
 	conn := client.Pool.Get()
 	defer conn.Close()
-	return ParseInfoReply(redis.Values(conn.Do("CF.INFO", key)))
+	return ParseInfoReply(redis.Values(conn.Do("CF.INFO", client.key(key))))
 }
 
 // TdCreate - Allocate the memory and initialize the t-digest
 func (client *Client) TdCreate(key string, compression int64) (string, error) {
+	return client.TdCreateWithOptions(key, WithCompression(compression))
+}
+
+// TdCreateDefault - Allocate the memory and initialize the t-digest with the server default compression
+func (client *Client) TdCreateDefault(key string) (string, error) {
+	return client.TdCreateWithOptions(key)
+}
+
+// TdCreateWithOptions - Allocate the memory and initialize the t-digest
+// args:
+// key - the name of the sketch
+// opts - WithCompression and WithExtraArgs
+func (client *Client) TdCreateWithOptions(key string, opts ...CallOption) (string, error) {
+	o := newCallOptions(opts)
+	modern, err := client.isModernTDigest()
+	if err != nil {
+		return "", err
+	}
+	args := redis.Args{client.key(key)}
+	if modern {
+		if o.compression != nil {
+			args = args.Add("COMPRESSION", *o.compression)
+		}
+	} else {
+		// servers prior to RedisBloom 2.4 require the compression as a positional argument
+		compression := int64(defaultTDigestCompression)
+		if o.compression != nil {
+			compression = *o.compression
+		}
+		args = args.Add(compression)
+	}
+	args = append(args, o.extraArgs...)
 	conn := client.Pool.Get()
 	defer conn.Close()
-	return redis.String(conn.Do("TDIGEST.CREATE", key, compression))
+	return redis.String(conn.Do("TDIGEST.CREATE", args...))
 }
 
 // TdReset - Reset the sketch to zero - empty out the sketch and re-initialize it
 func (client *Client) TdReset(key string) (string, error) {
 	conn := client.Pool.Get()
 	defer conn.Close()
-	return redis.String(conn.Do("TDIGEST.RESET", key))
+	return redis.String(conn.Do("TDIGEST.RESET", client.key(key)))
 }
 
 // TdAdd - Adds one or more samples to a sketch
+// Legacy: the value->weight form is only understood by RedisBloom versions prior to 2.4. On newer
+// servers each value is repeated weight times, which requires integral weights. Please use TdAddValues() instead
 func (client *Client) TdAdd(key string, samples map[float64]float64) (string, error) {
+	modern, err := client.isModernTDigest()
+	if err != nil {
+		return "", err
+	}
+	args := redis.Args{client.key(key)}
+	if modern {
+		for value, weight := range samples {
+			if weight < 0 || weight != math.Trunc(weight) {
+				return "", fmt.Errorf("TdAdd: weight %v of value %v is not a non-negative integer", weight, value)
+			}
+			for i := int64(0); i < int64(weight); i++ {
+				args = args.Add(value)
+			}
+		}
+	} else {
+		for k, v := range samples {
+			args = args.Add(k, v)
+		}
+	}
 	conn := client.Pool.Get()
 	defer conn.Close()
-	args := redis.Args{key}
-	for k, v := range samples {
-		args = args.Add(k, v)
-	}
 	reply, err := conn.Do("TDIGEST.ADD", args...)
 	return redis.String(reply, err)
 }
 
+// TdAddValues - Adds one or more observations to a sketch, in the given order
+func (client *Client) TdAddValues(key string, values ...float64) (string, error) {
+	modern, err := client.isModernTDigest()
+	if err != nil {
+		return "", err
+	}
+	args := redis.Args{client.key(key)}
+	for _, value := range values {
+		args = args.Add(value)
+		if !modern {
+			args = args.Add(1.0)
+		}
+	}
+	conn := client.Pool.Get()
+	defer conn.Close()
+	return redis.String(conn.Do("TDIGEST.ADD", args...))
+}
+
 // TdMerge - Merges all of the values from 'from' to 'this' sketch
+// On a Redis Cluster, returns a *CrossSlotError if the keys map to different slots.
 func (client *Client) TdMerge(toKey string, fromKey string) (string, error) {
 	conn := client.Pool.Get()
 	defer conn.Close()
-	return redis.String(conn.Do("TDIGEST.MERGE", toKey, fromKey))
+	reply, err := redis.String(conn.Do("TDIGEST.MERGE", client.key(toKey), client.key(fromKey)))
+	return reply, client.crossSlotError([]string{toKey, fromKey}, err)
 }
 
-// TdMin - Get minimum value from the sketch. Will return DBL_MAX if the sketch is empty
+// TdMin - Get minimum value from the sketch. Returns NaN and ErrEmptySketch if the sketch is empty
 func (client *Client) TdMin(key string) (float64, error) {
 	conn := client.Pool.Get()
 	defer conn.Close()
-	return redis.Float64(conn.Do("TDIGEST.MIN", key))
+	min, err := ParseFloat64Reply(conn.Do("TDIGEST.MIN", client.key(key)))
+	// servers prior to RedisBloom 2.4 reply DBL_MAX for an empty sketch
+	if err == nil && (math.IsNaN(min) || min == math.MaxFloat64) {
+		return math.NaN(), ErrEmptySketch
+	}
+	return min, err
 }
 
-// TdMax - Get maximum value from the sketch. Will return DBL_MIN if the sketch is empty
+// TdMax - Get maximum value from the sketch. Returns NaN and ErrEmptySketch if the sketch is empty
 func (client *Client) TdMax(key string) (float64, error) {
 	conn := client.Pool.Get()
 	defer conn.Close()
-	return redis.Float64(conn.Do("TDIGEST.MAX", key))
+	max, err := ParseFloat64Reply(conn.Do("TDIGEST.MAX", client.key(key)))
+	// servers prior to RedisBloom 2.4 reply -DBL_MAX for an empty sketch
+	if err == nil && (math.IsNaN(max) || max == -math.MaxFloat64) {
+		return math.NaN(), ErrEmptySketch
+	}
+	return max, err
 }
 
 // TdQuantile - Returns an estimate of the cutoff such that a specified fraction of the data added
-// to this TDigest would be less than or equal to the cutoff
+// to this TDigest would be less than or equal to the cutoff. The estimate is NaN if the sketch is empty
 func (client *Client) TdQuantile(key string, quantile float64) (float64, error) {
+	values, err := client.TdQuantiles(key, quantile)
+	if err != nil {
+		return 0, err
+	}
+	return values[0], nil
+}
+
+// TdQuantiles - Returns, for each of the given quantiles, an estimate of the cutoff such that the specified
+// fraction of the data added to this TDigest would be less than or equal to the cutoff.
+// The result is aligned with the order of quantiles, with NaN estimates if the sketch is empty.
+func (client *Client) TdQuantiles(key string, quantiles ...float64) ([]float64, error) {
+	if len(quantiles) == 0 {
+		return nil, errors.New("TdQuantiles expects at least one quantile")
+	}
+	modern, err := client.isModernTDigest()
+	if err != nil {
+		return nil, err
+	}
+	conn := client.Pool.Get()
+	defer conn.Close()
+	if modern {
+		return ParseFloat64sReply(conn.Do("TDIGEST.QUANTILE", redis.Args{client.key(key)}.AddFlat(quantiles)...))
+	}
+	// servers prior to RedisBloom 2.4 accept a single quantile per command, so pipeline them
+	for _, quantile := range quantiles {
+		if err := conn.Send("TDIGEST.QUANTILE", client.key(key), quantile); err != nil {
+			return nil, err
+		}
+	}
+	if err := conn.Flush(); err != nil {
+		return nil, err
+	}
+	values := make([]float64, len(quantiles))
+	for i := range quantiles {
+		value, err := ParseFloat64Reply(conn.Receive())
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+// TdByRank - Returns, for each of the given ranks, an estimate of the value with that rank,
+// where rank 0 is the smallest value. Ranks at or beyond the number of observations yield +Inf.
+// Requires RedisBloom 2.4 or newer
+func (client *Client) TdByRank(key string, ranks ...int64) ([]float64, error) {
+	conn := client.Pool.Get()
+	defer conn.Close()
+	return ParseFloat64sReply(conn.Do("TDIGEST.BYRANK", redis.Args{client.key(key)}.AddFlat(ranks)...))
+}
+
+// TdByRevRank - Returns, for each of the given reverse ranks, an estimate of the value with that rank,
+// where reverse rank 0 is the largest value. Ranks at or beyond the number of observations yield -Inf.
+// Requires RedisBloom 2.4 or newer
+func (client *Client) TdByRevRank(key string, ranks ...int64) ([]float64, error) {
 	conn := client.Pool.Get()
 	defer conn.Close()
-	return redis.Float64(conn.Do("TDIGEST.QUANTILE", key, quantile))
+	return ParseFloat64sReply(conn.Do("TDIGEST.BYREVRANK", redis.Args{client.key(key)}.AddFlat(ranks)...))
 }
 
-// TdCdf - Returns the fraction of all points added which are <= value
+// TdCdf - Returns the fraction of all points added which are <= value, NaN if the sketch is empty
 func (client *Client) TdCdf(key string, value float64) (float64, error) {
 	conn := client.Pool.Get()
 	defer conn.Close()
-	return redis.Float64(conn.Do("TDIGEST.CDF", key, value))
+	// RedisBloom 2.4 and newer reply an array, of a single fraction here
+	fractions, err := ParseFloat64sReply(conn.Do("TDIGEST.CDF", client.key(key), value))
+	if err != nil {
+		return 0, err
+	}
+	if len(fractions) != 1 {
+		return 0, errors.New("redisbloom: unexpected number of fractions in TDIGEST.CDF reply")
+	}
+	return fractions[0], nil
 }
 
 // TdInfo - Returns compression, capacity, total merged and unmerged nodes, the total
@@ -562,54 +1083,110 @@ func (client *Client) TdCdf(key string, value float64) (float64, error) {
 func (client *Client) TdInfo(key string) (TDigestInfo, error) {
 	conn := client.Pool.Get()
 	defer conn.Close()
-	return ParseTDigestInfo(redis.Values(conn.Do("TDIGEST.INFO", key)))
+	return ParseTDigestInfo(redis.Values(conn.Do("TDIGEST.INFO", client.key(key))))
 }
 
+// ParseInfoReply converts an INFO reply into a map of its integer fields.
+// Other fields, e.g. added by a newer module version, are left out.
 func ParseInfoReply(values []interface{}, err error) (map[string]int64, error) {
 	if err != nil {
 		return nil, err
 	}
-	if len(values)%2 != 0 {
-		return nil, errors.New("expects even number of values result")
+	return parseStringInt64Pairs(values, nil)
+}
+
+// parseInfoInt64s is the general form of ParseInfoReply, which parseStringInt64Pairs falls back to
+func parseInfoInt64s(values []interface{}) (map[string]int64, error) {
+	fields, err := parseInfoFields(values, nil)
+	if err != nil {
+		return nil, err
 	}
-	m := make(map[string]int64, len(values)/2)
-	for i := 0; i < len(values); i += 2 {
-		m[values[i].(string)] = values[i+1].(int64)
+	m := make(map[string]int64, len(fields))
+	for name, value := range fields {
+		if converted, err := redis.Int64(value, nil); err == nil {
+			m[name] = converted
+		}
 	}
-	return m, err
+	return m, nil
 }
 
-func ParseTDigestInfo(result interface{}, err error) (info TDigestInfo, outErr error) {
-	values, outErr := redis.Values(result, err)
-	if outErr != nil {
-		return TDigestInfo{}, err
-	}
-	if len(values)%2 != 0 {
-		return TDigestInfo{}, errors.New("ParseInfo expects even number of values result")
-	}
-	var key string
-	for i := 0; i < len(values); i += 2 {
-		key, outErr = redis.String(values[i], nil)
-		switch key {
-		case "Compression":
-			info.compression, outErr = redis.Int64(values[i+1], nil)
-		case "Capacity":
-			info.capacity, outErr = redis.Int64(values[i+1], nil)
-		case "Merged nodes":
-			info.mergedNodes, outErr = redis.Int64(values[i+1], nil)
-		case "Unmerged nodes":
-			info.unmergedNodes, outErr = redis.Int64(values[i+1], nil)
-		case "Merged weight":
-			info.mergedWeight, outErr = redis.Float64(values[i+1], nil)
-		case "Unmerged weight":
-			info.unmergedWeight, outErr = redis.Float64(values[i+1], nil)
-		case "Total compressions":
-			info.totalCompressions, outErr = redis.Int64(values[i+1], nil)
+// ParseFloat64Reply converts a float reply into a float64. The "nan", "-nan", "inf" and "-inf" replies of the
+// t-digest commands, e.g. for an empty sketch, are converted to math.NaN() and math.Inf().
+func ParseFloat64Reply(reply interface{}, err error) (float64, error) {
+	if err != nil {
+		return 0, err
+	}
+	var s string
+	switch reply := reply.(type) {
+	case []byte:
+		s = string(reply)
+	case string:
+		s = reply
+	default:
+		return redis.Float64(reply, nil)
+	}
+	switch strings.ToLower(s) {
+	case "nan", "-nan", "+nan":
+		return math.NaN(), nil
+	}
+	// ParseFloat also accepts "inf", "+inf" and "-inf", in any case
+	value, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("redisbloom: unexpected float reply %q", s)
+	}
+	return value, nil
+}
+
+// ParseFloat64sReply converts either an array reply or a single bulk string reply into a slice of floats,
+// as ParseFloat64Reply does for each float
+func ParseFloat64sReply(reply interface{}, err error) ([]float64, error) {
+	if err != nil {
+		return nil, err
+	}
+	replies, ok := reply.([]interface{})
+	if !ok {
+		value, err := ParseFloat64Reply(reply, nil)
+		if err != nil {
+			return nil, err
 		}
-		if outErr != nil {
-			return TDigestInfo{}, outErr
+		return []float64{value}, nil
+	}
+	values := make([]float64, len(replies))
+	for i, reply := range replies {
+		value, err := ParseFloat64Reply(reply, nil)
+		if err != nil {
+			return nil, err
 		}
+		values[i] = value
 	}
+	return values, nil
+}
 
+// Int64sToBools converts an integer array reply where 1 stands for true into a slice of booleans
+func Int64sToBools(values []int64, err error) ([]bool, error) {
+	if err != nil {
+		return nil, err
+	}
+	result := make([]bool, len(values))
+	for i, value := range values {
+		result[i] = value == 1
+	}
+	return result, nil
+}
+
+// ParseTDigestInfo converts a TDIGEST.INFO reply into a TDigestInfo
+func ParseTDigestInfo(result interface{}, err error) (info TDigestInfo, outErr error) {
+	fields, outErr := parseInfoFields(result, err)
+	if outErr != nil {
+		return TDigestInfo{}, outErr
+	}
+	fields.int64("Compression", &info.compression)
+	fields.int64("Capacity", &info.capacity)
+	fields.int64("Merged nodes", &info.mergedNodes)
+	fields.int64("Unmerged nodes", &info.unmergedNodes)
+	fields.float64("Merged weight", &info.mergedWeight)
+	fields.float64("Unmerged weight", &info.unmergedWeight)
+	fields.int64("Total compressions", &info.totalCompressions)
+	info.extra = fields.extra()
 	return info, nil
 }
@@ -3,6 +3,7 @@ package redis_bloom_go
 import (
 	"github.com/gomodule/redigo/redis"
 	"github.com/stretchr/testify/assert"
+	"math"
 	"os"
 	"testing"
 	"time"
@@ -130,6 +131,63 @@ func TestClient_BfExistsMulti(t *testing.T) {
 	assert.Equal(t, int64(0), existsResult[2])
 }
 
+func TestClient_BfAddVariadic(t *testing.T) {
+	client.FlushAll()
+	key := "test_add_variadic"
+	ret, err := client.BfAdd(key, "a", "b")
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1, 1}, ret)
+	ret, err = client.BfExists(key, "a", "c")
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1, 0}, ret)
+}
+
+func TestClient_MaxBatchSize(t *testing.T) {
+	host, password := getTestConnectionDetails()
+	pool := &redis.Pool{Dial: func() (redis.Conn, error) {
+		return redis.Dial("tcp", host, redis.DialPassword(password))
+	}, MaxIdle: maxConns}
+	batchClient := NewClientFromPool(pool, "bloom-client-batch", WithMaxBatchSize(2))
+	defer batchClient.Pool.Close()
+	batchClient.FlushAll()
+	key := "test_max_batch_size"
+	ret, err := batchClient.BfAddMulti(key, []string{"a", "b", "c", "d", "e"})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1, 1, 1, 1, 1}, ret)
+	ret, err = batchClient.BfExistsMulti(key, []string{"a", "x", "c", "y", "e"})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1, 0, 1, 0, 1}, ret)
+
+	cmsKey := "test_max_batch_size_cms"
+	_, err = batchClient.CmsInitByDim(cmsKey, 1000, 5)
+	assert.Nil(t, err)
+	_, err = batchClient.CmsIncrByItems(cmsKey, []CmsIncrement{{"a", 1}, {"b", 2}, {"c", 3}})
+	assert.Nil(t, err)
+	counts, err := batchClient.CmsQuery(cmsKey, []string{"c", "b", "a"})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{3, 2, 1}, counts)
+
+	// a failing batch surfaces the error
+	_, err = batchClient.CmsQuery("not_exists", []string{"a", "b", "c"})
+	assert.NotNil(t, err)
+}
+
+func TestClient_BfMultiBool(t *testing.T) {
+	client.FlushAll()
+	key := "test_multi_bool"
+	ret, err := client.BfAddMultiBool(key, []string{"a", "b", "a"})
+	assert.Nil(t, err)
+	assert.Equal(t, []bool{true, true, false}, ret)
+
+	ret, err = client.BfExistsMultiBool(key, []string{"a", "notexists"})
+	assert.Nil(t, err)
+	assert.Equal(t, []bool{true, false}, ret)
+
+	ret, err = client.BfInsertBool(key, -1, -1, -1, true, false, []string{"b", "c"})
+	assert.Nil(t, err)
+	assert.Equal(t, []bool{false, true}, ret)
+}
+
 func TestClient_BfInsert(t *testing.T) {
 	client.FlushAll()
 	key := "test_bf_insert"
@@ -236,6 +294,36 @@ func TestClient_TopkQuery(t *testing.T) {
 	assert.Equal(t, map[string]int64{"A": 4, "B": 3, "E": 3}, keysWithCount)
 }
 
+func TestClient_TopkVariadic(t *testing.T) {
+	client.FlushAll()
+	key := "test_topk_variadic"
+	ret, err := client.TopkReserve(key, 10, 2000, 7, 0.925)
+	assert.Nil(t, err)
+	assert.Equal(t, "OK", ret)
+	rets, err := client.TopkAddItems(key, "test", "test1")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(rets))
+	counts, err := client.TopkCountItems(key, "test", "nonexist")
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1, 0}, counts)
+	queryRet, err := client.TopkQueryItems(key, "test1", "nonexist")
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1, 0}, queryRet)
+}
+
+func TestClient_TopkQueryBool(t *testing.T) {
+	client.FlushAll()
+	key := "test_topk_query_bool"
+	ret, err := client.TopkReserve(key, 10, 2000, 7, 0.925)
+	assert.Nil(t, err)
+	assert.Equal(t, "OK", ret)
+	_, err = client.TopkAdd(key, []string{"test"})
+	assert.Nil(t, err)
+	queryRet, err := client.TopkQueryBool(key, []string{"test", "nonexist"})
+	assert.Nil(t, err)
+	assert.Equal(t, []bool{true, false}, queryRet)
+}
+
 func TestClient_TopkInfo(t *testing.T) {
 	client.FlushAll()
 	key := "test_topk_info"
@@ -269,6 +357,22 @@ func TestClient_TopkIncrBy(t *testing.T) {
 	assert.Equal(t, "", rets[2])
 }
 
+func TestClient_TopkIncrByItems(t *testing.T) {
+	client.FlushAll()
+	key := "test_topk_incrby_items"
+	ret, err := client.TopkReserve(key, 2, 50, 3, 0.9)
+	assert.Nil(t, err)
+	assert.Equal(t, "OK", ret)
+
+	rets, err := client.TopkIncrByItems(key, []TopkIncrement{{"foo", 3}, {"bar", 2}})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"", ""}, rets)
+
+	rets, err = client.TopkIncrByItems(key, []TopkIncrement{{"foo", 1}, {"baz", 30}})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"", "bar"}, rets)
+}
+
 func TestClient_CmsInitByDim(t *testing.T) {
 	client.FlushAll()
 	ret, err := client.CmsInitByDim("test_cms_initbydim", 1000, 5)
@@ -295,6 +399,20 @@ func TestClient_CmsIncrBy(t *testing.T) {
 	assert.Equal(t, int64(5), results[0])
 }
 
+func TestClient_CmsIncrByItems(t *testing.T) {
+	client.FlushAll()
+	key := "test_cms_incrby_items"
+	ret, err := client.CmsInitByDim(key, 1000, 5)
+	assert.Nil(t, err)
+	assert.Equal(t, "OK", ret)
+	results, err := client.CmsIncrByItems(key, []CmsIncrement{{"foo", 5}, {"bar", 3}, {"baz", 9}})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{5, 3, 9}, results)
+	results, err = client.CmsIncrByItems(key, []CmsIncrement{{"baz", 1}, {"foo", 2}})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{10, 7}, results)
+}
+
 func TestClient_CmsQuery(t *testing.T) {
 	client.FlushAll()
 	key := "test_cms_query"
@@ -311,6 +429,19 @@ func TestClient_CmsQuery(t *testing.T) {
 	assert.Equal(t, int64(5), results[0])
 }
 
+func TestClient_CmsQueryItems(t *testing.T) {
+	client.FlushAll()
+	key := "test_cms_query_items"
+	ret, err := client.CmsInitByDim(key, 1000, 5)
+	assert.Nil(t, err)
+	assert.Equal(t, "OK", ret)
+	_, err = client.CmsIncrByItems(key, []CmsIncrement{{"foo", 5}})
+	assert.Nil(t, err)
+	results, err := client.CmsQueryItems(key, "foo", "bar")
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{5, 0}, results)
+}
+
 func TestClient_CmsMerge(t *testing.T) {
 	client.FlushAll()
 	ret, err := client.CmsInitByDim("A", 1000, 5)
@@ -392,6 +523,23 @@ func TestClient_CfReserve(t *testing.T) {
 	assert.Equal(t, "OK", ret)
 }
 
+func TestClient_CfReserveWithOptions(t *testing.T) {
+	client.FlushAll()
+	key := "test_cf_reserve_options"
+	ret, err := client.CfReserveWithOptions(key, 1000, WithBucketSize(4), WithMaxIterations(20), WithExpansion(2))
+	assert.Nil(t, err)
+	assert.Equal(t, "OK", ret)
+	info, err := client.CfInfo(key)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(4), info["Bucket size"])
+	assert.Equal(t, int64(20), info["Max iterations"])
+	assert.Equal(t, int64(2), info["Expansion rate"])
+
+	ret, err = client.CfReserveWithOptions(key+"_default", 1000)
+	assert.Nil(t, err)
+	assert.Equal(t, "OK", ret)
+}
+
 func TestClient_CfAdd(t *testing.T) {
 	client.FlushAll()
 	key := "test_cf_add"
@@ -416,6 +564,49 @@ func TestClient_CfInsert(t *testing.T) {
 	assert.True(t, ret[0] > 0)
 }
 
+func TestClient_CfInsertWithOptions(t *testing.T) {
+	client.FlushAll()
+	key := "test_cf_insert_options"
+	ret, err := client.CfInsertWithOptions(key, []string{"a"}, WithCapacity(1000))
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1}, ret)
+	ret, err = client.CfInsertNxWithOptions(key, []string{"a", "b"}, WithNoCreate())
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{0, 1}, ret)
+	_, err = client.CfInsertWithOptions(key+"_missing", []string{"a"}, WithNoCreate())
+	assert.NotNil(t, err)
+}
+
+func TestClient_BfInsertWithOptions(t *testing.T) {
+	client.FlushAll()
+	key := "test_bf_insert_options"
+	ret, err := client.BfInsertWithOptions(key, []string{"a", "b"}, WithCapacity(1000), WithErrorRate(0.01), WithNonScaling())
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1, 1}, ret)
+	info, err := client.Info(key)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1000), info["Capacity"])
+	_, err = client.BfInsertWithOptions(key+"_missing", []string{"a"}, WithNoCreate())
+	assert.NotNil(t, err)
+}
+
+func TestClient_CfInsertBool(t *testing.T) {
+	client.FlushAll()
+	key := "test_cf_insert_bool"
+	ret, err := client.CfInsertBool(key, 1000, false, []string{"a", "b"})
+	assert.Nil(t, err)
+	assert.Equal(t, []bool{true, true}, ret)
+}
+
+func TestClient_CfInsertBool_Full(t *testing.T) {
+	conn := &fakeConn{replies: []interface{}{[]interface{}{int64(1), int64(-1)}}}
+	c := NewClientFromPool(nil, "test")
+	c.Pool = &fakePool{conn: conn}
+	ret, err := c.CfInsertBool("cf", 0, false, []string{"a", "b"})
+	assert.Equal(t, ErrFilterFull, err)
+	assert.Equal(t, []bool{true, false}, ret)
+}
+
 func TestClient_CfExists(t *testing.T) {
 	client.FlushAll()
 	key := "test_cf_exists"
@@ -427,6 +618,16 @@ func TestClient_CfExists(t *testing.T) {
 	assert.True(t, ret)
 }
 
+func TestClient_CfExistsMulti(t *testing.T) {
+	client.FlushAll()
+	key := "test_cf_exists_multi"
+	_, err := client.CfAdd(key, "a")
+	assert.Nil(t, err)
+	ret, err := client.CfExistsMulti(key, "a", "b")
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1, 0}, ret)
+}
+
 func TestClient_CfDel(t *testing.T) {
 	client.FlushAll()
 	key := "test_cf_del"
@@ -563,6 +764,73 @@ func TestClient_TdReset(t *testing.T) {
 	assert.Equal(t, int64(610), info.Capacity())
 }
 
+func TestClient_ModuleVersion(t *testing.T) {
+	version, err := client.ModuleVersion()
+	assert.Nil(t, err)
+	assert.Less(t, int64(0), version)
+	cached, err := client.ModuleVersion()
+	assert.Nil(t, err)
+	assert.Equal(t, version, cached)
+}
+
+func TestClient_ModuleVersionFailure(t *testing.T) {
+	denied := redis.Error("NOPERM this user has no permissions to run the 'module' command")
+	conn := &argsConn{fakeConn: &fakeConn{replies: []interface{}{denied, "OK"}}}
+	c := NewClientFromPool(nil, "test")
+	c.Pool = &fakePool{conn: conn}
+	// the failure is returned rather than guessing the legacy syntax, and cached
+	_, err := c.TdAddValues("td", 1, 2)
+	assert.Equal(t, denied, err)
+	_, err = c.TdAddValues("td", 1, 2)
+	assert.Equal(t, denied, err)
+	assert.Equal(t, []string{"MODULE"}, conn.commands)
+
+	c = NewClientFromPool(nil, "test", WithTDigestSyntax(TDigestSyntaxModern))
+	c.Pool = &fakePool{conn: conn}
+	ret, err := c.TdAddValues("td", 1, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, "OK", ret)
+	assert.Equal(t, []interface{}{"td", 1.0, 2.0}, conn.args[1])
+}
+
+func TestClient_TdAddValues(t *testing.T) {
+	client.FlushAll()
+	key := "test_td_add_values"
+	ret, err := client.TdCreate(key, 100)
+	assert.Nil(t, err)
+	assert.Equal(t, "OK", ret)
+
+	ret, err = client.TdAddValues(key, 1.0, 2.0, 3.0)
+	assert.Nil(t, err)
+	assert.Equal(t, "OK", ret)
+
+	info, err := client.TdInfo(key)
+	assert.Nil(t, err)
+	assert.Equal(t, 3.0, info.UnmergedWeight()+info.MergedWeight())
+
+	ans, err := client.TdMax(key)
+	assert.Nil(t, err)
+	assert.Equal(t, 3.0, ans)
+}
+
+func TestClient_TdCreateWithOptions(t *testing.T) {
+	client.FlushAll()
+	key := "test_td_create"
+	ret, err := client.TdCreateDefault(key)
+	assert.Nil(t, err)
+	assert.Equal(t, "OK", ret)
+	info, err := client.TdInfo(key)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(100), info.Compression())
+
+	ret, err = client.TdCreateWithOptions(key+"_compression", WithCompression(200))
+	assert.Nil(t, err)
+	assert.Equal(t, "OK", ret)
+	info, err = client.TdInfo(key + "_compression")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(200), info.Compression())
+}
+
 func TestClient_TdMerge(t *testing.T) {
 	key1 := "toKey"
 	key2 := "fromKey"
@@ -638,6 +906,124 @@ func TestClient_TdQuantile(t *testing.T) {
 	assert.Equal(t, 1.0, ans)
 }
 
+func TestClient_TdQuantiles(t *testing.T) {
+	client.FlushAll()
+	key := "test_td"
+	ret, err := client.TdCreate(key, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, "OK", ret)
+
+	ret, err = client.TdAddValues(key, 1.0, 2.0, 3.0)
+	assert.Nil(t, err)
+	assert.Equal(t, "OK", ret)
+
+	ans, err := client.TdQuantiles(key, 1.0, 0.0)
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{3.0, 1.0}, ans)
+
+	_, err = client.TdQuantiles(key)
+	assert.NotNil(t, err)
+}
+
+func TestClient_TdByRank(t *testing.T) {
+	client.FlushAll()
+	key := "test_td_byrank"
+	if modern, _ := client.isModernTDigest(); !modern {
+		t.Skip("TDIGEST.BYRANK requires RedisBloom 2.4")
+	}
+	ret, err := client.TdCreate(key, 100)
+	assert.Nil(t, err)
+	assert.Equal(t, "OK", ret)
+	ret, err = client.TdAddValues(key, 1.0, 2.0, 3.0)
+	assert.Nil(t, err)
+	assert.Equal(t, "OK", ret)
+
+	ans, err := client.TdByRank(key, 0, 2, 3)
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{1.0, 3.0, math.Inf(1)}, ans)
+
+	ans, err = client.TdByRevRank(key, 0, 2, 3)
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{3.0, 1.0, math.Inf(-1)}, ans)
+}
+
+func TestInt64sToBools(t *testing.T) {
+	ret, err := Int64sToBools([]int64{1, 0, -1}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []bool{true, false, false}, ret)
+	_, err = Int64sToBools(nil, redis.Error("ERR"))
+	assert.NotNil(t, err)
+}
+
+func TestParseFloat64sReply(t *testing.T) {
+	values, err := ParseFloat64sReply([]byte("1.5"), nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{1.5}, values)
+	values, err = ParseFloat64sReply([]interface{}{[]byte("1"), []byte("2.5")}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{1, 2.5}, values)
+	_, err = ParseFloat64sReply(nil, redis.Error("ERR"))
+	assert.NotNil(t, err)
+}
+
+func TestParseFloat64Reply(t *testing.T) {
+	value, err := ParseFloat64Reply([]byte("2.5"), nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 2.5, value)
+	for _, reply := range []string{"nan", "-nan", "NaN"} {
+		value, err = ParseFloat64Reply([]byte(reply), nil)
+		assert.Nil(t, err)
+		assert.True(t, math.IsNaN(value))
+	}
+	value, err = ParseFloat64Reply("inf", nil)
+	assert.Nil(t, err)
+	assert.True(t, math.IsInf(value, 1))
+	value, err = ParseFloat64Reply([]byte("-inf"), nil)
+	assert.Nil(t, err)
+	assert.True(t, math.IsInf(value, -1))
+	_, err = ParseFloat64Reply(nil, nil)
+	assert.Equal(t, redis.ErrNil, err)
+	_, err = ParseFloat64Reply([]byte("garbage"), nil)
+	assert.NotNil(t, err)
+	_, err = ParseFloat64Reply(nil, redis.Error("ERR"))
+	assert.Equal(t, redis.Error("ERR"), err)
+
+	values, err := ParseFloat64sReply([]interface{}{[]byte("inf"), []byte("-nan")}, nil)
+	assert.Nil(t, err)
+	assert.True(t, math.IsInf(values[0], 1))
+	assert.True(t, math.IsNaN(values[1]))
+}
+
+func TestClient_TdEmptySketch(t *testing.T) {
+	conn := &fakeConn{replies: []interface{}{
+		[]byte("nan"), []byte("1.7976931348623157e+308"), []byte("2"),
+		[]byte("-nan"), []byte("-1.7976931348623157e+308"),
+		[]interface{}{[]byte("nan")}, []interface{}{[]byte("inf"), []byte("-inf")},
+	}}
+	c := NewClientFromPool(nil, "test")
+	c.Pool = &fakePool{conn: conn}
+	for i := 0; i < 2; i++ {
+		min, err := c.TdMin("td")
+		assert.Equal(t, ErrEmptySketch, err)
+		assert.True(t, math.IsNaN(min))
+	}
+	min, err := c.TdMin("td")
+	assert.Nil(t, err)
+	assert.Equal(t, 2.0, min)
+	for i := 0; i < 2; i++ {
+		max, err := c.TdMax("td")
+		assert.Equal(t, ErrEmptySketch, err)
+		assert.True(t, math.IsNaN(max))
+	}
+	cdf, err := c.TdCdf("td", 1)
+	assert.Nil(t, err)
+	assert.True(t, math.IsNaN(cdf))
+	values, err := c.TdByRank("td", 10, 11)
+	assert.Nil(t, err)
+	assert.True(t, math.IsInf(values[0], 1))
+	assert.True(t, math.IsInf(values[1], -1))
+}
+
 func TestClient_TdCdf(t *testing.T) {
 	client.FlushAll()
 	key := "test_td"
@@ -658,3 +1044,40 @@ func TestClient_TdCdf(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, 0.0, ans)
 }
+
+func TestClient_BfExistsManyKeys(t *testing.T) {
+	client.FlushAll()
+	_, err := client.BfAddMulti("test_exists_many_a", []string{"x", "y"})
+	assert.Nil(t, err)
+	_, err = client.BfAddMulti("test_exists_many_b", []string{"z"})
+	assert.Nil(t, err)
+	exists, err := client.BfExistsManyKeys(map[string][]string{
+		"test_exists_many_a": {"x", "z"},
+		"test_exists_many_b": {"x", "z"},
+		"test_exists_many_c": {"x"},
+		"test_exists_many_d": {},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string][]bool{
+		"test_exists_many_a": {true, false},
+		"test_exists_many_b": {false, true},
+		"test_exists_many_c": {false},
+		"test_exists_many_d": {},
+	}, exists)
+}
+
+func TestClient_BfExistsManyKeys_Pipelined(t *testing.T) {
+	conn := &fakeConn{replies: []interface{}{[]interface{}{int64(1), int64(0)}}}
+	c := NewClientFromPool(nil, "test")
+	c.Pool = &fakePool{conn: conn}
+	exists, err := c.BfExistsManyKeys(map[string][]string{"a": {"x", "y"}, "b": nil})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string][]bool{"a": {true, false}, "b": {}}, exists)
+	assert.Equal(t, []string{"BF.MEXISTS"}, conn.commands)
+
+	conn = &fakeConn{replies: []interface{}{redis.Error("ERR boom"), redis.Error("ERR boom")}}
+	c.Pool = &fakePool{conn: conn}
+	_, err = c.BfExistsManyKeys(map[string][]string{"a": {"x"}, "b": {"y"}})
+	assert.Equal(t, redis.Error("ERR boom"), err)
+	assert.Equal(t, 0, conn.pending)
+}
@@ -0,0 +1,108 @@
+package redis_bloom_go
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// clusterSlots is the number of hash slots of a Redis Cluster
+const clusterSlots = 16384
+
+// CrossSlotError is returned when the keys of a multi-key command, such as CMS.MERGE or TDIGEST.MERGE,
+// map to different cluster slots
+type CrossSlotError struct {
+	// Keys are the keys of the command, as sent to the server
+	Keys []string
+	// Slots are the slots of Keys
+	Slots []int
+	// Err is the error returned by the server, nil if the keys were checked by the client
+	Err error
+}
+
+func (e *CrossSlotError) Error() string {
+	pairs := make([]string, len(e.Keys))
+	for i, key := range e.Keys {
+		pairs[i] = fmt.Sprintf("%s (slot %d)", key, e.Slots[i])
+	}
+	return fmt.Sprintf("redisbloom: keys %s map to different cluster slots; make them share a hash tag, "+
+		"e.g. with HashTagKeys, so that a Redis Cluster stores them on the same node", strings.Join(pairs, ", "))
+}
+
+// Unwrap returns the error returned by the server
+func (e *CrossSlotError) Unwrap() error {
+	return e.Err
+}
+
+// KeySlot returns the Redis Cluster hash slot of key. When key contains a non-empty hash tag, i.e. a
+// substring between the first '{' and the next '}', only the hash tag is hashed.
+func KeySlot(key string) int {
+	if start := strings.IndexByte(key, '{'); start >= 0 {
+		if end := strings.IndexByte(key[start+1:], '}'); end > 0 {
+			key = key[start+1 : start+1+end]
+		}
+	}
+	return int(crc16(key) % clusterSlots)
+}
+
+// keySlots returns the slots of keys
+func keySlots(keys []string) []int {
+	slots := make([]int, len(keys))
+	for i, key := range keys {
+		slots[i] = KeySlot(key)
+	}
+	return slots
+}
+
+// crc16 is the CRC16-CCITT (XMODEM) checksum Redis Cluster hashes keys with
+func crc16(data string) uint16 {
+	crc := uint16(0)
+	for i := 0; i < len(data); i++ {
+		crc ^= uint16(data[i]) << 8
+		for bit := 0; bit < 8; bit++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// HashTagKeys returns keys prefixed with the hash tag {tag}:, so that they all map to the slot of tag
+func HashTagKeys(tag string, keys ...string) []string {
+	tagged := make([]string, len(keys))
+	for i, key := range keys {
+		tagged[i] = "{" + tag + "}:" + key
+	}
+	return tagged
+}
+
+// CheckSameSlot - Returns a *CrossSlotError if keys, once the key prefix of the client is applied,
+// do not all map to the same cluster slot, and nil otherwise. Use it to validate the keys of CmsMerge,
+// TdMerge or any other multi-key operation before running it on a Redis Cluster.
+func (client *Client) CheckSameSlot(keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	prefixed := client.keys(keys)
+	slots := keySlots(prefixed)
+	for _, slot := range slots[1:] {
+		if slot != slots[0] {
+			return &CrossSlotError{Keys: prefixed, Slots: slots}
+		}
+	}
+	return nil
+}
+
+// crossSlotError converts the CROSSSLOT error the server returns for a multi-key command on keys
+// into a *CrossSlotError, and returns other errors unchanged
+func (client *Client) crossSlotError(keys []string, err error) error {
+	if redisErr, ok := err.(redis.Error); !ok || !strings.HasPrefix(string(redisErr), "CROSSSLOT") {
+		return err
+	}
+	prefixed := client.keys(keys)
+	return &CrossSlotError{Keys: prefixed, Slots: keySlots(prefixed), Err: err}
+}
@@ -0,0 +1,58 @@
+package redis_bloom_go
+
+import (
+	"testing"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeySlot(t *testing.T) {
+	assert.Equal(t, 12739, KeySlot("123456789"))
+	assert.Equal(t, 12182, KeySlot("foo"))
+	assert.Equal(t, KeySlot("{user1000}.following"), KeySlot("{user1000}.followers"))
+	assert.Equal(t, KeySlot("bar"), KeySlot("foo{bar}{zap}"))
+	assert.Equal(t, KeySlot("{bar"), KeySlot("foo{{bar}}zap"))
+	// an empty hash tag hashes the whole key
+	assert.NotEqual(t, KeySlot(""), KeySlot("foo{}{bar}"))
+}
+
+func TestHashTagKeys(t *testing.T) {
+	keys := HashTagKeys("daily", "cms:mon", "cms:tue")
+	assert.Equal(t, []string{"{daily}:cms:mon", "{daily}:cms:tue"}, keys)
+	assert.Equal(t, KeySlot(keys[0]), KeySlot(keys[1]))
+}
+
+func TestClient_CheckSameSlot(t *testing.T) {
+	c := NewClientFromPool(nil, "test", WithKeyPrefix("app:"))
+	assert.Nil(t, c.CheckSameSlot())
+	assert.Nil(t, c.CheckSameSlot(HashTagKeys("daily", "a", "b")...))
+
+	err := c.CheckSameSlot("foo", "bar")
+	crossSlot, ok := err.(*CrossSlotError)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"app:foo", "app:bar"}, crossSlot.Keys)
+	assert.Equal(t, []int{KeySlot("app:foo"), KeySlot("app:bar")}, crossSlot.Slots)
+	assert.Nil(t, crossSlot.Err)
+	assert.Contains(t, err.Error(), "app:foo (slot")
+
+	// a hash tag in the prefix puts every key of the client in the same slot
+	tagged := NewClientFromPool(nil, "test", WithKeyPrefix("{app}:"))
+	assert.Nil(t, tagged.CheckSameSlot("foo", "bar"))
+}
+
+func TestClient_CmsMerge_CrossSlot(t *testing.T) {
+	serverErr := redis.Error("CROSSSLOT Keys in request don't hash to the same slot")
+	conn := &fakeConn{replies: []interface{}{serverErr, redis.Error("ERR T-Digest: key does not exist")}}
+	c := NewClientFromPool(nil, "test")
+	c.Pool = &fakePool{conn: conn}
+
+	_, err := c.CmsMerge("dest", []string{"a", "b"}, nil)
+	crossSlot, ok := err.(*CrossSlotError)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"dest", "a", "b"}, crossSlot.Keys)
+	assert.Equal(t, serverErr, crossSlot.Unwrap())
+
+	_, err = c.TdMerge("to", "from")
+	assert.Equal(t, redis.Error("ERR T-Digest: key does not exist"), err)
+}
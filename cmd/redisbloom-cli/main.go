@@ -0,0 +1,292 @@
+// Command redisbloom-cli inspects, backs up, restores, copies and sizes RedisBloom data structures.
+//
+// Usage:
+//
+//	redisbloom-cli [-url redis://host:port/db] [-prefix prefix] <command> [arguments]
+//
+// Commands:
+//
+//	info <key>                          shows the type, memory and module information of a key
+//	backup [-pattern p] <file>          writes the keys matching the pattern to file, - for stdout
+//	restore <file>                      restores the keys of a backup file, - for stdin
+//	copy [-pattern p] <destination url> copies the keys matching the pattern to another server
+//	ingest [flags] <key> <file>         adds the items of a file to a key, - for stdin
+//	plan bloom <capacity> <error rate>  sizes a bloom filter
+//	plan cms <error rate> <probability> sizes a count-min sketch
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+
+	redisbloom "github.com/mohit-doubtnut/redisbloom-go"
+)
+
+const usage = `usage: redisbloom-cli [-url redis://host:port/db] [-prefix prefix] <command> [arguments]
+
+commands:
+  info <key>                          shows the type, memory and module information of a key
+  backup [-pattern p] <file>          writes the keys matching the pattern to file, - for stdout
+  restore <file>                      restores the keys of a backup file, - for stdin
+  copy [-pattern p] <destination url> copies the keys matching the pattern to another server
+  ingest [flags] <key> <file>         adds the items of a file to a key, - for stdin
+      -type bloom|cuckoo|cms|topk -format lines|csv|jsonl -field f -count-field f -offset n
+  plan bloom <capacity> <error rate>  sizes a bloom filter
+  plan cms <error rate> <probability> sizes a count-min sketch
+`
+
+var errUsage = errors.New("invalid arguments")
+
+func main() {
+	flags := flag.NewFlagSet("redisbloom-cli", flag.ExitOnError)
+	flags.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	url := flags.String("url", "redis://localhost:6379", "URL of the server")
+	prefix := flags.String("prefix", "", "prefix of the keys")
+	flags.Parse(os.Args[1:])
+
+	err := run(*url, *prefix, flags.Args(), os.Stdout)
+	if err == errUsage {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "redisbloom-cli:", err)
+		os.Exit(1)
+	}
+}
+
+func run(url string, prefix string, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+	if args[0] == "plan" {
+		return plan(args[1:], out)
+	}
+	client, err := newClient(url, prefix)
+	if err != nil {
+		return err
+	}
+	defer client.Pool.Close()
+	switch args[0] {
+	case "info":
+		return info(client, args[1:], out)
+	case "backup":
+		return backup(client, args[1:], out)
+	case "restore":
+		return restore(client, args[1:], out)
+	case "copy":
+		return copyKeys(client, prefix, args[1:], out)
+	case "ingest":
+		return ingest(client, args[1:], out)
+	}
+	return errUsage
+}
+
+func newClient(url string, prefix string) (*redisbloom.Client, error) {
+	return redisbloom.NewClientFromURL(url, "redisbloom-cli", redisbloom.WithKeyPrefix(prefix))
+}
+
+func info(client *redisbloom.Client, args []string, out io.Writer) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	key := args[0]
+	report, err := client.MemoryUsage(key)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "type: %s\nmemory usage: %d bytes\n", report.Type, report.MemoryUsage)
+	var fields map[string]string
+	switch report.Type {
+	case redisbloom.DataTypeBloom:
+		fields, err = int64Fields(client.Info(key))
+	case redisbloom.DataTypeCuckoo:
+		fields, err = int64Fields(client.CfInfo(key))
+	case redisbloom.DataTypeCMS:
+		fields, err = int64Fields(client.CmsInfo(key))
+	case redisbloom.DataTypeTopK:
+		fields, err = client.TopkInfo(key)
+	case redisbloom.DataTypeTDigest:
+		var td redisbloom.TDigestInfo
+		td, err = client.TdInfo(key)
+		fields = map[string]string{
+			"Compression":    strconv.FormatInt(td.Compression(), 10),
+			"Capacity":       strconv.FormatInt(td.Capacity(), 10),
+			"Merged nodes":   strconv.FormatInt(td.MergedNodes(), 10),
+			"Unmerged nodes": strconv.FormatInt(td.UnmergedNodes(), 10),
+		}
+	}
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(out, "%s: %s\n", name, fields[name])
+	}
+	return nil
+}
+
+func int64Fields(values map[string]int64, err error) (map[string]string, error) {
+	if err != nil {
+		return nil, err
+	}
+	fields := make(map[string]string, len(values))
+	for name, value := range values {
+		fields[name] = strconv.FormatInt(value, 10)
+	}
+	return fields, nil
+}
+
+func backup(client *redisbloom.Client, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("backup", flag.ContinueOnError)
+	pattern := flags.String("pattern", "*", "pattern of the keys to back up")
+	if err := flags.Parse(args); err != nil || flags.NArg() != 1 {
+		return errUsage
+	}
+	w := out
+	path := flags.Arg(0)
+	if path != "-" {
+		file, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+	n, err := client.Backup(context.Background(), w, *pattern)
+	if err != nil {
+		return err
+	}
+	if path != "-" {
+		fmt.Fprintf(out, "backed up %d keys to %s\n", n, path)
+	}
+	return nil
+}
+
+func restore(client *redisbloom.Client, args []string, out io.Writer) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	var r io.Reader = os.Stdin
+	if args[0] != "-" {
+		file, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		r = file
+	}
+	n, err := client.Restore(context.Background(), r)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "restored %d keys\n", n)
+	return nil
+}
+
+func copyKeys(client *redisbloom.Client, prefix string, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("copy", flag.ContinueOnError)
+	pattern := flags.String("pattern", "*", "pattern of the keys to copy")
+	if err := flags.Parse(args); err != nil || flags.NArg() != 1 {
+		return errUsage
+	}
+	dst, err := newClient(flags.Arg(0), prefix)
+	if err != nil {
+		return err
+	}
+	defer dst.Pool.Close()
+	n, err := redisbloom.CopyAll(client, dst, *pattern, func(progress redisbloom.CopyProgress) {
+		if progress.Done {
+			fmt.Fprintf(out, "copied %s (%d bytes)\n", progress.Key, progress.Bytes)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "copied %d keys\n", n)
+	return nil
+}
+
+func ingest(client *redisbloom.Client, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("ingest", flag.ContinueOnError)
+	dataType := flags.String("type", "bloom", "data structure of the key: bloom, cuckoo, cms or topk")
+	format := flags.String("format", "lines", "format of the file: lines, csv or jsonl")
+	field := flags.String("field", "", "CSV column or JSON field holding the items")
+	countField := flags.String("count-field", "", "CSV column or JSON field holding the increments")
+	offset := flags.Int64("offset", 0, "number of records to skip, to resume an interrupted ingest")
+	if err := flags.Parse(args); err != nil || flags.NArg() != 2 {
+		return errUsage
+	}
+	var r io.Reader = os.Stdin
+	if path := flags.Arg(1); path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		r = file
+	}
+	n, err := client.Ingest(context.Background(), flags.Arg(0), r, redisbloom.IngestOptions{
+		Type:       redisbloom.DataType(*dataType),
+		Format:     redisbloom.IngestFormat(*format),
+		Field:      *field,
+		CountField: *countField,
+		Offset:     *offset,
+	})
+	if err != nil {
+		return fmt.Errorf("%v (resume with -offset %d)", err, n)
+	}
+	fmt.Fprintf(out, "ingested %d records\n", n-*offset)
+	return nil
+}
+
+func plan(args []string, out io.Writer) error {
+	if len(args) != 3 {
+		return errUsage
+	}
+	switch args[0] {
+	case "bloom":
+		capacity, err := strconv.ParseUint(args[1], 10, 64)
+		if err != nil {
+			return err
+		}
+		errorRate, err := strconv.ParseFloat(args[2], 64)
+		if err != nil {
+			return err
+		}
+		p, err := redisbloom.PlanBloom(capacity, errorRate)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "capacity: %d\nerror rate: %g\nbits per item: %.2f\nbits: %d\nmemory: %d bytes\nhash functions: %d\n",
+			p.Capacity, p.ErrorRate, p.BitsPerItem, p.Bits, p.MemoryBytes, p.HashFunctions)
+		return nil
+	case "cms":
+		errorRate, err := strconv.ParseFloat(args[1], 64)
+		if err != nil {
+			return err
+		}
+		probability, err := strconv.ParseFloat(args[2], 64)
+		if err != nil {
+			return err
+		}
+		p, err := redisbloom.PlanCMS(errorRate, probability)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "error rate: %g\nprobability: %g\nwidth: %d\ndepth: %d\nmemory: %d bytes\n",
+			p.ErrorRate, p.Probability, p.Width, p.Depth, p.MemoryBytes)
+		return nil
+	}
+	return errUsage
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun_Plan(t *testing.T) {
+	var out bytes.Buffer
+	assert.Nil(t, run("", "", []string{"plan", "bloom", "1000", "0.01"}, &out))
+	assert.True(t, strings.Contains(out.String(), "hash functions: 7"))
+
+	out.Reset()
+	assert.Nil(t, run("", "", []string{"plan", "cms", "0.001", "0.01"}, &out))
+	assert.True(t, strings.Contains(out.String(), "width: 2000"))
+
+	assert.Equal(t, errUsage, run("", "", []string{"plan", "bloom", "1000"}, &out))
+	assert.NotNil(t, run("", "", []string{"plan", "bloom", "x", "0.01"}, &out))
+	assert.Equal(t, errUsage, run("", "", nil, &out))
+}
+
+func TestRun_IngestUsage(t *testing.T) {
+	var out bytes.Buffer
+	assert.Equal(t, errUsage, run("redis://localhost:6379", "", []string{"ingest", "key"}, &out))
+	assert.Equal(t, errUsage, run("redis://localhost:6379", "", []string{"ingest", "-offset", "x", "key", "-"}, &out))
+}
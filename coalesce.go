@@ -0,0 +1,85 @@
+package redis_bloom_go
+
+import (
+	"errors"
+	"strings"
+	"sync"
+)
+
+// errFlightPanicked is returned to the callers which waited for a lookup that panicked
+var errFlightPanicked = errors.New("redisbloom: coalesced lookup panicked")
+
+// flightGroup deduplicates concurrent identical lookups: while a lookup is in flight,
+// callers asking for the same one wait for it and share its result
+type flightGroup struct {
+	mutex   sync.Mutex
+	flights map[string]*flight
+}
+
+type flight struct {
+	wg    sync.WaitGroup
+	value interface{}
+	err   error
+}
+
+func newFlightGroup() *flightGroup {
+	return &flightGroup{flights: make(map[string]*flight)}
+}
+
+// do runs fn unless a call with the same id is in flight, in which case it waits for that call's result.
+// If fn panics, the panic goes on in the caller which ran it, and the waiting callers get errFlightPanicked.
+func (g *flightGroup) do(id string, fn func() (interface{}, error)) (interface{}, error) {
+	g.mutex.Lock()
+	if f, ok := g.flights[id]; ok {
+		g.mutex.Unlock()
+		f.wg.Wait()
+		return f.value, f.err
+	}
+	f := &flight{}
+	f.wg.Add(1)
+	g.flights[id] = f
+	g.mutex.Unlock()
+
+	f.err = errFlightPanicked
+	defer func() {
+		g.mutex.Lock()
+		delete(g.flights, id)
+		g.mutex.Unlock()
+		f.wg.Done()
+	}()
+	f.value, f.err = fn()
+	return f.value, f.err
+}
+
+// flightID identifies a lookup by command, prefixed key and items
+func flightID(command string, key string, items ...string) string {
+	return command + "\x00" + key + "\x00" + strings.Join(items, "\x00")
+}
+
+// coalesceBool runs a lookup returning a boolean, sharing it with identical concurrent lookups when coalescing is enabled
+func (client *Client) coalesceBool(command string, key string, item string, fn func() (bool, error)) (bool, error) {
+	if client.flights == nil {
+		return fn()
+	}
+	value, err := client.flights.do(flightID(command, key, item), func() (interface{}, error) {
+		return fn()
+	})
+	exists, _ := value.(bool)
+	return exists, err
+}
+
+// coalesceInt64s runs a lookup returning integers, sharing it with identical concurrent lookups when coalescing
+// is enabled. Every caller gets its own copy of the result.
+func (client *Client) coalesceInt64s(command string, key string, items []string, fn func() ([]int64, error)) ([]int64, error) {
+	if client.flights == nil {
+		return fn()
+	}
+	value, err := client.flights.do(flightID(command, key, items...), func() (interface{}, error) {
+		return fn()
+	})
+	shared, _ := value.([]int64)
+	if shared == nil {
+		return nil, err
+	}
+	return append([]int64(nil), shared...), err
+}
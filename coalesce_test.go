@@ -0,0 +1,86 @@
+package redis_bloom_go
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlightGroup(t *testing.T) {
+	g := newFlightGroup()
+	release := make(chan struct{})
+	started := make(chan struct{})
+	var calls int32
+	fn := func() (interface{}, error) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			close(started)
+		}
+		<-release
+		return true, nil
+	}
+
+	var wg sync.WaitGroup
+	results := make([]interface{}, 10)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		results[0], _ = g.do("id", fn)
+	}()
+	<-started
+	for i := 1; i < len(results); i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = g.do("id", fn)
+		}(i)
+	}
+	// give the other callers time to join the flight before releasing it
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	for _, result := range results {
+		assert.Equal(t, true, result)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	assert.Equal(t, 0, len(g.flights))
+
+	value, err := g.do("id", func() (interface{}, error) { return false, nil })
+	assert.Nil(t, err)
+	assert.Equal(t, false, value)
+}
+
+func TestFlightGroup_Panic(t *testing.T) {
+	g := newFlightGroup()
+	started := make(chan struct{})
+	var waiterErr error
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		<-started
+		_, waiterErr = g.do("id", func() (interface{}, error) { return true, nil })
+	}()
+	assert.Panics(t, func() {
+		g.do("id", func() (interface{}, error) {
+			close(started)
+			// give the other caller time to join the flight
+			time.Sleep(50 * time.Millisecond)
+			panic("boom")
+		})
+	})
+	<-done
+	assert.Equal(t, errFlightPanicked, waiterErr)
+	assert.Equal(t, 0, len(g.flights))
+
+	// the flight is gone, so the next caller runs its own lookup
+	value, err := g.do("id", func() (interface{}, error) { return false, nil })
+	assert.Nil(t, err)
+	assert.Equal(t, false, value)
+}
+
+func TestFlightID(t *testing.T) {
+	assert.NotEqual(t, flightID("BF.EXISTS", "k", "a"), flightID("CF.EXISTS", "k", "a"))
+	assert.NotEqual(t, flightID("CMS.QUERY", "k", "a", "b"), flightID("CMS.QUERY", "k", "ab"))
+}
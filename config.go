@@ -0,0 +1,301 @@
+package redis_bloom_go
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"gopkg.in/yaml.v3"
+)
+
+// ClientConfig describes a client, its connections and the options it is created with by NewClientFromConfig.
+// It can be read from YAML or JSON with LoadClientConfig, and from environment variables with ApplyEnv,
+// durations being written like "250ms" or "1m".
+type ClientConfig struct {
+	// Address is the host:port of the server, or a redis:// or rediss:// URL. It must be a rediss:// URL,
+	// or a host:port, when TLS is enabled.
+	Address string `yaml:"address"`
+	// Name is the name of the client, also set on its connections with CLIENT SETNAME
+	Name     string `yaml:"name"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	Database int    `yaml:"database"`
+	// KeyPrefix is prepended to every key, see WithKeyPrefix
+	KeyPrefix string         `yaml:"key_prefix"`
+	TLS       TLSConfig      `yaml:"tls"`
+	Pool      PoolConfig     `yaml:"pool"`
+	Timeouts  TimeoutsConfig `yaml:"timeouts"`
+	// Retry enables WithRetry when MaxAttempts is above 1
+	Retry RetryConfig `yaml:"retry"`
+}
+
+// TLSConfig describes the TLS connections of a ClientConfig
+type TLSConfig struct {
+	Enabled    bool   `yaml:"enabled"`
+	ServerName string `yaml:"server_name"`
+	// CAFile is a PEM file of the certificate authorities trusted instead of those of the system
+	CAFile string `yaml:"ca_file"`
+	// CertFile and KeyFile are the PEM files of the client certificate, for mutual TLS
+	CertFile           string `yaml:"cert_file"`
+	KeyFile            string `yaml:"key_file"`
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify"`
+}
+
+// PoolConfig describes the connection pool of a ClientConfig, see redis.Pool
+type PoolConfig struct {
+	// MaxIdle is 500 by default, like the pools of NewClient
+	MaxIdle     int           `yaml:"max_idle"`
+	MaxActive   int           `yaml:"max_active"`
+	IdleTimeout time.Duration `yaml:"idle_timeout"`
+	// Wait makes Get wait for a connection once MaxActive are in use, instead of failing
+	Wait bool `yaml:"wait"`
+}
+
+// TimeoutsConfig describes the timeouts of a ClientConfig
+type TimeoutsConfig struct {
+	Connect time.Duration `yaml:"connect"`
+	Read    time.Duration `yaml:"read"`
+	Write   time.Duration `yaml:"write"`
+	// Commands are the timeouts of WithCommandTimeouts, e.g. TDIGEST.MERGE: 5s. In an environment variable,
+	// they are written as a comma separated list, e.g. "TDIGEST.MERGE=5s,CMS=1s".
+	Commands map[string]time.Duration `yaml:"commands"`
+}
+
+// RetryConfig describes the RetryPolicy of a ClientConfig
+type RetryConfig struct {
+	MaxAttempts int           `yaml:"max_attempts"`
+	BaseDelay   time.Duration `yaml:"base_delay"`
+	MaxDelay    time.Duration `yaml:"max_delay"`
+	RetryWrites bool          `yaml:"retry_writes"`
+}
+
+// LoadClientConfig - Reads a ClientConfig from a YAML or JSON document, e.g.
+//
+//	address: redis.internal:6379
+//	password: secret
+//	key_prefix: "orders:"
+//	tls:
+//	  enabled: true
+//	pool:
+//	  max_active: 50
+//	  wait: true
+//	timeouts:
+//	  read: 500ms
+//	retry:
+//	  max_attempts: 3
+func LoadClientConfig(r io.Reader) (*ClientConfig, error) {
+	config := &ClientConfig{}
+	// JSON is a subset of YAML
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(config); err != nil && err != io.EOF {
+		return nil, err
+	}
+	return config, nil
+}
+
+// LoadClientConfigFromEnv - Reads a ClientConfig from the environment variables starting with prefix, see ApplyEnv
+func LoadClientConfigFromEnv(prefix string) (*ClientConfig, error) {
+	config := &ClientConfig{}
+	if err := config.ApplyEnv(prefix); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// ApplyEnv - Overrides the fields of the config set in environment variables, named after prefix and the path
+// of the field in YAML, e.g. REDISBLOOM_ADDRESS, REDISBLOOM_TLS_ENABLED or REDISBLOOM_POOL_MAX_ACTIVE for the
+// prefix REDISBLOOM. It lets the environment override a configuration file.
+func (config *ClientConfig) ApplyEnv(prefix string) error {
+	return applyEnv(reflect.ValueOf(config).Elem(), strings.ToUpper(prefix))
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// applyEnv sets the fields of the struct value from the environment variables named after prefix and their tags
+func applyEnv(value reflect.Value, prefix string) error {
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		name := prefix + "_" + strings.ToUpper(strings.Split(field.Tag.Get("yaml"), ",")[0])
+		if field.Type.Kind() == reflect.Struct {
+			if err := applyEnv(value.Field(i), name); err != nil {
+				return err
+			}
+			continue
+		}
+		env, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+		if err := setFromEnv(value.Field(i), env); err != nil {
+			return fmt.Errorf("redisbloom: invalid %s: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// setFromEnv parses env into the field
+func setFromEnv(field reflect.Value, env string) error {
+	switch {
+	case field.Type() == durationType:
+		d, err := time.ParseDuration(env)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(d))
+	case field.Kind() == reflect.String:
+		field.SetString(env)
+	case field.Kind() == reflect.Int:
+		n, err := strconv.Atoi(env)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(n))
+	case field.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(env)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case field.Kind() == reflect.Map && field.Type().Elem() == durationType:
+		timeouts := make(map[string]time.Duration)
+		for _, entry := range strings.Split(env, ",") {
+			if entry = strings.TrimSpace(entry); entry == "" {
+				continue
+			}
+			eq := strings.IndexByte(entry, '=')
+			if eq < 0 {
+				return fmt.Errorf("%q is not a name=duration pair", entry)
+			}
+			d, err := time.ParseDuration(entry[eq+1:])
+			if err != nil {
+				return err
+			}
+			timeouts[entry[:eq]] = d
+		}
+		field.Set(reflect.ValueOf(timeouts))
+	default:
+		return fmt.Errorf("unsupported field type %s", field.Type())
+	}
+	return nil
+}
+
+// NewClientFromConfig - Returns a client configured by config, opts being applied after the options of config.
+// Returns ErrOnConnectUnsupported if opts prepare new connections, e.g. WithDatabase, on a pool not supporting it.
+func NewClientFromConfig(config ClientConfig, opts ...ClientOption) (*Client, error) {
+	if config.Address == "" {
+		return nil, errors.New("redisbloom: the client config has no address")
+	}
+	// redis.DialURL picks TLS from the scheme, overriding the options of the config
+	if config.TLS.Enabled && strings.Contains(config.Address, "://") {
+		if u, err := url.Parse(config.Address); err != nil || u.Scheme != "rediss" {
+			return nil, errors.New("redisbloom: the client config enables TLS but its address is not a rediss:// URL")
+		}
+	}
+	dialOptions, err := config.dialOptions()
+	if err != nil {
+		return nil, err
+	}
+	dial := func() (redis.Conn, error) {
+		return redis.Dial("tcp", config.Address, dialOptions...)
+	}
+	if strings.Contains(config.Address, "://") {
+		dial = func() (redis.Conn, error) {
+			return redis.DialURL(config.Address, dialOptions...)
+		}
+	}
+	pool := &redis.Pool{
+		Dial:         dial,
+		TestOnBorrow: testOnBorrow,
+		MaxIdle:      config.Pool.MaxIdle,
+		MaxActive:    config.Pool.MaxActive,
+		IdleTimeout:  config.Pool.IdleTimeout,
+		Wait:         config.Pool.Wait,
+	}
+	if pool.MaxIdle <= 0 {
+		pool.MaxIdle = maxConns
+	}
+	var configOpts []ClientOption
+	if config.KeyPrefix != "" {
+		configOpts = append(configOpts, WithKeyPrefix(config.KeyPrefix))
+	}
+	if len(config.Timeouts.Commands) > 0 {
+		configOpts = append(configOpts, WithCommandTimeouts(config.Timeouts.Commands))
+	}
+	if config.Retry.MaxAttempts > 1 {
+		configOpts = append(configOpts, WithRetry(RetryPolicy{
+			MaxAttempts: config.Retry.MaxAttempts,
+			BaseDelay:   config.Retry.BaseDelay,
+			MaxDelay:    config.Retry.MaxDelay,
+			RetryWrites: config.Retry.RetryWrites,
+		}))
+	}
+	client := NewClientFromPool(pool, config.Name, append(configOpts, opts...)...)
+	if _, ok := basePool(client.Pool).(*unpreparedPool); ok {
+		return nil, ErrOnConnectUnsupported
+	}
+	return client, nil
+}
+
+// dialOptions returns the options of the connections of the config
+func (config *ClientConfig) dialOptions() ([]redis.DialOption, error) {
+	options := []redis.DialOption{
+		redis.DialConnectTimeout(config.Timeouts.Connect),
+		redis.DialReadTimeout(config.Timeouts.Read),
+		redis.DialWriteTimeout(config.Timeouts.Write),
+	}
+	if config.Username != "" {
+		options = append(options, redis.DialUsername(config.Username))
+	}
+	if config.Password != "" {
+		options = append(options, redis.DialPassword(config.Password))
+	}
+	if config.Database != 0 {
+		options = append(options, redis.DialDatabase(config.Database))
+	}
+	if config.Name != "" {
+		options = append(options, redis.DialClientName(config.Name))
+	}
+	if config.TLS.Enabled || strings.HasPrefix(config.Address, "rediss://") {
+		tlsConfig, err := config.TLS.tlsConfig()
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, redis.DialUseTLS(true), redis.DialTLSConfig(tlsConfig),
+			redis.DialTLSSkipVerify(config.TLS.InsecureSkipVerify))
+	}
+	return options, nil
+}
+
+// tlsConfig loads the certificates of the config
+func (config *TLSConfig) tlsConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{ServerName: config.ServerName, InsecureSkipVerify: config.InsecureSkipVerify}
+	if config.CAFile != "" {
+		pem, err := ioutil.ReadFile(config.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("redisbloom: no certificate found in %s", config.CAFile)
+		}
+	}
+	if config.CertFile != "" || config.KeyFile != "" {
+		certificate, err := tls.LoadX509KeyPair(config.CertFile, config.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+	return tlsConfig, nil
+}
@@ -0,0 +1,138 @@
+package redis_bloom_go
+
+import (
+	"net"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadClientConfig(t *testing.T) {
+	config, err := LoadClientConfig(strings.NewReader(`
+address: localhost:6379
+password: secret
+key_prefix: "orders:"
+tls:
+  enabled: true
+  insecure_skip_verify: true
+pool:
+  max_active: 50
+  wait: true
+timeouts:
+  read: 500ms
+  commands:
+    TDIGEST.MERGE: 5s
+retry:
+  max_attempts: 3
+`))
+	assert.Nil(t, err)
+	assert.Equal(t, "localhost:6379", config.Address)
+	assert.Equal(t, "orders:", config.KeyPrefix)
+	assert.True(t, config.TLS.Enabled)
+	assert.Equal(t, PoolConfig{MaxActive: 50, Wait: true}, config.Pool)
+	assert.Equal(t, 500*time.Millisecond, config.Timeouts.Read)
+	assert.Equal(t, map[string]time.Duration{"TDIGEST.MERGE": 5 * time.Second}, config.Timeouts.Commands)
+	assert.Equal(t, 3, config.Retry.MaxAttempts)
+
+	config, err = LoadClientConfig(strings.NewReader(`{"address": "redis://localhost:6379/1", "timeouts": {"connect": "1s"}}`))
+	assert.Nil(t, err)
+	assert.Equal(t, "redis://localhost:6379/1", config.Address)
+	assert.Equal(t, time.Second, config.Timeouts.Connect)
+
+	_, err = LoadClientConfig(strings.NewReader(`adress: localhost:6379`))
+	assert.NotNil(t, err)
+}
+
+func TestClientConfig_ApplyEnv(t *testing.T) {
+	env := map[string]string{
+		"TEST_BLOOM_ADDRESS":           "redis:6379",
+		"TEST_BLOOM_DATABASE":          "2",
+		"TEST_BLOOM_TLS_ENABLED":       "true",
+		"TEST_BLOOM_POOL_IDLE_TIMEOUT": "1m",
+		"TEST_BLOOM_TIMEOUTS_COMMANDS": "TDIGEST.MERGE=5s, CMS=1s",
+	}
+	for name, value := range env {
+		os.Setenv(name, value)
+		defer os.Unsetenv(name)
+	}
+	config := &ClientConfig{Address: "localhost:6379", Password: "secret"}
+	assert.Nil(t, config.ApplyEnv("test_bloom"))
+	assert.Equal(t, "redis:6379", config.Address)
+	assert.Equal(t, "secret", config.Password)
+	assert.Equal(t, 2, config.Database)
+	assert.True(t, config.TLS.Enabled)
+	assert.Equal(t, time.Minute, config.Pool.IdleTimeout)
+	assert.Equal(t, map[string]time.Duration{"TDIGEST.MERGE": 5 * time.Second, "CMS": time.Second}, config.Timeouts.Commands)
+
+	os.Setenv("TEST_BLOOM_RETRY_MAX_ATTEMPTS", "three")
+	defer os.Unsetenv("TEST_BLOOM_RETRY_MAX_ATTEMPTS")
+	_, err := LoadClientConfigFromEnv("TEST_BLOOM")
+	assert.EqualError(t, err, `redisbloom: invalid TEST_BLOOM_RETRY_MAX_ATTEMPTS: strconv.Atoi: parsing "three": invalid syntax`)
+}
+
+func TestNewClientFromConfig(t *testing.T) {
+	_, err := NewClientFromConfig(ClientConfig{})
+	assert.NotNil(t, err)
+	_, err = NewClientFromConfig(ClientConfig{Address: "localhost:6379", TLS: TLSConfig{Enabled: true, CAFile: "missing.pem"}})
+	assert.NotNil(t, err)
+
+	c, err := NewClientFromConfig(ClientConfig{
+		Address:   "localhost:6379",
+		Name:      "orders",
+		KeyPrefix: "orders:",
+		Pool:      PoolConfig{MaxActive: 10, Wait: true},
+		Timeouts:  TimeoutsConfig{Commands: map[string]time.Duration{"": time.Second}},
+		Retry:     RetryConfig{MaxAttempts: 3},
+	}, WithWriteTTL(time.Hour))
+	assert.Nil(t, err)
+	assert.Equal(t, "orders", c.Name)
+	assert.Equal(t, "orders:key", c.key("key"))
+	assert.Equal(t, time.Hour, c.writeTTL)
+	retry, ok := c.Pool.(*retryPool)
+	assert.True(t, ok)
+	assert.Equal(t, 3, retry.policy.MaxAttempts)
+	_, ok = retry.ConnPool.(*timeoutPool)
+	assert.True(t, ok)
+	pool := basePool(c.Pool).(*redis.Pool)
+	assert.Equal(t, maxConns, pool.MaxIdle)
+	assert.Equal(t, 10, pool.MaxActive)
+	assert.True(t, pool.Wait)
+}
+
+func TestNewClientFromConfig_TLS(t *testing.T) {
+	_, err := NewClientFromConfig(ClientConfig{Address: "redis://localhost:6379", TLS: TLSConfig{Enabled: true}})
+	assert.NotNil(t, err)
+
+	// the connections of a TLS config start with a handshake, never with a plaintext command
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer listener.Close()
+	for _, address := range []string{listener.Addr().String(), "rediss://" + listener.Addr().String()} {
+		c, err := NewClientFromConfig(ClientConfig{
+			Address:  address,
+			Password: "secret",
+			TLS:      TLSConfig{Enabled: true},
+			Timeouts: TimeoutsConfig{Connect: time.Second},
+		})
+		assert.Nil(t, err)
+		first := make(chan byte, 1)
+		go func() {
+			conn, err := listener.Accept()
+			if err != nil {
+				close(first)
+				return
+			}
+			defer conn.Close()
+			b := make([]byte, 1)
+			conn.Read(b)
+			first <- b[0]
+		}()
+		c.Pool.Get().Close()
+		// 0x16 starts a TLS handshake record
+		assert.Equal(t, byte(0x16), <-first, address)
+	}
+}
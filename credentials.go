@@ -0,0 +1,34 @@
+package redis_bloom_go
+
+import (
+	"context"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// CredentialsProvider returns the credentials to authenticate a new connection with. An empty username
+// authenticates with the password only, as the default user.
+type CredentialsProvider func(ctx context.Context) (username string, password string, err error)
+
+// WithCredentialsProvider authenticates every new connection of the client pool with the credentials
+// provider returns at that time, so that rotated passwords and short lived tokens, e.g. ElastiCache IAM
+// authentication tokens, are picked up without recreating the client. Connections authenticated earlier
+// are not affected. Like WithOnConnect, it must be given before any connection is made, and it authenticates
+// the connections before the functions of WithOnConnect and WithDatabase run, whatever the order of the options.
+// provider is given the context of the dial, which has no deadline unless the pool dials with DialContext.
+func WithCredentialsProvider(provider CredentialsProvider) ClientOption {
+	return func(client *Client) {
+		client.addConnectFunc(func(ctx context.Context, conn redis.Conn) error {
+			username, password, err := provider(ctx)
+			if err != nil {
+				return err
+			}
+			if username == "" {
+				_, err = conn.Do("AUTH", password)
+			} else {
+				_, err = conn.Do("AUTH", username, password)
+			}
+			return err
+		}, true)
+	}
+}
@@ -0,0 +1,96 @@
+package redis_bloom_go
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+// argsConn is a fakeConn recording the arguments of the commands sent with Do
+type argsConn struct {
+	*fakeConn
+	args [][]interface{}
+}
+
+func (c *argsConn) Do(command string, args ...interface{}) (interface{}, error) {
+	// copied, as the client reuses the argument slices of some commands
+	c.args = append(c.args, append([]interface{}(nil), args...))
+	return c.fakeConn.Do(command, args...)
+}
+
+func TestWithCredentialsProvider(t *testing.T) {
+	passwords := []string{"first", "second"}
+	calls := 0
+	provider := func(ctx context.Context) (string, string, error) {
+		calls++
+		return "app", passwords[calls-1], nil
+	}
+	conns := make([]*argsConn, 0)
+	pool := &redis.Pool{Dial: func() (redis.Conn, error) {
+		conn := &argsConn{fakeConn: &fakeConn{replies: []interface{}{"OK", int64(1)}}}
+		conns = append(conns, conn)
+		return conn, nil
+	}}
+	c := NewClientFromPool(pool, "test", WithCredentialsProvider(provider))
+
+	// hold the first connection so that the second command dials a new one
+	held := c.Pool.Get()
+	_, err := held.Do("PING")
+	assert.Nil(t, err)
+	_, err = c.Add("key", "item")
+	assert.Nil(t, err)
+	held.Close()
+
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, []string{"AUTH", "PING"}, conns[0].commands)
+	assert.Equal(t, []interface{}{"app", "first"}, conns[0].args[0])
+	assert.Equal(t, []string{"AUTH", "BF.ADD"}, conns[1].commands)
+	assert.Equal(t, []interface{}{"app", "second"}, conns[1].args[0])
+}
+
+func TestWithCredentialsProvider_Errors(t *testing.T) {
+	expired := errors.New("token expired")
+	pool := &redis.Pool{Dial: func() (redis.Conn, error) { return &fakeConn{}, nil }}
+	c := NewClientFromPool(pool, "test", WithCredentialsProvider(func(context.Context) (string, string, error) {
+		return "", "", expired
+	}))
+	_, err := c.Add("key", "item")
+	assert.Equal(t, expired, err)
+
+	conn := &argsConn{fakeConn: &fakeConn{replies: []interface{}{redis.Error("WRONGPASS invalid username-password pair")}}}
+	pool = &redis.Pool{Dial: func() (redis.Conn, error) { return conn, nil }}
+	c = NewClientFromPool(pool, "test", WithCredentialsProvider(func(context.Context) (string, string, error) {
+		return "", "secret", nil
+	}))
+	_, err = c.Add("key", "item")
+	assert.Equal(t, redis.Error("WRONGPASS invalid username-password pair"), err)
+	assert.Equal(t, []interface{}{"secret"}, conn.args[0])
+}
+
+func TestWithCredentialsProvider_Order(t *testing.T) {
+	type ctxKey struct{}
+	var dialValue interface{}
+	provider := func(ctx context.Context) (string, string, error) {
+		dialValue = ctx.Value(ctxKey{})
+		return "", "secret", nil
+	}
+	conn := &fakeConn{replies: []interface{}{"OK", "OK", "OK", int64(1)}}
+	pool := &redis.Pool{DialContext: func(context.Context) (redis.Conn, error) { return conn, nil }, MaxIdle: 1}
+	setName := func(conn redis.Conn) error {
+		_, err := conn.Do("CLIENT", "SETNAME", "bloom")
+		return err
+	}
+	// authentication comes first even when given last
+	c := NewClientFromPool(pool, "test", WithDatabase(3), WithOnConnect(setName), WithCredentialsProvider(provider))
+	pooled, err := pool.GetContext(context.WithValue(context.Background(), ctxKey{}, "dial"))
+	assert.Nil(t, err)
+	pooled.Close()
+	_, err = c.Add("key", "item")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"AUTH", "SELECT", "CLIENT", "BF.ADD"}, conn.commands)
+	// the provider is given the context of the dial
+	assert.Equal(t, "dial", dialValue)
+}
@@ -0,0 +1,88 @@
+package redis_bloom_go
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithDatabase(t *testing.T) {
+	conn := &fakeConn{replies: []interface{}{"OK", int64(1)}}
+	pool := &redis.Pool{Dial: func() (redis.Conn, error) { return conn, nil }}
+	c := NewClientFromPool(pool, "test", WithDatabase(3))
+	_, err := c.Exists("key", "item")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"SELECT", "BF.EXISTS"}, conn.commands)
+
+	conn = &fakeConn{replies: []interface{}{redis.Error("ERR DB index is out of range")}}
+	pool = &redis.Pool{Dial: func() (redis.Conn, error) { return conn, nil }}
+	c = NewClientFromPool(pool, "test", WithDatabase(100))
+	_, err = c.Exists("key", "item")
+	assert.Equal(t, redis.Error("ERR DB index is out of range"), err)
+}
+
+func TestWithOnConnect(t *testing.T) {
+	conn := &fakeConn{replies: []interface{}{"OK", "OK", int64(1)}}
+	pool := &redis.Pool{Dial: func() (redis.Conn, error) { return conn, nil }}
+	setName := func(conn redis.Conn) error {
+		_, err := conn.Do("CLIENT", "SETNAME", "bloom")
+		return err
+	}
+	c := NewClientFromPool(pool, "test", WithOnConnect(setName), WithDatabase(2))
+	_, err := c.Add("key", "item")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"CLIENT", "SELECT", "BF.ADD"}, conn.commands)
+
+	refused := errors.New("refused")
+	pool = &redis.Pool{Dial: func() (redis.Conn, error) { return &fakeConn{}, nil }}
+	c = NewClientFromPool(pool, "test", WithOnConnect(func(redis.Conn) error { return refused }))
+	_, err = c.Add("key", "item")
+	assert.Equal(t, refused, err)
+}
+
+func TestAddConnectChain(t *testing.T) {
+	chain := &connectChain{}
+	multi := NewMultiHostPool([]string{"a:1", "b:2"}, nil)
+	assert.True(t, addConnectChain(multi, chain))
+	assert.True(t, addConnectChain(multi, chain))
+	assert.Equal(t, 1, len(multi.connect))
+	single := NewSingleHostPool("a:1", nil)
+	assert.True(t, addConnectChain(&hookedPool{ConnPool: single}, chain))
+	assert.True(t, chain.wrapped[single.Pool])
+	assert.False(t, addConnectChain(&fakePool{}, chain))
+}
+
+func TestWithDatabase_Unsupported(t *testing.T) {
+	conn := &fakeConn{replies: []interface{}{int64(1)}}
+	custom := func(c *Client) { c.Pool = &fakePool{conn: conn} }
+	// the commands fail rather than run on the default database
+	c := NewClientFromPool(nil, "test", custom, WithDatabase(3))
+	_, err := c.Add("key", "item")
+	assert.Equal(t, ErrOnConnectUnsupported, err)
+	assert.Nil(t, conn.commands)
+
+	_, err = NewClientFromConfig(ClientConfig{Address: "localhost:6379"}, custom, WithDatabase(3))
+	assert.Equal(t, ErrOnConnectUnsupported, err)
+}
+
+func TestNewClientFromURL(t *testing.T) {
+	_, err := NewClientFromURL("redis://%zz", "test")
+	assert.NotNil(t, err)
+
+	client.FlushAll()
+	host, password := getTestConnectionDetails()
+	rawurl := "redis://" + host + "/1"
+	if password != "" {
+		rawurl = "redis://:" + password + "@" + host + "/1"
+	}
+	c, err := NewClientFromURL(rawurl, "bloom-client-url")
+	assert.Nil(t, err)
+	defer c.Pool.Close()
+	_, err = c.Add("test_url_db", "a")
+	assert.Nil(t, err)
+	exists, err := client.KeyExists("test_url_db")
+	assert.Nil(t, err)
+	assert.False(t, exists)
+}
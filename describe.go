@@ -0,0 +1,65 @@
+package redis_bloom_go
+
+import "github.com/gomodule/redigo/redis"
+
+// KeyDescription is the information about a RedisBloom key. Type tells which of the infos is set,
+// the others being nil.
+type KeyDescription struct {
+	Type    DataType
+	Bloom   *BloomInfo
+	Cuckoo  *CuckooInfo
+	CMS     *CMSInfo
+	TopK    *TopKInfo
+	TDigest *TDigestInfo
+}
+
+// Describe - Detects the data structure stored at key and returns its information, so that generic tooling
+// does not need to know which info command to call.
+// Returns redis.ErrNil if the key does not exist, ErrWrongType if it does not hold a RedisBloom data structure.
+func (client *Client) Describe(key string) (*KeyDescription, error) {
+	keyType, err := client.keyType(key)
+	if err != nil {
+		return nil, err
+	}
+	dataType, ok := dataTypes[keyType]
+	if !ok {
+		if keyType == "none" {
+			return nil, redis.ErrNil
+		}
+		return nil, ErrWrongType
+	}
+	description := &KeyDescription{Type: dataType}
+	switch dataType {
+	case DataTypeBloom:
+		info, err := client.BfInfoTyped(key)
+		if err != nil {
+			return nil, err
+		}
+		description.Bloom = &info
+	case DataTypeCuckoo:
+		info, err := client.CfInfoTyped(key)
+		if err != nil {
+			return nil, err
+		}
+		description.Cuckoo = &info
+	case DataTypeCMS:
+		info, err := client.CmsInfoTyped(key)
+		if err != nil {
+			return nil, err
+		}
+		description.CMS = &info
+	case DataTypeTopK:
+		info, err := client.TopkInfoTyped(key)
+		if err != nil {
+			return nil, err
+		}
+		description.TopK = &info
+	case DataTypeTDigest:
+		info, err := client.TdInfo(key)
+		if err != nil {
+			return nil, err
+		}
+		description.TDigest = &info
+	}
+	return description, nil
+}
@@ -0,0 +1,38 @@
+package redis_bloom_go
+
+import (
+	"testing"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_Describe_Fake(t *testing.T) {
+	conn := &fakeConn{replies: []interface{}{
+		"TopK-TYPE",
+		[]interface{}{"k", int64(10), "width", int64(2000), "depth", int64(7), "decay", []byte("0.925")},
+		"none",
+		"string",
+	}}
+	c := NewClientFromPool(nil, "test")
+	c.Pool = &fakePool{conn: conn}
+	description, err := c.Describe("topk")
+	assert.Nil(t, err)
+	assert.Equal(t, &KeyDescription{Type: DataTypeTopK, TopK: &TopKInfo{K: 10, Width: 2000, Depth: 7, Decay: 0.925}}, description)
+	assert.Equal(t, []string{"TYPE", "TOPK.INFO"}, conn.commands)
+
+	_, err = c.Describe("missing")
+	assert.Equal(t, redis.ErrNil, err)
+	_, err = c.Describe("string")
+	assert.Equal(t, ErrWrongType, err)
+}
+
+func TestClient_Describe(t *testing.T) {
+	client.FlushAll()
+	assert.Nil(t, client.Reserve("test_describe_bf", 0.01, 100))
+	description, err := client.Describe("test_describe_bf")
+	assert.Nil(t, err)
+	assert.Equal(t, DataTypeBloom, description.Type)
+	assert.Equal(t, int64(100), description.Bloom.Capacity)
+	assert.Nil(t, description.Cuckoo)
+}
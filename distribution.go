@@ -0,0 +1,203 @@
+package redis_bloom_go
+
+import (
+	"errors"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// HistogramBin is a bin of a histogram estimated from a t-digest
+type HistogramBin struct {
+	// Lower and Upper are the bounds of the bin, Lower excluded and Upper included
+	Lower float64
+	Upper float64
+	// Fraction is the fraction of the observations which fall in the bin
+	Fraction float64
+	// Count is the estimated number of observations which fall in the bin
+	Count float64
+}
+
+// TdCdfs - Returns, for each of the given values, the fraction of all points added which are <= value.
+// The result is aligned with the order of values.
+func (client *Client) TdCdfs(key string, values ...float64) ([]float64, error) {
+	if len(values) == 0 {
+		return nil, errors.New("TdCdfs expects at least one value")
+	}
+	modern, err := client.isModernTDigest()
+	if err != nil {
+		return nil, err
+	}
+	conn := client.Pool.Get()
+	defer conn.Close()
+	if modern {
+		return ParseFloat64sReply(conn.Do("TDIGEST.CDF", redis.Args{client.key(key)}.AddFlat(values)...))
+	}
+	// servers prior to RedisBloom 2.4 accept a single value per command, so pipeline them
+	for _, value := range values {
+		if err := conn.Send("TDIGEST.CDF", client.key(key), value); err != nil {
+			return nil, err
+		}
+	}
+	if err := conn.Flush(); err != nil {
+		return nil, err
+	}
+	fractions := make([]float64, len(values))
+	var outErr error
+	for i := range values {
+		fraction, err := ParseFloat64Reply(conn.Receive())
+		if err != nil && outErr == nil {
+			outErr = err
+		}
+		fractions[i] = fraction
+	}
+	if outErr != nil {
+		return nil, outErr
+	}
+	return fractions, nil
+}
+
+// TdQuantileCurve - Returns the cutoffs of n evenly spaced quantiles, from the minimum (quantile 0) to the
+// maximum (quantile 1), i.e. the cutoff of quantile i/(n-1) at index i, read with a single TdQuantiles call.
+// n must be at least 2.
+func (client *Client) TdQuantileCurve(key string, n int) ([]float64, error) {
+	if n < 2 {
+		return nil, errors.New("TdQuantileCurve expects at least 2 quantiles")
+	}
+	quantiles := make([]float64, n)
+	for i := range quantiles {
+		quantiles[i] = float64(i) / float64(n-1)
+	}
+	return client.TdQuantiles(key, quantiles...)
+}
+
+// TdHistogram - Returns the histogram of the observations of the t-digest stored at key over the bins delimited
+// by edges, which must be sorted in increasing order: bin i covers the values greater than edges[i] and lower
+// than or equal to edges[i+1]. The fractions are differences of the CDF at the edges, and the counts are
+// the fractions multiplied by the total weight of the t-digest.
+func (client *Client) TdHistogram(key string, edges []float64) ([]HistogramBin, error) {
+	if len(edges) < 2 {
+		return nil, errors.New("TdHistogram expects at least 2 edges")
+	}
+	for i := 1; i < len(edges); i++ {
+		if edges[i] < edges[i-1] {
+			return nil, errors.New("TdHistogram expects edges in increasing order")
+		}
+	}
+	info, err := client.TdInfo(key)
+	if err != nil {
+		return nil, err
+	}
+	cdfs, err := client.TdCdfs(key, edges...)
+	if err != nil {
+		return nil, err
+	}
+	total := info.MergedWeight() + info.UnmergedWeight()
+	bins := make([]HistogramBin, len(edges)-1)
+	for i := range bins {
+		fraction := cdfs[i+1] - cdfs[i]
+		bins[i] = HistogramBin{Lower: edges[i], Upper: edges[i+1], Fraction: fraction, Count: fraction * total}
+	}
+	return bins, nil
+}
+
+// tdSummaryTrim is the fraction of the lowest and of the highest observations left out of the mean of a TDigestSummary
+const tdSummaryTrim = 0.05
+
+// tdSummaryQuantiles are the quantiles of a TDigestSummary
+var tdSummaryQuantiles = []float64{0.5, 0.9, 0.95, 0.99}
+
+// TDigestSummary describes the distribution of the observations of a t-digest
+type TDigestSummary struct {
+	// Count is the number of observations
+	Count float64
+	Min   float64
+	Max   float64
+	// Mean is the mean of the observations between the 5th and the 95th percentiles
+	Mean float64
+	P50  float64
+	P90  float64
+	P95  float64
+	P99  float64
+}
+
+// TdPercentile - Returns an estimate of the cutoff below which percentile percents of the observations fall,
+// e.g. 99.9 for the 99.9th percentile
+func (client *Client) TdPercentile(key string, percentile float64) (float64, error) {
+	return client.TdQuantile(key, percentile/100)
+}
+
+// TdP50 - Returns an estimate of the median of the observations
+func (client *Client) TdP50(key string) (float64, error) {
+	return client.TdQuantile(key, 0.5)
+}
+
+// TdP95 - Returns an estimate of the 95th percentile of the observations
+func (client *Client) TdP95(key string) (float64, error) {
+	return client.TdQuantile(key, 0.95)
+}
+
+// TdP99 - Returns an estimate of the 99th percentile of the observations
+func (client *Client) TdP99(key string) (float64, error) {
+	return client.TdQuantile(key, 0.99)
+}
+
+// TdSummary - Returns the number of observations, minimum, maximum, trimmed mean and usual percentiles of the
+// t-digest stored at key, read in a single round trip. Returns ErrEmptySketch if it has no observations.
+// Requires RedisBloom 2.4 or newer
+func (client *Client) TdSummary(key string) (*TDigestSummary, error) {
+	conn := client.Pool.Get()
+	defer conn.Close()
+	k := client.key(key)
+	commands := []struct {
+		name string
+		args redis.Args
+	}{
+		{"TDIGEST.INFO", redis.Args{k}},
+		{"TDIGEST.MIN", redis.Args{k}},
+		{"TDIGEST.MAX", redis.Args{k}},
+		{"TDIGEST.TRIMMED_MEAN", redis.Args{k, tdSummaryTrim, 1 - tdSummaryTrim}},
+		{"TDIGEST.QUANTILE", redis.Args{k}.AddFlat(tdSummaryQuantiles)},
+	}
+	for _, command := range commands {
+		if err := conn.Send(command.name, command.args...); err != nil {
+			return nil, err
+		}
+	}
+	if err := conn.Flush(); err != nil {
+		return nil, err
+	}
+	replies := make([]interface{}, len(commands))
+	var outErr error
+	for i := range commands {
+		reply, err := conn.Receive()
+		if err != nil && outErr == nil {
+			outErr = err
+		}
+		replies[i] = reply
+	}
+	if outErr != nil {
+		return nil, outErr
+	}
+	info, err := ParseTDigestInfo(replies[0], nil)
+	if err != nil {
+		return nil, err
+	}
+	summary := &TDigestSummary{Count: info.MergedWeight() + info.UnmergedWeight()}
+	if summary.Count == 0 {
+		return nil, ErrEmptySketch
+	}
+	for i, target := range []*float64{&summary.Min, &summary.Max, &summary.Mean} {
+		if *target, err = ParseFloat64Reply(replies[i+1], nil); err != nil {
+			return nil, err
+		}
+	}
+	quantiles, err := ParseFloat64sReply(replies[4], nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(quantiles) != len(tdSummaryQuantiles) {
+		return nil, errors.New("redisbloom: unexpected number of quantiles in TDIGEST.QUANTILE reply")
+	}
+	summary.P50, summary.P90, summary.P95, summary.P99 = quantiles[0], quantiles[1], quantiles[2], quantiles[3]
+	return summary, nil
+}
@@ -0,0 +1,80 @@
+package redis_bloom_go
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_DistributionArguments(t *testing.T) {
+	_, err := client.TdCdfs("td")
+	assert.NotNil(t, err)
+	_, err = client.TdQuantileCurve("td", 1)
+	assert.NotNil(t, err)
+	_, err = client.TdHistogram("td", []float64{1})
+	assert.NotNil(t, err)
+	_, err = client.TdHistogram("td", []float64{2, 1})
+	assert.NotNil(t, err)
+}
+
+func TestClient_TdQuantileCurve(t *testing.T) {
+	client.FlushAll()
+	key := "test_td_curve"
+	client.TdCreate(key, 100)
+	client.TdAddValues(key, 1, 2, 3, 4, 5)
+	curve, err := client.TdQuantileCurve(key, 3)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(curve))
+	assert.Equal(t, 1.0, curve[0])
+	assert.Equal(t, 5.0, curve[2])
+
+	cdfs, err := client.TdCdfs(key, 0, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{0, 1}, cdfs)
+}
+
+func TestClient_TdHistogram(t *testing.T) {
+	client.FlushAll()
+	key := "test_td_histogram"
+	client.TdCreate(key, 100)
+	client.TdAddValues(key, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10)
+	bins, err := client.TdHistogram(key, []float64{0, 10, 100})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(bins))
+	assert.Equal(t, 0.0, bins[0].Lower)
+	assert.Equal(t, 10.0, bins[0].Upper)
+	assert.InDelta(t, 1.0, bins[0].Fraction+bins[1].Fraction, 1e-9)
+	assert.InDelta(t, 10.0, bins[0].Count+bins[1].Count, 1e-9)
+}
+
+func TestClient_TdSummary(t *testing.T) {
+	client.FlushAll()
+	key := "test_td_summary"
+	client.TdCreate(key, 100)
+	values := make([]float64, 100)
+	for i := range values {
+		values[i] = float64(i + 1)
+	}
+	client.TdAddValues(key, values...)
+
+	summary, err := client.TdSummary(key)
+	assert.Nil(t, err)
+	assert.Equal(t, 100.0, summary.Count)
+	assert.Equal(t, 1.0, summary.Min)
+	assert.Equal(t, 100.0, summary.Max)
+	assert.InDelta(t, 50.5, summary.Mean, 1)
+	assert.InDelta(t, 50, summary.P50, 2)
+	assert.InDelta(t, 99, summary.P99, 2)
+
+	p99, err := client.TdP99(key)
+	assert.Nil(t, err)
+	assert.Equal(t, summary.P99, p99)
+	p, err := client.TdPercentile(key, 50)
+	assert.Nil(t, err)
+	p50, err := client.TdP50(key)
+	assert.Nil(t, err)
+	assert.Equal(t, p50, p)
+
+	_, err = client.TdSummary("test_td_summary_missing")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,352 @@
+package redis_bloom_go
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// ErrDryRun is returned in dry-run mode for the commands whose reply cannot be made up, such as the scripts
+// of the composite commands. They are recorded but not sent.
+var ErrDryRun = errors.New("redisbloom: command cannot be dry-run")
+
+// AuditSink records the commands modifying data of a client in dry-run or audit mode.
+// args may be reused by the client once Record returns, so it must be copied to be kept.
+type AuditSink interface {
+	Record(command string, args []interface{})
+}
+
+// AuditSinkFunc adapts a function to an AuditSink
+type AuditSinkFunc func(command string, args []interface{})
+
+// Record calls f
+func (f AuditSinkFunc) Record(command string, args []interface{}) {
+	f(command, args)
+}
+
+// writerAuditSink writes each command on a line, like redis-cli
+type writerAuditSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewWriterAuditSink - Returns an AuditSink writing each command to w on a line of its own, with its arguments
+// quoted like redis-cli does, e.g. `BF.ADD "bf" "item"`. It is safe for concurrent use.
+func NewWriterAuditSink(w io.Writer) AuditSink {
+	return &writerAuditSink{w: w}
+}
+
+func (s *writerAuditSink) Record(command string, args []interface{}) {
+	var line strings.Builder
+	line.WriteString(command)
+	for _, arg := range args {
+		line.WriteByte(' ')
+		switch arg := arg.(type) {
+		case string:
+			line.WriteString(strconv.Quote(arg))
+		case []byte:
+			line.WriteString(strconv.Quote(string(arg)))
+		default:
+			fmt.Fprint(&line, arg)
+		}
+	}
+	line.WriteByte('\n')
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	io.WriteString(s.w, line.String())
+}
+
+// WithDryRun records the commands modifying data to sink instead of sending them, and replies to them with
+// a synthetic success: the items are reported added, deleted or not expelled, the count-min sketch counts
+// equal the increments and the other commands reply OK. Commands reading data are sent as usual. It suits
+// validating migrations and replaying traffic against production safely.
+// The scripts of the composite commands, such as BfAddIncr, are recorded and fail with ErrDryRun.
+func WithDryRun(sink AuditSink) ClientOption {
+	return func(client *Client) {
+		client.Pool = &auditPool{ConnPool: client.Pool, record: sinkRecorder(sink)}
+	}
+}
+
+// WithAudit records the commands modifying data to sink before sending them as usual
+func WithAudit(sink AuditSink) ClientOption {
+	return func(client *Client) {
+		client.Pool = &auditPool{ConnPool: client.Pool, record: sinkRecorder(sink), execute: true}
+	}
+}
+
+// DryRun - Returns a view of the client, sharing its connection pool, which records the commands modifying data
+// to sink instead of sending them, like a client created with WithDryRun
+func (client *Client) DryRun(sink AuditSink) *Client {
+	view := *client
+	view.Pool = &auditPool{ConnPool: client.Pool, record: sinkRecorder(sink)}
+	return &view
+}
+
+// sinkRecorder adapts sink to the record function of an auditPool
+func sinkRecorder(sink AuditSink) func(command string, args []interface{}) error {
+	return func(command string, args []interface{}) error {
+		sink.Record(command, args)
+		return nil
+	}
+}
+
+// auditPool hands out connections recording the commands modifying data, and sending them only if execute.
+// A command whose recording fails is not sent, and fails with the error of record.
+type auditPool struct {
+	ConnPool
+	record  func(command string, args []interface{}) error
+	execute bool
+}
+
+func (p *auditPool) unwrap() ConnPool {
+	return p.ConnPool
+}
+
+func (p *auditPool) Get() redis.Conn {
+	return &auditConn{Conn: p.ConnPool.Get(), pool: p}
+}
+
+// auditConn records the commands modifying data. In dry-run mode, it replies to them itself, keeping their
+// synthetic replies in pending in the order of the pipeline, a nil entry standing for a command actually sent.
+// Transactions are answered locally too, their reads being run on the server on EXEC.
+type auditConn struct {
+	redis.Conn
+	pool    *auditPool
+	pending []*dryRunReply
+	sent    int
+	multi   bool
+	queued  []queuedCommand
+}
+
+// dryRunReply is the synthetic reply of a command which was not sent
+type dryRunReply struct {
+	reply interface{}
+	err   error
+}
+
+// queuedCommand is a command of a dry-run transaction, run on EXEC unless it has a synthetic reply
+type queuedCommand struct {
+	command   string
+	args      []interface{}
+	synthetic *dryRunReply
+}
+
+// errDryRunPipelinedRead is returned by the EXEC of a dry-run transaction reading data while pipelined
+// commands are waiting for their replies
+var errDryRunPipelinedRead = errors.New("redisbloom: dry-run transaction cannot read data behind pipelined commands")
+
+// record records command if it modifies data, returning its synthetic reply if it must not be sent
+func (c *auditConn) record(command string, args []interface{}) (*dryRunReply, error) {
+	if !c.pool.execute {
+		switch strings.ToUpper(command) {
+		case "MULTI":
+			c.multi, c.queued = true, nil
+			return &dryRunReply{reply: "OK"}, nil
+		case "DISCARD":
+			if c.multi {
+				c.multi, c.queued = false, nil
+				return &dryRunReply{reply: "OK"}, nil
+			}
+		case "EXEC":
+			if c.multi {
+				return c.exec(), nil
+			}
+		}
+	}
+	var synthetic *dryRunReply
+	if inner, innerArgs, expireArgs, ok := expireOnSuccessCommand(command, args); ok {
+		// writes setting a TTL are recorded as the write and its PEXPIRE
+		if err := c.pool.record(inner, innerArgs); err != nil {
+			return nil, err
+		}
+		if err := c.pool.record("PEXPIRE", expireArgs); err != nil {
+			return nil, err
+		}
+		if !c.pool.execute {
+			reply, err := dryRunReplyFor(inner, innerArgs)
+			synthetic = &dryRunReply{reply: reply, err: err}
+		}
+	} else if !isAllowedReadOnly(command, args) {
+		if err := c.pool.record(command, args); err != nil {
+			return nil, err
+		}
+		if !c.pool.execute {
+			reply, err := dryRunReplyFor(command, args)
+			synthetic = &dryRunReply{reply: reply, err: err}
+		}
+	}
+	if c.multi {
+		// copied, as the client may reuse args before EXEC
+		queued := queuedCommand{command: command, args: append([]interface{}(nil), args...), synthetic: synthetic}
+		c.queued = append(c.queued, queued)
+		return &dryRunReply{reply: "QUEUED"}, nil
+	}
+	return synthetic, nil
+}
+
+// exec returns the reply of the EXEC of a dry-run transaction: the synthetic replies of its writes, and
+// those of its reads, which are run on the server
+func (c *auditConn) exec() *dryRunReply {
+	queued := c.queued
+	c.multi, c.queued = false, nil
+	replies := make([]interface{}, len(queued))
+	for i, command := range queued {
+		if command.synthetic != nil {
+			if command.synthetic.err != nil {
+				replies[i] = redis.Error(command.synthetic.err.Error())
+			} else {
+				replies[i] = command.synthetic.reply
+			}
+			continue
+		}
+		if c.sent > 0 {
+			return &dryRunReply{err: errDryRunPipelinedRead}
+		}
+		reply, err := c.Conn.Do(command.command, command.args...)
+		if _, ok := err.(redis.Error); ok {
+			reply = err
+		} else if err != nil {
+			return &dryRunReply{err: err}
+		}
+		replies[i] = reply
+	}
+	return &dryRunReply{reply: replies}
+}
+
+func (c *auditConn) Do(command string, args ...interface{}) (interface{}, error) {
+	return c.do(command, args, doCall(command, args))
+}
+
+func (c *auditConn) DoWithTimeout(timeout time.Duration, command string, args ...interface{}) (interface{}, error) {
+	return c.do(command, args, doWithTimeoutCall(timeout, command, args))
+}
+
+func (c *auditConn) do(command string, args []interface{}, call connCall) (interface{}, error) {
+	if command == "" {
+		return c.flush(call)
+	}
+	synthetic, err := c.record(command, args)
+	if err != nil {
+		return nil, err
+	}
+	if synthetic == nil {
+		c.pending, c.sent = nil, 0
+		return call(c.Conn)
+	}
+	if c.sent > 0 {
+		// read the replies of the pipelined commands, which Do would have discarded
+		if _, err := c.Conn.Do(""); err != nil {
+			c.pending, c.sent = nil, 0
+			return nil, err
+		}
+	}
+	c.pending, c.sent = nil, 0
+	return synthetic.reply, synthetic.err
+}
+
+// flush returns the replies of the pending commands, merging the synthetic ones with those of the server
+func (c *auditConn) flush(call connCall) (interface{}, error) {
+	pending := c.pending
+	c.pending, c.sent = nil, 0
+	received, err := redis.Values(call(c.Conn))
+	if err != nil {
+		return nil, err
+	}
+	if len(pending) == 0 {
+		return received, nil
+	}
+	replies := make([]interface{}, len(pending))
+	for i, synthetic := range pending {
+		switch {
+		case synthetic != nil && synthetic.err != nil:
+			replies[i] = redis.Error(synthetic.err.Error())
+		case synthetic != nil:
+			replies[i] = synthetic.reply
+		case len(received) > 0:
+			replies[i], received = received[0], received[1:]
+		}
+	}
+	return replies, nil
+}
+
+func (c *auditConn) Send(command string, args ...interface{}) error {
+	synthetic, err := c.record(command, args)
+	if err != nil {
+		return err
+	}
+	if synthetic == nil {
+		if err := c.Conn.Send(command, args...); err != nil {
+			return err
+		}
+		c.sent++
+	}
+	c.pending = append(c.pending, synthetic)
+	return nil
+}
+
+func (c *auditConn) Receive() (interface{}, error) {
+	return c.receive(receiveCall)
+}
+
+func (c *auditConn) ReceiveWithTimeout(timeout time.Duration) (interface{}, error) {
+	return c.receive(receiveWithTimeoutCall(timeout))
+}
+
+func (c *auditConn) receive(call connCall) (interface{}, error) {
+	if len(c.pending) > 0 {
+		synthetic := c.pending[0]
+		c.pending = c.pending[1:]
+		if synthetic != nil {
+			return synthetic.reply, synthetic.err
+		}
+		c.sent--
+	}
+	return call(c.Conn)
+}
+
+// dryRunReplyFor returns the synthetic success reply of a command modifying data
+func dryRunReplyFor(command string, args []interface{}) (interface{}, error) {
+	switch strings.ToUpper(command) {
+	case "BF.ADD", "CF.ADD", "CF.ADDNX", "CF.DEL", "DEL", "UNLINK", "EXPIRE", "PEXPIRE", "PERSIST":
+		return int64(1), nil
+	case "BF.MADD":
+		return repeatReply(int64(1), len(args)-1), nil
+	case "BF.INSERT", "CF.INSERT", "CF.INSERTNX":
+		for i, arg := range args {
+			if s, ok := arg.(string); ok && strings.EqualFold(s, "ITEMS") {
+				return repeatReply(int64(1), len(args)-i-1), nil
+			}
+		}
+		return []interface{}{}, nil
+	case "CMS.INCRBY":
+		counts := make([]interface{}, 0, len(args)/2)
+		for i := 2; i < len(args); i += 2 {
+			counts = append(counts, args[i])
+		}
+		return counts, nil
+	case "TOPK.ADD":
+		return repeatReply(nil, len(args)-1), nil
+	case "TOPK.INCRBY":
+		return repeatReply(nil, (len(args)-1)/2), nil
+	case "EVAL", "EVALSHA", "SCRIPT":
+		return nil, ErrDryRun
+	}
+	return "OK", nil
+}
+
+func repeatReply(reply interface{}, n int) []interface{} {
+	if n < 0 {
+		n = 0
+	}
+	replies := make([]interface{}, n)
+	for i := range replies {
+		replies[i] = reply
+	}
+	return replies
+}
@@ -0,0 +1,122 @@
+package redis_bloom_go
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingSink keeps the commands it records
+type recordingSink struct {
+	commands []string
+}
+
+func (s *recordingSink) Record(command string, args []interface{}) {
+	s.commands = append(s.commands, command)
+}
+
+func TestWithDryRun(t *testing.T) {
+	conn := &fakeConn{replies: []interface{}{int64(0)}}
+	sink := &recordingSink{}
+	c := NewClientFromPool(nil, "test")
+	c.Pool = &fakePool{conn: conn}
+	WithDryRun(sink)(c)
+
+	added, err := c.Add("bf", "a")
+	assert.Nil(t, err)
+	assert.True(t, added)
+	madded, err := c.BfAddMulti("bf", []string{"a", "b"})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1, 1}, madded)
+	counts, err := c.CmsIncrBy("cms", map[string]int64{"a": 3})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{3}, counts)
+	expelled, err := c.TopkAdd("topk", []string{"a", "b"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"", ""}, expelled)
+	assert.Nil(t, c.Reserve("bf2", 0.01, 1000))
+	_, _, err = c.BfAddIncr("bf", "a", "counter", 1)
+	assert.Equal(t, ErrDryRun, err)
+
+	exists, err := c.Exists("bf", "a")
+	assert.Nil(t, err)
+	assert.False(t, exists)
+	assert.Equal(t, []string{"BF.EXISTS"}, conn.commands)
+	assert.Equal(t, []string{"BF.ADD", "BF.MADD", "CMS.INCRBY", "TOPK.ADD", "BF.RESERVE", "EVALSHA"}, sink.commands)
+}
+
+func TestWithDryRun_Pipeline(t *testing.T) {
+	conn := &fakeConn{replies: []interface{}{int64(0)}}
+	c := NewClientFromPool(nil, "test")
+	c.Pool = &fakePool{conn: conn}
+	pooled := c.DryRun(&recordingSink{}).Pool.Get()
+	assert.Nil(t, pooled.Send("BF.ADD", "bf", "a"))
+	assert.Nil(t, pooled.Send("BF.EXISTS", "bf", "b"))
+	assert.Nil(t, pooled.Send("CF.DEL", "cf", "c"))
+	assert.Nil(t, pooled.Flush())
+	for _, expected := range []interface{}{int64(1), int64(0), int64(1)} {
+		reply, err := pooled.Receive()
+		assert.Nil(t, err)
+		assert.Equal(t, expected, reply)
+	}
+	assert.Equal(t, []string{"BF.EXISTS"}, conn.commands)
+}
+
+func TestWithDryRun_Transactions(t *testing.T) {
+	conn := &fakeConn{replies: []interface{}{int64(2)}}
+	sink := &recordingSink{}
+	c := NewClientFromPool(nil, "test", WithWriteTTL(time.Hour))
+	c.Pool = &fakePool{conn: conn}
+	WithDryRun(sink)(c)
+
+	added, err := c.Add("bf", "a")
+	assert.Nil(t, err)
+	assert.True(t, added)
+	assert.Nil(t, c.ReserveWithTTL("bf2", 0.01, 1000, time.Hour))
+	assert.Nil(t, c.ReportFalsePositive("bf", "a"))
+	assert.Nil(t, conn.commands)
+	assert.Equal(t, []string{"BF.ADD", "PEXPIRE", "BF.RESERVE", "PEXPIRE", "HINCRBY", "LPUSH", "LTRIM"}, sink.commands)
+
+	// the reads of a transaction are run on EXEC, its writes answered locally
+	pooled := c.Pool.Get()
+	reply, err := pooled.Do("MULTI")
+	assert.Nil(t, err)
+	assert.Equal(t, "OK", reply)
+	assert.Nil(t, pooled.Send("CF.DEL", "cf", "a"))
+	assert.Nil(t, pooled.Send("CF.COUNT", "cf", "a"))
+	replies, err := redis.Values(pooled.Do("EXEC"))
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{int64(1), int64(2)}, replies)
+	assert.Equal(t, []string{"CF.COUNT"}, conn.commands)
+}
+
+func TestWithAudit(t *testing.T) {
+	conn := &fakeConn{replies: []interface{}{int64(1), int64(0)}}
+	var out bytes.Buffer
+	c := NewClientFromPool(nil, "test")
+	c.Pool = &fakePool{conn: conn}
+	WithAudit(NewWriterAuditSink(&out))(c)
+
+	added, err := c.Add("bf", "an item")
+	assert.Nil(t, err)
+	assert.True(t, added)
+	_, err = c.Exists("bf", "a")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"BF.ADD", "BF.EXISTS"}, conn.commands)
+	assert.Equal(t, "BF.ADD \"bf\" \"an item\"\n", out.String())
+}
+
+func TestDryRunReplyFor(t *testing.T) {
+	reply, err := dryRunReplyFor("BF.INSERT", []interface{}{"bf", "CAPACITY", int64(10), "ITEMS", "a", "b"})
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{int64(1), int64(1)}, reply)
+	reply, err = dryRunReplyFor("TOPK.INCRBY", []interface{}{"topk", "a", int64(2)})
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{nil}, reply)
+	reply, err = dryRunReplyFor("TDIGEST.ADD", []interface{}{"td", 1.5})
+	assert.Nil(t, err)
+	assert.Equal(t, "OK", reply)
+}
@@ -0,0 +1,409 @@
+package redis_bloom_go
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"sort"
+)
+
+// dumpMagic identifies a stream written by the Dump*ToWriter helpers
+const dumpMagic = "RBDUMP"
+
+// dumpFormatVersion is the version of the stream layout written by the Dump*ToWriter helpers:
+//
+//	magic | version (1 byte) | type length (1 byte) | type | parameter count (uint16) |
+//	{ name length (uint16) | name | value (int64) }* |
+//	{ iterator (int64) | length (uint32) | compressed length (uint32) | CRC-32 (uint32) | gzip data }* |
+//	iterator 0 | chunk count (uint32) | total length (uint64) | CRC-32 of every chunk (uint32)
+//
+// where the type is the DataType of the filter, the parameters are its BF.INFO or CF.INFO fields and the
+// CRC-32 (IEEE) checksums cover the uncompressed data. Version 1 streams, whose layout is
+//
+//	magic | version (1 byte) | { iterator (int64) | length (uint32) | data }* | iterator 0
+//
+// can still be read.
+const dumpFormatVersion = 2
+
+// maxDumpChunkSize bounds the size of a single chunk read back from a stream
+const maxDumpChunkSize = 1 << 30
+
+// ErrInvalidDump is returned when a stream is not a dump written by this package
+var ErrInvalidDump = errors.New("redisbloom: invalid dump stream")
+
+// ErrCorruptDump is returned when the checksums of a dump stream do not match its content
+var ErrCorruptDump = errors.New("redisbloom: corrupt dump stream")
+
+type scanDumpFunc func(iter int64) (int64, []byte, error)
+
+// ScanDumpIterator walks the chunks of a SCANDUMP sequence:
+//
+//	it := client.BfScanDumpIterator(key)
+//	for it.Next() {
+//		iter, data := it.Chunk()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type ScanDumpIterator struct {
+	scanDump scanDumpFunc
+	iter     int64
+	data     []byte
+	err      error
+	done     bool
+	stop     chan struct{}
+}
+
+// BfScanDumpIterator - Returns an iterator over the BF.SCANDUMP chunks of the bloom filter stored at key
+func (client *Client) BfScanDumpIterator(key string) *ScanDumpIterator {
+	return &ScanDumpIterator{scanDump: func(iter int64) (int64, []byte, error) {
+		return client.BfScanDump(key, iter)
+	}}
+}
+
+// CfScanDumpIterator - Returns an iterator over the CF.SCANDUMP chunks of the cuckoo filter stored at key
+func (client *Client) CfScanDumpIterator(key string) *ScanDumpIterator {
+	return &ScanDumpIterator{scanDump: func(iter int64) (int64, []byte, error) {
+		return client.CfScanDump(key, iter)
+	}}
+}
+
+// Next fetches the next chunk, returning false once the dump is complete or an error occurred
+func (it *ScanDumpIterator) Next() bool {
+	if it.done {
+		return false
+	}
+	iter, data, err := it.scanDump(it.iter)
+	if err != nil || iter == 0 {
+		it.err = err
+		it.done = true
+		it.iter, it.data = 0, nil
+		return false
+	}
+	it.iter, it.data = iter, data
+	return true
+}
+
+// Chunk returns the iterator and data of the current chunk, to be passed to the matching LOADCHUNK command
+func (it *ScanDumpIterator) Chunk() (int64, []byte) {
+	return it.iter, it.data
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *ScanDumpIterator) Err() error {
+	return it.err
+}
+
+type loadChunkFunc func(iter int64, data []byte) error
+
+// DumpHeader describes a stream written by BfDumpToWriter or CfDumpToWriter
+type DumpHeader struct {
+	// Version is the version of the stream layout
+	Version int
+	// Type is the data structure dumped, empty for version 1 streams
+	Type DataType
+	// Params are the BF.INFO or CF.INFO fields of the filter when it was dumped, nil for version 1 streams
+	Params map[string]int64
+}
+
+// ReadDumpHeader - Reads the header of a stream written by BfDumpToWriter or CfDumpToWriter
+func ReadDumpHeader(r io.Reader) (*DumpHeader, error) {
+	d, err := newDumpReader(bufio.NewReader(r))
+	if err != nil {
+		return nil, err
+	}
+	return &d.header, nil
+}
+
+// BfDumpToWriter - Writes the bloom filter stored at key to w as a stream of compressed and checksummed
+// BF.SCANDUMP chunks, which can be restored with BfLoadFromReader
+func (client *Client) BfDumpToWriter(key string, w io.Writer) error {
+	params, err := client.Info(key)
+	if err != nil {
+		return err
+	}
+	return dumpToWriter(w, DataTypeBloom, params, client.BfScanDumpIterator(key))
+}
+
+// BfLoadFromReader - Restores into key a bloom filter previously written with BfDumpToWriter.
+// Returns ErrCorruptDump if the content of the stream does not match its checksums, in which case
+// the filter may have been partially restored.
+func (client *Client) BfLoadFromReader(key string, r io.Reader) error {
+	return loadFromReader(r, DataTypeBloom, func(iter int64, data []byte) error {
+		_, err := client.BfLoadChunk(key, iter, data)
+		return err
+	})
+}
+
+// CfDumpToWriter - Writes the cuckoo filter stored at key to w as a stream of compressed and checksummed
+// CF.SCANDUMP chunks, which can be restored with CfLoadFromReader
+func (client *Client) CfDumpToWriter(key string, w io.Writer) error {
+	params, err := client.CfInfo(key)
+	if err != nil {
+		return err
+	}
+	return dumpToWriter(w, DataTypeCuckoo, params, client.CfScanDumpIterator(key))
+}
+
+// CfLoadFromReader - Restores into key a cuckoo filter previously written with CfDumpToWriter.
+// Returns ErrCorruptDump if the content of the stream does not match its checksums, in which case
+// the filter may have been partially restored.
+func (client *Client) CfLoadFromReader(key string, r io.Reader) error {
+	return loadFromReader(r, DataTypeCuckoo, func(iter int64, data []byte) error {
+		_, err := client.CfLoadChunk(key, iter, data)
+		return err
+	})
+}
+
+func dumpToWriter(w io.Writer, dataType DataType, params map[string]int64, it *ScanDumpIterator) error {
+	defer it.Prefetch(dumpPrefetchDepth).Stop()
+	bw := bufio.NewWriter(w)
+	if err := writeDumpHeader(bw, dataType, params); err != nil {
+		return err
+	}
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	checksum := crc32.NewIEEE()
+	chunks, size := uint32(0), uint64(0)
+	for it.Next() {
+		iter, data := it.Chunk()
+		compressed.Reset()
+		gz.Reset(&compressed)
+		if _, err := gz.Write(data); err != nil {
+			return err
+		}
+		if err := gz.Close(); err != nil {
+			return err
+		}
+		checksum.Write(data)
+		chunks++
+		size += uint64(len(data))
+		chunkHeader := []interface{}{iter, uint32(len(data)), uint32(compressed.Len()), crc32.ChecksumIEEE(data)}
+		for _, field := range chunkHeader {
+			if err := binary.Write(bw, binary.BigEndian, field); err != nil {
+				return err
+			}
+		}
+		if _, err := bw.Write(compressed.Bytes()); err != nil {
+			return err
+		}
+	}
+	if err := it.Err(); err != nil {
+		return err
+	}
+	for _, field := range []interface{}{int64(0), chunks, size, checksum.Sum32()} {
+		if err := binary.Write(bw, binary.BigEndian, field); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+func writeDumpHeader(bw *bufio.Writer, dataType DataType, params map[string]int64) error {
+	if _, err := bw.WriteString(dumpMagic); err != nil {
+		return err
+	}
+	if err := bw.WriteByte(dumpFormatVersion); err != nil {
+		return err
+	}
+	if err := bw.WriteByte(byte(len(dataType))); err != nil {
+		return err
+	}
+	if _, err := bw.WriteString(string(dataType)); err != nil {
+		return err
+	}
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if err := binary.Write(bw, binary.BigEndian, uint16(len(names))); err != nil {
+		return err
+	}
+	for _, name := range names {
+		if err := binary.Write(bw, binary.BigEndian, uint16(len(name))); err != nil {
+			return err
+		}
+		if _, err := bw.WriteString(name); err != nil {
+			return err
+		}
+		if err := binary.Write(bw, binary.BigEndian, params[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func loadFromReader(r io.Reader, dataType DataType, loadChunk loadChunkFunc) error {
+	d, err := newDumpReader(bufio.NewReader(r))
+	if err != nil {
+		return err
+	}
+	if err = d.checkType(dataType); err != nil {
+		return err
+	}
+	for {
+		iter, data, err := d.next()
+		if err != nil || iter == 0 {
+			return err
+		}
+		if err := loadChunk(iter, data); err != nil {
+			return err
+		}
+	}
+}
+
+// dumpReader reads the chunks of a dump stream, checking them against their checksums
+type dumpReader struct {
+	br       *bufio.Reader
+	header   DumpHeader
+	gz       *gzip.Reader
+	checksum hash.Hash32
+	chunks   uint32
+	size     uint64
+}
+
+// newDumpReader reads and checks the header of a dump stream
+func newDumpReader(br *bufio.Reader) (*dumpReader, error) {
+	magic := make([]byte, len(dumpMagic)+1)
+	if _, err := io.ReadFull(br, magic); err != nil {
+		return nil, ErrInvalidDump
+	}
+	if string(magic[:len(dumpMagic)]) != dumpMagic {
+		return nil, ErrInvalidDump
+	}
+	d := &dumpReader{br: br, header: DumpHeader{Version: int(magic[len(dumpMagic)])}, checksum: crc32.NewIEEE()}
+	switch d.header.Version {
+	case 1:
+		return d, nil
+	case dumpFormatVersion:
+	default:
+		return nil, fmt.Errorf("redisbloom: unsupported dump format version %d", d.header.Version)
+	}
+	typeLength, err := br.ReadByte()
+	if err != nil {
+		return nil, ErrInvalidDump
+	}
+	dataType := make([]byte, typeLength)
+	if _, err = io.ReadFull(br, dataType); err != nil {
+		return nil, ErrInvalidDump
+	}
+	d.header.Type = DataType(dataType)
+	var count uint16
+	if err = binary.Read(br, binary.BigEndian, &count); err != nil {
+		return nil, ErrInvalidDump
+	}
+	d.header.Params = make(map[string]int64, count)
+	for i := uint16(0); i < count; i++ {
+		var nameLength uint16
+		if err = binary.Read(br, binary.BigEndian, &nameLength); err != nil {
+			return nil, ErrInvalidDump
+		}
+		name := make([]byte, nameLength)
+		if _, err = io.ReadFull(br, name); err != nil {
+			return nil, ErrInvalidDump
+		}
+		var value int64
+		if err = binary.Read(br, binary.BigEndian, &value); err != nil {
+			return nil, ErrInvalidDump
+		}
+		d.header.Params[string(name)] = value
+	}
+	return d, nil
+}
+
+// checkType returns an error if the stream holds another data structure than dataType
+func (d *dumpReader) checkType(dataType DataType) error {
+	if d.header.Type != "" && d.header.Type != dataType {
+		return fmt.Errorf("redisbloom: dump stream holds a %s filter, not a %s filter", d.header.Type, dataType)
+	}
+	return nil
+}
+
+// next reads the next chunk of the stream, whose iterator is 0 at the end of the stream
+func (d *dumpReader) next() (int64, []byte, error) {
+	var iter int64
+	if err := binary.Read(d.br, binary.BigEndian, &iter); err != nil {
+		return 0, nil, fmt.Errorf("redisbloom: reading dump chunk iterator: %v", err)
+	}
+	if iter == 0 {
+		return 0, nil, d.checkTrailer()
+	}
+	if d.header.Version == 1 {
+		data, err := d.readBytes()
+		return iter, data, err
+	}
+	var length, compressedLength, checksum uint32
+	for _, field := range []*uint32{&length, &compressedLength, &checksum} {
+		if err := binary.Read(d.br, binary.BigEndian, field); err != nil {
+			return 0, nil, fmt.Errorf("redisbloom: reading dump chunk header: %v", err)
+		}
+	}
+	if length > maxDumpChunkSize || compressedLength > maxDumpChunkSize {
+		return 0, nil, ErrInvalidDump
+	}
+	compressed := io.LimitReader(d.br, int64(compressedLength))
+	var err error
+	if d.gz == nil {
+		d.gz, err = gzip.NewReader(compressed)
+	} else {
+		err = d.gz.Reset(compressed)
+	}
+	if err != nil {
+		return 0, nil, ErrCorruptDump
+	}
+	data, err := ioutil.ReadAll(io.LimitReader(d.gz, int64(length)+1))
+	if err != nil || uint32(len(data)) != length || crc32.ChecksumIEEE(data) != checksum {
+		return 0, nil, ErrCorruptDump
+	}
+	// skip what the gzip reader left unread, e.g. after a corrupted stream ended early
+	if _, err = io.Copy(ioutil.Discard, compressed); err != nil {
+		return 0, nil, fmt.Errorf("redisbloom: reading dump chunk data: %v", err)
+	}
+	d.checksum.Write(data)
+	d.chunks++
+	d.size += uint64(len(data))
+	return iter, data, nil
+}
+
+// readBytes reads the length prefixed data of a version 1 chunk
+func (d *dumpReader) readBytes() ([]byte, error) {
+	var length uint32
+	if err := binary.Read(d.br, binary.BigEndian, &length); err != nil {
+		return nil, fmt.Errorf("redisbloom: reading dump chunk length: %v", err)
+	}
+	if length > maxDumpChunkSize {
+		return nil, ErrInvalidDump
+	}
+	data := make([]byte, length)
+	if _, err := io.ReadFull(d.br, data); err != nil {
+		return nil, fmt.Errorf("redisbloom: reading dump chunk data: %v", err)
+	}
+	return data, nil
+}
+
+// checkTrailer checks the chunks read against the trailer of a version 2 stream
+func (d *dumpReader) checkTrailer() error {
+	if d.header.Version == 1 {
+		return nil
+	}
+	var chunks, checksum uint32
+	var size uint64
+	for _, field := range []interface{}{&chunks, &size, &checksum} {
+		if err := binary.Read(d.br, binary.BigEndian, field); err != nil {
+			return fmt.Errorf("redisbloom: reading dump trailer: %v", err)
+		}
+	}
+	if chunks != d.chunks || size != d.size || checksum != d.checksum.Sum32() {
+		return ErrCorruptDump
+	}
+	return nil
+}
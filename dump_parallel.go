@@ -0,0 +1,179 @@
+package redis_bloom_go
+
+import (
+	"bufio"
+	"io"
+	"sync"
+)
+
+// dumpPrefetchDepth is the number of chunks fetched ahead when a dump is written or copied
+const dumpPrefetchDepth = 4
+
+// ParallelLoadOptions tunes the loading of a dump over several connections
+type ParallelLoadOptions struct {
+	// Workers is the number of connections chunks are loaded over concurrently, 4 if zero
+	Workers int
+	// Pipeline is the number of chunks a worker sends in a single round trip, 1 if zero
+	Pipeline int
+}
+
+// dumpChunk is a chunk of a SCANDUMP sequence
+type dumpChunk struct {
+	iter int64
+	data []byte
+	err  error
+}
+
+// Prefetch makes the iterator fetch up to depth chunks ahead of Next in a goroutine, overlapping the SCANDUMP
+// round trips with the processing of the chunks already fetched. Each SCANDUMP reply carries the iterator of
+// the next chunk, so chunks cannot be requested concurrently: prefetching hides their latency instead.
+// Call it before the first call to Next, and call Stop if the iteration is abandoned before its end.
+func (it *ScanDumpIterator) Prefetch(depth int) *ScanDumpIterator {
+	fetched := make(chan dumpChunk, depth)
+	stop := make(chan struct{})
+	scanDump := it.scanDump
+	go func() {
+		defer close(fetched)
+		iter := int64(0)
+		for {
+			next, data, err := scanDump(iter)
+			select {
+			case fetched <- dumpChunk{iter: next, data: data, err: err}:
+			case <-stop:
+				return
+			}
+			if err != nil || next == 0 {
+				return
+			}
+			iter = next
+		}
+	}()
+	it.stop = stop
+	it.scanDump = func(int64) (int64, []byte, error) {
+		chunk, ok := <-fetched
+		if !ok {
+			return 0, nil, nil
+		}
+		return chunk.iter, chunk.data, chunk.err
+	}
+	return it
+}
+
+// Stop ends the prefetching of an iteration abandoned before its end
+func (it *ScanDumpIterator) Stop() {
+	if it.stop != nil {
+		close(it.stop)
+		it.stop = nil
+	}
+}
+
+// BfLoadFromReaderParallel - Restores into key a bloom filter previously written with BfDumpToWriter, like
+// BfLoadFromReader, loading its chunks concurrently over several connections. LOADCHUNK writes each chunk at
+// the position given by its iterator, so only the first chunk, which creates the filter, is loaded on its own.
+func (client *Client) BfLoadFromReaderParallel(key string, r io.Reader, opts ParallelLoadOptions) error {
+	return loadFromReaderParallel(r, DataTypeBloom, opts, client.loadChunks("BF.LOADCHUNK", key))
+}
+
+// CfLoadFromReaderParallel - Restores into key a cuckoo filter previously written with CfDumpToWriter, like
+// CfLoadFromReader, loading its chunks concurrently over several connections
+func (client *Client) CfLoadFromReaderParallel(key string, r io.Reader, opts ParallelLoadOptions) error {
+	return loadFromReaderParallel(r, DataTypeCuckoo, opts, client.loadChunks("CF.LOADCHUNK", key))
+}
+
+// loadChunks returns a function pipelining command for a batch of chunks of key over a single connection
+func (client *Client) loadChunks(command string, key string) func(chunks []dumpChunk) error {
+	return func(chunks []dumpChunk) error {
+		conn := client.Pool.Get()
+		defer conn.Close()
+		for _, chunk := range chunks {
+			if err := conn.Send(command, client.key(key), chunk.iter, chunk.data); err != nil {
+				return err
+			}
+		}
+		if err := conn.Flush(); err != nil {
+			return err
+		}
+		var outErr error
+		for range chunks {
+			if _, err := conn.Receive(); err != nil && outErr == nil {
+				outErr = err
+			}
+		}
+		return outErr
+	}
+}
+
+// loadFromReaderParallel reads the chunks of a dump stream and hands them, in batches, to workers calling
+// loadBatch concurrently, once the first chunk has been loaded. Returns the first error encountered.
+func loadFromReaderParallel(r io.Reader, dataType DataType, opts ParallelLoadOptions, loadBatch func(chunks []dumpChunk) error) error {
+	workers, pipeline := opts.Workers, opts.Pipeline
+	if workers <= 0 {
+		workers = 4
+	}
+	if pipeline <= 0 {
+		pipeline = 1
+	}
+	d, err := newDumpReader(bufio.NewReader(r))
+	if err != nil {
+		return err
+	}
+	if err = d.checkType(dataType); err != nil {
+		return err
+	}
+	iter, data, err := d.next()
+	if err != nil || iter == 0 {
+		return err
+	}
+	if err = loadBatch([]dumpChunk{{iter: iter, data: data}}); err != nil {
+		return err
+	}
+
+	var mutex sync.Mutex
+	var loadErr error
+	failed := func() bool {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return loadErr != nil
+	}
+	batches := make(chan []dumpChunk, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for batch := range batches {
+				if failed() {
+					continue
+				}
+				if err := loadBatch(batch); err != nil {
+					mutex.Lock()
+					if loadErr == nil {
+						loadErr = err
+					}
+					mutex.Unlock()
+				}
+			}
+		}()
+	}
+	batch := make([]dumpChunk, 0, pipeline)
+	for !failed() {
+		iter, data, err = d.next()
+		if err != nil || iter == 0 {
+			break
+		}
+		batch = append(batch, dumpChunk{iter: iter, data: data})
+		if len(batch) == pipeline {
+			batches <- batch
+			batch = make([]dumpChunk, 0, pipeline)
+		}
+	}
+	if err == nil && len(batch) > 0 {
+		batches <- batch
+	}
+	close(batches)
+	wg.Wait()
+	if loadErr != nil {
+		return loadErr
+	}
+	return err
+}
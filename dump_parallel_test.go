@@ -0,0 +1,134 @@
+package redis_bloom_go
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testDumpStream returns a dump stream of chunks chunks, the first one being the header
+func testDumpStream(t *testing.T, chunks int) []byte {
+	var buf bytes.Buffer
+	err := dumpToWriter(&buf, DataTypeBloom, nil, &ScanDumpIterator{scanDump: func(iter int64) (int64, []byte, error) {
+		if iter == int64(chunks) {
+			return 0, nil, nil
+		}
+		return iter + 1, []byte{byte(iter + 1)}, nil
+	}})
+	assert.Nil(t, err)
+	return buf.Bytes()
+}
+
+func TestLoadFromReaderParallel(t *testing.T) {
+	stream := testDumpStream(t, 10)
+	var mutex sync.Mutex
+	var loaded []int64
+	var batchSizes []int
+	err := loadFromReaderParallel(bytes.NewReader(stream), DataTypeBloom, ParallelLoadOptions{Workers: 3, Pipeline: 4}, func(chunks []dumpChunk) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+		for _, chunk := range chunks {
+			assert.Equal(t, []byte{byte(chunk.iter)}, chunk.data)
+			loaded = append(loaded, chunk.iter)
+		}
+		batchSizes = append(batchSizes, len(chunks))
+		return nil
+	})
+	assert.Nil(t, err)
+	// the header chunk is loaded first, on its own
+	assert.Equal(t, int64(1), loaded[0])
+	assert.Equal(t, 1, batchSizes[0])
+	sort.Slice(loaded, func(i, j int) bool { return loaded[i] < loaded[j] })
+	assert.Equal(t, []int64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, loaded)
+	sort.Ints(batchSizes[1:])
+	assert.Equal(t, []int{1, 1, 4, 4}, batchSizes)
+}
+
+func TestLoadFromReaderParallel_Errors(t *testing.T) {
+	stream := testDumpStream(t, 20)
+	loadErr := errors.New("load failed")
+	err := loadFromReaderParallel(bytes.NewReader(stream), DataTypeBloom, ParallelLoadOptions{}, func(chunks []dumpChunk) error {
+		if chunks[0].iter == 5 {
+			return loadErr
+		}
+		return nil
+	})
+	assert.Equal(t, loadErr, err)
+
+	err = loadFromReaderParallel(bytes.NewReader(stream[:len(stream)-3]), DataTypeBloom, ParallelLoadOptions{}, func(chunks []dumpChunk) error {
+		return nil
+	})
+	assert.NotNil(t, err)
+
+	err = loadFromReaderParallel(bytes.NewReader([]byte("not a dump")), DataTypeBloom, ParallelLoadOptions{}, func(chunks []dumpChunk) error {
+		return nil
+	})
+	assert.Equal(t, ErrInvalidDump, err)
+}
+
+func TestScanDumpIterator_Prefetch(t *testing.T) {
+	it := (&ScanDumpIterator{scanDump: func(iter int64) (int64, []byte, error) {
+		if iter == 3 {
+			return 0, nil, nil
+		}
+		return iter + 1, []byte{byte(iter + 1)}, nil
+	}}).Prefetch(2)
+	iters := make([]int64, 0)
+	for it.Next() {
+		iter, data := it.Chunk()
+		assert.Equal(t, []byte{byte(iter)}, data)
+		iters = append(iters, iter)
+	}
+	assert.Nil(t, it.Err())
+	assert.Equal(t, []int64{1, 2, 3}, iters)
+	it.Stop()
+
+	scanErr := errors.New("scan failed")
+	it = (&ScanDumpIterator{scanDump: func(iter int64) (int64, []byte, error) {
+		if iter == 1 {
+			return 0, nil, scanErr
+		}
+		return 1, []byte("a"), nil
+	}}).Prefetch(1)
+	assert.True(t, it.Next())
+	assert.False(t, it.Next())
+	assert.Equal(t, scanErr, it.Err())
+
+	// an abandoned iteration stops fetching
+	fetched := make(chan int64, 100)
+	it = (&ScanDumpIterator{scanDump: func(iter int64) (int64, []byte, error) {
+		fetched <- iter
+		return iter + 1, nil, nil
+	}}).Prefetch(1)
+	assert.True(t, it.Next())
+	it.Stop()
+	assert.True(t, len(fetched) <= 4)
+}
+
+func TestClient_BfLoadFromReaderParallel(t *testing.T) {
+	client.FlushAll()
+	key := "test_bf_load_parallel"
+	err := client.Reserve(key, 0.01, 100000)
+	assert.Nil(t, err)
+	items := make([]string, 1000)
+	for i := range items {
+		items[i] = string(rune('a'+i%26)) + string(rune('a'+i/26))
+	}
+	_, err = client.BfAddMulti(key, items)
+	assert.Nil(t, err)
+	var buf bytes.Buffer
+	assert.Nil(t, client.BfDumpToWriter(key, &buf))
+
+	restored := key + "_restored"
+	err = client.BfLoadFromReaderParallel(restored, bytes.NewReader(buf.Bytes()), ParallelLoadOptions{Workers: 2, Pipeline: 2})
+	assert.Nil(t, err)
+	exists, err := client.BfExistsMulti(restored, items)
+	assert.Nil(t, err)
+	for _, found := range exists {
+		assert.Equal(t, int64(1), found)
+	}
+}
@@ -0,0 +1,205 @@
+package redis_bloom_go
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDumpToWriterLoadFromReader(t *testing.T) {
+	chunks := map[int64][]byte{1: []byte("header"), 9: []byte("bits"), 17: {}}
+	next := map[int64]int64{0: 1, 1: 9, 9: 17, 17: 0}
+	var buf bytes.Buffer
+	err := dumpToWriter(&buf, DataTypeBloom, nil, &ScanDumpIterator{scanDump: func(iter int64) (int64, []byte, error) {
+		return next[iter], chunks[next[iter]], nil
+	}})
+	assert.Nil(t, err)
+
+	loaded := map[int64][]byte{}
+	err = loadFromReader(bytes.NewReader(buf.Bytes()), DataTypeBloom, func(iter int64, data []byte) error {
+		loaded[iter] = data
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, chunks, loaded)
+
+	// truncated stream
+	err = loadFromReader(bytes.NewReader(buf.Bytes()[:buf.Len()-3]), DataTypeBloom, func(iter int64, data []byte) error {
+		return nil
+	})
+	assert.NotNil(t, err)
+
+	// unknown content
+	err = loadFromReader(bytes.NewReader([]byte("not a dump")), DataTypeBloom, func(iter int64, data []byte) error {
+		return nil
+	})
+	assert.Equal(t, ErrInvalidDump, err)
+
+	// scan errors are propagated
+	scanErr := errors.New("scan failed")
+	err = dumpToWriter(&buf, DataTypeBloom, nil, &ScanDumpIterator{scanDump: func(iter int64) (int64, []byte, error) {
+		return 0, nil, scanErr
+	}})
+	assert.Equal(t, scanErr, err)
+}
+
+func TestDumpFormat(t *testing.T) {
+	data := bytes.Repeat([]byte("bloom bits "), 1000)
+	var buf bytes.Buffer
+	err := dumpToWriter(&buf, DataTypeCuckoo, map[string]int64{"Size": 1080, "Bucket size": 2}, &ScanDumpIterator{
+		scanDump: func(iter int64) (int64, []byte, error) {
+			if iter == 0 {
+				return 1, data, nil
+			}
+			return 0, nil, nil
+		}})
+	assert.Nil(t, err)
+	// chunks are compressed
+	assert.True(t, buf.Len() < len(data)/10)
+	stream := buf.Bytes()
+
+	header, err := ReadDumpHeader(bytes.NewReader(stream))
+	assert.Nil(t, err)
+	assert.Equal(t, &DumpHeader{
+		Version: 2,
+		Type:    DataTypeCuckoo,
+		Params:  map[string]int64{"Size": 1080, "Bucket size": 2},
+	}, header)
+
+	var loaded []byte
+	err = loadFromReader(bytes.NewReader(stream), DataTypeCuckoo, func(iter int64, chunk []byte) error {
+		loaded = chunk
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, data, loaded)
+
+	// a cuckoo filter dump cannot be loaded as a bloom filter
+	err = loadFromReader(bytes.NewReader(stream), DataTypeBloom, func(int64, []byte) error { return nil })
+	assert.NotNil(t, err)
+
+	// corruption of the chunk data or of the trailer is detected
+	for _, offset := range []int{len(stream) - 30, len(stream) - 2} {
+		corrupted := append([]byte{}, stream...)
+		corrupted[offset] ^= 0xff
+		err = loadFromReader(bytes.NewReader(corrupted), DataTypeCuckoo, func(int64, []byte) error { return nil })
+		assert.Equal(t, ErrCorruptDump, err)
+	}
+}
+
+func TestDumpFormat_Version1(t *testing.T) {
+	var buf bytes.Buffer
+	buf.WriteString(dumpMagic)
+	buf.WriteByte(1)
+	binary.Write(&buf, binary.BigEndian, int64(1))
+	binary.Write(&buf, binary.BigEndian, uint32(6))
+	buf.WriteString("header")
+	binary.Write(&buf, binary.BigEndian, int64(0))
+
+	header, err := ReadDumpHeader(bytes.NewReader(buf.Bytes()))
+	assert.Nil(t, err)
+	assert.Equal(t, &DumpHeader{Version: 1}, header)
+
+	loaded := map[int64][]byte{}
+	err = loadFromReader(bytes.NewReader(buf.Bytes()), DataTypeBloom, func(iter int64, data []byte) error {
+		loaded[iter] = data
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, map[int64][]byte{1: []byte("header")}, loaded)
+}
+
+func TestScanDumpIterator(t *testing.T) {
+	calls := 0
+	it := &ScanDumpIterator{scanDump: func(iter int64) (int64, []byte, error) {
+		calls++
+		if iter == 0 {
+			return 1, []byte("a"), nil
+		}
+		return 0, nil, nil
+	}}
+	assert.True(t, it.Next())
+	iter, data := it.Chunk()
+	assert.Equal(t, int64(1), iter)
+	assert.Equal(t, []byte("a"), data)
+	assert.False(t, it.Next())
+	assert.False(t, it.Next())
+	assert.Nil(t, it.Err())
+	assert.Equal(t, 2, calls)
+
+	scanErr := errors.New("scan failed")
+	it = &ScanDumpIterator{scanDump: func(iter int64) (int64, []byte, error) {
+		return 0, nil, scanErr
+	}}
+	assert.False(t, it.Next())
+	assert.Equal(t, scanErr, it.Err())
+}
+
+func TestClient_BfScanDumpIterator(t *testing.T) {
+	client.FlushAll()
+	key := "test_bf_scandump_iterator"
+	err := client.Reserve(key, 0.01, 1000)
+	assert.Nil(t, err)
+	client.Add(key, "1")
+	type chunk struct {
+		iter int64
+		data []byte
+	}
+	chunks := make([]chunk, 0)
+	it := client.BfScanDumpIterator(key)
+	for it.Next() {
+		iter, data := it.Chunk()
+		chunks = append(chunks, chunk{iter, data})
+	}
+	assert.Nil(t, it.Err())
+	assert.NotEmpty(t, chunks)
+
+	client.FlushAll()
+	for _, c := range chunks {
+		ret, err := client.BfLoadChunk(key, c.iter, c.data)
+		assert.Nil(t, err)
+		assert.Equal(t, "OK", ret)
+	}
+	exists, err := client.Exists(key, "1")
+	assert.Nil(t, err)
+	assert.True(t, exists)
+}
+
+func TestClient_BfDumpToWriter(t *testing.T) {
+	client.FlushAll()
+	key := "test_bf_dump_writer"
+	err := client.Reserve(key, 0.01, 1000)
+	assert.Nil(t, err)
+	client.Add(key, "1")
+	var buf bytes.Buffer
+	err = client.BfDumpToWriter(key, &buf)
+	assert.Nil(t, err)
+
+	client.FlushAll()
+	err = client.BfLoadFromReader(key, &buf)
+	assert.Nil(t, err)
+	exists, err := client.Exists(key, "1")
+	assert.Nil(t, err)
+	assert.True(t, exists)
+}
+
+func TestClient_CfDumpToWriter(t *testing.T) {
+	client.FlushAll()
+	key := "test_cf_dump_writer"
+	_, err := client.CfReserveWithOptions(key, 100, WithBucketSize(50))
+	assert.Nil(t, err)
+	client.CfAdd(key, "a")
+	var buf bytes.Buffer
+	err = client.CfDumpToWriter(key, &buf)
+	assert.Nil(t, err)
+
+	client.FlushAll()
+	err = client.CfLoadFromReader(key, &buf)
+	assert.Nil(t, err)
+	exists, err := client.CfExists(key, "a")
+	assert.Nil(t, err)
+	assert.True(t, exists)
+}
@@ -0,0 +1,193 @@
+package redis_bloom_go
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// Defaults of CF.RESERVE, used to check cuckoo filters created without the corresponding option
+const (
+	defaultCuckooBucketSize    = 2
+	defaultCuckooMaxIterations = 20
+	defaultCuckooExpansion     = 1
+)
+
+// ParamMismatch is a parameter of an existing data structure which differs from the expected one
+type ParamMismatch struct {
+	// Name is the name of the parameter, as reported by the INFO command
+	Name     string
+	Expected interface{}
+	Actual   interface{}
+}
+
+// ParamsMismatchError is returned by the Ensure helpers when the data structure stored at Key exists
+// but was created with different parameters
+type ParamsMismatchError struct {
+	Key        string
+	Type       DataType
+	Mismatches []ParamMismatch
+}
+
+func (e *ParamsMismatchError) Error() string {
+	diffs := make([]string, len(e.Mismatches))
+	for i, mismatch := range e.Mismatches {
+		diffs[i] = fmt.Sprintf("%s is %v instead of %v", mismatch.Name, mismatch.Actual, mismatch.Expected)
+	}
+	return fmt.Sprintf("redisbloom: %s %s exists with different parameters: %s", e.Type, e.Key, strings.Join(diffs, ", "))
+}
+
+// paramsCheck gathers the mismatches found while comparing parameters
+type paramsCheck []ParamMismatch
+
+func (c *paramsCheck) compare(name string, expected interface{}, actual interface{}) {
+	if expected != actual {
+		*c = append(*c, ParamMismatch{Name: name, Expected: expected, Actual: actual})
+	}
+}
+
+// err returns a *ParamsMismatchError if mismatches were found, nil otherwise
+func (c paramsCheck) err(key string, dataType DataType) error {
+	if len(c) == 0 {
+		return nil
+	}
+	return &ParamsMismatchError{Key: key, Type: dataType, Mismatches: c}
+}
+
+// isExistsError reports whether err is the error a creation command replies when its key already exists
+func isExistsError(err error) bool {
+	_, ok := err.(redis.Error)
+	return ok && (strings.Contains(err.Error(), "item exists") || strings.Contains(err.Error(), "already exists"))
+}
+
+// EnsureBfReserved - Creates the bloom filter stored at key with errorRate and capacity if it does not exist.
+// If it exists, checks its capacity and, when set with WithExpansion, its expansion rate against BF.INFO, and
+// returns a *ParamsMismatchError if they differ. BF.INFO does not report the error rate, which is not checked.
+// opts - WithExpansion and WithExtraArgs
+func (client *Client) EnsureBfReserved(key string, errorRate float64, capacity uint64, opts ...CallOption) error {
+	if err := client.checkBloomBudget(key, errorRate, capacity); err != nil {
+		return err
+	}
+	o := newCallOptions(opts)
+	args := redis.Args{client.key(key)}.Add(strconv.FormatFloat(errorRate, 'g', 16, 64), capacity)
+	if o.expansion != nil {
+		args = args.Add("EXPANSION", *o.expansion)
+	}
+	args = append(args, o.extraArgs...)
+	conn := client.Pool.Get()
+	_, err := conn.Do("BF.RESERVE", args...)
+	conn.Close()
+	if !isExistsError(err) {
+		return err
+	}
+	info, err := client.BfInfoTyped(key)
+	if err != nil {
+		return err
+	}
+	check := paramsCheck{}
+	// Capacity adds up the capacities of the sub-filters, each expansion times larger than the previous one
+	sum := 0.0
+	for _, c := range subFilterCapacities(1, info.Filters, float64(info.ExpansionRate)) {
+		sum += c
+	}
+	check.compare("Capacity", int64(capacity), int64(math.Round(float64(info.Capacity)/sum)))
+	if o.expansion != nil {
+		check.compare("Expansion rate", *o.expansion, info.ExpansionRate)
+	}
+	return check.err(key, DataTypeBloom)
+}
+
+// EnsureCfReserved - Creates the cuckoo filter stored at key with capacity if it does not exist.
+// If it exists, checks its number of buckets, which derives from capacity and the bucket size, its bucket
+// size, max iterations and expansion rate against CF.INFO, the server defaults standing for options not set,
+// and returns a *ParamsMismatchError if they differ.
+// opts - WithBucketSize, WithMaxIterations, WithExpansion and WithExtraArgs
+func (client *Client) EnsureCfReserved(key string, capacity int64, opts ...CallOption) error {
+	_, err := client.CfReserveWithOptions(key, capacity, opts...)
+	if !isExistsError(err) {
+		return err
+	}
+	info, err := client.CfInfoTyped(key)
+	if err != nil {
+		return err
+	}
+	o := newCallOptions(opts)
+	bucketSize, maxIterations, expansion := int64(defaultCuckooBucketSize), int64(defaultCuckooMaxIterations), int64(defaultCuckooExpansion)
+	if o.bucketSize != nil {
+		bucketSize = *o.bucketSize
+	}
+	if o.maxIterations != nil {
+		maxIterations = *o.maxIterations
+	}
+	if o.expansion != nil {
+		expansion = *o.expansion
+	}
+	check := paramsCheck{}
+	// CF.RESERVE rounds the number of buckets of the first sub-filter, which INFO reports, up to a power of 2
+	check.compare("Number of buckets", cuckooBuckets(capacity, bucketSize), info.Buckets)
+	check.compare("Bucket size", bucketSize, info.BucketSize)
+	check.compare("Max iterations", maxIterations, info.MaxIterations)
+	check.compare("Expansion rate", expansion, info.ExpansionRate)
+	return check.err(key, DataTypeCuckoo)
+}
+
+// EnsureCmsInitByDim - Creates the count-min sketch stored at key with width and depth if it does not exist.
+// If it exists, checks its width and depth against CMS.INFO and returns a *ParamsMismatchError if they differ.
+func (client *Client) EnsureCmsInitByDim(key string, width int64, depth int64) error {
+	_, err := client.CmsInitByDim(key, width, depth)
+	if !isExistsError(err) {
+		return err
+	}
+	info, err := client.CmsInfoTyped(key)
+	if err != nil {
+		return err
+	}
+	check := paramsCheck{}
+	check.compare("width", width, info.Width)
+	check.compare("depth", depth, info.Depth)
+	return check.err(key, DataTypeCMS)
+}
+
+// EnsureTopkReserved - Creates the top-k stored at key with topk, width, depth and decay if it does not exist.
+// If it exists, checks its parameters against TOPK.INFO and returns a *ParamsMismatchError if they differ.
+func (client *Client) EnsureTopkReserved(key string, topk int64, width int64, depth int64, decay float64) error {
+	_, err := client.TopkReserve(key, topk, width, depth, decay)
+	if !isExistsError(err) {
+		return err
+	}
+	info, err := client.TopkInfoTyped(key)
+	if err != nil {
+		return err
+	}
+	check := paramsCheck{}
+	check.compare("k", topk, info.K)
+	check.compare("width", width, info.Width)
+	check.compare("depth", depth, info.Depth)
+	// TOPK.INFO formats the decay with more digits than it was given with
+	if math.Abs(decay-info.Decay) > 1e-9 {
+		check.compare("decay", decay, info.Decay)
+	}
+	return check.err(key, DataTypeTopK)
+}
+
+// EnsureTdCreated - Creates the t-digest stored at key if it does not exist. If it exists and a compression
+// is set with WithCompression, checks it against TDIGEST.INFO and returns a *ParamsMismatchError if it differs.
+// opts - WithCompression and WithExtraArgs
+func (client *Client) EnsureTdCreated(key string, opts ...CallOption) error {
+	_, err := client.TdCreateWithOptions(key, opts...)
+	if !isExistsError(err) {
+		return err
+	}
+	info, err := client.TdInfo(key)
+	if err != nil {
+		return err
+	}
+	check := paramsCheck{}
+	if o := newCallOptions(opts); o.compression != nil {
+		check.compare("Compression", *o.compression, info.Compression())
+	}
+	return check.err(key, DataTypeTDigest)
+}
@@ -0,0 +1,55 @@
+package redis_bloom_go
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParamsMismatchError(t *testing.T) {
+	check := paramsCheck{}
+	check.compare("width", int64(100), int64(100))
+	assert.Nil(t, check.err("sketch", DataTypeCMS))
+	check.compare("depth", int64(5), int64(7))
+	err := check.err("sketch", DataTypeCMS)
+	assert.Equal(t, &ParamsMismatchError{Key: "sketch", Type: DataTypeCMS, Mismatches: []ParamMismatch{{Name: "depth", Expected: int64(5), Actual: int64(7)}}}, err)
+	assert.Equal(t, "redisbloom: cms sketch exists with different parameters: depth is 7 instead of 5", err.Error())
+}
+
+func TestIsExistsError(t *testing.T) {
+	assert.True(t, isExistsError(redis.Error("ERR item exists")))
+	assert.True(t, isExistsError(redis.Error("CMS: key already exists")))
+	assert.False(t, isExistsError(redis.Error("CMS: key does not exist")))
+	assert.False(t, isExistsError(errors.New("item exists")))
+	assert.False(t, isExistsError(nil))
+}
+
+func TestClient_EnsureReserved(t *testing.T) {
+	client.FlushAll()
+	assert.Nil(t, client.EnsureBfReserved("test_ensure_bf", 0.01, 1000, WithExpansion(2)))
+	assert.Nil(t, client.EnsureBfReserved("test_ensure_bf", 0.01, 1000, WithExpansion(2)))
+	err := client.EnsureBfReserved("test_ensure_bf", 0.01, 2000)
+	assert.IsType(t, &ParamsMismatchError{}, err)
+	assert.Equal(t, "Capacity", err.(*ParamsMismatchError).Mismatches[0].Name)
+
+	assert.Nil(t, client.EnsureCfReserved("test_ensure_cf", 1000, WithBucketSize(4)))
+	assert.Nil(t, client.EnsureCfReserved("test_ensure_cf", 1000, WithBucketSize(4)))
+	assert.IsType(t, &ParamsMismatchError{}, client.EnsureCfReserved("test_ensure_cf", 1000))
+
+	assert.Nil(t, client.EnsureCmsInitByDim("test_ensure_cms", 100, 5))
+	assert.Nil(t, client.EnsureCmsInitByDim("test_ensure_cms", 100, 5))
+	assert.IsType(t, &ParamsMismatchError{}, client.EnsureCmsInitByDim("test_ensure_cms", 100, 7))
+
+	assert.Nil(t, client.EnsureTopkReserved("test_ensure_topk", 10, 50, 5, 0.9))
+	assert.Nil(t, client.EnsureTopkReserved("test_ensure_topk", 10, 50, 5, 0.9))
+	assert.IsType(t, &ParamsMismatchError{}, client.EnsureTopkReserved("test_ensure_topk", 20, 50, 5, 0.9))
+
+	assert.Nil(t, client.EnsureTdCreated("test_ensure_td", WithCompression(200)))
+	assert.Nil(t, client.EnsureTdCreated("test_ensure_td"))
+	assert.IsType(t, &ParamsMismatchError{}, client.EnsureTdCreated("test_ensure_td", WithCompression(100)))
+
+	// a key of another data structure is not mistaken for an existing one
+	assert.NotNil(t, client.EnsureCmsInitByDim("test_ensure_bf", 100, 5))
+}
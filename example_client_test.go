@@ -88,6 +88,26 @@ func ExampleClient_TdAdd() {
 
 }
 
+// exemplifies the TdAddValues function
+func ExampleClient_TdAddValues() {
+	host := "localhost:6379"
+	var client = redisbloom.NewClient(host, "nohelp", nil)
+
+	key := "example_values"
+	_, err := client.TdCreate(key, 100)
+	if err != nil {
+		fmt.Println("Error:", err)
+	}
+
+	ret, err := client.TdAddValues(key, 1.0, 2.0, 2.0, 3.0)
+	if err != nil {
+		fmt.Println("Error:", err)
+	}
+
+	fmt.Println(ret)
+	// Output: OK
+}
+
 // exemplifies the TdMin function
 func ExampleClient_TdMin() {
 	host := "localhost:6379"
@@ -0,0 +1,99 @@
+package redis_bloom_go
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+)
+
+// ItemCount is an item and its count in a CountsReport
+type ItemCount struct {
+	Item  string `json:"item"`
+	Count int64  `json:"count"`
+}
+
+// CountsReport is a snapshot of the counts of items in a top-k or a count-min sketch, for reporting pipelines
+type CountsReport struct {
+	Key  string   `json:"key"`
+	Type DataType `json:"type"`
+	// Time is when the counts were read
+	Time time.Time `json:"time"`
+	// Info holds the parameters of the sketch: k, width, depth and decay of a top-k,
+	// width, depth and total count of a count-min sketch
+	Info   map[string]interface{} `json:"info"`
+	Counts []ItemCount            `json:"counts"`
+}
+
+// TopkReport - Returns the items of the top-k stored at key with their counts, from the most to the least
+// frequent, along with the parameters of the top-k
+func (client *Client) TopkReport(key string) (*CountsReport, error) {
+	info, err := client.TopkInfoTyped(key)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := client.TopkListWithCount(key)
+	if err != nil {
+		return nil, err
+	}
+	report := &CountsReport{
+		Key:  key,
+		Type: DataTypeTopK,
+		Time: time.Now().UTC(),
+		Info: map[string]interface{}{"k": info.K, "width": info.Width, "depth": info.Depth, "decay": info.Decay},
+	}
+	for _, hitter := range topHeavyHitters(counts, int64(len(counts))) {
+		report.Counts = append(report.Counts, ItemCount{Item: hitter.Item, Count: hitter.Count})
+	}
+	return report, nil
+}
+
+// CmsReport - Returns the counts of items in the count-min sketch stored at key, in the order of items,
+// along with the parameters of the sketch
+func (client *Client) CmsReport(key string, items []string) (*CountsReport, error) {
+	info, err := client.CmsInfoTyped(key)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := client.CmsQuery(key, items)
+	if err != nil {
+		return nil, err
+	}
+	report := &CountsReport{
+		Key:  key,
+		Type: DataTypeCMS,
+		Time: time.Now().UTC(),
+		Info: map[string]interface{}{"width": info.Width, "depth": info.Depth, "count": info.Count},
+	}
+	for i, item := range items {
+		report.Counts = append(report.Counts, ItemCount{Item: item, Count: counts[i]})
+	}
+	return report, nil
+}
+
+// WriteJSON - Writes the report as a single JSON object
+func (r *CountsReport) WriteJSON(w io.Writer) error {
+	return json.NewEncoder(w).Encode(r)
+}
+
+// WriteCSV - Writes a CSV record per item, with the columns time, key, type, item and count, preceded by a
+// header record if header is set. The time is formatted with RFC 3339. Reports of several keys or times
+// can be written one after the other to the same writer.
+func (r *CountsReport) WriteCSV(w io.Writer, header bool) error {
+	writer := csv.NewWriter(w)
+	if header {
+		if err := writer.Write([]string{"time", "key", "type", "item", "count"}); err != nil {
+			return err
+		}
+	}
+	timestamp := r.Time.Format(time.RFC3339Nano)
+	for _, count := range r.Counts {
+		record := []string{timestamp, r.Key, string(r.Type), count.Item, strconv.FormatInt(count.Count, 10)}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
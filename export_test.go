@@ -0,0 +1,55 @@
+package redis_bloom_go
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testCountsReport() *CountsReport {
+	return &CountsReport{
+		Key:    "pages",
+		Type:   DataTypeTopK,
+		Time:   time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		Info:   map[string]interface{}{"k": int64(2)},
+		Counts: []ItemCount{{"/home", 10}, {"/a,b", 3}},
+	}
+}
+
+func TestCountsReport_WriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	assert.Nil(t, testCountsReport().WriteCSV(&buf, true))
+	assert.Nil(t, testCountsReport().WriteCSV(&buf, false))
+	assert.Equal(t, "time,key,type,item,count\n"+
+		"2024-05-01T12:00:00Z,pages,topk,/home,10\n"+
+		"2024-05-01T12:00:00Z,pages,topk,\"/a,b\",3\n"+
+		"2024-05-01T12:00:00Z,pages,topk,/home,10\n"+
+		"2024-05-01T12:00:00Z,pages,topk,\"/a,b\",3\n", buf.String())
+}
+
+func TestCountsReport_WriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	assert.Nil(t, testCountsReport().WriteJSON(&buf))
+	assert.JSONEq(t, `{"key":"pages","type":"topk","time":"2024-05-01T12:00:00Z","info":{"k":2},
+		"counts":[{"item":"/home","count":10},{"item":"/a,b","count":3}]}`, buf.String())
+}
+
+func TestClient_Reports(t *testing.T) {
+	client.FlushAll()
+	client.TopkReserve("test_report_topk", 2, 50, 5, 0.9)
+	client.TopkAdd("test_report_topk", []string{"a", "b", "b"})
+	report, err := client.TopkReport("test_report_topk")
+	assert.Nil(t, err)
+	assert.Equal(t, DataTypeTopK, report.Type)
+	assert.Equal(t, int64(2), report.Info["k"])
+	assert.Equal(t, []ItemCount{{"b", 2}, {"a", 1}}, report.Counts)
+
+	client.CmsInitByDim("test_report_cms", 100, 5)
+	client.CmsIncrBy("test_report_cms", map[string]int64{"a": 3})
+	report, err = client.CmsReport("test_report_cms", []string{"a", "z"})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), report.Info["count"])
+	assert.Equal(t, []ItemCount{{"a", 3}, {"z", 0}}, report.Counts)
+}
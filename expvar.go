@@ -0,0 +1,75 @@
+package redis_bloom_go
+
+import "expvar"
+
+// WithExpvar publishes the statistics of the client with expvar under name, for the applications monitored
+// through /debug/vars rather than Prometheus: the commands sent, their errors and total latency by command,
+// the retries of WithRetry, the connections refused by an exhausted pool and the statistics of the connection
+// pool. Like expvar.Publish, it panics if name is already published. Commands are counted per attempt when
+// WithExpvar is given before WithRetry.
+func WithExpvar(name string) ClientOption {
+	return func(client *Client) {
+		NewMetrics(client).PublishExpvar(name)
+	}
+}
+
+// PublishExpvar - Publishes the metrics with expvar under name, as a JSON object computed on each read.
+// Like expvar.Publish, it panics if name is already published.
+func (m *Metrics) PublishExpvar(name string) {
+	expvar.Publish(name, expvar.Func(func() interface{} {
+		return m.expvarStats()
+	}))
+}
+
+// expvarCommandStats are the statistics of a command published by PublishExpvar
+type expvarCommandStats struct {
+	Count        uint64  `json:"count"`
+	Errors       uint64  `json:"errors"`
+	TotalSeconds float64 `json:"total_seconds"`
+}
+
+// poolStatsReport are the statistics of a connection pool, as published by PublishExpvar and the health handler
+type poolStatsReport struct {
+	Connections     int     `json:"connections"`
+	IdleConnections int     `json:"idle_connections"`
+	Waits           int64   `json:"waits"`
+	WaitSeconds     float64 `json:"wait_seconds"`
+}
+
+// expvarStats are the statistics published by PublishExpvar
+type expvarStats struct {
+	Commands      map[string]expvarCommandStats `json:"commands"`
+	CommandsTotal uint64                        `json:"commands_total"`
+	ErrorsTotal   uint64                        `json:"errors_total"`
+	Retries       uint64                        `json:"retries"`
+	PoolExhausted uint64                        `json:"pool_exhausted"`
+	Pool          *poolStatsReport              `json:"pool,omitempty"`
+}
+
+func (m *Metrics) expvarStats() expvarStats {
+	stats := expvarStats{Retries: poolRetries(m.client.Pool), Pool: newPoolStatsReport(m.client.Pool)}
+	m.mutex.Lock()
+	stats.Commands = make(map[string]expvarCommandStats, len(m.commands))
+	for name, metrics := range m.commands {
+		stats.Commands[name] = expvarCommandStats{Count: metrics.count, Errors: metrics.errors, TotalSeconds: metrics.sum}
+		stats.CommandsTotal += metrics.count
+		stats.ErrorsTotal += metrics.errors
+	}
+	stats.PoolExhausted = m.poolExhausted
+	m.mutex.Unlock()
+	return stats
+}
+
+// newPoolStatsReport returns the statistics of pool, nil if it is not and does not wrap a redis.Pool
+func newPoolStatsReport(pool ConnPool) *poolStatsReport {
+	stats, ok := poolStats(pool)
+	if !ok {
+		return nil
+	}
+	return &poolStatsReport{
+		Connections:     stats.ActiveCount,
+		IdleConnections: stats.IdleCount,
+		Waits:           stats.WaitCount,
+		WaitSeconds:     stats.WaitDuration.Seconds(),
+	}
+}
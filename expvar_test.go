@@ -0,0 +1,43 @@
+package redis_bloom_go
+
+import (
+	"encoding/json"
+	"errors"
+	"expvar"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithExpvar(t *testing.T) {
+	conn := &fakeConn{replies: []interface{}{io.EOF, int64(1), int64(0)}}
+	c := NewClientFromPool(&redis.Pool{}, "test")
+	pool := c.Pool
+	c.Pool = &fakePool{conn: conn}
+	WithRetry(RetryPolicy{BaseDelay: time.Microsecond})(c)
+	WithExpvar("redisbloom_test_expvar")(c)
+
+	exists, err := c.Exists("bf", "a")
+	assert.Nil(t, err)
+	assert.True(t, exists)
+	_, err = c.Add("bf", "a")
+	assert.Nil(t, err)
+
+	var stats expvarStats
+	assert.Nil(t, json.Unmarshal([]byte(expvar.Get("redisbloom_test_expvar").String()), &stats))
+	assert.Equal(t, uint64(2), stats.CommandsTotal)
+	assert.Equal(t, uint64(0), stats.ErrorsTotal)
+	assert.Equal(t, uint64(1), stats.Retries)
+	assert.Equal(t, uint64(1), stats.Commands["BF.EXISTS"].Count)
+	assert.Nil(t, stats.Pool)
+
+	m := NewMetrics(NewClientFromPool(pool.(*redis.Pool), "test"))
+	m.AfterCommand("CMS.QUERY", nil, time.Second, errors.New("boom"))
+	m.PublishExpvar("redisbloom_test_expvar_metrics")
+	assert.Nil(t, json.Unmarshal([]byte(expvar.Get("redisbloom_test_expvar_metrics").String()), &stats))
+	assert.Equal(t, expvarCommandStats{Count: 1, Errors: 1, TotalSeconds: 1}, stats.Commands["CMS.QUERY"])
+	assert.Equal(t, &poolStatsReport{}, stats.Pool)
+}
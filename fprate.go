@@ -0,0 +1,109 @@
+package redis_bloom_go
+
+import (
+	"github.com/gomodule/redigo/redis"
+)
+
+const (
+	// fpStatsSuffix is appended to the key of a filter to store the counters of its reported false positives
+	fpStatsSuffix = ":fp"
+	// fpItemsSuffix is appended to the key of a filter to store its last reported false positives
+	fpItemsSuffix = ":fp:items"
+	// fpRecentItems is the number of reported false positives kept by ReportFalsePositive
+	fpRecentItems = 100
+)
+
+// FpReport compares the false positives observed on a bloom filter with the estimated false positive rate
+type FpReport struct {
+	// EstimatedFpRate is the false positive rate estimated from BF.INFO, as in FilterHealth
+	EstimatedFpRate float64
+	// FalsePositives is the number of false positives reported with ReportFalsePositive
+	FalsePositives int64
+	// Lookups is the number of lookups of items known not to be in the filter, reported with ReportLookups
+	Lookups int64
+	// ObservedFpRate is FalsePositives divided by Lookups, zero when no lookup was reported
+	ObservedFpRate float64
+	// RecentItems are the last items reported with ReportFalsePositive, the most recent first
+	RecentItems []string
+}
+
+// BfEstimatedFpRate - Estimates the probability that the bloom filter stored at key, which was created with
+// errorRate (BF.INFO does not report it), answers positively for an item never added, from the capacity,
+// number of items and sub-filters reported by BF.INFO
+func (client *Client) BfEstimatedFpRate(key string, errorRate float64) (float64, error) {
+	health, err := client.BfHealth(key, errorRate)
+	if err != nil {
+		return 0, err
+	}
+	return health.EstimatedFpRate, nil
+}
+
+// ReportFalsePositive - Records that the filter stored at key answered positively for item although item was
+// never added to it, e.g. as found by the lookup in the source of truth that a positive answer triggers.
+// The count of false positives is stored at key:fp and the last 100 items at key:fp:items.
+func (client *Client) ReportFalsePositive(key string, item string) error {
+	conn := client.Pool.Get()
+	defer conn.Close()
+	itemsKey := client.key(key + fpItemsSuffix)
+	if err := conn.Send("MULTI"); err != nil {
+		return err
+	}
+	if err := conn.Send("HINCRBY", client.key(key+fpStatsSuffix), "false_positives", 1); err != nil {
+		return err
+	}
+	if err := conn.Send("LPUSH", itemsKey, item); err != nil {
+		return err
+	}
+	if err := conn.Send("LTRIM", itemsKey, 0, fpRecentItems-1); err != nil {
+		return err
+	}
+	_, err := conn.Do("EXEC")
+	return err
+}
+
+// ReportLookups - Records that the filter stored at key was queried for n items known not to be in it,
+// whatever its answers, so that BfFpReport can compute the observed false positive rate
+func (client *Client) ReportLookups(key string, n int64) error {
+	conn := client.Pool.Get()
+	defer conn.Close()
+	_, err := conn.Do("HINCRBY", client.key(key+fpStatsSuffix), "lookups", n)
+	return err
+}
+
+// BfFpReport - Returns the false positives reported for the bloom filter stored at key, which was created with
+// errorRate, along with its estimated false positive rate, to decide whether the filter should be rebuilt
+func (client *Client) BfFpReport(key string, errorRate float64) (*FpReport, error) {
+	estimated, err := client.BfEstimatedFpRate(key, errorRate)
+	if err != nil {
+		return nil, err
+	}
+	conn := client.Pool.Get()
+	defer conn.Close()
+	stats, err := redis.Int64Map(conn.Do("HGETALL", client.key(key+fpStatsSuffix)))
+	if err != nil {
+		return nil, err
+	}
+	items, err := redis.Strings(conn.Do("LRANGE", client.key(key+fpItemsSuffix), 0, -1))
+	if err != nil {
+		return nil, err
+	}
+	report := &FpReport{
+		EstimatedFpRate: estimated,
+		FalsePositives:  stats["false_positives"],
+		Lookups:         stats["lookups"],
+		RecentItems:     items,
+	}
+	if report.Lookups > 0 {
+		report.ObservedFpRate = float64(report.FalsePositives) / float64(report.Lookups)
+	}
+	return report, nil
+}
+
+// ResetFpReport - Deletes the false positives and lookups reported for the filter stored at key,
+// e.g. once it has been rebuilt
+func (client *Client) ResetFpReport(key string) error {
+	conn := client.Pool.Get()
+	defer conn.Close()
+	_, err := conn.Do("DEL", client.key(key+fpStatsSuffix), client.key(key+fpItemsSuffix))
+	return err
+}
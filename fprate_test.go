@@ -0,0 +1,39 @@
+package redis_bloom_go
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_BfFpReport(t *testing.T) {
+	client.FlushAll()
+	key := "test_fp_report"
+	assert.Nil(t, client.Reserve(key, 0.01, 1000))
+	client.BfAddMulti(key, []string{"a", "b", "c"})
+
+	estimated, err := client.BfEstimatedFpRate(key, 0.01)
+	assert.Nil(t, err)
+	assert.True(t, estimated > 0 && estimated < 0.01)
+
+	assert.Nil(t, client.ReportFalsePositive(key, "x"))
+	assert.Nil(t, client.ReportFalsePositive(key, "y"))
+	assert.Nil(t, client.ReportLookups(key, 50))
+	report, err := client.BfFpReport(key, 0.01)
+	assert.Nil(t, err)
+	assert.Equal(t, estimated, report.EstimatedFpRate)
+	assert.Equal(t, int64(2), report.FalsePositives)
+	assert.Equal(t, int64(50), report.Lookups)
+	assert.Equal(t, 0.04, report.ObservedFpRate)
+	assert.Equal(t, []string{"y", "x"}, report.RecentItems)
+
+	assert.Nil(t, client.ResetFpReport(key))
+	report, err = client.BfFpReport(key, 0.01)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), report.FalsePositives)
+	assert.Equal(t, 0.0, report.ObservedFpRate)
+	assert.Equal(t, []string{}, report.RecentItems)
+
+	_, err = client.BfFpReport("test_fp_report_missing", 0.01)
+	assert.NotNil(t, err)
+}
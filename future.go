@@ -0,0 +1,57 @@
+package redis_bloom_go
+
+// BoolFuture is the pending boolean result of a command sent in the background
+type BoolFuture struct {
+	done  chan struct{}
+	value bool
+	err   error
+}
+
+func newBoolFuture() *BoolFuture {
+	return &BoolFuture{done: make(chan struct{})}
+}
+
+// resolve sets the result of the future and wakes up its waiters. It must be called exactly once.
+func (f *BoolFuture) resolve(value bool, err error) {
+	f.value, f.err = value, err
+	close(f.done)
+}
+
+// Done returns a channel closed once the result is available
+func (f *BoolFuture) Done() <-chan struct{} {
+	return f.done
+}
+
+// Get waits for the result and returns it
+func (f *BoolFuture) Get() (bool, error) {
+	<-f.done
+	return f.value, f.err
+}
+
+// Int64sFuture is the pending integer slice result of a command sent in the background
+type Int64sFuture struct {
+	done  chan struct{}
+	value []int64
+	err   error
+}
+
+func newInt64sFuture() *Int64sFuture {
+	return &Int64sFuture{done: make(chan struct{})}
+}
+
+// resolve sets the result of the future and wakes up its waiters. It must be called exactly once.
+func (f *Int64sFuture) resolve(value []int64, err error) {
+	f.value, f.err = value, err
+	close(f.done)
+}
+
+// Done returns a channel closed once the result is available
+func (f *Int64sFuture) Done() <-chan struct{} {
+	return f.done
+}
+
+// Get waits for the result and returns it
+func (f *Int64sFuture) Get() ([]int64, error) {
+	<-f.done
+	return f.value, f.err
+}
@@ -0,0 +1,145 @@
+package redis_bloom_go
+
+import (
+	"math"
+)
+
+// bloomTighteningRatio is the factor RedisBloom applies to the error rate of each new sub-filter
+const bloomTighteningRatio = 0.5
+
+// cuckooFingerprintBits is the size of the fingerprints stored by RedisBloom cuckoo filters
+const cuckooFingerprintBits = 8
+
+// FilterHealth is an estimate of the saturation of a bloom or cuckoo filter
+type FilterHealth struct {
+	// Type is the data structure of the filter
+	Type DataType
+	// Capacity is the number of items all the sub-filters can hold
+	Capacity int64
+	// Items is the number of items currently in the filter
+	Items int64
+	// FillRatio is the ratio of Items to Capacity
+	FillRatio float64
+	// CurrentFillRatio is the fill ratio of the last sub-filter, the one receiving new items.
+	// A non-scaling filter rejects new items once it reaches 1
+	CurrentFillRatio float64
+	// ScaleOuts is the number of sub-filters added since the filter was created
+	ScaleOuts int64
+	// EstimatedFpRate is the estimated probability of a false positive answer for an item never added
+	EstimatedFpRate float64
+}
+
+// BfHealth - Estimates the saturation of the bloom filter stored at key, which was created with errorRate
+// (BF.INFO does not report it).
+func (client *Client) BfHealth(key string, errorRate float64) (*FilterHealth, error) {
+	info, err := client.Info(key)
+	if err != nil {
+		return nil, err
+	}
+	return bloomHealth(info, errorRate), nil
+}
+
+// CfHealth - Estimates the saturation of the cuckoo filter stored at key
+func (client *Client) CfHealth(key string) (*FilterHealth, error) {
+	info, err := client.CfInfo(key)
+	if err != nil {
+		return nil, err
+	}
+	return cuckooHealth(info), nil
+}
+
+// subFilterCapacities splits capacity among filters sub-filters, each expansion times larger than the previous one
+func subFilterCapacities(first float64, filters int64, expansion float64) []float64 {
+	if filters < 1 {
+		filters = 1
+	}
+	if expansion < 1 {
+		expansion = 1
+	}
+	capacities := make([]float64, filters)
+	for i := range capacities {
+		capacities[i] = first * math.Pow(expansion, float64(i))
+	}
+	return capacities
+}
+
+// subFilterItems spreads items over sub-filters of the given capacities, filling each one before the next
+func subFilterItems(capacities []float64, items float64) []float64 {
+	counts := make([]float64, len(capacities))
+	for i, capacity := range capacities {
+		if i == len(capacities)-1 || items <= capacity {
+			counts[i] = math.Max(items, 0)
+			break
+		}
+		counts[i] = capacity
+		items -= capacity
+	}
+	return counts
+}
+
+func newFilterHealth(dataType DataType, capacities []float64, counts []float64, items int64) *FilterHealth {
+	total := 0.0
+	for _, capacity := range capacities {
+		total += capacity
+	}
+	last := len(capacities) - 1
+	health := &FilterHealth{
+		Type:      dataType,
+		Capacity:  int64(math.Round(total)),
+		Items:     items,
+		ScaleOuts: int64(last),
+	}
+	if total > 0 {
+		health.FillRatio = float64(items) / total
+	}
+	if capacities[last] > 0 {
+		health.CurrentFillRatio = counts[last] / capacities[last]
+	}
+	return health
+}
+
+func bloomHealth(info map[string]int64, errorRate float64) *FilterHealth {
+	filters := info["Number of filters"]
+	expansion := float64(info["Expansion rate"])
+	capacity := float64(info["Capacity"])
+	sum := 0.0
+	for _, c := range subFilterCapacities(1, filters, expansion) {
+		sum += c
+	}
+	capacities := subFilterCapacities(capacity/sum, filters, expansion)
+	items := info["Number of items inserted"]
+	counts := subFilterItems(capacities, float64(items))
+	health := newFilterHealth(DataTypeBloom, capacities, counts, items)
+
+	// a lookup is negative only if every sub-filter answers negatively
+	negative := 1.0
+	rate := errorRate
+	for i, capacity := range capacities {
+		bitsPerItem := -math.Log(rate) / (math.Ln2 * math.Ln2)
+		hashes := math.Ceil(math.Ln2 * bitsPerItem)
+		bits := capacity * bitsPerItem
+		fp := math.Pow(1-math.Exp(-hashes*counts[i]/bits), hashes)
+		negative *= 1 - fp
+		rate *= bloomTighteningRatio
+	}
+	health.EstimatedFpRate = 1 - negative
+	return health
+}
+
+func cuckooHealth(info map[string]int64) *FilterHealth {
+	bucketSize := float64(info["Bucket size"])
+	capacities := subFilterCapacities(float64(info["Number of buckets"])*bucketSize, info["Number of filters"], float64(info["Expansion rate"]))
+	items := info["Number of items inserted"] - info["Number of items deleted"]
+	counts := subFilterItems(capacities, float64(items))
+	health := newFilterHealth(DataTypeCuckoo, capacities, counts, items)
+
+	// each lookup compares the fingerprint with the 2 candidate buckets of every sub-filter
+	fp := 0.0
+	for i, capacity := range capacities {
+		if capacity > 0 {
+			fp += 2 * bucketSize * (counts[i] / capacity) / math.Exp2(cuckooFingerprintBits)
+		}
+	}
+	health.EstimatedFpRate = math.Min(fp, 1)
+	return health
+}
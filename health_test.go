@@ -0,0 +1,74 @@
+package redis_bloom_go
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBloomHealth(t *testing.T) {
+	health := bloomHealth(map[string]int64{
+		"Capacity":                 1000,
+		"Number of filters":        1,
+		"Number of items inserted": 500,
+		"Expansion rate":           2,
+	}, 0.01)
+	assert.Equal(t, DataTypeBloom, health.Type)
+	assert.Equal(t, int64(1000), health.Capacity)
+	assert.Equal(t, int64(0), health.ScaleOuts)
+	assert.Equal(t, 0.5, health.FillRatio)
+	assert.Equal(t, 0.5, health.CurrentFillRatio)
+	assert.Less(t, health.EstimatedFpRate, 0.01)
+
+	full := bloomHealth(map[string]int64{
+		"Capacity":                 1000,
+		"Number of filters":        1,
+		"Number of items inserted": 1000,
+		"Expansion rate":           2,
+	}, 0.01)
+	assert.InDelta(t, 0.01, full.EstimatedFpRate, 0.002)
+
+	// 3 sub-filters of 1000, 2000 and 4000 items
+	scaled := bloomHealth(map[string]int64{
+		"Capacity":                 7000,
+		"Number of filters":        3,
+		"Number of items inserted": 5000,
+		"Expansion rate":           2,
+	}, 0.01)
+	assert.Equal(t, int64(2), scaled.ScaleOuts)
+	assert.Equal(t, int64(7000), scaled.Capacity)
+	assert.Equal(t, 0.5, scaled.CurrentFillRatio)
+	assert.Less(t, full.EstimatedFpRate, scaled.EstimatedFpRate)
+}
+
+func TestCuckooHealth(t *testing.T) {
+	health := cuckooHealth(map[string]int64{
+		"Number of buckets":        512,
+		"Number of filters":        1,
+		"Number of items inserted": 600,
+		"Number of items deleted":  88,
+		"Bucket size":              2,
+		"Expansion rate":           1,
+	})
+	assert.Equal(t, DataTypeCuckoo, health.Type)
+	assert.Equal(t, int64(1024), health.Capacity)
+	assert.Equal(t, int64(512), health.Items)
+	assert.Equal(t, 0.5, health.FillRatio)
+	assert.InDelta(t, 2*2*0.5/256, health.EstimatedFpRate, 1e-9)
+}
+
+func TestClient_BfHealth(t *testing.T) {
+	client.FlushAll()
+	err := client.Reserve("test_bf_health", 0.01, 1000)
+	assert.Nil(t, err)
+	client.BfAddMulti("test_bf_health", []string{"a", "b"})
+	health, err := client.BfHealth("test_bf_health", 0.01)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), health.Items)
+	assert.Equal(t, int64(1000), health.Capacity)
+
+	client.CfAdd("test_cf_health", "a")
+	health, err = client.CfHealth("test_cf_health")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), health.Items)
+}
@@ -0,0 +1,117 @@
+package redis_bloom_go
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// Statuses reported by the health handler
+const (
+	healthStatusOK          = "ok"
+	healthStatusUnavailable = "unavailable"
+)
+
+// HealthHandlerConfig configures the handler returned by NewHealthHandler
+type HealthHandlerConfig struct {
+	// Filters are the bloom and cuckoo filters whose saturation is reported, see WatchedFilter
+	Filters []WatchedFilter
+	// Timeout bounds the time the handler spends on the server, 2s by default
+	Timeout time.Duration
+}
+
+// defaultHealthTimeout is the time the health handler spends on the server at most by default
+const defaultHealthTimeout = 2 * time.Second
+
+// healthReport is the JSON document served by the health handler
+type healthReport struct {
+	Status        string               `json:"status"`
+	Error         string               `json:"error,omitempty"`
+	PingSeconds   float64              `json:"ping_seconds"`
+	ModuleVersion int64                `json:"module_version,omitempty"`
+	Pool          *poolStatsReport     `json:"pool,omitempty"`
+	Filters       []filterHealthReport `json:"filters,omitempty"`
+}
+
+// filterHealthReport is the saturation of a filter in the health report
+type filterHealthReport struct {
+	Key              string   `json:"key"`
+	Type             DataType `json:"type"`
+	Error            string   `json:"error,omitempty"`
+	Capacity         int64    `json:"capacity,omitempty"`
+	Items            int64    `json:"items,omitempty"`
+	FillRatio        float64  `json:"fill_ratio,omitempty"`
+	CurrentFillRatio float64  `json:"current_fill_ratio,omitempty"`
+	ScaleOuts        int64    `json:"scale_outs,omitempty"`
+	EstimatedFpRate  float64  `json:"estimated_fp_rate,omitempty"`
+}
+
+// NewHealthHandler - Returns an http.Handler reporting, as JSON, whether the server answers PING and how fast,
+// the RedisBloom module version, the statistics of the connection pool and the saturation of config.Filters.
+// It replies 200 when the server answers, and 503 otherwise; a filter which cannot be read is reported with its
+// error without failing the check, so that a missing filter does not take a service out of rotation.
+func NewHealthHandler(client *Client, config HealthHandlerConfig) http.Handler {
+	if config.Timeout <= 0 {
+		config.Timeout = defaultHealthTimeout
+	}
+	return &healthHandler{client: client, config: config}
+}
+
+type healthHandler struct {
+	client *Client
+	config HealthHandlerConfig
+}
+
+func (h *healthHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	report := h.report(r)
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if report.Status != healthStatusOK {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(report)
+}
+
+func (h *healthHandler) report(r *http.Request) *healthReport {
+	ctx, cancel := context.WithTimeout(r.Context(), h.config.Timeout)
+	defer cancel()
+	report := &healthReport{Status: healthStatusOK, Pool: newPoolStatsReport(h.client.Pool)}
+	start := time.Now()
+	if _, err := h.client.Do(ctx, "PING"); err != nil {
+		report.Status, report.Error = healthStatusUnavailable, err.Error()
+		return report
+	}
+	report.PingSeconds = time.Since(start).Seconds()
+	if version, err := h.client.ModuleVersion(); err == nil {
+		report.ModuleVersion = version
+	}
+	for _, filter := range h.config.Filters {
+		if ctx.Err() != nil {
+			break
+		}
+		var health *FilterHealth
+		var err error
+		if filter.Type == DataTypeCuckoo {
+			health, err = h.client.CfHealth(filter.Key)
+		} else {
+			health, err = h.client.BfHealth(filter.Key, filter.ErrorRate)
+		}
+		filterReport := filterHealthReport{Key: filter.Key, Type: filter.Type}
+		if filterReport.Type == "" {
+			filterReport.Type = DataTypeBloom
+		}
+		if err != nil {
+			filterReport.Error = err.Error()
+		} else {
+			filterReport.Capacity = health.Capacity
+			filterReport.Items = health.Items
+			filterReport.FillRatio = health.FillRatio
+			filterReport.CurrentFillRatio = health.CurrentFillRatio
+			filterReport.ScaleOuts = health.ScaleOuts
+			filterReport.EstimatedFpRate = health.EstimatedFpRate
+		}
+		report.Filters = append(report.Filters, filterReport)
+	}
+	return report
+}
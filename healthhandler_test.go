@@ -0,0 +1,57 @@
+package redis_bloom_go
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+func serveHealth(t *testing.T, c *Client, config HealthHandlerConfig) (int, map[string]interface{}) {
+	recorder := httptest.NewRecorder()
+	NewHealthHandler(c, config).ServeHTTP(recorder, httptest.NewRequest("GET", "/health", nil))
+	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	var report map[string]interface{}
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &report))
+	return recorder.Code, report
+}
+
+func TestHealthHandler(t *testing.T) {
+	conn := &fakeConn{replies: []interface{}{
+		"PONG",
+		[]interface{}{[]interface{}{[]byte("name"), []byte("bf"), []byte("ver"), int64(20400)}},
+		[]interface{}{[]byte("Capacity"), int64(100), []byte("Size"), int64(296), []byte("Number of filters"), int64(1),
+			[]byte("Number of items inserted"), int64(50), []byte("Expansion rate"), int64(2)},
+		redis.Error("ERR not found"),
+	}}
+	c := NewClientFromPool(nil, "test")
+	c.Pool = &fakePool{conn: conn}
+	code, report := serveHealth(t, c, HealthHandlerConfig{Filters: []WatchedFilter{
+		{Key: "bf", ErrorRate: 0.01}, {Key: "cf", Type: DataTypeCuckoo},
+	}})
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "ok", report["status"])
+	assert.Equal(t, float64(20400), report["module_version"])
+	filters := report["filters"].([]interface{})
+	assert.Len(t, filters, 2)
+	bloom := filters[0].(map[string]interface{})
+	assert.Equal(t, "bloom", bloom["type"])
+	assert.Equal(t, float64(100), bloom["capacity"])
+	assert.Equal(t, 0.5, bloom["fill_ratio"])
+	cuckoo := filters[1].(map[string]interface{})
+	assert.Equal(t, "cuckoo", cuckoo["type"])
+	assert.Equal(t, "ERR not found", cuckoo["error"])
+}
+
+func TestHealthHandler_Unavailable(t *testing.T) {
+	c := NewClientFromPool(nil, "test")
+	c.Pool = &fakePool{conn: &fakeConn{replies: []interface{}{errors.New("connection refused")}}}
+	code, report := serveHealth(t, c, HealthHandlerConfig{})
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "unavailable", report["status"])
+	assert.Equal(t, "connection refused", report["error"])
+}
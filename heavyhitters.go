@@ -0,0 +1,203 @@
+package redis_bloom_go
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrInvalidTopK is returned by NewHeavyHitters when the number of heavy hitters tracked is not positive
+var ErrInvalidTopK = errors.New("redisbloom: number of heavy hitters must be positive")
+
+// topkMissingKeyError is the error RedisBloom replies when a top-k sketch does not exist
+const topkMissingKeyError = "TopK: key does not exist"
+
+// HeavyHittersConfig describes the sketches and the window of a HeavyHitters
+type HeavyHittersConfig struct {
+	// TopK is the number of heavy hitters tracked in each interval and reported by Snapshot. It is required.
+	TopK int64
+	// Width, Depth and Decay are the TOPK.RESERVE parameters of each interval sketch, 8*TopK, 7 and 0.9 by default
+	Width int64
+	Depth int64
+	Decay float64
+	// Interval is the duration covered by each sketch, one minute by default, and Intervals the number of
+	// sketches in the window, one by default
+	Interval  time.Duration
+	Intervals int64
+	// OnChange, if set, is called by Poll when items enter or leave the heavy hitters
+	OnChange func(HeavyHittersChange)
+}
+
+// HeavyHitter is an item reported by HeavyHitters with its estimated count over the window
+type HeavyHitter struct {
+	Item  string
+	Count int64
+}
+
+// HeavyHittersChange lists the items that entered and left the heavy hitters between two polls,
+// along with the new heavy hitters
+type HeavyHittersChange struct {
+	Entered []string
+	Left    []string
+	Current []HeavyHitter
+}
+
+// HeavyHitters tracks the most frequent items over a sliding window made of one top-k sketch per
+// time interval, kept by a WindowedTopK. Items are added to the sketch of the current interval, and each
+// sketch expires once it leaves the window.
+type HeavyHitters struct {
+	window *WindowedTopK
+	config HeavyHittersConfig
+	now    func() time.Time
+
+	polling sync.Mutex
+	last    map[string]bool
+
+	lifecycle sync.Mutex
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+// NewHeavyHitters - Returns heavy hitters storing their sketches under keys name:<interval index>.
+// Returns ErrInvalidTopK if config.TopK is not positive.
+func NewHeavyHitters(client *Client, name string, config HeavyHittersConfig) (*HeavyHitters, error) {
+	if config.TopK <= 0 {
+		return nil, ErrInvalidTopK
+	}
+	if config.Width <= 0 {
+		config.Width = 8 * config.TopK
+	}
+	if config.Depth <= 0 {
+		config.Depth = 7
+	}
+	if config.Decay <= 0 {
+		config.Decay = 0.9
+	}
+	if config.Interval <= 0 {
+		config.Interval = time.Minute
+	}
+	if config.Intervals <= 0 {
+		config.Intervals = 1
+	}
+	h := &HeavyHitters{config: config, now: time.Now}
+	h.window = newWindowedTopK(client, name, config.Interval, config.Intervals, config.TopK, config.Width, config.Depth, config.Decay)
+	h.window.now = func() time.Time { return h.now() }
+	return h, nil
+}
+
+// Keys - Returns the keys of the sketches in the window, from the oldest to the current one
+func (h *HeavyHitters) Keys() []string {
+	return h.window.Keys()
+}
+
+// Add - Counts items in the sketch of the current interval, creating the sketch if needed
+func (h *HeavyHitters) Add(items ...string) error {
+	_, err := h.window.Add(items...)
+	return err
+}
+
+// Snapshot - Returns the TopK most frequent items over the window with their counts summed across
+// the sketches, from the most to the least frequent
+func (h *HeavyHitters) Snapshot() ([]HeavyHitter, error) {
+	return h.window.List()
+}
+
+// topHeavyHitters returns the n items with the highest counts, ties broken by item
+func topHeavyHitters(counts map[string]int64, n int64) []HeavyHitter {
+	hitters := make([]HeavyHitter, 0, len(counts))
+	for item, count := range counts {
+		hitters = append(hitters, HeavyHitter{Item: item, Count: count})
+	}
+	sort.Slice(hitters, func(i, j int) bool {
+		if hitters[i].Count != hitters[j].Count {
+			return hitters[i].Count > hitters[j].Count
+		}
+		return hitters[i].Item < hitters[j].Item
+	})
+	if int64(len(hitters)) > n {
+		hitters = hitters[:n]
+	}
+	return hitters
+}
+
+// Poll - Takes a snapshot and reports to OnChange the items that entered or left the heavy hitters since the previous poll
+func (h *HeavyHitters) Poll() ([]HeavyHitter, error) {
+	h.polling.Lock()
+	defer h.polling.Unlock()
+	current, err := h.Snapshot()
+	if err != nil {
+		return nil, err
+	}
+	change := h.changes(current)
+	if h.config.OnChange != nil && (len(change.Entered) > 0 || len(change.Left) > 0) {
+		h.config.OnChange(change)
+	}
+	return current, nil
+}
+
+// changes diffs current against the previous poll and remembers it
+func (h *HeavyHitters) changes(current []HeavyHitter) HeavyHittersChange {
+	change := HeavyHittersChange{Current: current}
+	items := make(map[string]bool, len(current))
+	for _, hitter := range current {
+		items[hitter.Item] = true
+		if !h.last[hitter.Item] {
+			change.Entered = append(change.Entered, hitter.Item)
+		}
+	}
+	for item := range h.last {
+		if !items[item] {
+			change.Left = append(change.Left, item)
+		}
+	}
+	sort.Strings(change.Left)
+	h.last = items
+	return change
+}
+
+// Start - Polls every interval in a background goroutine. Calling Start on started heavy hitters does nothing.
+// Polling errors are not reported. A non-positive interval polls once per interval of the window.
+func (h *HeavyHitters) Start(interval time.Duration) {
+	if interval <= 0 {
+		interval = h.config.Interval
+	}
+	h.lifecycle.Lock()
+	defer h.lifecycle.Unlock()
+	if h.stop != nil {
+		return
+	}
+	h.stop = make(chan struct{})
+	h.done = make(chan struct{})
+	go h.run(interval, h.stop, h.done)
+	h.window.client.register(h, h.Stop)
+}
+
+// Stop - Stops polling and waits for the polling goroutine to exit
+func (h *HeavyHitters) Stop() {
+	h.lifecycle.Lock()
+	stop, done := h.stop, h.done
+	h.stop, h.done = nil, nil
+	h.lifecycle.Unlock()
+	if stop == nil {
+		return
+	}
+	h.window.client.unregister(h)
+	close(stop)
+	<-done
+}
+
+func (h *HeavyHitters) run(interval time.Duration, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	h.Poll()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			h.Poll()
+		}
+	}
+}
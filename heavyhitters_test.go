@@ -0,0 +1,71 @@
+package redis_bloom_go
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTopHeavyHitters(t *testing.T) {
+	hitters := topHeavyHitters(map[string]int64{"a": 1, "b": 5, "c": 5, "d": 3}, 3)
+	assert.Equal(t, []HeavyHitter{{"b", 5}, {"c", 5}, {"d", 3}}, hitters)
+}
+
+func TestHeavyHitters_changes(t *testing.T) {
+	h, err := NewHeavyHitters(client, "test", HeavyHittersConfig{TopK: 2, Interval: time.Minute})
+	assert.Nil(t, err)
+	change := h.changes([]HeavyHitter{{"a", 3}, {"b", 2}})
+	assert.Equal(t, []string{"a", "b"}, change.Entered)
+	assert.Nil(t, change.Left)
+	change = h.changes([]HeavyHitter{{"c", 4}, {"a", 3}})
+	assert.Equal(t, []string{"c"}, change.Entered)
+	assert.Equal(t, []string{"b"}, change.Left)
+}
+
+func TestNewHeavyHitters_Invalid(t *testing.T) {
+	_, err := NewHeavyHitters(client, "top", HeavyHittersConfig{})
+	assert.Equal(t, ErrInvalidTopK, err)
+}
+
+func TestHeavyHitters_StartDefaultInterval(t *testing.T) {
+	c := NewClientFromPool(nil, "test")
+	c.Pool = &fakePool{conn: &fakeConn{}}
+	h, err := NewHeavyHitters(c, "top", HeavyHittersConfig{TopK: 2})
+	assert.Nil(t, err)
+	// a non-positive interval would panic in the polling goroutine
+	h.Start(0)
+	h.Stop()
+}
+
+func TestHeavyHitters(t *testing.T) {
+	client.FlushAll()
+	now := time.Unix(600, 0)
+	var changes []HeavyHittersChange
+	h, err := NewHeavyHitters(client, "test_heavy_hitters", HeavyHittersConfig{
+		TopK: 2, Interval: time.Minute, Intervals: 2,
+		OnChange: func(change HeavyHittersChange) { changes = append(changes, change) },
+	})
+	assert.Nil(t, err)
+	h.now = func() time.Time { return now }
+
+	assert.Nil(t, h.Add("a", "a", "a", "b", "b", "c"))
+	hitters, err := h.Poll()
+	assert.Nil(t, err)
+	assert.Equal(t, []HeavyHitter{{"a", 3}, {"b", 2}}, hitters)
+
+	now = now.Add(time.Minute)
+	assert.Nil(t, h.Add("c", "c", "c", "c"))
+	hitters, err = h.Poll()
+	assert.Nil(t, err)
+	assert.Equal(t, []HeavyHitter{{"c", 5}, {"a", 3}}, hitters)
+	assert.Equal(t, 2, len(changes))
+	assert.Equal(t, []string{"c"}, changes[1].Entered)
+	assert.Equal(t, []string{"b"}, changes[1].Left)
+
+	// the first interval has left the window
+	now = now.Add(time.Minute)
+	hitters, err = h.Snapshot()
+	assert.Nil(t, err)
+	assert.Equal(t, []HeavyHitter{{"c", 4}}, hitters)
+}
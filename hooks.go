@@ -0,0 +1,165 @@
+package redis_bloom_go
+
+import (
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// Hook observes, and may veto, every command a Client sends to the server. It can be used
+// for logging, metrics, tracing or fault injection.
+type Hook interface {
+	// BeforeCommand is called before command is sent. A non-nil error aborts the command, and is returned
+	// to the caller instead of its reply; AfterCommand is then not called.
+	BeforeCommand(command string, args []interface{}) error
+	// AfterCommand is called once the reply of command was received, or sending it failed.
+	// err is the error returned to the caller, including error replies of the server.
+	// args may be reused by the client once AfterCommand returns, so it must be copied to be kept.
+	AfterCommand(command string, args []interface{}, duration time.Duration, err error)
+}
+
+// AddHook - Registers hook on the client. Views created from the client after its first hook was added,
+// such as ForTenant views, share its hooks; views created before that do not see them.
+// Hooks are called in the order they were added. AddHook must not be called concurrently with commands.
+func (client *Client) AddHook(hook Hook) {
+	pool, ok := client.Pool.(*hookedPool)
+	if !ok {
+		pool = &hookedPool{ConnPool: client.Pool}
+		client.Pool = pool
+	}
+	pool.hooks = append(pool.hooks, hook)
+}
+
+// WithHooks registers hooks on the client, as AddHook does
+func WithHooks(hooks ...Hook) ClientOption {
+	return func(client *Client) {
+		for _, hook := range hooks {
+			client.AddHook(hook)
+		}
+	}
+}
+
+// hookedPool wraps the connections of a pool so that they call hooks around each command
+type hookedPool struct {
+	ConnPool
+	hooks []Hook
+}
+
+// wrappingPool is implemented by the pools wrapping the connections of another pool
+type wrappingPool interface {
+	unwrap() ConnPool
+}
+
+func (p *hookedPool) unwrap() ConnPool {
+	return p.ConnPool
+}
+
+// Get returns a connection of the pool, reporting the time it took to the hooks implementing PoolHook
+func (p *hookedPool) Get() redis.Conn {
+	start := time.Now()
+	conn := p.ConnPool.Get()
+	wait := time.Since(start)
+	for _, hook := range p.hooks {
+		if poolHook, ok := hook.(PoolHook); ok {
+			poolHook.AfterGet(wait, conn.Err())
+		}
+	}
+	return &hookedConn{Conn: conn, hooks: p.hooks}
+}
+
+// hookedConn calls hooks around the commands of a connection. Pipelined commands are reported
+// when their reply is received; the duration then spans from Send to Receive.
+type hookedConn struct {
+	redis.Conn
+	hooks   []Hook
+	pending []hookedCommand
+}
+
+type hookedCommand struct {
+	command string
+	args    []interface{}
+	start   time.Time
+}
+
+func (c *hookedConn) before(command string, args []interface{}) error {
+	for _, hook := range c.hooks {
+		if err := hook.BeforeCommand(command, args); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *hookedConn) after(sent hookedCommand, err error) {
+	duration := time.Since(sent.start)
+	for _, hook := range c.hooks {
+		hook.AfterCommand(sent.command, sent.args, duration, err)
+	}
+}
+
+// flushPending reports the pending pipelined commands whose replies were consumed by Do
+func (c *hookedConn) flushPending(err error) {
+	for _, sent := range c.pending {
+		c.after(sent, err)
+	}
+	c.pending = nil
+}
+
+func (c *hookedConn) Do(command string, args ...interface{}) (interface{}, error) {
+	return c.do(command, args, doCall(command, args))
+}
+
+func (c *hookedConn) DoWithTimeout(timeout time.Duration, command string, args ...interface{}) (interface{}, error) {
+	return c.do(command, args, doWithTimeoutCall(timeout, command, args))
+}
+
+func (c *hookedConn) do(command string, args []interface{}, call connCall) (interface{}, error) {
+	if command == "" {
+		reply, err := call(c.Conn)
+		c.flushPending(err)
+		return reply, err
+	}
+	if err := c.before(command, args); err != nil {
+		return nil, err
+	}
+	sent := hookedCommand{command: command, args: args, start: time.Now()}
+	reply, err := call(c.Conn)
+	if _, ok := err.(redis.Error); ok {
+		c.flushPending(nil)
+	} else {
+		c.flushPending(err)
+	}
+	c.after(sent, err)
+	return reply, err
+}
+
+func (c *hookedConn) Send(command string, args ...interface{}) error {
+	if err := c.before(command, args); err != nil {
+		return err
+	}
+	sent := hookedCommand{command: command, args: args, start: time.Now()}
+	if err := c.Conn.Send(command, args...); err != nil {
+		c.after(sent, err)
+		return err
+	}
+	c.pending = append(c.pending, sent)
+	return nil
+}
+
+func (c *hookedConn) Receive() (interface{}, error) {
+	return c.receive(receiveCall)
+}
+
+func (c *hookedConn) ReceiveWithTimeout(timeout time.Duration) (interface{}, error) {
+	return c.receive(receiveWithTimeoutCall(timeout))
+}
+
+func (c *hookedConn) receive(call connCall) (interface{}, error) {
+	reply, err := call(c.Conn)
+	if len(c.pending) > 0 {
+		sent := c.pending[0]
+		c.pending = c.pending[1:]
+		c.after(sent, err)
+	}
+	return reply, err
+}
@@ -0,0 +1,123 @@
+package redis_bloom_go
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeConn replies to commands with canned replies, in order, recording the commands it receives
+type fakeConn struct {
+	replies  []interface{}
+	commands []string
+	pending  int
+}
+
+func (c *fakeConn) next() (interface{}, error) {
+	if len(c.replies) == 0 {
+		return nil, errors.New("fakeConn: no more replies")
+	}
+	reply := c.replies[0]
+	c.replies = c.replies[1:]
+	if err, ok := reply.(error); ok {
+		return nil, err
+	}
+	return reply, nil
+}
+
+func (c *fakeConn) Close() error { return nil }
+func (c *fakeConn) Err() error   { return nil }
+func (c *fakeConn) Flush() error { return nil }
+
+func (c *fakeConn) Do(command string, args ...interface{}) (interface{}, error) {
+	if command != "" {
+		c.commands = append(c.commands, command)
+	}
+	for ; c.pending > 0; c.pending-- {
+		c.next()
+	}
+	if command == "" {
+		return nil, nil
+	}
+	return c.next()
+}
+
+func (c *fakeConn) Send(command string, args ...interface{}) error {
+	c.commands = append(c.commands, command)
+	c.pending++
+	return nil
+}
+
+func (c *fakeConn) Receive() (interface{}, error) {
+	c.pending--
+	return c.next()
+}
+
+// fakePool hands out the same fakeConn
+type fakePool struct {
+	conn redis.Conn
+}
+
+func (p *fakePool) Get() redis.Conn { return p.conn }
+func (p *fakePool) Close() error    { return nil }
+
+type recordingHook struct {
+	before []string
+	after  []string
+	errs   []error
+	veto   error
+}
+
+func (h *recordingHook) BeforeCommand(command string, args []interface{}) error {
+	h.before = append(h.before, command)
+	return h.veto
+}
+
+func (h *recordingHook) AfterCommand(command string, args []interface{}, duration time.Duration, err error) {
+	h.after = append(h.after, command)
+	h.errs = append(h.errs, err)
+}
+
+func TestHooks(t *testing.T) {
+	conn := &fakeConn{replies: []interface{}{int64(1), redis.Error("ERR boom"), []interface{}{int64(1)}, int64(0)}}
+	hook := &recordingHook{}
+	c := NewClientFromPool(nil, "test", WithHooks(hook))
+	c.Pool.(*hookedPool).ConnPool = &fakePool{conn: conn}
+
+	added, err := c.Add("key", "item")
+	assert.Nil(t, err)
+	assert.True(t, added)
+	_, err = c.CfExists("key", "item")
+	assert.Equal(t, redis.Error("ERR boom"), err)
+	assert.Equal(t, []string{"BF.ADD", "CF.EXISTS"}, hook.before)
+	assert.Equal(t, []string{"BF.ADD", "CF.EXISTS"}, hook.after)
+	assert.Equal(t, []error{nil, redis.Error("ERR boom")}, hook.errs)
+
+	// pipelined commands are reported on receive
+	pipe := c.Pool.Get()
+	pipe.Send("BF.MADD", "key", "a")
+	pipe.Send("BF.EXISTS", "key", "b")
+	assert.Equal(t, 2, len(hook.after))
+	pipe.Receive()
+	assert.Equal(t, "BF.MADD", hook.after[2])
+	pipe.Receive()
+	assert.Equal(t, "BF.EXISTS", hook.after[3])
+
+	hook.veto = errors.New("injected")
+	_, err = c.Exists("key", "item")
+	assert.Equal(t, hook.veto, err)
+	assert.Equal(t, 4, len(hook.after))
+	assert.Equal(t, []string{"BF.ADD", "CF.EXISTS", "BF.MADD", "BF.EXISTS"}, conn.commands)
+}
+
+func TestClient_AddHook(t *testing.T) {
+	c := NewClientFromPool(nil, "test")
+	c.AddHook(&recordingHook{})
+	c.AddHook(&recordingHook{})
+	pool, ok := c.Pool.(*hookedPool)
+	assert.True(t, ok)
+	assert.Equal(t, 2, len(pool.hooks))
+}
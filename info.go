@@ -0,0 +1,323 @@
+package redis_bloom_go
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// BloomInfo is the reply of BF.INFO
+type BloomInfo struct {
+	Capacity      int64
+	Size          int64
+	Filters       int64
+	Items         int64
+	ExpansionRate int64
+	// Extra holds the fields this client does not know about, e.g. added by a newer module version,
+	// with bulk strings converted to strings. Nil when there are none.
+	Extra map[string]interface{}
+}
+
+// CuckooInfo is the reply of CF.INFO
+type CuckooInfo struct {
+	Size          int64
+	Buckets       int64
+	Filters       int64
+	Items         int64
+	Deleted       int64
+	BucketSize    int64
+	ExpansionRate int64
+	MaxIterations int64
+	// Extra holds the fields this client does not know about, e.g. added by a newer module version,
+	// with bulk strings converted to strings. Nil when there are none.
+	Extra map[string]interface{}
+}
+
+// CMSInfo is the reply of CMS.INFO
+type CMSInfo struct {
+	Width int64
+	Depth int64
+	Count int64
+	// Extra holds the fields this client does not know about, e.g. added by a newer module version,
+	// with bulk strings converted to strings. Nil when there are none.
+	Extra map[string]interface{}
+}
+
+// TopKInfo is the reply of TOPK.INFO
+type TopKInfo struct {
+	K     int64
+	Width int64
+	Depth int64
+	Decay float64
+	// Extra holds the fields this client does not know about, e.g. added by a newer module version,
+	// with bulk strings converted to strings. Nil when there are none.
+	Extra map[string]interface{}
+}
+
+// BfInfoTyped - Returns the information about the bloom filter stored at key
+func (client *Client) BfInfoTyped(key string) (BloomInfo, error) {
+	conn := client.Pool.Get()
+	defer conn.Close()
+	return ParseBloomInfo(conn.Do("BF.INFO", client.key(key)))
+}
+
+// CfInfoTyped - Returns the information about the cuckoo filter stored at key
+func (client *Client) CfInfoTyped(key string) (CuckooInfo, error) {
+	conn := client.Pool.Get()
+	defer conn.Close()
+	return ParseCuckooInfo(conn.Do("CF.INFO", client.key(key)))
+}
+
+// CmsInfoTyped - Returns the width, depth and total count of the sketch stored at key
+func (client *Client) CmsInfoTyped(key string) (CMSInfo, error) {
+	conn := client.Pool.Get()
+	defer conn.Close()
+	return ParseCMSInfo(conn.Do("CMS.INFO", client.key(key)))
+}
+
+// TopkInfoTyped - Returns the number of required items (k), width, depth and decay of the top-k stored at key
+func (client *Client) TopkInfoTyped(key string) (TopKInfo, error) {
+	conn := client.Pool.Get()
+	defer conn.Close()
+	return ParseTopKInfo(conn.Do("TOPK.INFO", client.key(key)))
+}
+
+// InfoMulti - Returns the information about the bloom filters stored at keys, fetched with one pipeline.
+// Keys which do not hold a bloom filter, e.g. because they do not exist, are left out of the map.
+func (client *Client) InfoMulti(keys ...string) (map[string]BloomInfo, error) {
+	infos := make(map[string]BloomInfo, len(keys))
+	err := client.infoMulti("BF.INFO", keys, func(key string, reply interface{}) error {
+		info, err := ParseBloomInfo(reply, nil)
+		infos[key] = info
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return infos, nil
+}
+
+// CfInfoMulti - Returns the information about the cuckoo filters stored at keys, like InfoMulti
+func (client *Client) CfInfoMulti(keys ...string) (map[string]CuckooInfo, error) {
+	infos := make(map[string]CuckooInfo, len(keys))
+	err := client.infoMulti("CF.INFO", keys, func(key string, reply interface{}) error {
+		info, err := ParseCuckooInfo(reply, nil)
+		infos[key] = info
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return infos, nil
+}
+
+// CmsInfoMulti - Returns the width, depth and total count of the sketches stored at keys, like InfoMulti
+func (client *Client) CmsInfoMulti(keys ...string) (map[string]CMSInfo, error) {
+	infos := make(map[string]CMSInfo, len(keys))
+	err := client.infoMulti("CMS.INFO", keys, func(key string, reply interface{}) error {
+		info, err := ParseCMSInfo(reply, nil)
+		infos[key] = info
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return infos, nil
+}
+
+// TopkInfoMulti - Returns the number of required items (k), width, depth and decay of the top-k stored at keys,
+// like InfoMulti
+func (client *Client) TopkInfoMulti(keys ...string) (map[string]TopKInfo, error) {
+	infos := make(map[string]TopKInfo, len(keys))
+	err := client.infoMulti("TOPK.INFO", keys, func(key string, reply interface{}) error {
+		info, err := ParseTopKInfo(reply, nil)
+		infos[key] = info
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return infos, nil
+}
+
+// TdInfoMulti - Returns the information about the t-digests stored at keys, like InfoMulti
+func (client *Client) TdInfoMulti(keys ...string) (map[string]TDigestInfo, error) {
+	infos := make(map[string]TDigestInfo, len(keys))
+	err := client.infoMulti("TDIGEST.INFO", keys, func(key string, reply interface{}) error {
+		info, err := ParseTDigestInfo(reply, nil)
+		infos[key] = info
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return infos, nil
+}
+
+// infoMulti sends command for each of keys in one pipeline and passes the replies to parse. Error replies
+// are skipped; the first other error is returned once every reply was drained.
+func (client *Client) infoMulti(command string, keys []string, parse func(key string, reply interface{}) error) error {
+	conn := client.Pool.Get()
+	defer conn.Close()
+	for _, key := range keys {
+		if err := conn.Send(command, client.key(key)); err != nil {
+			return err
+		}
+	}
+	if err := conn.Flush(); err != nil {
+		return err
+	}
+	var outErr error
+	for _, key := range keys {
+		reply, err := conn.Receive()
+		if err == nil {
+			err = parse(key, reply)
+		}
+		if _, ok := err.(redis.Error); err != nil && !ok && outErr == nil {
+			outErr = err
+		}
+	}
+	return outErr
+}
+
+// ParseBloomInfo converts a BF.INFO reply into a BloomInfo
+func ParseBloomInfo(result interface{}, err error) (BloomInfo, error) {
+	fields, err := parseInfoFields(result, err)
+	if err != nil {
+		return BloomInfo{}, err
+	}
+	info := BloomInfo{}
+	fields.int64("Capacity", &info.Capacity)
+	fields.int64("Size", &info.Size)
+	fields.int64("Number of filters", &info.Filters)
+	fields.int64("Number of items inserted", &info.Items)
+	fields.int64("Expansion rate", &info.ExpansionRate)
+	info.Extra = fields.extra()
+	return info, nil
+}
+
+// ParseCuckooInfo converts a CF.INFO reply into a CuckooInfo
+func ParseCuckooInfo(result interface{}, err error) (CuckooInfo, error) {
+	fields, err := parseInfoFields(result, err)
+	if err != nil {
+		return CuckooInfo{}, err
+	}
+	info := CuckooInfo{}
+	fields.int64("Size", &info.Size)
+	fields.int64("Number of buckets", &info.Buckets)
+	fields.int64("Number of filters", &info.Filters)
+	fields.int64("Number of items inserted", &info.Items)
+	fields.int64("Number of items deleted", &info.Deleted)
+	fields.int64("Bucket size", &info.BucketSize)
+	fields.int64("Expansion rate", &info.ExpansionRate)
+	fields.int64("Max iterations", &info.MaxIterations)
+	info.Extra = fields.extra()
+	return info, nil
+}
+
+// ParseCMSInfo converts a CMS.INFO reply into a CMSInfo
+func ParseCMSInfo(result interface{}, err error) (CMSInfo, error) {
+	fields, err := parseInfoFields(result, err)
+	if err != nil {
+		return CMSInfo{}, err
+	}
+	info := CMSInfo{}
+	fields.int64("width", &info.Width)
+	fields.int64("depth", &info.Depth)
+	fields.int64("count", &info.Count)
+	info.Extra = fields.extra()
+	return info, nil
+}
+
+// ParseTopKInfo converts a TOPK.INFO reply into a TopKInfo
+func ParseTopKInfo(result interface{}, err error) (TopKInfo, error) {
+	fields, err := parseInfoFields(result, err)
+	if err != nil {
+		return TopKInfo{}, err
+	}
+	info := TopKInfo{}
+	fields.int64("k", &info.K)
+	fields.int64("width", &info.Width)
+	fields.int64("depth", &info.Depth)
+	fields.float64("decay", &info.Decay)
+	info.Extra = fields.extra()
+	return info, nil
+}
+
+// infoFields are the name/value pairs of an INFO reply, with values as returned by redigo
+type infoFields map[string]interface{}
+
+// parseInfoFields splits an INFO reply into its fields
+func parseInfoFields(result interface{}, err error) (infoFields, error) {
+	values, err := redis.Values(result, err)
+	if err != nil {
+		return nil, err
+	}
+	if len(values)%2 != 0 {
+		return nil, errors.New("expects even number of values result")
+	}
+	fields := make(infoFields, len(values)/2)
+	for i := 0; i < len(values); i += 2 {
+		name, err := redis.String(values[i], nil)
+		if err != nil {
+			return nil, err
+		}
+		fields[name] = values[i+1]
+	}
+	return fields, nil
+}
+
+// int64 converts the field name into dst and consumes it. A missing field, or one which is
+// not an integer, is left alone so that it ends up in extra rather than failing the whole reply.
+func (fields infoFields) int64(name string, dst *int64) {
+	if value, ok := fields[name]; ok {
+		if converted, err := redis.Int64(value, nil); err == nil {
+			*dst = converted
+			delete(fields, name)
+		}
+	}
+}
+
+// float64 converts the field name into dst and consumes it, like int64
+func (fields infoFields) float64(name string, dst *float64) {
+	if value, ok := fields[name]; ok {
+		if converted, err := redis.Float64(value, nil); err == nil {
+			*dst = converted
+			delete(fields, name)
+		}
+	}
+}
+
+// extra returns the fields not consumed yet, or nil if there are none
+func (fields infoFields) extra() map[string]interface{} {
+	if len(fields) == 0 {
+		return nil
+	}
+	extra := make(map[string]interface{}, len(fields))
+	for name, value := range fields {
+		extra[name] = infoValue(value)
+	}
+	return extra
+}
+
+// infoValue converts bulk strings, including those nested in arrays, into strings
+func infoValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case []byte:
+		return string(v)
+	case []interface{}:
+		converted := make([]interface{}, len(v))
+		for i, element := range v {
+			converted[i] = infoValue(element)
+		}
+		return converted
+	default:
+		return v
+	}
+}
+
+// infoString formats an INFO field value for the string maps of TopkInfo
+func infoString(value interface{}) string {
+	return fmt.Sprint(infoValue(value))
+}
@@ -0,0 +1,143 @@
+package redis_bloom_go
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseBloomInfo(t *testing.T) {
+	reply := []interface{}{
+		"Capacity", int64(100),
+		"Size", int64(296),
+		"Number of filters", int64(1),
+		"Number of items inserted", int64(3),
+		"Expansion rate", int64(2),
+		"Hash function", []byte("murmur64"),
+		"Fill ratios", []interface{}{[]byte("0.03")},
+	}
+	info, err := ParseBloomInfo(reply, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, BloomInfo{
+		Capacity:      100,
+		Size:          296,
+		Filters:       1,
+		Items:         3,
+		ExpansionRate: 2,
+		Extra: map[string]interface{}{
+			"Hash function": "murmur64",
+			"Fill ratios":   []interface{}{"0.03"},
+		},
+	}, info)
+
+	info, err = ParseBloomInfo([]interface{}{"Capacity", int64(100)}, nil)
+	assert.Nil(t, err)
+	assert.Nil(t, info.Extra)
+
+	_, err = ParseBloomInfo([]interface{}{"Capacity"}, nil)
+	assert.NotNil(t, err)
+}
+
+func TestParseCuckooInfo_UnexpectedType(t *testing.T) {
+	// a known field changing type ends up in Extra rather than failing the whole reply
+	info, err := ParseCuckooInfo([]interface{}{
+		"Size", int64(1080),
+		"Bucket size", []byte("2.5"),
+	}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1080), info.Size)
+	assert.Equal(t, int64(0), info.BucketSize)
+	assert.Equal(t, map[string]interface{}{"Bucket size": "2.5"}, info.Extra)
+}
+
+func TestParseTopKInfo(t *testing.T) {
+	info, err := ParseTopKInfo([]interface{}{
+		"k", int64(10), "width", int64(2000), "depth", int64(7), "decay", []byte("0.925"),
+	}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, TopKInfo{K: 10, Width: 2000, Depth: 7, Decay: 0.925}, info)
+}
+
+func TestParseInfoReply_SkipsNonIntegers(t *testing.T) {
+	info, err := ParseInfoReply([]interface{}{
+		"width", int64(2000), "depth", int64(7), "count", int64(0), "Hash function", []byte("murmur64"),
+	}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]int64{"width": 2000, "depth": 7, "count": 0}, info)
+}
+
+func TestParseTDigestInfo_Extra(t *testing.T) {
+	info, err := ParseTDigestInfo([]interface{}{
+		"Compression", int64(100), "Capacity", int64(610), "Observations", int64(3),
+	}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(100), info.Compression())
+	assert.Equal(t, map[string]interface{}{"Observations": int64(3)}, info.Extra())
+}
+
+func TestClient_InfoTyped(t *testing.T) {
+	client.FlushAll()
+	assert.Nil(t, client.Reserve("test_info_typed_bf", 0.01, 100))
+	bfInfo, err := client.BfInfoTyped("test_info_typed_bf")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(100), bfInfo.Capacity)
+	assert.Equal(t, int64(1), bfInfo.Filters)
+
+	_, err = client.CfReserve("test_info_typed_cf", 1000, 0, 0, 0)
+	assert.Nil(t, err)
+	cfInfo, err := client.CfInfoTyped("test_info_typed_cf")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), cfInfo.Filters)
+
+	_, err = client.CmsInitByDim("test_info_typed_cms", 2000, 7)
+	assert.Nil(t, err)
+	cmsInfo, err := client.CmsInfoTyped("test_info_typed_cms")
+	assert.Nil(t, err)
+	assert.Equal(t, CMSInfo{Width: 2000, Depth: 7}, cmsInfo)
+
+	_, err = client.TopkReserve("test_info_typed_topk", 10, 2000, 7, 0.925)
+	assert.Nil(t, err)
+	topkInfo, err := client.TopkInfoTyped("test_info_typed_topk")
+	assert.Nil(t, err)
+	assert.Equal(t, TopKInfo{K: 10, Width: 2000, Depth: 7, Decay: 0.925}, topkInfo)
+
+	_, err = client.BfInfoTyped("notexists")
+	assert.NotNil(t, err)
+}
+
+func TestClient_InfoMulti_Pipelined(t *testing.T) {
+	conn := &fakeConn{replies: []interface{}{
+		[]interface{}{"Capacity", int64(100), "Number of items inserted", int64(3)},
+		redis.Error("ERR not found"),
+		[]interface{}{"Capacity", int64(200)},
+	}}
+	c := NewClientFromPool(nil, "test")
+	c.Pool = &fakePool{conn: conn}
+	infos, err := c.InfoMulti("a", "missing", "b")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]BloomInfo{"a": {Capacity: 100, Items: 3}, "b": {Capacity: 200}}, infos)
+	assert.Equal(t, []string{"BF.INFO", "BF.INFO", "BF.INFO"}, conn.commands)
+	assert.Equal(t, 0, conn.pending)
+
+	conn = &fakeConn{replies: []interface{}{errors.New("connection reset"), []interface{}{"k", int64(10)}}}
+	c.Pool = &fakePool{conn: conn}
+	_, err = c.TopkInfoMulti("a", "b")
+	assert.EqualError(t, err, "connection reset")
+	assert.Equal(t, 0, conn.pending)
+}
+
+func TestClient_CmsInfoMulti(t *testing.T) {
+	client.FlushAll()
+	_, err := client.CmsInitByDim("test_info_multi_a", 100, 5)
+	assert.Nil(t, err)
+	_, err = client.CmsInitByDim("test_info_multi_b", 200, 4)
+	assert.Nil(t, err)
+	infos, err := client.CmsInfoMulti("test_info_multi_a", "test_info_multi_b", "test_info_multi_missing")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]CMSInfo{
+		"test_info_multi_a": {Width: 100, Depth: 5},
+		"test_info_multi_b": {Width: 200, Depth: 4},
+	}, infos)
+}
@@ -0,0 +1,295 @@
+package redis_bloom_go
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// IngestFormat is the layout of the records read by Ingest
+type IngestFormat string
+
+// Formats read by Ingest
+const (
+	// FormatLines reads one item per line
+	FormatLines IngestFormat = "lines"
+	// FormatCSV reads comma separated records
+	FormatCSV IngestFormat = "csv"
+	// FormatJSONL reads one JSON object per line
+	FormatJSONL IngestFormat = "jsonl"
+)
+
+const (
+	defaultIngestBatchSize = 1000
+	defaultIngestPipeline  = 8
+)
+
+// IngestOptions tunes Ingest
+type IngestOptions struct {
+	// Type is the data structure items are added to, DataTypeBloom if empty. Bloom and cuckoo filters are created
+	// if needed, count-min sketches and top-k must exist.
+	Type DataType
+	// Format is the layout of the records, FormatLines if empty
+	Format IngestFormat
+	// Field is the CSV column or the JSON field holding the item. A CSV reader with a Field or a CountField
+	// reads the column names from its first record. Without Field, the item is the first CSV column.
+	// Field is required by FormatJSONL.
+	Field string
+	// CountField is the CSV column or the JSON field holding the increment of the item in a count-min sketch
+	// or a top-k, 1 if empty
+	CountField string
+	// BatchSize is the number of records sent per command, 1000 if zero
+	BatchSize int
+	// Pipeline is the number of commands sent per round trip, 8 if zero
+	Pipeline int
+	// Offset is the number of records to skip, e.g. the offset reported by the last progress of an interrupted run
+	Offset int64
+	// Progress, if set, is called after each round trip with the offset of the next record to send
+	Progress func(offset int64)
+}
+
+// ingestRecord is an item read by Ingest with its increment
+type ingestRecord struct {
+	item  string
+	count int64
+}
+
+// recordReader returns the records of an ingested stream one by one, and io.EOF at its end
+type recordReader func() (ingestRecord, error)
+
+// Ingest - Streams the records of r into the data structure stored at key, sending pipelined batches of
+// BF.MADD, CF.INSERT, CMS.INCRBY or TOPK.INCRBY over a single connection. Returns the offset of the next record
+// to send, which equals the number of records in r once it was fully ingested. On error, pass the returned
+// offset as IngestOptions.Offset to resume: the records of the failed round trip may have been partly applied,
+// which adding them again does not affect for filters but counts twice in sketches.
+func (client *Client) Ingest(ctx context.Context, key string, r io.Reader, opts IngestOptions) (int64, error) {
+	if opts.Type == "" {
+		opts.Type = DataTypeBloom
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaultIngestBatchSize
+	}
+	if opts.Pipeline <= 0 {
+		opts.Pipeline = defaultIngestPipeline
+	}
+	command, ok := ingestCommands[opts.Type]
+	if !ok {
+		return 0, fmt.Errorf("redisbloom: cannot ingest into %s", opts.Type)
+	}
+	next, err := newRecordReader(r, opts)
+	if err != nil {
+		return 0, err
+	}
+	offset := int64(0)
+	for ; offset < opts.Offset; offset++ {
+		if _, err = next(); err != nil {
+			if err == io.EOF {
+				return offset, nil
+			}
+			return offset, err
+		}
+	}
+
+	conn := client.Pool.Get()
+	defer conn.Close()
+	for {
+		if err = ctx.Err(); err != nil {
+			return offset, err
+		}
+		var batches [][]ingestRecord
+		var readErr error
+		for len(batches) < opts.Pipeline && readErr == nil {
+			batch := make([]ingestRecord, 0, opts.BatchSize)
+			for len(batch) < opts.BatchSize {
+				record, err := next()
+				if err != nil {
+					readErr = err
+					break
+				}
+				batch = append(batch, record)
+			}
+			if len(batch) > 0 {
+				batches = append(batches, batch)
+			}
+		}
+		if len(batches) > 0 {
+			if err = client.sendIngestBatches(conn, key, command, batches); err != nil {
+				return offset, err
+			}
+			for _, batch := range batches {
+				offset += int64(len(batch))
+			}
+			if opts.Progress != nil {
+				opts.Progress(offset)
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return offset, readErr
+		}
+	}
+	if client.writeTTL > 0 && (opts.Type == DataTypeBloom || opts.Type == DataTypeCuckoo) {
+		if _, err = conn.Do("PEXPIRE", client.key(key), int64(client.writeTTL/time.Millisecond)); err != nil {
+			return offset, err
+		}
+	}
+	return offset, nil
+}
+
+// ingestCommands build the command adding a batch of records to each data structure
+var ingestCommands = map[DataType]func(key string, batch []ingestRecord) (string, redis.Args){
+	DataTypeBloom: func(key string, batch []ingestRecord) (string, redis.Args) {
+		args := redis.Args{key}
+		for _, record := range batch {
+			args = append(args, record.item)
+		}
+		return "BF.MADD", args
+	},
+	DataTypeCuckoo: func(key string, batch []ingestRecord) (string, redis.Args) {
+		args := redis.Args{key, "ITEMS"}
+		for _, record := range batch {
+			args = append(args, record.item)
+		}
+		return "CF.INSERT", args
+	},
+	DataTypeCMS: func(key string, batch []ingestRecord) (string, redis.Args) {
+		return "CMS.INCRBY", ingestIncrements(key, batch)
+	},
+	DataTypeTopK: func(key string, batch []ingestRecord) (string, redis.Args) {
+		return "TOPK.INCRBY", ingestIncrements(key, batch)
+	},
+}
+
+func ingestIncrements(key string, batch []ingestRecord) redis.Args {
+	args := redis.Args{key}
+	for _, record := range batch {
+		args = append(args, record.item, record.count)
+	}
+	return args
+}
+
+// sendIngestBatches pipelines one command per batch and returns the first error, once every reply is drained
+func (client *Client) sendIngestBatches(conn redis.Conn, key string, command func(key string, batch []ingestRecord) (string, redis.Args), batches [][]ingestRecord) error {
+	for _, batch := range batches {
+		name, args := command(client.key(key), batch)
+		if err := conn.Send(name, args...); err != nil {
+			return err
+		}
+	}
+	if err := conn.Flush(); err != nil {
+		return err
+	}
+	var outErr error
+	for range batches {
+		if _, err := conn.Receive(); err != nil && outErr == nil {
+			outErr = err
+		}
+	}
+	return outErr
+}
+
+// newRecordReader returns a reader of the records of r in the format of opts
+func newRecordReader(r io.Reader, opts IngestOptions) (recordReader, error) {
+	switch opts.Format {
+	case "", FormatLines:
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+		return func() (ingestRecord, error) {
+			if !scanner.Scan() {
+				if err := scanner.Err(); err != nil {
+					return ingestRecord{}, err
+				}
+				return ingestRecord{}, io.EOF
+			}
+			return ingestRecord{item: scanner.Text(), count: 1}, nil
+		}, nil
+	case FormatCSV:
+		return newCSVRecordReader(r, opts)
+	case FormatJSONL:
+		if opts.Field == "" {
+			return nil, fmt.Errorf("redisbloom: ingesting JSONL requires a field")
+		}
+		decoder := json.NewDecoder(r)
+		decoder.UseNumber()
+		return func() (ingestRecord, error) {
+			var object map[string]interface{}
+			if err := decoder.Decode(&object); err != nil {
+				return ingestRecord{}, err
+			}
+			item, ok := object[opts.Field]
+			if !ok {
+				return ingestRecord{}, fmt.Errorf("redisbloom: JSON record without field %s", opts.Field)
+			}
+			record := ingestRecord{item: fmt.Sprint(item), count: 1}
+			if opts.CountField != "" {
+				count, err := strconv.ParseInt(fmt.Sprint(object[opts.CountField]), 10, 64)
+				if err != nil {
+					return ingestRecord{}, fmt.Errorf("redisbloom: JSON record with invalid count: %v", err)
+				}
+				record.count = count
+			}
+			return record, nil
+		}, nil
+	}
+	return nil, fmt.Errorf("redisbloom: unknown ingest format %q", opts.Format)
+}
+
+// newCSVRecordReader returns a reader of CSV records, reading the column names first if opts names columns
+func newCSVRecordReader(r io.Reader, opts IngestOptions) (recordReader, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+	itemColumn, countColumn := 0, -1
+	if opts.Field != "" || opts.CountField != "" {
+		header, err := reader.Read()
+		if err != nil {
+			return nil, err
+		}
+		columns := make(map[string]int, len(header))
+		for i, name := range header {
+			columns[name] = i
+		}
+		column := func(name string) (int, error) {
+			i, ok := columns[name]
+			if !ok {
+				return 0, fmt.Errorf("redisbloom: CSV header without column %s", name)
+			}
+			return i, nil
+		}
+		if opts.Field != "" {
+			if itemColumn, err = column(opts.Field); err != nil {
+				return nil, err
+			}
+		}
+		if opts.CountField != "" {
+			if countColumn, err = column(opts.CountField); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return func() (ingestRecord, error) {
+		fields, err := reader.Read()
+		if err != nil {
+			return ingestRecord{}, err
+		}
+		if itemColumn >= len(fields) || countColumn >= len(fields) {
+			return ingestRecord{}, fmt.Errorf("redisbloom: CSV record with %d columns", len(fields))
+		}
+		record := ingestRecord{item: fields[itemColumn], count: 1}
+		if countColumn >= 0 {
+			if record.count, err = strconv.ParseInt(fields[countColumn], 10, 64); err != nil {
+				return ingestRecord{}, fmt.Errorf("redisbloom: CSV record with invalid count: %v", err)
+			}
+		}
+		return record, nil
+	}, nil
+}
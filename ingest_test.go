@@ -0,0 +1,70 @@
+package redis_bloom_go
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+// readRecords returns every record of r
+func readRecords(t *testing.T, r string, opts IngestOptions) []ingestRecord {
+	next, err := newRecordReader(strings.NewReader(r), opts)
+	assert.Nil(t, err)
+	records := []ingestRecord{}
+	for {
+		record, err := next()
+		if err == io.EOF {
+			return records
+		}
+		assert.Nil(t, err)
+		records = append(records, record)
+	}
+}
+
+func TestRecordReaders(t *testing.T) {
+	assert.Equal(t, []ingestRecord{{"a", 1}, {"b c", 1}}, readRecords(t, "a\nb c\n", IngestOptions{}))
+	assert.Equal(t, []ingestRecord{{"a", 1}, {"b", 1}}, readRecords(t, "a,1\nb,2\n", IngestOptions{Format: FormatCSV}))
+	assert.Equal(t, []ingestRecord{{"a", 1}, {"b", 2}}, readRecords(t, "count,user\n1,a\n2,b\n", IngestOptions{Format: FormatCSV, Field: "user", CountField: "count"}))
+	assert.Equal(t, []ingestRecord{{"a", 3}, {"42", 1}}, readRecords(t, `{"user":"a","n":3}
+{"user":42,"n":1}`, IngestOptions{Format: FormatJSONL, Field: "user", CountField: "n"}))
+
+	_, err := newRecordReader(strings.NewReader("count\n1\n"), IngestOptions{Format: FormatCSV, Field: "user"})
+	assert.NotNil(t, err)
+	_, err = newRecordReader(strings.NewReader(""), IngestOptions{Format: FormatJSONL})
+	assert.NotNil(t, err)
+	_, err = newRecordReader(strings.NewReader(""), IngestOptions{Format: "xml"})
+	assert.NotNil(t, err)
+	next, _ := newRecordReader(strings.NewReader(`{"name":"a"}`), IngestOptions{Format: FormatJSONL, Field: "user"})
+	_, err = next()
+	assert.NotNil(t, err)
+}
+
+func TestClient_Ingest(t *testing.T) {
+	conn := &fakeConn{replies: []interface{}{
+		[]interface{}{int64(1), int64(1)}, []interface{}{int64(1)},
+		redis.Error("CMS: key does not exist"),
+	}}
+	c := NewClientFromPool(nil, "test")
+	c.Pool = &fakePool{conn: conn}
+	var offsets []int64
+	offset, err := c.Ingest(context.Background(), "seen", strings.NewReader("skipped\na\nb\nc\n"), IngestOptions{
+		BatchSize: 2,
+		Offset:    1,
+		Progress:  func(offset int64) { offsets = append(offsets, offset) },
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(4), offset)
+	assert.Equal(t, []int64{4}, offsets)
+	assert.Equal(t, []string{"BF.MADD", "BF.MADD"}, conn.commands)
+
+	offset, err = c.Ingest(context.Background(), "counts", strings.NewReader("a\n"), IngestOptions{Type: DataTypeCMS})
+	assert.Equal(t, redis.Error("CMS: key does not exist"), err)
+	assert.Equal(t, int64(0), offset)
+
+	_, err = c.Ingest(context.Background(), "digest", strings.NewReader("1\n"), IngestOptions{Type: DataTypeTDigest})
+	assert.NotNil(t, err)
+}
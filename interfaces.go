@@ -0,0 +1,68 @@
+package redis_bloom_go
+
+// BloomCommands are the bloom filter commands of a client
+type BloomCommands interface {
+	Reserve(key string, error_rate float64, capacity uint64) error
+	Add(key string, item string) (bool, error)
+	Exists(key string, item string) (bool, error)
+	BfAddMulti(key string, items []string) ([]int64, error)
+	BfExistsMulti(key string, items []string) ([]int64, error)
+	Info(key string) (map[string]int64, error)
+}
+
+// CuckooCommands are the cuckoo filter commands of a client
+type CuckooCommands interface {
+	CfReserve(key string, capacity int64, bucketSize int64, maxIterations int64, expansion int64) (string, error)
+	CfAdd(key string, item string) (bool, error)
+	CfAddNx(key string, item string) (bool, error)
+	CfExists(key string, item string) (bool, error)
+	CfExistsMulti(key string, items ...string) ([]int64, error)
+	CfDel(key string, item string) (bool, error)
+	CfCount(key string, item string) (int64, error)
+	CfInfo(key string) (map[string]int64, error)
+}
+
+// CMSCommands are the count-min sketch commands of a client
+type CMSCommands interface {
+	CmsInitByDim(key string, width int64, depth int64) (string, error)
+	CmsInitByProb(key string, error float64, probability float64) (string, error)
+	CmsIncrBy(key string, itemIncrements map[string]int64) ([]int64, error)
+	CmsIncrByItems(key string, increments []CmsIncrement) ([]int64, error)
+	CmsQuery(key string, items []string) ([]int64, error)
+	CmsInfo(key string) (map[string]int64, error)
+}
+
+// TopKCommands are the top-k commands of a client
+type TopKCommands interface {
+	TopkReserve(key string, topk int64, width int64, depth int64, decay float64) (string, error)
+	TopkAdd(key string, items []string) ([]string, error)
+	TopkQuery(key string, items []string) ([]int64, error)
+	TopkCount(key string, items []string) ([]int64, error)
+	TopkList(key string) ([]string, error)
+	TopkListWithCount(key string) (map[string]int64, error)
+}
+
+// TDigestCommands are the t-digest commands of a client
+type TDigestCommands interface {
+	TdCreate(key string, compression int64) (string, error)
+	TdReset(key string) (string, error)
+	TdAdd(key string, samples map[float64]float64) (string, error)
+	TdAddValues(key string, values ...float64) (string, error)
+	TdMin(key string) (float64, error)
+	TdMax(key string) (float64, error)
+	TdQuantile(key string, quantile float64) (float64, error)
+	TdQuantiles(key string, quantiles ...float64) ([]float64, error)
+	TdCdf(key string, value float64) (float64, error)
+}
+
+// Commands are the commands of every RedisBloom data type. Depend on it, or on the interface of a single
+// data type, rather than on *Client to substitute mocks, fakes such as bloomtest.Fake, or decorators.
+type Commands interface {
+	BloomCommands
+	CuckooCommands
+	CMSCommands
+	TopKCommands
+	TDigestCommands
+}
+
+var _ Commands = (*Client)(nil)
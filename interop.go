@@ -0,0 +1,159 @@
+package redis_bloom_go
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/bits"
+	"math/rand"
+	"strconv"
+)
+
+// bloomDumpLinkSize is the size of the description of a sub-filter in the BF.SCANDUMP header:
+// bytes (uint64) | bits (uint64) | size (uint64) | error (double) | bpe (double) | hashes (uint32) |
+// entries (uint64) | n2 (uint8)
+const bloomDumpLinkSize = 53
+
+// ErrLocalBloomFull is returned by LocalBloom.Add once the last sub-filter holds as many items as its capacity
+var ErrLocalBloomFull = errors.New("redisbloom: local bloom filter is full")
+
+// LocalBloom is an in process bloom filter laid out and hashed exactly like a RedisBloom filter, so that it can
+// be exported from a server and imported into one bit for bit, e.g. to analyze a filter offline or to build a
+// large filter in a batch job rather than adding hundreds of millions of items over the network.
+// Libraries such as bits-and-blooms/bloom hash items differently, hence their bit arrays cannot be converted
+// to or from a RedisBloom filter: items must be added to a LocalBloom instead.
+// A LocalBloom is not safe for concurrent use.
+type LocalBloom struct {
+	header  []byte
+	iters   []int64
+	lengths []int
+	filter  *localBloom
+}
+
+// BfExportLocal - Returns an in process copy of the bloom filter stored at key, fetched with BF.SCANDUMP
+func (client *Client) BfExportLocal(key string) (*LocalBloom, error) {
+	it := client.BfScanDumpIterator(key).Prefetch(dumpPrefetchDepth)
+	defer it.Stop()
+	local := &LocalBloom{}
+	var chunks [][]byte
+	for it.Next() {
+		iter, data := it.Chunk()
+		if local.header == nil {
+			local.header = data
+		} else {
+			chunks = append(chunks, data)
+			local.lengths = append(local.lengths, len(data))
+		}
+		local.iters = append(local.iters, iter)
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	filter, err := parseBloomDump(local.header, chunks)
+	if err != nil {
+		return nil, err
+	}
+	// BfImportLocal rebuilds the chunks from the bit arrays of the sub-filters
+	dumped, linked := 0, 0
+	for _, length := range local.lengths {
+		dumped += length
+	}
+	for _, link := range filter.links {
+		linked += len(link.data)
+	}
+	if dumped != linked {
+		return nil, ErrUnsupportedDump
+	}
+	local.filter = filter
+	return local, nil
+}
+
+// BfNewLocal - Returns an empty in process bloom filter with the layout of a filter created by BF.RESERVE with
+// errorRate and capacity on this server. The layout is taken from a filter reserved under a temporary key, which
+// is deleted right away.
+func (client *Client) BfNewLocal(errorRate float64, capacity uint64) (*LocalBloom, error) {
+	key := "redisbloom-go:local-template:" + strconv.FormatInt(rand.Int63(), 36)
+	if err := client.Reserve(key, errorRate, capacity); err != nil {
+		return nil, err
+	}
+	local, err := client.BfExportLocal(key)
+	if _, delErr := client.DeleteFilter(key); err == nil {
+		err = delErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	return local, nil
+}
+
+// BfImportLocal - Stores local at key with BF.LOADCHUNK. The key must not exist.
+func (client *Client) BfImportLocal(key string, local *LocalBloom) error {
+	load := client.loadChunks("BF.LOADCHUNK", key)
+	if err := load([]dumpChunk{{iter: local.iters[0], data: local.header}}); err != nil {
+		return err
+	}
+	data := make([]byte, 0)
+	for _, link := range local.filter.links {
+		data = append(data, link.data...)
+	}
+	for i, length := range local.lengths {
+		if err := load([]dumpChunk{{iter: local.iters[i+1], data: data[:length]}}); err != nil {
+			return err
+		}
+		data = data[length:]
+	}
+	return nil
+}
+
+// Add - Adds item to the filter. Returns false if the item may already exist, and ErrLocalBloomFull if the
+// last sub-filter is at capacity: unlike the server, a LocalBloom cannot scale by adding a sub-filter.
+// Returns ErrUnsupportedDump if the filter was dumped by a module version with an unknown header layout.
+func (b *LocalBloom) Add(item string) (bool, error) {
+	a, h := b.filter.hash([]byte(item))
+	for _, link := range b.filter.links {
+		if link.contains(a, h, b.filter.force64) {
+			return false, nil
+		}
+	}
+	last := len(b.filter.links) - 1
+	if len(b.header) != bloomDumpHeaderSize+len(b.filter.links)*bloomDumpLinkSize {
+		return false, ErrUnsupportedDump
+	}
+	description := b.header[bloomDumpHeaderSize+last*bloomDumpLinkSize:]
+	size := binary.LittleEndian.Uint64(description[16:24])
+	if size >= binary.LittleEndian.Uint64(description[44:52]) {
+		return false, ErrLocalBloomFull
+	}
+	link := &b.filter.links[last]
+	for _, x := range link.positions(a, h, b.filter.force64) {
+		link.data[x>>3] |= 1 << (x % 8)
+	}
+	binary.LittleEndian.PutUint64(description[16:24], size+1)
+	binary.LittleEndian.PutUint64(b.header[0:8], binary.LittleEndian.Uint64(b.header[0:8])+1)
+	return true, nil
+}
+
+// Exists - Determines whether item may exist in the filter
+func (b *LocalBloom) Exists(item string) bool {
+	return b.filter.exists([]byte(item))
+}
+
+// Count - Returns the number of items added to the filter, as reported by BF.INFO
+func (b *LocalBloom) Count() int64 {
+	return int64(binary.LittleEndian.Uint64(b.header[0:8]))
+}
+
+// FillRatio - Returns the ratio of bits set in the bit arrays of the filter
+func (b *LocalBloom) FillRatio() float64 {
+	set, total := 0, uint64(0)
+	for _, link := range b.filter.links {
+		full := link.bits / 8
+		for _, octet := range link.data[:full] {
+			set += bits.OnesCount8(octet)
+		}
+		if rest := link.bits % 8; rest > 0 {
+			set += bits.OnesCount8(link.data[full] & (1<<rest - 1))
+		}
+		total += link.bits
+	}
+	return float64(set) / float64(total)
+}
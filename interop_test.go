@@ -0,0 +1,76 @@
+package redis_bloom_go
+
+import (
+	"encoding/binary"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testLocalBloom returns an empty LocalBloom of a single sub-filter with the given capacity
+func testLocalBloom(capacity uint64) *LocalBloom {
+	header, data := testBloomDump(bloomOptForce64, 8*1024, 7)
+	binary.LittleEndian.PutUint64(header[bloomDumpHeaderSize+44:bloomDumpHeaderSize+52], capacity)
+	filter, _ := parseBloomDump(header, [][]byte{data})
+	return &LocalBloom{header: header, iters: []int64{1, 1025}, lengths: []int{len(data)}, filter: filter}
+}
+
+func TestLocalBloom(t *testing.T) {
+	local := testLocalBloom(3)
+	added, err := local.Add("a")
+	assert.Nil(t, err)
+	assert.True(t, added)
+	added, err = local.Add("a")
+	assert.Nil(t, err)
+	assert.False(t, added)
+	local.Add("b")
+	local.Add("c")
+	assert.True(t, local.Exists("a"))
+	assert.False(t, local.Exists("d"))
+	assert.Equal(t, int64(3), local.Count())
+	assert.Equal(t, uint64(3), binary.LittleEndian.Uint64(local.header[bloomDumpHeaderSize+16:]))
+	assert.InDelta(t, 21.0/(8*1024), local.FillRatio(), 0.001)
+
+	_, err = local.Add("d")
+	assert.Equal(t, ErrLocalBloomFull, err)
+
+	// the bits of a LocalBloom are those parseBloomDump reads
+	_, data := testBloomDump(bloomOptForce64, 8*1024, 7, "a", "b", "c")
+	assert.Equal(t, data, local.filter.links[0].data)
+}
+
+func TestClient_BfExportImportLocal(t *testing.T) {
+	client.FlushAll()
+	key := "test_bf_export_local"
+	assert.Nil(t, client.Reserve(key, 0.01, 1000))
+	_, err := client.BfAddMulti(key, []string{"a", "b", "c"})
+	assert.Nil(t, err)
+	exported, err := client.BfExportLocal(key)
+	assert.Nil(t, err)
+	assert.True(t, exported.Exists("a"))
+	assert.True(t, exported.Exists("c"))
+	assert.Equal(t, int64(3), exported.Count())
+
+	local, err := client.BfNewLocal(0.01, 1000)
+	assert.Nil(t, err)
+	items := make([]string, 500)
+	for i := range items {
+		items[i] = fmt.Sprintf("item-%d", i)
+		_, err = local.Add(items[i])
+		assert.Nil(t, err)
+	}
+	imported := "test_bf_import_local"
+	assert.Nil(t, client.BfImportLocal(imported, local))
+	found, err := client.BfExistsMulti(imported, items)
+	assert.Nil(t, err)
+	for _, exists := range found {
+		assert.Equal(t, int64(1), exists)
+	}
+	info, err := client.Info(imported)
+	assert.Nil(t, err)
+	assert.Equal(t, local.Count(), info["Number of items inserted"])
+	keys, err := client.ListKeys("redisbloom-go:local-template:*")
+	assert.Nil(t, err)
+	assert.Empty(t, keys.Bloom)
+}
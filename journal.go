@@ -0,0 +1,379 @@
+package redis_bloom_go
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// journalMagic identifies a file written by a FileJournal
+const journalMagic = "RBJOURNL"
+
+// journalFormatVersion is the version of the journal file layout:
+// magic | version (1 byte) | { time (int64, Unix nanoseconds) | argument count (uint32) | { length (uint32) | argument }* }*
+// where the first argument of an entry is its command
+const journalFormatVersion = 1
+
+// maxJournalArgs bounds the number of arguments of an entry read back from a journal
+const maxJournalArgs = 1 << 24
+
+// ErrInvalidJournal is returned when replaying a file which is not a journal written by a FileJournal, or is corrupt
+var ErrInvalidJournal = errors.New("redisbloom: invalid journal")
+
+// journalStreamPageSize is the number of entries a StreamJournal reads at once when replaying
+const journalStreamPageSize = 1000
+
+// JournalEntry is a command modifying data, as recorded by a Journal. Its keys include the key prefix of the
+// client which sent it.
+type JournalEntry struct {
+	Time    time.Time
+	Command string
+	Args    [][]byte
+}
+
+// Journal is a write-ahead log of the commands modifying data of a client, see WithJournal
+type Journal interface {
+	// Append records entry, before its command is sent
+	Append(entry JournalEntry) error
+	// Replay sends the recorded commands to target in order, returning the number of commands applied
+	Replay(ctx context.Context, target *Client) (int, error)
+}
+
+// WithJournal records each command modifying data to journal before sending it, so that the data structures of
+// the client can be rebuilt on a fresh instance with journal.Replay after a data loss. A command which cannot be
+// recorded is not sent, and fails with the error of the journal. Commands failing on the server are recorded as
+// well, and skipped on replay.
+func WithJournal(journal Journal) ClientOption {
+	return func(client *Client) {
+		client.Pool = &auditPool{ConnPool: client.Pool, execute: true, record: func(command string, args []interface{}) error {
+			return journal.Append(newJournalEntry(command, args))
+		}}
+	}
+}
+
+// newJournalEntry converts the arguments of a command to their wire format, like redigo does
+func newJournalEntry(command string, args []interface{}) JournalEntry {
+	entry := JournalEntry{Time: time.Now(), Command: command, Args: make([][]byte, len(args))}
+	for i, arg := range args {
+		switch arg := arg.(type) {
+		case string:
+			entry.Args[i] = []byte(arg)
+		case []byte:
+			entry.Args[i] = append([]byte(nil), arg...)
+		case int:
+			entry.Args[i] = strconv.AppendInt(nil, int64(arg), 10)
+		case int64:
+			entry.Args[i] = strconv.AppendInt(nil, arg, 10)
+		case uint64:
+			entry.Args[i] = strconv.AppendUint(nil, arg, 10)
+		case float64:
+			entry.Args[i] = strconv.AppendFloat(nil, arg, 'g', -1, 64)
+		case bool:
+			if arg {
+				entry.Args[i] = []byte("1")
+			} else {
+				entry.Args[i] = []byte("0")
+			}
+		case nil:
+			entry.Args[i] = []byte{}
+		default:
+			entry.Args[i] = []byte(fmt.Sprint(arg))
+		}
+	}
+	return entry
+}
+
+// replayEntries sends the entries returned by next to target until it returns io.EOF or an entry recorded after
+// until, if until is not zero. Error replies are skipped: the commands failed when they were recorded too.
+func replayEntries(ctx context.Context, target *Client, until time.Time, next func() (JournalEntry, error)) (int, error) {
+	conn := target.Pool.Get()
+	defer conn.Close()
+	count, scriptsLoaded := 0, false
+	for {
+		if err := ctx.Err(); err != nil {
+			return count, err
+		}
+		entry, err := next()
+		if err == io.EOF {
+			return count, nil
+		}
+		if err != nil {
+			return count, err
+		}
+		if !until.IsZero() && entry.Time.After(until) {
+			return count, nil
+		}
+		if strings.EqualFold(entry.Command, "EVALSHA") && !scriptsLoaded {
+			// the scripts of the composite commands are not cached by a fresh instance
+			if err = target.LoadScripts(); err != nil {
+				return count, err
+			}
+			scriptsLoaded = true
+		}
+		args := make([]interface{}, len(entry.Args))
+		for i, arg := range entry.Args {
+			args[i] = arg
+		}
+		if _, err = conn.Do(entry.Command, args...); err != nil {
+			if _, ok := err.(redis.Error); !ok {
+				return count, fmt.Errorf("redisbloom: replaying %s: %v", entry.Command, err)
+			}
+			continue
+		}
+		count++
+	}
+}
+
+// FileJournal is a Journal appending to a file
+type FileJournal struct {
+	path  string
+	mutex sync.Mutex
+	file  *os.File
+}
+
+// OpenFileJournal - Opens the journal file at path for appending, creating it if needed
+func OpenFileJournal(path string) (*FileJournal, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	info, err := file.Stat()
+	if err == nil && info.Size() == 0 {
+		_, err = file.Write(append([]byte(journalMagic), journalFormatVersion))
+	}
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &FileJournal{path: path, file: file}, nil
+}
+
+// Append writes entry to the file in a single write. Sync must be called for it to survive a crash of the host.
+func (j *FileJournal) Append(entry JournalEntry) error {
+	size := 12 + 4 + len(entry.Command)
+	for _, arg := range entry.Args {
+		size += 4 + len(arg)
+	}
+	record := make([]byte, 12, size)
+	binary.BigEndian.PutUint64(record, uint64(entry.Time.UnixNano()))
+	binary.BigEndian.PutUint32(record[8:], uint32(len(entry.Args)+1))
+	record = appendJournalBytes(record, []byte(entry.Command))
+	for _, arg := range entry.Args {
+		record = appendJournalBytes(record, arg)
+	}
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	_, err := j.file.Write(record)
+	return err
+}
+
+func appendJournalBytes(record []byte, data []byte) []byte {
+	var length [4]byte
+	binary.BigEndian.PutUint32(length[:], uint32(len(data)))
+	return append(append(record, length[:]...), data...)
+}
+
+// Sync commits the entries appended so far to stable storage
+func (j *FileJournal) Sync() error {
+	return j.file.Sync()
+}
+
+// Close closes the file
+func (j *FileJournal) Close() error {
+	return j.file.Close()
+}
+
+// Replay sends every recorded command to target
+func (j *FileJournal) Replay(ctx context.Context, target *Client) (int, error) {
+	return j.ReplayUntil(ctx, target, time.Time{})
+}
+
+// ReplayUntil sends the commands recorded up to until to target, rebuilding the data as it was at that time
+func (j *FileJournal) ReplayUntil(ctx context.Context, target *Client, until time.Time) (int, error) {
+	file, err := os.Open(j.path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+	return ReplayJournal(ctx, file, target, until)
+}
+
+// ReplayJournal - Sends the commands recorded up to until, or all of them if until is zero, in the journal file
+// read from r to target. Returns the number of commands applied.
+func ReplayJournal(ctx context.Context, r io.Reader, target *Client, until time.Time) (int, error) {
+	br := bufio.NewReader(r)
+	header := make([]byte, len(journalMagic)+1)
+	if _, err := io.ReadFull(br, header); err != nil || string(header[:len(journalMagic)]) != journalMagic {
+		return 0, ErrInvalidJournal
+	}
+	if header[len(journalMagic)] != journalFormatVersion {
+		return 0, fmt.Errorf("redisbloom: unsupported journal format version %d", header[len(journalMagic)])
+	}
+	return replayEntries(ctx, target, until, func() (JournalEntry, error) {
+		return readJournalEntry(br)
+	})
+}
+
+func readJournalEntry(br *bufio.Reader) (JournalEntry, error) {
+	var head [12]byte
+	if _, err := io.ReadFull(br, head[:]); err != nil {
+		if err == io.EOF {
+			return JournalEntry{}, io.EOF
+		}
+		return JournalEntry{}, fmt.Errorf("redisbloom: reading journal entry: %v", err)
+	}
+	count := binary.BigEndian.Uint32(head[8:])
+	if count == 0 || count > maxJournalArgs {
+		return JournalEntry{}, ErrInvalidJournal
+	}
+	entry := JournalEntry{Time: time.Unix(0, int64(binary.BigEndian.Uint64(head[:]))), Args: make([][]byte, count-1)}
+	command, err := readBackupBytes(br, maxDumpChunkSize)
+	if err != nil {
+		return JournalEntry{}, err
+	}
+	entry.Command = string(command)
+	for i := range entry.Args {
+		if entry.Args[i], err = readBackupBytes(br, maxDumpChunkSize); err != nil {
+			return JournalEntry{}, err
+		}
+	}
+	return entry, nil
+}
+
+// StreamJournal is a Journal appending to a Redis stream, whose entries hold the time of the command in the
+// field "t", the command in "c" and its arguments in repeated "a" fields
+type StreamJournal struct {
+	client *Client
+	stream string
+	maxLen int64
+}
+
+// NewStreamJournal - Returns a journal appending to the stream stored at stream, trimmed to about maxLen entries
+// if maxLen is positive. client should connect to another instance than the clients using the journal, and
+// must not use the journal itself.
+func NewStreamJournal(client *Client, stream string, maxLen int64) *StreamJournal {
+	return &StreamJournal{client: client, stream: stream, maxLen: maxLen}
+}
+
+// Append adds entry to the stream
+func (j *StreamJournal) Append(entry JournalEntry) error {
+	args := redis.Args{j.client.key(j.stream)}
+	if j.maxLen > 0 {
+		args = args.Add("MAXLEN", "~", j.maxLen)
+	}
+	args = args.Add("*", "t", entry.Time.UnixNano(), "c", entry.Command)
+	for _, arg := range entry.Args {
+		args = args.Add("a", arg)
+	}
+	conn := j.client.Pool.Get()
+	defer conn.Close()
+	_, err := conn.Do("XADD", args...)
+	return err
+}
+
+// Replay sends every recorded command to target
+func (j *StreamJournal) Replay(ctx context.Context, target *Client) (int, error) {
+	return j.ReplayUntil(ctx, target, time.Time{})
+}
+
+// ReplayUntil sends the commands recorded up to until to target, rebuilding the data as it was at that time
+func (j *StreamJournal) ReplayUntil(ctx context.Context, target *Client, until time.Time) (int, error) {
+	start := "-"
+	var page []JournalEntry
+	var pageIDs []string
+	return replayEntries(ctx, target, until, func() (JournalEntry, error) {
+		if len(page) == 0 {
+			if start == "" {
+				return JournalEntry{}, io.EOF
+			}
+			var err error
+			if pageIDs, page, err = j.readPage(start); err != nil {
+				return JournalEntry{}, err
+			}
+			if len(page) < journalStreamPageSize {
+				start = ""
+			} else if start, err = nextStreamID(pageIDs[len(pageIDs)-1]); err != nil {
+				return JournalEntry{}, err
+			}
+			if len(page) == 0 {
+				return JournalEntry{}, io.EOF
+			}
+		}
+		entry := page[0]
+		page = page[1:]
+		return entry, nil
+	})
+}
+
+// readPage reads the entries of the stream from the ID start
+func (j *StreamJournal) readPage(start string) ([]string, []JournalEntry, error) {
+	conn := j.client.Pool.Get()
+	defer conn.Close()
+	messages, err := redis.Values(conn.Do("XRANGE", j.client.key(j.stream), start, "+", "COUNT", journalStreamPageSize))
+	if err != nil {
+		return nil, nil, err
+	}
+	ids := make([]string, 0, len(messages))
+	entries := make([]JournalEntry, 0, len(messages))
+	for _, message := range messages {
+		parts, err := redis.Values(message, nil)
+		if err != nil || len(parts) != 2 {
+			return nil, nil, errMalformedJournalEntry
+		}
+		id, err := redis.String(parts[0], nil)
+		if err != nil {
+			return nil, nil, errMalformedJournalEntry
+		}
+		fields, err := redis.ByteSlices(parts[1], nil)
+		if err != nil || len(fields)%2 != 0 {
+			return nil, nil, errMalformedJournalEntry
+		}
+		entry := JournalEntry{}
+		for i := 0; i < len(fields); i += 2 {
+			switch string(fields[i]) {
+			case "t":
+				nanos, err := strconv.ParseInt(string(fields[i+1]), 10, 64)
+				if err != nil {
+					return nil, nil, errMalformedJournalEntry
+				}
+				entry.Time = time.Unix(0, nanos)
+			case "c":
+				entry.Command = string(fields[i+1])
+			case "a":
+				entry.Args = append(entry.Args, fields[i+1])
+			}
+		}
+		if entry.Command == "" {
+			return nil, nil, errMalformedJournalEntry
+		}
+		ids = append(ids, id)
+		entries = append(entries, entry)
+	}
+	return ids, entries, nil
+}
+
+// errMalformedJournalEntry is returned when a stream entry was not written by a StreamJournal
+var errMalformedJournalEntry = errors.New("redisbloom: malformed journal stream entry")
+
+// nextStreamID returns the smallest stream ID greater than id
+func nextStreamID(id string) (string, error) {
+	dash := strings.IndexByte(id, '-')
+	if dash < 0 {
+		return "", errMalformedJournalEntry
+	}
+	seq, err := strconv.ParseUint(id[dash+1:], 10, 64)
+	if err != nil {
+		return "", errMalformedJournalEntry
+	}
+	return id[:dash+1] + strconv.FormatUint(seq+1, 10), nil
+}
@@ -0,0 +1,153 @@
+package redis_bloom_go
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryJournal keeps its entries in memory
+type memoryJournal struct {
+	entries []JournalEntry
+}
+
+func (j *memoryJournal) Append(entry JournalEntry) error {
+	j.entries = append(j.entries, entry)
+	return nil
+}
+
+func (j *memoryJournal) Replay(ctx context.Context, target *Client) (int, error) {
+	i := 0
+	return replayEntries(ctx, target, time.Time{}, func() (JournalEntry, error) {
+		if i == len(j.entries) {
+			return JournalEntry{}, io.EOF
+		}
+		i++
+		return j.entries[i-1], nil
+	})
+}
+
+func TestWithJournal(t *testing.T) {
+	conn := &fakeConn{replies: []interface{}{int64(1), int64(1), []interface{}{int64(2)}}}
+	journal := &memoryJournal{}
+	c := NewClientFromPool(nil, "test", WithKeyPrefix("app:"))
+	c.Pool = &fakePool{conn: conn}
+	WithJournal(journal)(c)
+
+	_, err := c.Add("bf", "a")
+	assert.Nil(t, err)
+	_, err = c.Exists("bf", "a")
+	assert.Nil(t, err)
+	_, err = c.CmsIncrBy("cms", map[string]int64{"a": 2})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"BF.ADD", "BF.EXISTS", "CMS.INCRBY"}, conn.commands)
+	assert.Len(t, journal.entries, 2)
+	assert.Equal(t, "BF.ADD", journal.entries[0].Command)
+	assert.Equal(t, [][]byte{[]byte("app:bf"), []byte("a")}, journal.entries[0].Args)
+	assert.Equal(t, [][]byte{[]byte("app:cms"), []byte("a"), []byte("2")}, journal.entries[1].Args)
+
+	target := &argsConn{fakeConn: &fakeConn{replies: []interface{}{int64(1), redis.Error("ERR boom")}}}
+	targetClient := NewClientFromPool(nil, "target")
+	targetClient.Pool = &fakePool{conn: target}
+	count, err := journal.Replay(context.Background(), targetClient)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, []string{"BF.ADD", "CMS.INCRBY"}, target.commands)
+	assert.Equal(t, []interface{}{[]byte("app:bf"), []byte("a")}, target.args[0])
+}
+
+func TestWithJournal_AppendError(t *testing.T) {
+	conn := &fakeConn{}
+	c := NewClientFromPool(nil, "test")
+	c.Pool = &fakePool{conn: conn}
+	WithJournal(failingJournal{})(c)
+	_, err := c.Add("bf", "a")
+	assert.EqualError(t, err, "journal full")
+	assert.Empty(t, conn.commands)
+}
+
+type failingJournal struct{}
+
+func (failingJournal) Append(JournalEntry) error {
+	return errors.New("journal full")
+}
+
+func (failingJournal) Replay(context.Context, *Client) (int, error) {
+	return 0, nil
+}
+
+func TestFileJournal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal")
+	journal, err := OpenFileJournal(path)
+	assert.Nil(t, err)
+	start := time.Now()
+	assert.Nil(t, journal.Append(JournalEntry{Time: start, Command: "BF.ADD", Args: [][]byte{[]byte("bf"), []byte("a")}}))
+	assert.Nil(t, journal.Close())
+	journal, err = OpenFileJournal(path)
+	assert.Nil(t, err)
+	assert.Nil(t, journal.Append(JournalEntry{Time: start.Add(time.Hour), Command: "BF.ADD", Args: [][]byte{[]byte("bf"), []byte("b")}}))
+	assert.Nil(t, journal.Sync())
+	defer journal.Close()
+
+	target := &argsConn{fakeConn: &fakeConn{replies: []interface{}{int64(1), int64(1), int64(1)}}}
+	c := NewClientFromPool(nil, "target")
+	c.Pool = &fakePool{conn: target}
+	count, err := journal.Replay(context.Background(), c)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, count)
+	count, err = journal.ReplayUntil(context.Background(), c, start.Add(time.Minute))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, []interface{}{[]byte("bf"), []byte("b")}, target.args[1])
+
+	_, err = ReplayJournal(context.Background(), bytes.NewReader([]byte("not a journal")), c, time.Time{})
+	assert.Equal(t, ErrInvalidJournal, err)
+}
+
+func TestNewJournalEntry(t *testing.T) {
+	entry := newJournalEntry("TDIGEST.ADD", []interface{}{"td", 1.5, int64(2), []byte("x"), true})
+	assert.Equal(t, [][]byte{[]byte("td"), []byte("1.5"), []byte("2"), []byte("x"), []byte("1")}, entry.Args)
+}
+
+func TestNextStreamID(t *testing.T) {
+	id, err := nextStreamID("1526919030474-55")
+	assert.Nil(t, err)
+	assert.Equal(t, "1526919030474-56", id)
+	_, err = nextStreamID("bogus")
+	assert.NotNil(t, err)
+}
+
+func TestStreamJournal(t *testing.T) {
+	client.FlushAll()
+	journal := NewStreamJournal(client, "test_journal", 0)
+	c := NewClientFromPool(nil, "journaled")
+	c.Pool = client.Pool
+	WithJournal(journal)(c)
+	_, err := c.Add("test_journal_bf", "a")
+	assert.Nil(t, err)
+	_, err = c.CmsInitByDim("test_journal_cms", 100, 5)
+	assert.Nil(t, err)
+	_, err = c.CmsIncrBy("test_journal_cms", map[string]int64{"a": 3})
+	assert.Nil(t, err)
+
+	conn := client.Pool.Get()
+	_, err = conn.Do("DEL", "test_journal_bf", "test_journal_cms")
+	conn.Close()
+	assert.Nil(t, err)
+	count, err := journal.Replay(context.Background(), client)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, count)
+	exists, err := client.Exists("test_journal_bf", "a")
+	assert.Nil(t, err)
+	assert.True(t, exists)
+	counts, err := client.CmsQuery("test_journal_cms", []string{"a"})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{3}, counts)
+}
@@ -0,0 +1,210 @@
+package redis_bloom_go
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// Module type names reported by the TYPE command for RedisBloom keys
+const (
+	bloomTypeName   = "MBbloom--"
+	cuckooTypeName  = "MBbloomCF"
+	cmsTypeName     = "CMSk-TYPE"
+	topkTypeName    = "TopK-TYPE"
+	tdigestTypeName = "TDIS-TYPE"
+)
+
+// ErrWrongType is returned when a key does not hold a RedisBloom data structure
+var ErrWrongType = errors.New("redisbloom: key does not hold a RedisBloom data structure")
+
+// DataType is a RedisBloom data structure
+type DataType string
+
+// RedisBloom data structures
+const (
+	DataTypeBloom   DataType = "bloom"
+	DataTypeCuckoo  DataType = "cuckoo"
+	DataTypeCMS     DataType = "cms"
+	DataTypeTopK    DataType = "topk"
+	DataTypeTDigest DataType = "tdigest"
+)
+
+// dataTypes maps module type names to data structures
+var dataTypes = map[string]DataType{
+	bloomTypeName:   DataTypeBloom,
+	cuckooTypeName:  DataTypeCuckoo,
+	cmsTypeName:     DataTypeCMS,
+	topkTypeName:    DataTypeTopK,
+	tdigestTypeName: DataTypeTDigest,
+}
+
+// KeyListing is an inventory of RedisBloom keys grouped by data structure
+type KeyListing struct {
+	Bloom   []string
+	Cuckoo  []string
+	CMS     []string
+	TopK    []string
+	TDigest []string
+}
+
+// scanBatchSize is the COUNT hint passed to SCAN
+const scanBatchSize = 1000
+
+// keyType returns the type name of the value stored at key, "none" if the key does not exist
+func (client *Client) keyType(key string) (string, error) {
+	conn := client.Pool.Get()
+	defer conn.Close()
+	return redis.String(conn.Do("TYPE", client.key(key)))
+}
+
+// DeleteFilter - Deletes the key holding a filter or sketch. Returns false if the key did not exist.
+func (client *Client) DeleteFilter(key string) (bool, error) {
+	conn := client.Pool.Get()
+	defer conn.Close()
+	client.forgetCached(key)
+	return redis.Bool(conn.Do("DEL", client.key(key)))
+}
+
+// ExpireFilter - Sets the time to live of the key holding a filter or sketch.
+// Returns false if the key does not exist, and ErrInvalidTTL if ttl is shorter than a millisecond.
+func (client *Client) ExpireFilter(key string, ttl time.Duration) (bool, error) {
+	// PEXPIRE 0 would delete the key
+	if ttl < time.Millisecond {
+		return false, ErrInvalidTTL
+	}
+	conn := client.Pool.Get()
+	defer conn.Close()
+	return redis.Bool(conn.Do("PEXPIRE", client.key(key), int64(ttl/time.Millisecond)))
+}
+
+// PersistFilter - Removes the time to live of the key holding a filter or sketch.
+// Returns false if the key does not exist or has no time to live.
+func (client *Client) PersistFilter(key string) (bool, error) {
+	conn := client.Pool.Get()
+	defer conn.Close()
+	return redis.Bool(conn.Do("PERSIST", client.key(key)))
+}
+
+// KeyExists - Determines whether key exists, whatever its type.
+func (client *Client) KeyExists(key string) (bool, error) {
+	conn := client.Pool.Get()
+	defer conn.Close()
+	return redis.Bool(conn.Do("EXISTS", client.key(key)))
+}
+
+// RenameFilter - Renames the key holding a filter or sketch, overwriting newKey if it exists,
+// which allows atomically swapping a rebuilt filter in place of the live one.
+// Returns ErrWrongType if oldKey does not hold a RedisBloom data structure.
+func (client *Client) RenameFilter(oldKey string, newKey string) error {
+	if err := client.checkDataType(oldKey); err != nil {
+		return err
+	}
+	conn := client.Pool.Get()
+	defer conn.Close()
+	_, err := conn.Do("RENAME", client.key(oldKey), client.key(newKey))
+	client.forgetCached(oldKey)
+	client.forgetCached(newKey)
+	return err
+}
+
+// forgetCached drops the cached lookups of key
+func (client *Client) forgetCached(key string) {
+	if client.existsCache != nil {
+		client.existsCache.forget(client.key(key))
+	}
+}
+
+// CopyFilter - Copies the key holding a filter or sketch to dst, which must not exist. Requires Redis 6.2 or newer.
+// Returns false if dst already exists, and ErrWrongType if src does not hold a RedisBloom data structure.
+// Unlike the package-level CopyFilter, which copies a filter between two clients, the copy is made by the server itself.
+func (client *Client) CopyFilter(src string, dst string) (bool, error) {
+	if err := client.checkDataType(src); err != nil {
+		return false, err
+	}
+	conn := client.Pool.Get()
+	defer conn.Close()
+	return redis.Bool(conn.Do("COPY", client.key(src), client.key(dst)))
+}
+
+// checkDataType returns ErrWrongType unless key holds a RedisBloom data structure
+func (client *Client) checkDataType(key string) error {
+	keyType, err := client.keyType(key)
+	if err != nil {
+		return err
+	}
+	if _, ok := dataTypes[keyType]; !ok {
+		return ErrWrongType
+	}
+	return nil
+}
+
+// ListKeys - Scans the keys matching pattern and classifies the RedisBloom ones by data structure.
+// Keys holding other types are left out.
+func (client *Client) ListKeys(pattern string) (*KeyListing, error) {
+	listing := &KeyListing{}
+	err := client.scanKeys(pattern, func(key string) error {
+		keyType, err := client.keyType(key)
+		if err != nil {
+			return err
+		}
+		switch dataTypes[keyType] {
+		case DataTypeBloom:
+			listing.Bloom = append(listing.Bloom, key)
+		case DataTypeCuckoo:
+			listing.Cuckoo = append(listing.Cuckoo, key)
+		case DataTypeCMS:
+			listing.CMS = append(listing.CMS, key)
+		case DataTypeTopK:
+			listing.TopK = append(listing.TopK, key)
+		case DataTypeTDigest:
+			listing.TDigest = append(listing.TDigest, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return listing, nil
+}
+
+// scanKeys calls fn for each key matching pattern, stopping at the first error.
+// The pattern and the keys passed to fn are relative to the client key prefix
+func (client *Client) scanKeys(pattern string, fn func(key string) error) error {
+	conn := client.Pool.Get()
+	defer conn.Close()
+	cursor := int64(0)
+	for {
+		reply, err := redis.Values(conn.Do("SCAN", cursor, "MATCH", escapeGlob(client.keyPrefix)+pattern, "COUNT", scanBatchSize))
+		if err != nil {
+			return err
+		}
+		var keys []string
+		if _, err = redis.Scan(reply, &cursor, &keys); err != nil {
+			return err
+		}
+		for _, key := range keys {
+			if err = fn(strings.TrimPrefix(key, client.keyPrefix)); err != nil {
+				return err
+			}
+		}
+		if cursor == 0 {
+			return nil
+		}
+	}
+}
+
+// escapeGlob escapes the characters of s that SCAN MATCH treats as glob syntax
+func escapeGlob(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '*', '?', '[', ']', '\\':
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,148 @@
+package redis_bloom_go
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_ListKeys(t *testing.T) {
+	client.FlushAll()
+	client.Add("list:bf", "a")
+	client.CfAdd("list:cf", "b")
+	client.CmsInitByDim("list:cms", 1000, 5)
+	client.TopkReserve("list:topk", 10, 2000, 7, 0.925)
+	client.TdCreate("list:td", 100)
+	client.Add("other:bf", "c")
+	conn := client.Pool.Get()
+	defer conn.Close()
+	conn.Do("SET", "list:plain", "value")
+
+	listing, err := client.ListKeys("list:*")
+	assert.Nil(t, err)
+	assert.Equal(t, &KeyListing{
+		Bloom:   []string{"list:bf"},
+		Cuckoo:  []string{"list:cf"},
+		CMS:     []string{"list:cms"},
+		TopK:    []string{"list:topk"},
+		TDigest: []string{"list:td"},
+	}, listing)
+}
+
+func TestClient_KeyLifecycle(t *testing.T) {
+	client.FlushAll()
+	key := "test_key_lifecycle"
+	exists, err := client.KeyExists(key)
+	assert.Nil(t, err)
+	assert.False(t, exists)
+	ok, err := client.ExpireFilter(key, time.Hour)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	client.Add(key, "a")
+	exists, err = client.KeyExists(key)
+	assert.Nil(t, err)
+	assert.True(t, exists)
+	ok, err = client.ExpireFilter(key, time.Hour)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	ok, err = client.PersistFilter(key)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	ok, err = client.PersistFilter(key)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+	ok, err = client.DeleteFilter(key)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	ok, err = client.DeleteFilter(key)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+}
+
+func TestClient_ExpireFilter_InvalidTTL(t *testing.T) {
+	conn := &fakeConn{}
+	c := NewClientFromPool(nil, "test")
+	c.Pool = &fakePool{conn: conn}
+	for _, ttl := range []time.Duration{0, -time.Second, time.Microsecond} {
+		_, err := c.ExpireFilter("bf", ttl)
+		assert.Equal(t, ErrInvalidTTL, err)
+	}
+	assert.Nil(t, conn.commands)
+}
+
+func TestClient_RenameCopyFilter(t *testing.T) {
+	client.FlushAll()
+	client.Add("live", "old")
+	client.Add("staging", "new")
+	conn := client.Pool.Get()
+	defer conn.Close()
+	conn.Do("SET", "plain", "value")
+
+	err := client.RenameFilter("staging", "live")
+	assert.Nil(t, err)
+	exists, err := client.Exists("live", "new")
+	assert.Nil(t, err)
+	assert.True(t, exists)
+	exists, err = client.KeyExists("staging")
+	assert.Nil(t, err)
+	assert.False(t, exists)
+
+	err = client.RenameFilter("plain", "live")
+	assert.Equal(t, ErrWrongType, err)
+	err = client.RenameFilter("missing", "live")
+	assert.Equal(t, ErrWrongType, err)
+
+	copied, err := client.CopyFilter("live", "copy")
+	assert.Nil(t, err)
+	assert.True(t, copied)
+	copied, err = client.CopyFilter("live", "copy")
+	assert.Nil(t, err)
+	assert.False(t, copied)
+	exists, err = client.Exists("copy", "new")
+	assert.Nil(t, err)
+	assert.True(t, exists)
+	_, err = client.CopyFilter("plain", "copy2")
+	assert.Equal(t, ErrWrongType, err)
+}
+
+func TestEscapeGlob(t *testing.T) {
+	assert.Equal(t, "svc:bloom:", escapeGlob("svc:bloom:"))
+	assert.Equal(t, `a\*b\?c\[d\]e\\`, escapeGlob(`a*b?c[d]e\`))
+}
+
+func TestClient_KeyPrefix(t *testing.T) {
+	client.FlushAll()
+	host, password := getTestConnectionDetails()
+	pool := &redis.Pool{Dial: func() (redis.Conn, error) {
+		return redis.Dial("tcp", host, redis.DialPassword(password))
+	}, MaxIdle: maxConns}
+	prefixed := NewClientFromPool(pool, "bloom-client-prefix", WithKeyPrefix("svc:bloom:"))
+	defer prefixed.Pool.Close()
+	assert.Equal(t, []string{"svc:bloom:a", "svc:bloom:b"}, prefixed.keys([]string{"a", "b"}))
+
+	_, err := prefixed.Add("filter", "a")
+	assert.Nil(t, err)
+	exists, err := client.Exists("svc:bloom:filter", "a")
+	assert.Nil(t, err)
+	assert.True(t, exists)
+	exists, err = prefixed.Exists("filter", "a")
+	assert.Nil(t, err)
+	assert.True(t, exists)
+
+	client.Add("filter", "b")
+	listing, err := prefixed.ListKeys("*")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"filter"}, listing.Bloom)
+
+	prefixed.CmsInitByDim("A", 1000, 5)
+	prefixed.CmsInitByDim("B", 1000, 5)
+	prefixed.CmsIncrByItems("A", []CmsIncrement{{"foo", 5}})
+	_, err = prefixed.CmsMerge("B", []string{"A"}, nil)
+	assert.Nil(t, err)
+	counts, err := client.CmsQuery("svc:bloom:B", []string{"foo"})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{5}, counts)
+}
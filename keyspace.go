@@ -0,0 +1,250 @@
+package redis_bloom_go
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// KeyEventType is the kind of keyspace notification reported by a KeyspaceListener
+type KeyEventType int
+
+// Kinds of keyspace notifications reported by a KeyspaceListener
+const (
+	// KeyExpired is reported when a key expired
+	KeyExpired KeyEventType = iota + 1
+	// KeyDeleted is reported when a key was deleted with DEL or UNLINK
+	KeyDeleted
+	// KeyEvicted is reported when a key was evicted by the maxmemory policy
+	KeyEvicted
+	// KeyRenamedFrom is reported for the old name of a renamed key
+	KeyRenamedFrom
+	// KeyRenamedTo is reported for the new name of a renamed key
+	KeyRenamedTo
+	// KeyListenerError is reported when the subscription failed; the listener subscribes again after a delay
+	KeyListenerError
+)
+
+// keyEventTypes are the keyspace notifications a KeyspaceListener reports, by name
+var keyEventTypes = map[string]KeyEventType{
+	"expired":     KeyExpired,
+	"del":         KeyDeleted,
+	"evicted":     KeyEvicted,
+	"rename_from": KeyRenamedFrom,
+	"rename_to":   KeyRenamedTo,
+}
+
+// keyspaceNotifications are the notify-keyspace-events flags a KeyspaceListener needs: keyspace events,
+// generic commands, expirations and evictions
+const keyspaceNotifications = "Kgxe"
+
+// defaultKeyspaceRetryDelay is the delay before subscribing again after a failure
+const defaultKeyspaceRetryDelay = time.Second
+
+// KeyEvent is a keyspace notification about a watched key
+type KeyEvent struct {
+	// Key is the key the notification is about, without the key prefix of the client
+	Key  string
+	Type KeyEventType
+	// Err is the error of KeyListenerError
+	Err error
+}
+
+// KeyspaceListenerConfig configures a KeyspaceListener
+type KeyspaceListenerConfig struct {
+	// Keys are the keys to watch, and Prefixes the key prefixes, relative to the key prefix of the client
+	Keys     []string
+	Prefixes []string
+	// Database is the logical database of the keys, 0 by default
+	Database int
+	// EnableNotifications turns on the keyspace notifications the listener needs with CONFIG SET when it
+	// starts. They are off by default on the server; enabling them is global to the server.
+	EnableNotifications bool
+	// RetryDelay is the delay before subscribing again after a failure, 1s by default
+	RetryDelay time.Duration
+	// OnEvent is called, from the listening goroutine, for every event
+	OnEvent func(KeyEvent)
+	// Events, if set, receives every event. Events are dropped rather than stalling the subscription when it is full.
+	Events chan<- KeyEvent
+}
+
+// KeyspaceListener subscribes to the keyspace notifications of watched keys, reporting when they expire, are
+// deleted, evicted or renamed, e.g. so that an application notices a dedup filter vanishing under it.
+// Notifications are delivered at most once: those sent while the listener is not subscribed are lost.
+type KeyspaceListener struct {
+	client   *Client
+	config   KeyspaceListenerConfig
+	patterns []interface{}
+
+	mutex sync.Mutex
+	stop  chan struct{}
+	done  chan struct{}
+	conn  redis.Conn
+}
+
+// NewKeyspaceListener - Returns a listener of the keyspace notifications of the keys and prefixes of config,
+// which subscribes once Start is called
+func NewKeyspaceListener(client *Client, config KeyspaceListenerConfig) *KeyspaceListener {
+	if config.RetryDelay <= 0 {
+		config.RetryDelay = defaultKeyspaceRetryDelay
+	}
+	channel := "__keyspace@" + strconv.Itoa(config.Database) + "__:"
+	l := &KeyspaceListener{client: client, config: config}
+	for _, key := range config.Keys {
+		l.patterns = append(l.patterns, channel+escapeGlob(client.key(key)))
+	}
+	for _, prefix := range config.Prefixes {
+		l.patterns = append(l.patterns, channel+escapeGlob(client.key(prefix))+"*")
+	}
+	return l
+}
+
+// Start - Enables the notifications if configured to, then subscribes in a background goroutine.
+// Calling Start on a started listener does nothing.
+func (l *KeyspaceListener) Start() error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.stop != nil {
+		return nil
+	}
+	if l.config.EnableNotifications {
+		if err := l.client.EnableKeyspaceNotifications(); err != nil {
+			return err
+		}
+	}
+	l.stop = make(chan struct{})
+	l.done = make(chan struct{})
+	go l.run(l.stop, l.done)
+	l.client.register(l, l.Stop)
+	return nil
+}
+
+// Stop - Unsubscribes and waits for the listening goroutine to exit
+func (l *KeyspaceListener) Stop() {
+	l.mutex.Lock()
+	stop, done, conn := l.stop, l.done, l.conn
+	l.stop, l.done = nil, nil
+	if stop != nil {
+		// closed while locked, so that listen does not subscribe a new connection past this point
+		close(stop)
+	}
+	l.mutex.Unlock()
+	if stop == nil {
+		return
+	}
+	l.client.unregister(l)
+	if conn != nil {
+		// the subscription ends once the server confirms, unblocking Receive
+		redis.PubSubConn{Conn: conn}.PUnsubscribe()
+	}
+	<-done
+}
+
+// EnableKeyspaceNotifications - Turns on the keyspace notifications of expirations, evictions and generic
+// commands such as DEL and RENAME, keeping the notifications already enabled on the server
+func (client *Client) EnableKeyspaceNotifications() error {
+	conn := basePool(client.Pool).Get()
+	defer conn.Close()
+	values, err := redis.Strings(conn.Do("CONFIG", "GET", "notify-keyspace-events"))
+	if err != nil {
+		return err
+	}
+	flags := ""
+	if len(values) == 2 {
+		flags = values[1]
+	}
+	for _, flag := range keyspaceNotifications {
+		// A enables every class of events but key-miss and new
+		if !strings.ContainsRune(flags, flag) && !(flag != 'K' && flag != 'E' && strings.ContainsRune(flags, 'A')) {
+			flags += string(flag)
+		}
+	}
+	_, err = conn.Do("CONFIG", "SET", "notify-keyspace-events", flags)
+	return err
+}
+
+// basePool returns the pool wrapped by the pools of this package, such as those of hooks, whose
+// connections can be used concurrently by a reader and a writer
+func basePool(pool ConnPool) ConnPool {
+	for {
+		wrapping, ok := pool.(wrappingPool)
+		if !ok {
+			return pool
+		}
+		pool = wrapping.unwrap()
+	}
+}
+
+func (l *KeyspaceListener) run(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+	for {
+		err := l.listen(stop)
+		select {
+		case <-stop:
+			return
+		default:
+		}
+		l.emit(KeyEvent{Type: KeyListenerError, Err: err})
+		select {
+		case <-stop:
+			return
+		case <-time.After(l.config.RetryDelay):
+		}
+	}
+}
+
+// listen subscribes on a new connection and reports notifications until the subscription ends
+func (l *KeyspaceListener) listen(stop <-chan struct{}) error {
+	psc := redis.PubSubConn{Conn: basePool(l.client.Pool).Get()}
+	defer psc.Close()
+	l.mutex.Lock()
+	select {
+	case <-stop:
+		l.mutex.Unlock()
+		return nil
+	default:
+	}
+	l.conn = psc.Conn
+	l.mutex.Unlock()
+	defer func() {
+		l.mutex.Lock()
+		l.conn = nil
+		l.mutex.Unlock()
+	}()
+	if err := psc.PSubscribe(l.patterns...); err != nil {
+		return err
+	}
+	channel := "__keyspace@" + strconv.Itoa(l.config.Database) + "__:"
+	for {
+		switch message := psc.Receive().(type) {
+		case redis.Message:
+			eventType, ok := keyEventTypes[string(message.Data)]
+			if !ok || !strings.HasPrefix(message.Channel, channel) {
+				continue
+			}
+			key := strings.TrimPrefix(message.Channel[len(channel):], l.client.keyPrefix)
+			l.emit(KeyEvent{Key: key, Type: eventType})
+		case redis.Subscription:
+			if message.Count == 0 {
+				return nil
+			}
+		case error:
+			return message
+		}
+	}
+}
+
+func (l *KeyspaceListener) emit(event KeyEvent) {
+	if l.config.OnEvent != nil {
+		l.config.OnEvent(event)
+	}
+	if l.config.Events != nil {
+		select {
+		case l.config.Events <- event:
+		default:
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package redis_bloom_go
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyspaceListener_Patterns(t *testing.T) {
+	c := NewClientFromPool(nil, "test", WithKeyPrefix("app:"))
+	l := NewKeyspaceListener(c, KeyspaceListenerConfig{Keys: []string{"dedup[1]"}, Prefixes: []string{"bf:"}, Database: 2})
+	assert.Equal(t, []interface{}{`__keyspace@2__:app:dedup\[1\]`, `__keyspace@2__:app:bf:*`}, l.patterns)
+}
+
+func TestKeyspaceListener_Listen(t *testing.T) {
+	pmessage := func(channel string, event string) interface{} {
+		return []interface{}{[]byte("pmessage"), []byte("__keyspace@0__:app:*"), []byte(channel), []byte(event)}
+	}
+	conn := &fakeConn{replies: []interface{}{
+		[]interface{}{[]byte("psubscribe"), []byte("__keyspace@0__:app:*"), int64(1)},
+		pmessage("__keyspace@0__:app:dedup", "expired"),
+		pmessage("__keyspace@0__:app:dedup", "bf.add"),
+		pmessage("__keyspace@0__:app:old", "rename_from"),
+		pmessage("__keyspace@0__:app:new", "rename_to"),
+		pmessage("__keyspace@0__:app:other", "del"),
+		pmessage("__keyspace@0__:app:cold", "evicted"),
+		[]interface{}{[]byte("punsubscribe"), []byte("__keyspace@0__:app:*"), int64(0)},
+	}}
+	c := NewClientFromPool(nil, "test", WithKeyPrefix("app:"))
+	c.Pool = &fakePool{conn: conn}
+	var events []KeyEvent
+	l := NewKeyspaceListener(c, KeyspaceListenerConfig{Prefixes: []string{""}, OnEvent: func(event KeyEvent) {
+		events = append(events, event)
+	}})
+	assert.Nil(t, l.listen(make(chan struct{})))
+	assert.Equal(t, []string{"PSUBSCRIBE"}, conn.commands)
+	assert.Equal(t, []KeyEvent{
+		{Key: "dedup", Type: KeyExpired},
+		{Key: "old", Type: KeyRenamedFrom},
+		{Key: "new", Type: KeyRenamedTo},
+		{Key: "other", Type: KeyDeleted},
+		{Key: "cold", Type: KeyEvicted},
+	}, events)
+}
+
+func TestEnableKeyspaceNotifications(t *testing.T) {
+	conn := &argsConn{fakeConn: &fakeConn{replies: []interface{}{
+		[]interface{}{[]byte("notify-keyspace-events"), []byte("Ex")}, "OK",
+		[]interface{}{[]byte("notify-keyspace-events"), []byte("KA")}, "OK",
+	}}}
+	c := NewClientFromPool(nil, "test")
+	c.Pool = &fakePool{conn: conn}
+	assert.Nil(t, c.EnableKeyspaceNotifications())
+	// evictions are enabled too, as a filter evicted by the maxmemory policy vanishes like a deleted one
+	assert.Equal(t, []interface{}{"SET", "notify-keyspace-events", "ExKge"}, conn.args[1])
+	assert.Nil(t, c.EnableKeyspaceNotifications())
+	assert.Equal(t, []interface{}{"SET", "notify-keyspace-events", "KA"}, conn.args[3])
+}
+
+func TestKeyspaceListener(t *testing.T) {
+	client.FlushAll()
+	events := make(chan KeyEvent, 10)
+	l := NewKeyspaceListener(client, KeyspaceListenerConfig{
+		Keys: []string{"test_keyspace_bf"}, EnableNotifications: true, Events: events,
+	})
+	assert.Nil(t, l.Start())
+	defer l.Stop()
+	// let the subscription start
+	time.Sleep(100 * time.Millisecond)
+	_, err := client.Add("test_keyspace_bf", "a")
+	assert.Nil(t, err)
+	conn := client.Pool.Get()
+	_, err = conn.Do("DEL", "test_keyspace_bf")
+	conn.Close()
+	assert.Nil(t, err)
+	select {
+	case event := <-events:
+		assert.Equal(t, KeyEvent{Key: "test_keyspace_bf", Type: KeyDeleted}, event)
+	case <-time.After(time.Second):
+		t.Fatal("no keyspace notification")
+	}
+}
@@ -0,0 +1,57 @@
+package redis_bloom_go
+
+import (
+	"github.com/gomodule/redigo/redis"
+)
+
+// MemoryReport is the memory footprint of a RedisBloom key
+type MemoryReport struct {
+	// Type is the data structure held by the key
+	Type DataType
+	// MemoryUsage is the number of bytes the key and its value take in RAM, as reported by MEMORY USAGE
+	MemoryUsage int64
+	// Size is the number of bytes allocated by the filter, as reported by BF.INFO or CF.INFO.
+	// Zero for other data structures
+	Size int64
+	// Filters is the number of sub-filters of a bloom or cuckoo filter. Zero for other data structures
+	Filters int64
+}
+
+// MemoryUsage - Returns the memory footprint of the RedisBloom key, along with the size and
+// number of sub-filters of bloom and cuckoo filters.
+// Returns redis.ErrNil if the key does not exist, ErrWrongType if it does not hold a RedisBloom data structure.
+func (client *Client) MemoryUsage(key string) (*MemoryReport, error) {
+	keyType, err := client.keyType(key)
+	if err != nil {
+		return nil, err
+	}
+	dataType, ok := dataTypes[keyType]
+	if !ok {
+		if keyType == "none" {
+			return nil, redis.ErrNil
+		}
+		return nil, ErrWrongType
+	}
+	conn := client.Pool.Get()
+	usage, err := redis.Int64(conn.Do("MEMORY", "USAGE", client.key(key)))
+	conn.Close()
+	if err != nil {
+		return nil, err
+	}
+	report := &MemoryReport{Type: dataType, MemoryUsage: usage}
+	var info map[string]int64
+	switch dataType {
+	case DataTypeBloom:
+		info, err = client.Info(key)
+	case DataTypeCuckoo:
+		info, err = client.CfInfo(key)
+	default:
+		return report, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	report.Size = info["Size"]
+	report.Filters = info["Number of filters"]
+	return report, nil
+}
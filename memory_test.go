@@ -0,0 +1,35 @@
+package redis_bloom_go
+
+import (
+	"testing"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_MemoryUsage(t *testing.T) {
+	client.FlushAll()
+	err := client.Reserve("test_memory_bf", 0.1, 1000)
+	assert.Nil(t, err)
+	report, err := client.MemoryUsage("test_memory_bf")
+	assert.Nil(t, err)
+	assert.Equal(t, DataTypeBloom, report.Type)
+	assert.Equal(t, int64(936), report.Size)
+	assert.Equal(t, int64(1), report.Filters)
+	assert.LessOrEqual(t, report.Size, report.MemoryUsage)
+
+	client.CmsInitByDim("test_memory_cms", 1000, 5)
+	report, err = client.MemoryUsage("test_memory_cms")
+	assert.Nil(t, err)
+	assert.Equal(t, DataTypeCMS, report.Type)
+	assert.Less(t, int64(0), report.MemoryUsage)
+	assert.Equal(t, int64(0), report.Filters)
+
+	_, err = client.MemoryUsage("missing")
+	assert.Equal(t, redis.ErrNil, err)
+	conn := client.Pool.Get()
+	defer conn.Close()
+	conn.Do("SET", "plain", "value")
+	_, err = client.MemoryUsage("plain")
+	assert.Equal(t, ErrWrongType, err)
+}
@@ -0,0 +1,229 @@
+package redis_bloom_go
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// defaultLatencyBuckets are the upper bounds, in seconds, of the command latency histogram buckets
+var defaultLatencyBuckets = []float64{0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1}
+
+// Metrics is a Hook collecting per command latency histograms and error counts, exposed along with
+// the connection pool statistics and the stats of selected bloom filters in the Prometheus text format.
+// It needs no Prometheus library: serve it as an http.Handler on the endpoint scraped by Prometheus.
+type Metrics struct {
+	client     *Client
+	buckets    []float64
+	filterKeys []string
+
+	mutex    sync.Mutex
+	commands map[string]*commandMetrics
+	// poolGets are the connections taken from the pool, their wait being observed as a command
+	poolGets      commandMetrics
+	poolExhausted uint64
+}
+
+type commandMetrics struct {
+	buckets []uint64
+	count   uint64
+	sum     float64
+	errors  uint64
+}
+
+// NewMetrics - Returns metrics registered as a hook of client. The stats of the bloom filters stored
+// at filterKeys are read with BF.INFO on each scrape.
+func NewMetrics(client *Client, filterKeys ...string) *Metrics {
+	m := &Metrics{
+		client:     client,
+		buckets:    defaultLatencyBuckets,
+		filterKeys: filterKeys,
+		commands:   make(map[string]*commandMetrics),
+		poolGets:   commandMetrics{buckets: make([]uint64, len(defaultLatencyBuckets))},
+	}
+	client.AddHook(m)
+	return m
+}
+
+// BeforeCommand implements Hook
+func (m *Metrics) BeforeCommand(command string, args []interface{}) error {
+	return nil
+}
+
+// AfterCommand implements Hook
+func (m *Metrics) AfterCommand(command string, args []interface{}, duration time.Duration, err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	metrics, ok := m.commands[command]
+	if !ok {
+		metrics = &commandMetrics{buckets: make([]uint64, len(m.buckets))}
+		m.commands[command] = metrics
+	}
+	m.observe(metrics, duration, err)
+}
+
+// AfterGet implements PoolHook
+func (m *Metrics) AfterGet(wait time.Duration, err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.observe(&m.poolGets, wait, err)
+	if err == ErrPoolExhausted {
+		m.poolExhausted++
+	}
+}
+
+// observe adds an observation of duration to metrics, with the mutex held
+func (m *Metrics) observe(metrics *commandMetrics, duration time.Duration, err error) {
+	seconds := duration.Seconds()
+	for i, bound := range m.buckets {
+		if seconds <= bound {
+			metrics.buckets[i]++
+		}
+	}
+	metrics.count++
+	metrics.sum += seconds
+	if err != nil {
+		metrics.errors++
+	}
+}
+
+// ServeHTTP writes the metrics in the Prometheus text format
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	m.WriteTo(w)
+}
+
+// WriteTo - Writes the metrics to w in the Prometheus text format
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	counter := &countingWriter{w: w}
+	bw := bufio.NewWriter(counter)
+	m.writeCommands(bw)
+	m.writePoolGets(bw)
+	if stats, ok := poolStats(m.client.Pool); ok {
+		writeMetric(bw, "redisbloom_pool_connections", "gauge", "Connections of the pool, idle or in use.", "", float64(stats.ActiveCount))
+		writeMetric(bw, "redisbloom_pool_idle_connections", "gauge", "Idle connections of the pool.", "", float64(stats.IdleCount))
+		writeMetric(bw, "redisbloom_pool_waits_total", "counter", "Connections waited for.", "", float64(stats.WaitCount))
+		writeMetric(bw, "redisbloom_pool_wait_seconds_total", "counter", "Time spent waiting for a connection.", "", stats.WaitDuration.Seconds())
+	}
+	m.writeFilters(bw)
+	err := bw.Flush()
+	return counter.n, err
+}
+
+func (m *Metrics) writeCommands(w *bufio.Writer) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	names := make([]string, 0, len(m.commands))
+	for name := range m.commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Fprintln(w, "# HELP redisbloom_command_duration_seconds Latency of the commands sent to the server.")
+	fmt.Fprintln(w, "# TYPE redisbloom_command_duration_seconds histogram")
+	for _, name := range names {
+		metrics := m.commands[name]
+		label := `command="` + escapeLabel(name) + `"`
+		for i, bound := range m.buckets {
+			fmt.Fprintf(w, "redisbloom_command_duration_seconds_bucket{%s,le=\"%s\"} %d\n", label, formatFloat(bound), metrics.buckets[i])
+		}
+		fmt.Fprintf(w, "redisbloom_command_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", label, metrics.count)
+		fmt.Fprintf(w, "redisbloom_command_duration_seconds_sum{%s} %s\n", label, formatFloat(metrics.sum))
+		fmt.Fprintf(w, "redisbloom_command_duration_seconds_count{%s} %d\n", label, metrics.count)
+	}
+	fmt.Fprintln(w, "# HELP redisbloom_command_errors_total Commands that failed, including error replies.")
+	fmt.Fprintln(w, "# TYPE redisbloom_command_errors_total counter")
+	for _, name := range names {
+		fmt.Fprintf(w, "redisbloom_command_errors_total{command=\"%s\"} %d\n", escapeLabel(name), m.commands[name].errors)
+	}
+}
+
+func (m *Metrics) writePoolGets(w *bufio.Writer) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	fmt.Fprintln(w, "# HELP redisbloom_pool_get_duration_seconds Time taken to get a connection from the pool.")
+	fmt.Fprintln(w, "# TYPE redisbloom_pool_get_duration_seconds histogram")
+	for i, bound := range m.buckets {
+		fmt.Fprintf(w, "redisbloom_pool_get_duration_seconds_bucket{le=\"%s\"} %d\n", formatFloat(bound), m.poolGets.buckets[i])
+	}
+	fmt.Fprintf(w, "redisbloom_pool_get_duration_seconds_bucket{le=\"+Inf\"} %d\n", m.poolGets.count)
+	fmt.Fprintf(w, "redisbloom_pool_get_duration_seconds_sum %s\n", formatFloat(m.poolGets.sum))
+	fmt.Fprintf(w, "redisbloom_pool_get_duration_seconds_count %d\n", m.poolGets.count)
+	writeMetric(w, "redisbloom_pool_exhausted_total", "counter", "Connections not obtained as the pool was exhausted.", "", float64(m.poolExhausted))
+}
+
+// writeFilters writes the BF.INFO stats of the filter keys, skipping the filters that cannot be read
+func (m *Metrics) writeFilters(w *bufio.Writer) {
+	if len(m.filterKeys) == 0 {
+		return
+	}
+	infos := make(map[string]map[string]int64, len(m.filterKeys))
+	for _, key := range m.filterKeys {
+		if info, err := m.client.Info(key); err == nil {
+			infos[key] = info
+		}
+	}
+	gauges := []struct{ name, help, field string }{
+		{"redisbloom_filter_capacity", "Capacity of the bloom filter.", "Capacity"},
+		{"redisbloom_filter_items", "Items inserted in the bloom filter.", "Number of items inserted"},
+		{"redisbloom_filter_size_bytes", "Memory used by the bloom filter.", "Size"},
+		{"redisbloom_filter_sub_filters", "Sub-filters of the bloom filter.", "Number of filters"},
+	}
+	for _, gauge := range gauges {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n", gauge.name, gauge.help, gauge.name)
+		for _, key := range m.filterKeys {
+			if info, ok := infos[key]; ok {
+				fmt.Fprintf(w, "%s{key=\"%s\"} %d\n", gauge.name, escapeLabel(key), info[gauge.field])
+			}
+		}
+	}
+}
+
+func writeMetric(w *bufio.Writer, name string, kind string, help string, labels string, value float64) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+	if labels != "" {
+		fmt.Fprintf(w, "%s{%s} %s\n", name, labels, formatFloat(value))
+	} else {
+		fmt.Fprintf(w, "%s %s\n", name, formatFloat(value))
+	}
+}
+
+// poolStats returns the statistics of pool, when it is, or wraps, a redis.Pool
+func poolStats(pool ConnPool) (redis.PoolStats, bool) {
+	switch p := pool.(type) {
+	case wrappingPool:
+		return poolStats(p.unwrap())
+	case interface{ Stats() redis.PoolStats }:
+		return p.Stats(), true
+	}
+	return redis.PoolStats{}, false
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// escapeLabel escapes a Prometheus label value
+func escapeLabel(value string) string {
+	return labelEscaper.Replace(value)
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
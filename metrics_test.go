@@ -0,0 +1,57 @@
+package redis_bloom_go
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics(t *testing.T) {
+	c := NewClientFromPool(&redis.Pool{}, "test")
+	m := NewMetrics(c)
+	m.AfterCommand("BF.ADD", nil, 2*time.Millisecond, nil)
+	m.AfterCommand("BF.ADD", nil, 200*time.Millisecond, errors.New("boom"))
+	m.AfterCommand("CF.EXISTS", nil, time.Millisecond, nil)
+
+	var buf bytes.Buffer
+	n, err := m.WriteTo(&buf)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(buf.Len()), n)
+	out := buf.String()
+	assert.True(t, strings.Contains(out, `redisbloom_command_duration_seconds_bucket{command="BF.ADD",le="0.0025"} 1`))
+	assert.True(t, strings.Contains(out, `redisbloom_command_duration_seconds_bucket{command="BF.ADD",le="0.25"} 2`))
+	assert.True(t, strings.Contains(out, `redisbloom_command_duration_seconds_bucket{command="BF.ADD",le="+Inf"} 2`))
+	assert.True(t, strings.Contains(out, `redisbloom_command_duration_seconds_count{command="CF.EXISTS"} 1`))
+	assert.True(t, strings.Contains(out, `redisbloom_command_errors_total{command="BF.ADD"} 1`))
+	assert.True(t, strings.Contains(out, "redisbloom_pool_connections 0\n"))
+}
+
+func TestEscapeLabel(t *testing.T) {
+	assert.Equal(t, `a\"b\\c\n`, escapeLabel("a\"b\\c\n"))
+}
+
+func TestMetrics_Filters(t *testing.T) {
+	client.FlushAll()
+	client.Reserve("test_metrics", 0.01, 1000)
+	client.Add("test_metrics", "a")
+	host, password := getTestConnectionDetails()
+	pool := &redis.Pool{Dial: func() (redis.Conn, error) {
+		return redis.Dial("tcp", host, redis.DialPassword(password))
+	}, MaxIdle: maxConns}
+	c := NewClientFromPool(pool, "bloom-client-metrics")
+	defer c.Pool.Close()
+	m := NewMetrics(c, "test_metrics", "missing")
+
+	var buf bytes.Buffer
+	_, err := m.WriteTo(&buf)
+	assert.Nil(t, err)
+	out := buf.String()
+	assert.True(t, strings.Contains(out, `redisbloom_filter_capacity{key="test_metrics"} 1000`))
+	assert.True(t, strings.Contains(out, `redisbloom_filter_items{key="test_metrics"} 1`))
+	assert.False(t, strings.Contains(out, `key="missing"`))
+}
@@ -0,0 +1,83 @@
+package redis_bloom_go
+
+import (
+	"fmt"
+)
+
+// CopyProgress reports the state of a filter copy to a progress callback
+type CopyProgress struct {
+	Key    string
+	Chunks int
+	Bytes  int64
+	Done   bool
+}
+
+// CopyFilter - Copies the bloom or cuckoo filter stored at key from src to dst by streaming its SCANDUMP
+// chunks and loading them with LOADCHUNK. The key must not exist on dst.
+// progress, if not nil, is called after each chunk and once the copy is done.
+func CopyFilter(src *Client, dst *Client, key string, progress func(CopyProgress)) error {
+	keyType, err := src.keyType(key)
+	if err != nil {
+		return err
+	}
+	var it *ScanDumpIterator
+	var loadChunk loadChunkFunc
+	switch keyType {
+	case bloomTypeName:
+		it = src.BfScanDumpIterator(key)
+		loadChunk = func(iter int64, data []byte) error {
+			_, err := dst.BfLoadChunk(key, iter, data)
+			return err
+		}
+	case cuckooTypeName:
+		it = src.CfScanDumpIterator(key)
+		loadChunk = func(iter int64, data []byte) error {
+			_, err := dst.CfLoadChunk(key, iter, data)
+			return err
+		}
+	default:
+		return fmt.Errorf("redisbloom: key %s holds a %s, not a bloom or cuckoo filter", key, keyType)
+	}
+	defer it.Prefetch(dumpPrefetchDepth).Stop()
+	state := CopyProgress{Key: key}
+	for it.Next() {
+		iter, data := it.Chunk()
+		if err = loadChunk(iter, data); err != nil {
+			return err
+		}
+		state.Chunks++
+		state.Bytes += int64(len(data))
+		if progress != nil {
+			progress(state)
+		}
+	}
+	if err = it.Err(); err != nil {
+		return err
+	}
+	state.Done = true
+	if progress != nil {
+		progress(state)
+	}
+	return nil
+}
+
+// CopyAll - Copies every bloom and cuckoo filter whose key matches pattern from src to dst,
+// skipping keys of other types. Returns the number of filters copied.
+func CopyAll(src *Client, dst *Client, pattern string, progress func(CopyProgress)) (int, error) {
+	copied := 0
+	err := src.scanKeys(pattern, func(key string) error {
+		keyType, err := src.keyType(key)
+		if err != nil {
+			return err
+		}
+		if keyType != bloomTypeName && keyType != cuckooTypeName {
+			return nil
+		}
+		if err = CopyFilter(src, dst, key, progress); err != nil {
+			return err
+		}
+		copied++
+		return nil
+	})
+	return copied, err
+}
@@ -0,0 +1,69 @@
+package redis_bloom_go
+
+import (
+	"testing"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+// createDatabaseClient returns a client on another logical database of the test server
+func createDatabaseClient(db int) *Client {
+	host, password := getTestConnectionDetails()
+	pool := &redis.Pool{Dial: func() (redis.Conn, error) {
+		return redis.Dial("tcp", host, redis.DialPassword(password), redis.DialDatabase(db))
+	}, MaxIdle: maxConns}
+	return NewClientFromPool(pool, "bloom-client-db")
+}
+
+func TestCopyFilter(t *testing.T) {
+	client.FlushAll()
+	dst := createDatabaseClient(1)
+	defer dst.Pool.Close()
+
+	err := client.Reserve("bf_src", 0.01, 1000)
+	assert.Nil(t, err)
+	client.Add("bf_src", "a")
+	var last CopyProgress
+	err = CopyFilter(client, dst, "bf_src", func(p CopyProgress) {
+		last = p
+	})
+	assert.Nil(t, err)
+	assert.True(t, last.Done)
+	assert.Equal(t, "bf_src", last.Key)
+	assert.Less(t, 0, last.Chunks)
+	exists, err := dst.Exists("bf_src", "a")
+	assert.Nil(t, err)
+	assert.True(t, exists)
+
+	conn := client.Pool.Get()
+	defer conn.Close()
+	_, err = conn.Do("SET", "plain", "value")
+	assert.Nil(t, err)
+	err = CopyFilter(client, dst, "plain", nil)
+	assert.NotNil(t, err)
+}
+
+func TestCopyAll(t *testing.T) {
+	client.FlushAll()
+	dst := createDatabaseClient(1)
+	defer dst.Pool.Close()
+
+	client.Add("filters:bf", "a")
+	client.CfAdd("filters:cf", "b")
+	client.CmsInitByDim("filters:cms", 1000, 5)
+	client.Add("other:bf", "c")
+	copied, err := CopyAll(client, dst, "filters:*", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, copied)
+
+	exists, err := dst.Exists("filters:bf", "a")
+	assert.Nil(t, err)
+	assert.True(t, exists)
+	exists, err = dst.CfExists("filters:cf", "b")
+	assert.Nil(t, err)
+	assert.True(t, exists)
+	exists, err = dst.Exists("other:bf", "c")
+	assert.Nil(t, err)
+	assert.False(t, exists)
+}
@@ -0,0 +1,197 @@
+package redis_bloom_go
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// QuantileAlertType is the kind of alert reported by a QuantileMonitor
+type QuantileAlertType int
+
+// Kinds of alerts reported by a QuantileMonitor
+const (
+	// AlertFiring is reported when a quantile has been above its threshold for the configured number of checks
+	AlertFiring QuantileAlertType = iota + 1
+	// AlertResolved is reported when the quantile of a firing rule falls back to or below its threshold
+	AlertResolved
+	// AlertError is reported when the quantiles of a t-digest could not be read
+	AlertError
+)
+
+// defaultMonitorInterval is the delay between two checks of a QuantileMonitor when none is configured
+const defaultMonitorInterval = 10 * time.Second
+
+// QuantileRule is a threshold on a quantile of the t-digest stored at Key, e.g. the 0.99 quantile above 250
+type QuantileRule struct {
+	Key       string
+	Quantile  float64
+	Threshold float64
+	// Consecutive is the number of consecutive checks the quantile must be above Threshold to fire, 1 if zero
+	Consecutive int
+}
+
+// QuantileAlert is a change in the state of a QuantileRule
+type QuantileAlert struct {
+	Rule QuantileRule
+	Type QuantileAlertType
+	// Value is the quantile read by the check which raised the alert, zero for AlertError
+	Value float64
+	// Err is the error of AlertError
+	Err error
+}
+
+// QuantileMonitorConfig configures a QuantileMonitor
+type QuantileMonitorConfig struct {
+	// Interval is the delay between two checks, 10s by default
+	Interval time.Duration
+	// OnAlert is called, from the polling goroutine, for every alert
+	OnAlert func(QuantileAlert)
+	// Alerts, if set, receives every alert. Alerts are dropped rather than stalling the checks when it is full.
+	Alerts chan<- QuantileAlert
+}
+
+// quantileRuleState is what a QuantileMonitor remembers of a rule between two checks
+type quantileRuleState struct {
+	breaches int
+	firing   bool
+}
+
+// QuantileMonitor periodically reads quantiles of t-digests and alerts when they stay above a threshold
+type QuantileMonitor struct {
+	client  *Client
+	config  QuantileMonitorConfig
+	rules   []QuantileRule
+	states  []quantileRuleState
+	stop    chan struct{}
+	done    chan struct{}
+	mutex   sync.Mutex
+	polling sync.Mutex
+}
+
+// NewQuantileMonitor - Returns a monitor of rules, which starts polling once Start is called
+func NewQuantileMonitor(client *Client, config QuantileMonitorConfig, rules ...QuantileRule) *QuantileMonitor {
+	if config.Interval <= 0 {
+		config.Interval = defaultMonitorInterval
+	}
+	for i := range rules {
+		if rules[i].Consecutive <= 0 {
+			rules[i].Consecutive = 1
+		}
+	}
+	return &QuantileMonitor{
+		client: client,
+		config: config,
+		rules:  rules,
+		states: make([]quantileRuleState, len(rules)),
+	}
+}
+
+// Start - Starts polling in a background goroutine. Calling Start on a started monitor does nothing.
+func (m *QuantileMonitor) Start() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.stop != nil {
+		return
+	}
+	m.stop = make(chan struct{})
+	m.done = make(chan struct{})
+	go m.run(m.stop, m.done)
+	m.client.register(m, m.Stop)
+}
+
+// Stop - Stops polling and waits for the polling goroutine to exit
+func (m *QuantileMonitor) Stop() {
+	m.mutex.Lock()
+	stop, done := m.stop, m.done
+	m.stop, m.done = nil, nil
+	m.mutex.Unlock()
+	if stop == nil {
+		return
+	}
+	m.client.unregister(m)
+	close(stop)
+	<-done
+}
+
+func (m *QuantileMonitor) run(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(m.config.Interval)
+	defer ticker.Stop()
+	m.Poll()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			m.Poll()
+		}
+	}
+}
+
+// Poll - Checks every rule once, reading the quantiles of each t-digest with a single command,
+// and reports the resulting alerts
+func (m *QuantileMonitor) Poll() {
+	m.polling.Lock()
+	defer m.polling.Unlock()
+	var keys []string
+	byKey := make(map[string][]int)
+	for i, rule := range m.rules {
+		if _, ok := byKey[rule.Key]; !ok {
+			keys = append(keys, rule.Key)
+		}
+		byKey[rule.Key] = append(byKey[rule.Key], i)
+	}
+	for _, key := range keys {
+		indexes := byKey[key]
+		quantiles := make([]float64, len(indexes))
+		for j, i := range indexes {
+			quantiles[j] = m.rules[i].Quantile
+		}
+		values, err := m.client.TdQuantiles(key, quantiles...)
+		for j, i := range indexes {
+			if err != nil {
+				m.emit(QuantileAlert{Rule: m.rules[i], Type: AlertError, Err: err})
+				continue
+			}
+			if alert, ok := m.check(i, values[j]); ok {
+				m.emit(alert)
+			}
+		}
+	}
+}
+
+// check updates the state of the rule at index i with value, and returns the alert it raises, if any.
+// NaN, the quantile of an empty t-digest, neither breaches nor resolves the rule.
+func (m *QuantileMonitor) check(i int, value float64) (QuantileAlert, bool) {
+	rule, state := m.rules[i], &m.states[i]
+	if math.IsNaN(value) {
+		return QuantileAlert{}, false
+	}
+	if value <= rule.Threshold {
+		state.breaches = 0
+		if state.firing {
+			state.firing = false
+			return QuantileAlert{Rule: rule, Type: AlertResolved, Value: value}, true
+		}
+		return QuantileAlert{}, false
+	}
+	state.breaches++
+	if !state.firing && state.breaches >= rule.Consecutive {
+		state.firing = true
+		return QuantileAlert{Rule: rule, Type: AlertFiring, Value: value}, true
+	}
+	return QuantileAlert{}, false
+}
+
+func (m *QuantileMonitor) emit(alert QuantileAlert) {
+	if m.config.OnAlert != nil {
+		m.config.OnAlert(alert)
+	}
+	if m.config.Alerts != nil {
+		select {
+		case m.config.Alerts <- alert:
+		default:
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package redis_bloom_go
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuantileMonitor_check(t *testing.T) {
+	m := NewQuantileMonitor(client, QuantileMonitorConfig{}, QuantileRule{Key: "latency", Quantile: 0.99, Threshold: 100, Consecutive: 3})
+	checks := func(values ...float64) []QuantileAlertType {
+		result := []QuantileAlertType{}
+		for _, value := range values {
+			if alert, ok := m.check(0, value); ok {
+				result = append(result, alert.Type)
+			}
+		}
+		return result
+	}
+
+	assert.Equal(t, []QuantileAlertType{}, checks(150, 150, 50, 150, 150))
+	alert, ok := m.check(0, 120)
+	assert.True(t, ok)
+	assert.Equal(t, AlertFiring, alert.Type)
+	assert.Equal(t, 120.0, alert.Value)
+	assert.Equal(t, "latency", alert.Rule.Key)
+	assert.Equal(t, []QuantileAlertType{}, checks(200, 300))
+	assert.Equal(t, []QuantileAlertType{AlertResolved}, checks(100, 90))
+	assert.Equal(t, []QuantileAlertType{AlertFiring}, checks(101, 101, 101, 101))
+
+	// the quantile of an empty digest neither breaches nor resolves, nor resets the consecutive breaches
+	assert.Equal(t, []QuantileAlertType{}, checks(math.NaN()))
+	assert.Equal(t, []QuantileAlertType{AlertResolved}, checks(50, 150, 150, math.NaN()))
+	assert.Equal(t, []QuantileAlertType{AlertFiring}, checks(150))
+	assert.Equal(t, []QuantileAlertType{}, checks(math.NaN(), math.NaN()))
+	assert.Equal(t, []QuantileAlertType{AlertResolved}, checks(90))
+
+	// a rule without Consecutive fires on the first breach
+	m = NewQuantileMonitor(client, QuantileMonitorConfig{}, QuantileRule{Key: "latency", Quantile: 0.5, Threshold: 10})
+	assert.Equal(t, []QuantileAlertType{AlertFiring}, checks(11))
+}
+
+func TestQuantileMonitor_Start(t *testing.T) {
+	client.FlushAll()
+	key := "test_quantile_monitor"
+	client.TdCreate(key, 100)
+	client.TdAddValues(key, 10, 20, 30)
+	alerts := make(chan QuantileAlert, 10)
+	m := NewQuantileMonitor(client, QuantileMonitorConfig{Interval: 10 * time.Millisecond, Alerts: alerts},
+		QuantileRule{Key: key, Quantile: 1, Threshold: 100, Consecutive: 2},
+		QuantileRule{Key: "test_quantile_monitor_missing", Quantile: 0.5, Threshold: 1})
+	m.Start()
+	defer m.Stop()
+	alert := <-alerts
+	assert.Equal(t, AlertError, alert.Type)
+	assert.NotNil(t, alert.Err)
+
+	client.TdAddValues(key, 500)
+	for alert = range alerts {
+		if alert.Type != AlertError {
+			break
+		}
+	}
+	assert.Equal(t, AlertFiring, alert.Type)
+	assert.Equal(t, 500.0, alert.Value)
+}
+
+func TestNewQuantileMonitor_DefaultInterval(t *testing.T) {
+	m := NewQuantileMonitor(NewClientFromPool(nil, "test"), QuantileMonitorConfig{})
+	assert.Equal(t, defaultMonitorInterval, m.config.Interval)
+	m.Start()
+	m.Stop()
+}
+
+func TestQuantileMonitor_EmptyDigest(t *testing.T) {
+	conn := &fakeConn{replies: []interface{}{[]interface{}{[]byte("nan")}}}
+	c := NewClientFromPool(nil, "test", WithTDigestSyntax(TDigestSyntaxModern))
+	c.Pool = &fakePool{conn: conn}
+	var alerts []QuantileAlert
+	m := NewQuantileMonitor(c, QuantileMonitorConfig{OnAlert: func(alert QuantileAlert) { alerts = append(alerts, alert) }},
+		QuantileRule{Key: "latency", Quantile: 0.99, Threshold: 100})
+	m.Poll()
+	assert.Equal(t, []string{"TDIGEST.QUANTILE"}, conn.commands)
+	assert.Nil(t, alerts)
+}
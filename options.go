@@ -0,0 +1,195 @@
+package redis_bloom_go
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// ClientOption configures a Client at construction time
+type ClientOption func(*Client)
+
+// WithMaxBatchSize splits multi-item commands carrying more than maxBatchSize items
+// into pipelined commands of at most maxBatchSize items each. Zero disables splitting.
+// Applies to BfAddMulti, BfExistsMulti and CmsQuery
+func WithMaxBatchSize(maxBatchSize int) ClientOption {
+	return func(client *Client) {
+		client.maxBatchSize = maxBatchSize
+	}
+}
+
+// WithKeyPrefix prepends prefix to every key the client sends to the server, so that
+// several applications can share a server without key collisions. Keys returned by
+// the client, e.g. by ListKeys, are relative to the prefix.
+func WithKeyPrefix(prefix string) ClientOption {
+	return func(client *Client) {
+		client.keyPrefix = prefix
+	}
+}
+
+// WithWriteTTL sets the time to live of bloom and cuckoo filters on every write
+// (Add, BfAddMulti, BfInsert, CfAdd, CfAddNx, CfInsert, CfInsertNx), atomically with each successful
+// write, so that filters created implicitly never live forever. Writes fail with ErrInvalidTTL if ttl
+// is positive but shorter than a millisecond.
+// When BfAddMulti is split in several batches, the TTL is set after the last one.
+func WithWriteTTL(ttl time.Duration) ClientOption {
+	return func(client *Client) {
+		client.writeTTL = ttl
+	}
+}
+
+// WithExistsCache keeps in process up to size (key, item) pairs for which Exists returned true,
+// answering repeated lookups without a round trip. Since items are never removed from a bloom filter
+// a cached answer only becomes stale if the key is deleted or renamed behind the client back, which
+// ttl bounds; zero ttl keeps entries until evicted. DeleteFilter and RenameFilter invalidate the cache.
+func WithExistsCache(size int, ttl time.Duration) ClientOption {
+	return func(client *Client) {
+		client.existsCache = newPositiveCache(size, ttl)
+	}
+}
+
+// WithCoalescing makes concurrent identical Exists, CfExists and CmsQuery calls share a single
+// round trip: a call arriving while the same lookup is in flight waits for it and returns its result.
+func WithCoalescing() ClientOption {
+	return func(client *Client) {
+		client.flights = newFlightGroup()
+	}
+}
+
+// WithOnConnect runs onConnect on every new connection of the client pool, including the replicas of
+// WithReplicas, before it is first used, e.g. to send CLIENT SETNAME or CLIENT TRACKING. A connection for
+// which onConnect returns an error is closed and the error is returned to the command that needed it.
+// It applies to the pools of this package and to redis.Pool, whose dial function it wraps, and must
+// therefore be given before any connection is made. With any other ConnPool implementation, every command
+// of the client fails with ErrOnConnectUnsupported rather than run on connections left unprepared.
+func WithOnConnect(onConnect func(conn redis.Conn) error) ClientOption {
+	return func(client *Client) {
+		client.addConnectFunc(func(ctx context.Context, conn redis.Conn) error { return onConnect(conn) }, false)
+	}
+}
+
+// addConnectFunc runs connect on every new connection of the client pool, before the functions which do not
+// authenticate if auth
+func (client *Client) addConnectFunc(connect connectFunc, auth bool) {
+	if client.connect == nil {
+		client.connect = &connectChain{}
+	}
+	client.connect.add(connect, auth)
+	if !addConnectChain(client.Pool, client.connect) {
+		// e.g. writes must not land in database 0 when WithDatabase selects another one
+		client.Pool = &unpreparedPool{ConnPool: client.Pool}
+	}
+}
+
+// ErrOnConnectUnsupported is returned by the commands of a client given WithOnConnect, WithDatabase or
+// WithCredentialsProvider along with a ConnPool whose new connections they cannot prepare
+var ErrOnConnectUnsupported = errors.New("redisbloom: the client pool cannot prepare its new connections")
+
+// unpreparedPool fails every command with ErrOnConnectUnsupported
+type unpreparedPool struct {
+	ConnPool
+}
+
+func (p *unpreparedPool) Get() redis.Conn {
+	return errorConn{ErrOnConnectUnsupported}
+}
+
+// WithDatabase selects the logical database db on every new connection of the client pool, like WithOnConnect
+func WithDatabase(db int) ClientOption {
+	return WithOnConnect(func(conn redis.Conn) error {
+		_, err := conn.Do("SELECT", db)
+		return err
+	})
+}
+
+// CallOption sets an optional argument of a single command call.
+// Options that do not apply to a given command are ignored by it.
+type CallOption func(*callOptions)
+
+type callOptions struct {
+	bucketSize    *int64
+	maxIterations *int64
+	expansion     *int64
+	compression   *int64
+	capacity      *int64
+	errorRate     *float64
+	noCreate      bool
+	nonScaling    bool
+	extraArgs     []interface{}
+}
+
+func newCallOptions(opts []CallOption) *callOptions {
+	o := &callOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithBucketSize sets the number of items in each bucket (CF.RESERVE BUCKETSIZE)
+func WithBucketSize(bucketSize int64) CallOption {
+	return func(o *callOptions) {
+		o.bucketSize = &bucketSize
+	}
+}
+
+// WithMaxIterations sets the number of attempts to swap items between buckets
+// before declaring the filter as full (CF.RESERVE MAXITERATIONS)
+func WithMaxIterations(maxIterations int64) CallOption {
+	return func(o *callOptions) {
+		o.maxIterations = &maxIterations
+	}
+}
+
+// WithExpansion sets the growth factor applied when a new sub-filter is created (EXPANSION)
+func WithExpansion(expansion int64) CallOption {
+	return func(o *callOptions) {
+		o.expansion = &expansion
+	}
+}
+
+// WithCompression sets the accuracy/memory tradeoff of a t-digest sketch (TDIGEST.CREATE COMPRESSION)
+func WithCompression(compression int64) CallOption {
+	return func(o *callOptions) {
+		o.compression = &compression
+	}
+}
+
+// WithCapacity sets the capacity of a filter created by an insert command (CAPACITY)
+func WithCapacity(capacity int64) CallOption {
+	return func(o *callOptions) {
+		o.capacity = &capacity
+	}
+}
+
+// WithErrorRate sets the false positive rate of a bloom filter created by an insert command (ERROR)
+func WithErrorRate(errorRate float64) CallOption {
+	return func(o *callOptions) {
+		o.errorRate = &errorRate
+	}
+}
+
+// WithNoCreate makes an insert command fail instead of creating a filter that does not exist (NOCREATE)
+func WithNoCreate() CallOption {
+	return func(o *callOptions) {
+		o.noCreate = true
+	}
+}
+
+// WithExtraArgs appends args to the optional arguments of a command, before ITEMS for the insert commands,
+// e.g. to pass flags of a RedisBloom release newer than this client
+func WithExtraArgs(args ...interface{}) CallOption {
+	return func(o *callOptions) {
+		o.extraArgs = append(o.extraArgs, args...)
+	}
+}
+
+// WithNonScaling prevents a bloom filter created by an insert command from adding sub-filters
+// once full (NONSCALING)
+func WithNonScaling() CallOption {
+	return func(o *callOptions) {
+		o.nonScaling = true
+	}
+}
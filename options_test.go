@@ -0,0 +1,40 @@
+package redis_bloom_go
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInsertOptionsArgs(t *testing.T) {
+	conn := &argsConn{fakeConn: &fakeConn{replies: []interface{}{
+		[]interface{}{int64(1)}, []interface{}{int64(1)}, []interface{}{int64(1)},
+	}}}
+	c := NewClientFromPool(nil, "test")
+	c.Pool = &fakePool{conn: conn}
+
+	_, err := c.BfInsert("bf", 1000, 0.01, -1, true, true, []string{"a"})
+	assert.Nil(t, err)
+	_, err = c.CfInsertWithOptions("cf", []string{"a"}, WithCapacity(500), WithNoCreate())
+	assert.Nil(t, err)
+	_, err = c.CfInsertNx("cf", -1, false, []string{"a"})
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{"bf", "CAPACITY", int64(1000), "ERROR", 0.01, "NOCREATE", "NONSCALING", "ITEMS", "a"}, conn.args[0])
+	assert.Equal(t, []interface{}{"cf", "CAPACITY", int64(500), "NOCREATE", "ITEMS", "a"}, conn.args[1])
+	assert.Equal(t, []interface{}{"cf", "ITEMS", "a"}, conn.args[2])
+}
+
+func TestExtraArgs(t *testing.T) {
+	conn := &argsConn{fakeConn: &fakeConn{replies: []interface{}{
+		[]interface{}{int64(1)}, "OK",
+	}}}
+	c := NewClientFromPool(nil, "test")
+	c.Pool = &fakePool{conn: conn}
+
+	_, err := c.BfInsertWithOptions("bf", []string{"a"}, WithCapacity(100), WithExtraArgs("NEWFLAG", 3))
+	assert.Nil(t, err)
+	_, err = c.CfReserveWithOptions("cf", 1000, WithExtraArgs("NEWFLAG"), WithExtraArgs("OTHER"))
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{"bf", "CAPACITY", int64(100), "NEWFLAG", 3, "ITEMS", "a"}, conn.args[0])
+	assert.Equal(t, []interface{}{"cf", int64(1000), "NEWFLAG", "OTHER"}, conn.args[1])
+}
@@ -0,0 +1,118 @@
+package redis_bloom_go
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrInvalidPlan is returned by the planning functions for out of range arguments
+var ErrInvalidPlan = errors.New("redisbloom: capacity must be positive and rates must be in (0, 1)")
+
+// BloomPlan is the recommended sizing of a bloom filter
+type BloomPlan struct {
+	// Capacity is the number of items the filter is reserved for
+	Capacity uint64
+	// ErrorRate is the targeted false positive rate
+	ErrorRate float64
+	// BitsPerItem is the number of bits spent per item to reach ErrorRate
+	BitsPerItem float64
+	// Bits is the total size of the bit array
+	Bits uint64
+	// MemoryBytes is the estimated memory taken by the bit array
+	MemoryBytes uint64
+	// HashFunctions is the number of hash functions applied to each item
+	HashFunctions int64
+}
+
+// CMSPlan is the recommended sizing of a count-min sketch
+type CMSPlan struct {
+	// ErrorRate is the over-count bound as a fraction of the total count (epsilon)
+	ErrorRate float64
+	// Probability is the desired probability of an over-count above ErrorRate (delta)
+	Probability float64
+	// Width is the number of counters in each row
+	Width int64
+	// Depth is the number of rows
+	Depth int64
+	// MemoryBytes is the estimated memory taken by the counters
+	MemoryBytes int64
+}
+
+// cmsCounterBytes is the size of a count-min sketch counter
+const cmsCounterBytes = 4
+
+// PlanBloom - Computes the sizing of a bloom filter holding capacity items with a false positive rate
+// of errorRate, using the same formulas as BF.RESERVE. The result can be passed to Reserve.
+func PlanBloom(capacity uint64, errorRate float64) (BloomPlan, error) {
+	if capacity == 0 || errorRate <= 0 || errorRate >= 1 {
+		return BloomPlan{}, ErrInvalidPlan
+	}
+	bitsPerItem := -math.Log(errorRate) / (math.Ln2 * math.Ln2)
+	bits := uint64(math.Ceil(float64(capacity) * bitsPerItem))
+	return BloomPlan{
+		Capacity:      capacity,
+		ErrorRate:     errorRate,
+		BitsPerItem:   bitsPerItem,
+		Bits:          bits,
+		MemoryBytes:   (bits + 7) / 8,
+		HashFunctions: int64(math.Ceil(math.Ln2 * bitsPerItem)),
+	}, nil
+}
+
+// PlanCMS - Computes the dimensions of a count-min sketch whose estimates exceed the true counts by more than
+// errorRate times the total count with a probability of at most probability, using the same formulas
+// as CMS.INITBYPROB. The result can be passed to CmsInitByDim.
+func PlanCMS(errorRate float64, probability float64) (CMSPlan, error) {
+	if errorRate <= 0 || errorRate >= 1 || probability <= 0 || probability >= 1 {
+		return CMSPlan{}, ErrInvalidPlan
+	}
+	width := int64(math.Ceil(2 / errorRate))
+	depth := int64(math.Ceil(math.Log10(probability) / math.Log10(0.5)))
+	return CMSPlan{
+		ErrorRate:   errorRate,
+		Probability: probability,
+		Width:       width,
+		Depth:       depth,
+		MemoryBytes: width * depth * cmsCounterBytes,
+	}, nil
+}
+
+// PlanCMSFromDim - Computes the error bounds of a count-min sketch of width and depth, the inverse of PlanCMS:
+// an estimate exceeds the true count by more than 2/width times the total count with a probability of at most
+// 0.5^depth, as assumed by CMS.INITBYPROB
+func PlanCMSFromDim(width int64, depth int64) (CMSPlan, error) {
+	if width <= 0 || depth <= 0 {
+		return CMSPlan{}, ErrInvalidPlan
+	}
+	return CMSPlan{
+		ErrorRate:   2 / float64(width),
+		Probability: math.Pow(0.5, float64(depth)),
+		Width:       width,
+		Depth:       depth,
+		MemoryBytes: width * depth * cmsCounterBytes,
+	}, nil
+}
+
+// CMSErrorBounds are the error bounds of an existing count-min sketch
+type CMSErrorBounds struct {
+	CMSPlan
+	// Count is the total count of the sketch
+	Count int64
+	// MaxOverCount is the over-count an estimate exceeds with a probability of at most Probability,
+	// i.e. ErrorRate times Count
+	MaxOverCount float64
+}
+
+// CmsErrorBounds - Returns the error bounds of the count-min sketch stored at key, derived from the width,
+// depth and total count reported by CMS.INFO
+func (client *Client) CmsErrorBounds(key string) (CMSErrorBounds, error) {
+	info, err := client.CmsInfoTyped(key)
+	if err != nil {
+		return CMSErrorBounds{}, err
+	}
+	plan, err := PlanCMSFromDim(info.Width, info.Depth)
+	if err != nil {
+		return CMSErrorBounds{}, err
+	}
+	return CMSErrorBounds{CMSPlan: plan, Count: info.Count, MaxOverCount: plan.ErrorRate * float64(info.Count)}, nil
+}
@@ -0,0 +1,65 @@
+package redis_bloom_go
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlanBloom(t *testing.T) {
+	plan, err := PlanBloom(1000000, 0.01)
+	assert.Nil(t, err)
+	assert.InDelta(t, 9.585, plan.BitsPerItem, 0.001)
+	assert.Equal(t, uint64(9585059), plan.Bits)
+	assert.Equal(t, uint64(1198133), plan.MemoryBytes)
+	assert.Equal(t, int64(7), plan.HashFunctions)
+
+	_, err = PlanBloom(0, 0.01)
+	assert.Equal(t, ErrInvalidPlan, err)
+	_, err = PlanBloom(1000, 1)
+	assert.Equal(t, ErrInvalidPlan, err)
+}
+
+func TestPlanCMS(t *testing.T) {
+	plan, err := PlanCMS(0.001, 0.01)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2000), plan.Width)
+	assert.Equal(t, int64(7), plan.Depth)
+	assert.Equal(t, int64(56000), plan.MemoryBytes)
+
+	_, err = PlanCMS(0, 0.01)
+	assert.Equal(t, ErrInvalidPlan, err)
+}
+
+func TestPlanCMSFromDim(t *testing.T) {
+	plan, err := PlanCMSFromDim(2000, 7)
+	assert.Nil(t, err)
+	assert.InDelta(t, 0.001, plan.ErrorRate, 1e-12)
+	assert.InDelta(t, 0.0078125, plan.Probability, 1e-12)
+	assert.Equal(t, int64(56000), plan.MemoryBytes)
+
+	// planning from the bounds gives back the dimensions
+	back, err := PlanCMS(plan.ErrorRate, plan.Probability)
+	assert.Nil(t, err)
+	assert.Equal(t, plan.Width, back.Width)
+	assert.Equal(t, plan.Depth, back.Depth)
+
+	_, err = PlanCMSFromDim(0, 7)
+	assert.Equal(t, ErrInvalidPlan, err)
+}
+
+func TestClient_CmsErrorBounds(t *testing.T) {
+	client.FlushAll()
+	key := "test_cms_error_bounds"
+	client.CmsInitByDim(key, 2000, 7)
+	client.CmsIncrBy(key, map[string]int64{"a": 300, "b": 700})
+
+	bounds, err := client.CmsErrorBounds(key)
+	assert.Nil(t, err)
+	assert.InDelta(t, 0.001, bounds.ErrorRate, 1e-12)
+	assert.Equal(t, int64(1000), bounds.Count)
+	assert.InDelta(t, 1.0, bounds.MaxOverCount, 1e-9)
+
+	_, err = client.CmsErrorBounds("test_cms_error_bounds_missing")
+	assert.NotNil(t, err)
+}
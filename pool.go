@@ -1,6 +1,7 @@
 package redis_bloom_go
 
 import (
+	"context"
 	"fmt"
 	"math/rand"
 	"sync"
@@ -38,6 +39,7 @@ type MultiHostPool struct {
 	pools    map[string]*redis.Pool
 	hosts    []string
 	authPass *string
+	connect  []*connectChain
 }
 
 func (p *MultiHostPool) Close() (err error) {
@@ -78,6 +80,9 @@ func (p *MultiHostPool) Get() redis.Conn {
 			TestOnBorrow: testOnBorrow,
 			MaxIdle:      maxConns,
 		}
+		for _, chain := range p.connect {
+			chain.wrap(pool)
+		}
 		p.pools[host] = pool
 	}
 
@@ -103,3 +108,108 @@ func testOnBorrow(c redis.Conn, t time.Time) (err error) {
 	}
 	return
 }
+
+// connectFunc prepares a new connection before it joins a pool, ctx being the context of the dial
+type connectFunc func(ctx context.Context, conn redis.Conn) error
+
+// connectChain holds the functions preparing the new connections of a client. Those authenticating the
+// connections run first, whatever the order of the options adding them.
+type connectChain struct {
+	mutex   sync.Mutex
+	auth    []connectFunc
+	connect []connectFunc
+	// wrapped are the pools whose dial functions run the chain
+	wrapped map[*redis.Pool]bool
+}
+
+// add adds connect to the chain, before the functions which do not authenticate if auth
+func (c *connectChain) add(connect connectFunc, auth bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if auth {
+		c.auth = append(c.auth, connect)
+	} else {
+		c.connect = append(c.connect, connect)
+	}
+}
+
+// run runs the functions of the chain on conn, stopping at the first error
+func (c *connectChain) run(ctx context.Context, conn redis.Conn) error {
+	c.mutex.Lock()
+	funcs := append(append([]connectFunc(nil), c.auth...), c.connect...)
+	c.mutex.Unlock()
+	for _, connect := range funcs {
+		if err := connect(ctx, conn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dial dials with dial and runs the chain on the connection, closing it if the chain fails
+func (c *connectChain) dial(ctx context.Context, dial func(ctx context.Context) (redis.Conn, error)) (redis.Conn, error) {
+	conn, err := dial(ctx)
+	if err != nil {
+		return conn, err
+	}
+	if err := c.run(ctx, conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// wrap makes the dial functions of pool run the chain, unless they already do
+func (c *connectChain) wrap(pool *redis.Pool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.wrapped[pool] {
+		return
+	}
+	if c.wrapped == nil {
+		c.wrapped = make(map[*redis.Pool]bool)
+	}
+	c.wrapped[pool] = true
+	if dial := pool.Dial; dial != nil {
+		pool.Dial = func() (redis.Conn, error) {
+			return c.dial(context.Background(), func(context.Context) (redis.Conn, error) { return dial() })
+		}
+	}
+	if dialContext := pool.DialContext; dialContext != nil {
+		pool.DialContext = func(ctx context.Context) (redis.Conn, error) {
+			return c.dial(ctx, dialContext)
+		}
+	}
+}
+
+// addConnectChain makes pool run chain on each new connection. It supports the pools of this package,
+// redis.Pool and the pools wrapping them, and returns false for any other pool.
+func addConnectChain(pool ConnPool, chain *connectChain) bool {
+	switch p := pool.(type) {
+	case *routingPool:
+		replicas := addConnectChain(p.replicas, chain)
+		return addConnectChain(p.ConnPool, chain) && replicas
+	case wrappingPool:
+		return addConnectChain(p.unwrap(), chain)
+	case *SingleHostPool:
+		return addConnectChain(p.Pool, chain)
+	case *redis.Pool:
+		chain.wrap(p)
+		return true
+	case *MultiHostPool:
+		p.Lock()
+		defer p.Unlock()
+		known := false
+		for _, added := range p.connect {
+			known = known || added == chain
+		}
+		if !known {
+			p.connect = append(p.connect, chain)
+		}
+		for _, hostPool := range p.pools {
+			chain.wrap(hostPool)
+		}
+		return true
+	}
+	return false
+}
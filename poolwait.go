@@ -0,0 +1,71 @@
+package redis_bloom_go
+
+import (
+	"context"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// ErrPoolExhausted is returned by the commands which got no connection from the pool: all of them were in
+// use and the pool does not wait, or none was returned within the wait allowed by WithPoolWait
+var ErrPoolExhausted = redis.ErrPoolExhausted
+
+// PoolHook is implemented by the hooks which also observe the connection pool
+type PoolHook interface {
+	// AfterGet is called each time a connection is taken from the pool. wait is the time it took, mostly
+	// spent waiting for a connection to be returned when the pool is saturated. err is the error of the
+	// connection, ErrPoolExhausted when none was available.
+	AfterGet(wait time.Duration, err error)
+}
+
+// WithPoolWait makes the commands wait up to maxWait for a connection once the pool is saturated, and then
+// fail with ErrPoolExhausted, rather than queue for as long as it takes. A zero maxWait fails them at once.
+// It sets Wait on the redis.Pool of the client, and must be given before the options wrapping its connections,
+// such as WithHooks or WithRetry. Pools which cannot wait for a connection until a deadline are left as is.
+func WithPoolWait(maxWait time.Duration) ClientOption {
+	return func(client *Client) {
+		var pool *redis.Pool
+		switch p := client.Pool.(type) {
+		case *redis.Pool:
+			pool = p
+		case *SingleHostPool:
+			pool = p.Pool
+		default:
+			return
+		}
+		pool.Wait = maxWait > 0
+		if pool.Wait {
+			client.Pool = &waitPool{ConnPool: client.Pool, maxWait: maxWait}
+		}
+	}
+}
+
+// waitPool bounds the wait for a connection of a pool supporting GetContext
+type waitPool struct {
+	ConnPool
+	maxWait time.Duration
+}
+
+func (p *waitPool) unwrap() ConnPool {
+	return p.ConnPool
+}
+
+func (p *waitPool) Get() redis.Conn {
+	conn, err := p.GetContext(context.Background())
+	if err != nil {
+		return errorConn{err}
+	}
+	return conn
+}
+
+// GetContext waits for a connection until ctx is done or maxWait elapsed, returning ErrPoolExhausted in the latter case
+func (p *waitPool) GetContext(ctx context.Context) (redis.Conn, error) {
+	waitCtx, cancel := context.WithTimeout(ctx, p.maxWait)
+	defer cancel()
+	conn, err := p.ConnPool.(contextPool).GetContext(waitCtx)
+	if err != nil && ctx.Err() == nil && waitCtx.Err() == context.DeadlineExceeded {
+		err = ErrPoolExhausted
+	}
+	return conn, err
+}
@@ -0,0 +1,65 @@
+package redis_bloom_go
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithPoolWait(t *testing.T) {
+	pool := &redis.Pool{MaxActive: 1, Dial: func() (redis.Conn, error) { return &fakeConn{}, nil }}
+	c := NewClientFromPool(pool, "test", WithPoolWait(20*time.Millisecond))
+	assert.True(t, pool.Wait)
+	m := NewMetrics(c)
+
+	held := c.Pool.Get()
+	assert.Nil(t, held.Err())
+	start := time.Now()
+	conn := c.Pool.Get()
+	assert.Equal(t, ErrPoolExhausted, conn.Err())
+	assert.True(t, time.Since(start) >= 20*time.Millisecond)
+	_, err := conn.Do("PING")
+	assert.Equal(t, ErrPoolExhausted, err)
+
+	// a context done first is not reported as an exhausted pool
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = c.Pool.(*hookedPool).ConnPool.(*waitPool).GetContext(ctx)
+	assert.Equal(t, context.Canceled, err)
+
+	held.Close()
+	conn = c.Pool.Get()
+	assert.Nil(t, conn.Err())
+	conn.Close()
+
+	var buf bytes.Buffer
+	_, err = m.WriteTo(&buf)
+	assert.Nil(t, err)
+	out := buf.String()
+	assert.True(t, strings.Contains(out, "redisbloom_pool_get_duration_seconds_count 3\n"))
+	assert.True(t, strings.Contains(out, `redisbloom_pool_get_duration_seconds_bucket{le="0.01"} 2`))
+	assert.True(t, strings.Contains(out, "redisbloom_pool_exhausted_total 1\n"))
+	assert.Equal(t, uint64(1), m.expvarStats().PoolExhausted)
+}
+
+func TestWithPoolWait_FailFast(t *testing.T) {
+	pool := &redis.Pool{MaxActive: 1, Wait: true, Dial: func() (redis.Conn, error) { return &fakeConn{}, nil }}
+	c := NewClientFromPool(pool, "test", WithPoolWait(0))
+	assert.False(t, pool.Wait)
+	assert.Equal(t, pool, c.Pool)
+
+	held := c.Pool.Get()
+	defer held.Close()
+	assert.Equal(t, ErrPoolExhausted, c.Pool.Get().Err())
+
+	// pools which cannot wait until a deadline are left as is
+	multi := NewMultiHostPool([]string{"localhost:6379"}, nil)
+	c.Pool = multi
+	WithPoolWait(time.Second)(c)
+	assert.Equal(t, multi, c.Pool)
+}
@@ -0,0 +1,132 @@
+package redis_bloom_go
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// cmsMissingKeyError is the error RedisBloom replies when a sketch does not exist
+const cmsMissingKeyError = "CMS: key does not exist"
+
+// RateLimiterConfig describes the limit enforced by a RateLimiter
+type RateLimiterConfig struct {
+	// Limit is the number of calls allowed per identifier and window
+	Limit int64
+	// Window is the duration of a window, one second by default
+	Window time.Duration
+	// Sliding weighs in the count of the previous window by its overlap with a window ending now,
+	// instead of resetting the count at the start of each window
+	Sliding bool
+	// Width and Depth are the dimensions of the sketch of each window, 2000 and 5 by default.
+	// Counts are overestimated by at most 2/Width of the total calls of the window and never
+	// underestimated, so sketch collisions can only make the limiter deny calls early.
+	Width int64
+	Depth int64
+}
+
+const (
+	defaultRateLimiterWindow = time.Second
+	defaultRateLimiterWidth  = 2000
+	defaultRateLimiterDepth  = 5
+)
+
+// RateLimiter is an approximate per identifier rate limiter storing the counts of each window
+// in a single count-min sketch, so that its memory does not grow with the number of identifiers
+type RateLimiter struct {
+	client *Client
+	name   string
+	config RateLimiterConfig
+	now    func() time.Time
+}
+
+// NewRateLimiter - Returns a rate limiter storing its sketches under keys name:<window index>
+func NewRateLimiter(client *Client, name string, config RateLimiterConfig) *RateLimiter {
+	if config.Window <= 0 {
+		config.Window = defaultRateLimiterWindow
+	}
+	if config.Width <= 0 {
+		config.Width = defaultRateLimiterWidth
+	}
+	if config.Depth <= 0 {
+		config.Depth = defaultRateLimiterDepth
+	}
+	return &RateLimiter{client: client, name: name, config: config, now: time.Now}
+}
+
+// windowKey returns the key of the sketch of the window with the given index
+func (l *RateLimiter) windowKey(index int64) string {
+	return fmt.Sprintf("%s:%d", l.name, index)
+}
+
+// Allow - Counts a call of id and reports whether it is within the limit. Denied calls are counted as well.
+func (l *RateLimiter) Allow(id string) (bool, error) {
+	return l.AllowN(id, 1)
+}
+
+// AllowN - Counts n calls of id at once and reports whether they are within the limit
+func (l *RateLimiter) AllowN(id string, n int64) (bool, error) {
+	now := l.now()
+	index := now.UnixNano() / int64(l.config.Window)
+	count, err := l.increment(l.windowKey(index), id, n)
+	if err != nil {
+		return false, err
+	}
+	if !l.config.Sliding {
+		return count <= l.config.Limit, nil
+	}
+	previous, err := l.query(l.windowKey(index-1), id)
+	if err != nil {
+		return false, err
+	}
+	elapsed := float64(now.UnixNano()%int64(l.config.Window)) / float64(l.config.Window)
+	weighted := float64(count) + float64(previous)*(1-elapsed)
+	return weighted <= float64(l.config.Limit), nil
+}
+
+// increment adds n to the count of id in the sketch at key, creating the sketch if needed
+func (l *RateLimiter) increment(key string, id string, n int64) (int64, error) {
+	counts, err := l.client.CmsIncrByItems(key, []CmsIncrement{{id, n}})
+	if err != nil && strings.Contains(err.Error(), cmsMissingKeyError) {
+		if err = l.create(key); err != nil {
+			return 0, err
+		}
+		counts, err = l.client.CmsIncrByItems(key, []CmsIncrement{{id, n}})
+	}
+	if err != nil {
+		return 0, err
+	}
+	return counts[0], nil
+}
+
+// create creates the sketch of a window, expiring once no limit decision depends on it any more.
+// Losing the race to create it to another caller is not an error.
+func (l *RateLimiter) create(key string) error {
+	ttl := l.config.Window
+	if l.config.Sliding {
+		ttl *= 2
+	}
+	conn := l.client.Pool.Get()
+	defer conn.Close()
+	_, err := l.client.doWithTTL(conn, key, ttl, "CMS.INITBYDIM", l.client.key(key), l.config.Width, l.config.Depth)
+	if err != nil && strings.Contains(err.Error(), "already exists") {
+		return nil
+	}
+	return err
+}
+
+// query returns the count of id in the sketch at key, 0 if the sketch does not exist
+func (l *RateLimiter) query(key string, id string) (int64, error) {
+	conn := l.client.Pool.Get()
+	defer conn.Close()
+	counts, err := redis.Int64s(conn.Do("CMS.QUERY", l.client.key(key), id))
+	if err != nil {
+		if strings.Contains(err.Error(), cmsMissingKeyError) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return counts[0], nil
+}
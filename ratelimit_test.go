@@ -0,0 +1,58 @@
+package redis_bloom_go
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter(t *testing.T) {
+	client.FlushAll()
+	now := time.Unix(1000, 0)
+	limiter := NewRateLimiter(client, "test_rate_limiter", RateLimiterConfig{Limit: 3, Window: time.Minute})
+	limiter.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		allowed, err := limiter.Allow("user-1")
+		assert.Nil(t, err)
+		assert.True(t, allowed)
+	}
+	allowed, err := limiter.Allow("user-1")
+	assert.Nil(t, err)
+	assert.False(t, allowed)
+	allowed, err = limiter.Allow("user-2")
+	assert.Nil(t, err)
+	assert.True(t, allowed)
+
+	now = now.Add(time.Minute)
+	allowed, err = limiter.Allow("user-1")
+	assert.Nil(t, err)
+	assert.True(t, allowed)
+}
+
+func TestRateLimiter_Sliding(t *testing.T) {
+	client.FlushAll()
+	now := time.Unix(960, 0)
+	limiter := NewRateLimiter(client, "test_rate_limiter", RateLimiterConfig{Limit: 4, Window: time.Minute, Sliding: true})
+	limiter.now = func() time.Time { return now }
+
+	allowed, err := limiter.AllowN("user-1", 4)
+	assert.Nil(t, err)
+	assert.True(t, allowed)
+
+	// a quarter into the next window, 3/4 of the previous count still weighs in
+	now = now.Add(75 * time.Second)
+	allowed, err = limiter.Allow("user-1")
+	assert.Nil(t, err)
+	assert.True(t, allowed)
+	allowed, err = limiter.Allow("user-1")
+	assert.Nil(t, err)
+	assert.False(t, allowed)
+}
+
+func TestNewRateLimiter_Defaults(t *testing.T) {
+	limiter := NewRateLimiter(NewClientFromPool(nil, "test"), "limiter", RateLimiterConfig{Limit: 1})
+	assert.Equal(t, time.Second, limiter.config.Window)
+	assert.Equal(t, int64(defaultRateLimiterWidth), limiter.config.Width)
+}
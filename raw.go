@@ -0,0 +1,47 @@
+package redis_bloom_go
+
+import (
+	"context"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// contextPool is implemented by pools which can wait for a connection until a context is done, e.g. *redis.Pool
+type contextPool interface {
+	GetContext(ctx context.Context) (redis.Conn, error)
+}
+
+// Do - Runs an arbitrary command on a connection of the pool and returns its raw reply, e.g. to call module
+// commands this client does not wrap yet. The connection is returned to the pool before Do returns.
+// ctx bounds the wait for a connection when the pool supports it, and the command through its deadline.
+// The arguments are sent as is: keys must be passed through PrefixedKey to honour the key prefix of the client.
+func (client *Client) Do(ctx context.Context, commandName string, args ...interface{}) (interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var conn redis.Conn
+	if pool, ok := client.Pool.(contextPool); ok {
+		var err error
+		if conn, err = pool.GetContext(ctx); err != nil {
+			return nil, err
+		}
+	} else {
+		conn = client.Pool.Get()
+	}
+	defer conn.Close()
+	if cwc, ok := conn.(redis.ConnWithContext); ok {
+		return cwc.DoContext(ctx, commandName, args...)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		if _, ok := conn.(redis.ConnWithTimeout); ok {
+			return redis.DoWithTimeout(conn, time.Until(deadline), commandName, args...)
+		}
+	}
+	return conn.Do(commandName, args...)
+}
+
+// PrefixedKey - Returns the name under which key is stored on the server, with the key prefix of the client
+func (client *Client) PrefixedKey(key string) string {
+	return client.key(key)
+}
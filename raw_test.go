@@ -0,0 +1,34 @@
+package redis_bloom_go
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_Do_Fake(t *testing.T) {
+	conn := &argsConn{fakeConn: &fakeConn{replies: []interface{}{int64(1)}}}
+	c := NewClientFromPool(nil, "test", WithKeyPrefix("app:"))
+	c.Pool = &fakePool{conn: conn}
+	reply, err := c.Do(context.Background(), "BF.ADD", c.PrefixedKey("bf"), "a")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), reply)
+	assert.Equal(t, [][]interface{}{{"app:bf", "a"}}, conn.args)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = c.Do(ctx, "BF.ADD", "bf", "a")
+	assert.Equal(t, context.Canceled, err)
+	assert.Len(t, conn.args, 1)
+}
+
+func TestClient_Do(t *testing.T) {
+	client.FlushAll()
+	reply, err := client.Do(context.Background(), "BF.RESERVE", client.PrefixedKey("test_do"), "0.01", 100)
+	assert.Nil(t, err)
+	assert.Equal(t, "OK", reply)
+	info, err := client.BfInfoTyped("test_do")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(100), info.Capacity)
+}
@@ -0,0 +1,107 @@
+package redis_bloom_go
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// ErrReadOnly is returned by a read-only client for the commands modifying data, which are not sent
+var ErrReadOnly = errors.New("redisbloom: command rejected by a read-only client")
+
+// readOnlyControlCommands are the commands a read-only client allows besides readOnlyCommands: they manage the
+// connection or wrap other commands, which are checked on their own
+var readOnlyControlCommands = commandSet(
+	"AUTH", "SELECT", "HELLO", "READONLY", "ECHO",
+	"MULTI", "EXEC", "DISCARD", "WATCH", "UNWATCH",
+)
+
+// readOnlySubcommands are the subcommands a read-only client allows of the commands which can also modify the
+// server, e.g. CLIENT KILL, CLUSTER RESET or MODULE UNLOAD. The other subcommands are rejected.
+var readOnlySubcommands = map[string]map[string]bool{
+	"CLIENT":  commandSet("SETNAME", "GETNAME", "SETINFO", "ID", "INFO", "TRACKING"),
+	"CLUSTER": commandSet("SLOTS", "SHARDS", "NODES", "INFO", "MYID", "KEYSLOT"),
+	"MODULE":  commandSet("LIST"),
+	"MEMORY":  commandSet("USAGE", "STATS", "DOCTOR"),
+}
+
+// isAllowedReadOnly reports whether a read-only client may send command with args
+func isAllowedReadOnly(command string, args []interface{}) bool {
+	command = strings.ToUpper(command)
+	if subcommands, ok := readOnlySubcommands[command]; ok {
+		if len(args) == 0 {
+			return false
+		}
+		var subcommand string
+		switch arg := args[0].(type) {
+		case string:
+			subcommand = arg
+		case []byte:
+			subcommand = string(arg)
+		}
+		return subcommands[strings.ToUpper(subcommand)]
+	}
+	return readOnlyCommands[command] || readOnlyControlCommands[command]
+}
+
+// WithReadOnly makes the client reject, with ErrReadOnly and without sending them, the commands modifying
+// data: adding, inserting, incrementing, reserving, deleting, loading chunks, running scripts and raw
+// writes through Do, as well as the server administration commands such as CLIENT KILL or MODULE UNLOAD. It suits analytics and reporting services which must never mutate production data.
+func WithReadOnly() ClientOption {
+	return func(client *Client) {
+		client.Pool = &readOnlyPool{ConnPool: client.Pool}
+	}
+}
+
+// ReadOnly - Returns a view of the client, sharing its connection pool, which rejects the commands modifying
+// data like a client created with WithReadOnly
+func (client *Client) ReadOnly() *Client {
+	view := *client
+	view.Pool = &readOnlyPool{ConnPool: client.Pool}
+	return &view
+}
+
+// readOnlyPool hands out connections rejecting the commands modifying data
+type readOnlyPool struct {
+	ConnPool
+}
+
+func (p *readOnlyPool) unwrap() ConnPool {
+	return p.ConnPool
+}
+
+func (p *readOnlyPool) Get() redis.Conn {
+	return &readOnlyConn{Conn: p.ConnPool.Get()}
+}
+
+// readOnlyConn rejects the commands modifying data before they reach the connection
+type readOnlyConn struct {
+	redis.Conn
+}
+
+func (c *readOnlyConn) Do(command string, args ...interface{}) (interface{}, error) {
+	if command != "" && !isAllowedReadOnly(command, args) {
+		return nil, ErrReadOnly
+	}
+	return c.Conn.Do(command, args...)
+}
+
+func (c *readOnlyConn) DoWithTimeout(timeout time.Duration, command string, args ...interface{}) (interface{}, error) {
+	if command != "" && !isAllowedReadOnly(command, args) {
+		return nil, ErrReadOnly
+	}
+	return redis.DoWithTimeout(c.Conn, timeout, command, args...)
+}
+
+func (c *readOnlyConn) Send(command string, args ...interface{}) error {
+	if !isAllowedReadOnly(command, args) {
+		return ErrReadOnly
+	}
+	return c.Conn.Send(command, args...)
+}
+
+func (c *readOnlyConn) ReceiveWithTimeout(timeout time.Duration) (interface{}, error) {
+	return redis.ReceiveWithTimeout(c.Conn, timeout)
+}
@@ -0,0 +1,88 @@
+package redis_bloom_go
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithReadOnly(t *testing.T) {
+	conn := &fakeConn{replies: []interface{}{int64(1), []interface{}{int64(1), int64(0)}}}
+	c := NewClientFromPool(nil, "test")
+	c.Pool = &fakePool{conn: conn}
+	WithReadOnly()(c)
+
+	_, err := c.Add("bf", "a")
+	assert.Equal(t, ErrReadOnly, err)
+	_, err = c.CmsIncrBy("cms", map[string]int64{"a": 1})
+	assert.Equal(t, ErrReadOnly, err)
+	assert.Equal(t, ErrReadOnly, c.Reserve("bf", 0.01, 1000))
+	_, err = c.CfDel("cf", "a")
+	assert.Equal(t, ErrReadOnly, err)
+	_, err = c.BfLoadChunk("bf", 1, []byte("chunk"))
+	assert.Equal(t, ErrReadOnly, err)
+	assert.Empty(t, conn.commands)
+
+	exists, err := c.Exists("bf", "a")
+	assert.Nil(t, err)
+	assert.True(t, exists)
+	found, err := c.BfExistsMulti("bf", []string{"a", "b"})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1, 0}, found)
+	assert.Equal(t, []string{"BF.EXISTS", "BF.MEXISTS"}, conn.commands)
+}
+
+func TestClient_ReadOnly(t *testing.T) {
+	conn := &fakeConn{replies: []interface{}{int64(1)}}
+	c := NewClientFromPool(nil, "test")
+	c.Pool = &fakePool{conn: conn}
+
+	_, err := c.ReadOnly().Add("bf", "a")
+	assert.Equal(t, ErrReadOnly, err)
+	added, err := c.Add("bf", "a")
+	assert.Nil(t, err)
+	assert.True(t, added)
+	assert.Equal(t, []string{"BF.ADD"}, conn.commands)
+
+	assert.True(t, isAllowedReadOnly("bf.info", []interface{}{"bf"}))
+	assert.True(t, isAllowedReadOnly("SELECT", []interface{}{1}))
+	assert.False(t, isAllowedReadOnly("EVALSHA", nil))
+	assert.False(t, isAllowedReadOnly("DEL", []interface{}{"bf"}))
+}
+
+func TestWithReadOnly_Subcommands(t *testing.T) {
+	conn := &fakeConn{replies: []interface{}{"OK", []interface{}{}, []interface{}{}}}
+	c := NewClientFromPool(nil, "test")
+	c.Pool = &fakePool{conn: conn}
+	WithReadOnly()(c)
+
+	for _, command := range [][]interface{}{
+		{"CLIENT", "KILL", "ID", 42}, {"CLUSTER", "RESET"}, {"cluster", "failover"},
+		{"MODULE", "UNLOAD", "bf"}, {"MEMORY", "PURGE"}, {"CLIENT"},
+	} {
+		_, err := c.Do(context.Background(), command[0].(string), command[1:]...)
+		assert.Equal(t, ErrReadOnly, err, command)
+	}
+	assert.Empty(t, conn.commands)
+
+	_, err := c.Do(context.Background(), "CLIENT", "SETNAME", "reports")
+	assert.Nil(t, err)
+	_, err = c.Do(context.Background(), "cluster", []byte("slots"))
+	assert.Nil(t, err)
+	_, err = c.Do(context.Background(), "MODULE", "LIST")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"CLIENT", "cluster", "MODULE"}, conn.commands)
+}
+
+func TestWithReadOnly_CommandTimeouts(t *testing.T) {
+	conn := &timeoutFakeConn{fakeConn: &fakeConn{replies: []interface{}{[]interface{}{int64(1)}}}}
+	c := NewClientFromPool(nil, "test")
+	c.Pool = &timeoutFakePool{conn: conn}
+	WithReadOnly()(c)
+	WithCommandTimeouts(map[string]time.Duration{"": time.Second})(c)
+	found, err := c.BfExistsManyKeys(map[string][]string{"bf": {"a"}})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string][]bool{"bf": {true}}, found)
+}
@@ -0,0 +1,132 @@
+package redis_bloom_go
+
+// rebuildSuffix is appended to the key of a filter to build its replacement
+const rebuildSuffix = ":rebuild"
+
+// defaultRebuildBatchSize is the number of items sent per CF.INSERT when backfilling a cuckoo filter
+const defaultRebuildBatchSize = 1000
+
+// ItemIterator yields the items to backfill a rebuilt filter with, e.g. the rows of a database cursor
+type ItemIterator interface {
+	// Next advances to the next item, returning false once the items are exhausted or on error
+	Next() bool
+	// Item returns the current item
+	Item() string
+	// Err returns the error that stopped the iteration, if any
+	Err() error
+}
+
+// RebuilderConfig describes the filter a Rebuilder creates
+type RebuilderConfig struct {
+	// Type is the data structure of the filter, DataTypeBloom or DataTypeCuckoo. DataTypeBloom if empty
+	Type DataType
+	// Reserve creates the new, empty filter at key with the updated parameters, e.g. by calling
+	// Reserve or CfReserveWithOptions
+	Reserve func(key string) error
+	// Bulk tunes the backfill of a bloom filter, which is done with BulkLoad
+	Bulk BulkOptions
+	// BatchSize is the number of items sent per CF.INSERT when backfilling a cuckoo filter, 1000 if zero
+	BatchSize int
+}
+
+// Rebuilder replaces a degraded filter, e.g. a scaling bloom filter which added too many sub-filters, with a
+// new filter created with updated parameters. The new filter is created at key:rebuild, backfilled from items
+// supplied by the caller and renamed over the live filter, so that readers switch to it atomically.
+// Bloom and cuckoo filters do not store their items, hence they must come from the source of truth.
+type Rebuilder struct {
+	client *Client
+	key    string
+	config RebuilderConfig
+}
+
+// NewRebuilder - Returns a rebuilder of the filter stored at key
+func NewRebuilder(client *Client, key string, config RebuilderConfig) *Rebuilder {
+	if config.Type == "" {
+		config.Type = DataTypeBloom
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaultRebuildBatchSize
+	}
+	return &Rebuilder{client: client, key: key, config: config}
+}
+
+// Rebuild - Creates the new filter, adds every item received from items until it is closed, then swaps it in
+// place of the live filter. Returns the number of items newly added. On error the new filter is deleted and the
+// live filter is left untouched, and items may not have been drained.
+// Reserve fails if key:rebuild exists, which prevents concurrent rebuilds of the same filter.
+func (r *Rebuilder) Rebuild(items <-chan string) (int64, error) {
+	return r.rebuild(items, func() error { return nil })
+}
+
+// rebuild implements Rebuild. done is called once items are drained, and the new filter is swapped in
+// only if it returns nil.
+func (r *Rebuilder) rebuild(items <-chan string, done func() error) (int64, error) {
+	tmpKey := r.key + rebuildSuffix
+	if err := r.config.Reserve(tmpKey); err != nil {
+		return 0, err
+	}
+	added, err := r.backfill(tmpKey, items)
+	if err == nil {
+		err = done()
+	}
+	if err == nil {
+		err = r.client.RenameFilter(tmpKey, r.key)
+	}
+	if err != nil {
+		r.client.DeleteFilter(tmpKey)
+		return added, err
+	}
+	return added, nil
+}
+
+// RebuildFromIterator - Same as Rebuild, reading the items from it
+func (r *Rebuilder) RebuildFromIterator(it ItemIterator) (int64, error) {
+	items := make(chan string, r.config.BatchSize)
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		defer close(items)
+		for it.Next() {
+			select {
+			case items <- it.Item():
+			case <-stop:
+				return
+			}
+		}
+	}()
+	// items is drained once the backfill succeeds, so the iteration has ended
+	return r.rebuild(items, it.Err)
+}
+
+// backfill adds every item received from items to the filter at key
+func (r *Rebuilder) backfill(key string, items <-chan string) (int64, error) {
+	if r.config.Type == DataTypeBloom {
+		return r.client.BulkLoad(key, items, r.config.Bulk)
+	}
+	var added int64
+	batch := make([]string, 0, r.config.BatchSize)
+	flush := func() error {
+		results, err := r.client.CfInsert(key, 0, true, batch)
+		for _, result := range results {
+			if result == 1 {
+				added++
+			}
+		}
+		batch = batch[:0]
+		return err
+	}
+	for item := range items {
+		batch = append(batch, item)
+		if len(batch) == r.config.BatchSize {
+			if err := flush(); err != nil {
+				return added, err
+			}
+		}
+	}
+	if len(batch) > 0 {
+		if err := flush(); err != nil {
+			return added, err
+		}
+	}
+	return added, nil
+}
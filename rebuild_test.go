@@ -0,0 +1,79 @@
+package redis_bloom_go
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// sliceIterator is an ItemIterator over items, failing with err once they are exhausted
+type sliceIterator struct {
+	items []string
+	item  string
+	err   error
+}
+
+func (it *sliceIterator) Next() bool {
+	if len(it.items) == 0 {
+		return false
+	}
+	it.item, it.items = it.items[0], it.items[1:]
+	return true
+}
+
+func (it *sliceIterator) Item() string { return it.item }
+func (it *sliceIterator) Err() error   { return it.err }
+
+func TestRebuilder_Bloom(t *testing.T) {
+	client.FlushAll()
+	key := "test_rebuild_bloom"
+	client.Reserve(key, 0.1, 10)
+	client.BfAddMulti(key, []string{"stale"})
+
+	r := NewRebuilder(client, key, RebuilderConfig{Reserve: func(key string) error {
+		return client.Reserve(key, 0.001, 1000)
+	}})
+	items := make(chan string, 3)
+	items <- "a"
+	items <- "b"
+	items <- "c"
+	close(items)
+	added, err := r.Rebuild(items)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), added)
+	info, err := client.Info(key)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1000), info["Capacity"])
+	exists, err := client.BfExistsMulti(key, []string{"a", "b", "c", "stale"})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1, 1, 1, 0}, exists)
+	found, err := client.KeyExists(key + rebuildSuffix)
+	assert.Nil(t, err)
+	assert.False(t, found)
+}
+
+func TestRebuilder_CuckooFromIterator(t *testing.T) {
+	client.FlushAll()
+	key := "test_rebuild_cuckoo"
+	client.CfReserveWithOptions(key, 10)
+	r := NewRebuilder(client, key, RebuilderConfig{Type: DataTypeCuckoo, BatchSize: 2, Reserve: func(key string) error {
+		_, err := client.CfReserveWithOptions(key, 1000)
+		return err
+	}})
+
+	// a failed iteration leaves the live filter untouched
+	iterErr := errors.New("cursor failed")
+	_, err := r.RebuildFromIterator(&sliceIterator{items: []string{"a"}, err: iterErr})
+	assert.Equal(t, iterErr, err)
+	info, err := client.CfInfo(key)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), info["Number of items inserted"])
+
+	added, err := r.RebuildFromIterator(&sliceIterator{items: []string{"a", "b", "c"}})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), added)
+	exists, err := client.CfExistsMulti(key, "a", "b", "c")
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1, 1, 1}, exists)
+}
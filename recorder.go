@@ -0,0 +1,181 @@
+package redis_bloom_go
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrRecorderClosed is returned by Flush once a Recorder is closed
+var ErrRecorderClosed = errors.New("redisbloom: recorder is closed")
+
+// tdigestMissingKeyError is the error RedisBloom replies when a t-digest does not exist
+const tdigestMissingKeyError = "T-Digest: key does not exist"
+
+// RecorderConfig tunes how a Recorder stores and flushes its values
+type RecorderConfig struct {
+	// Unit is the duration a value is expressed in once stored in the t-digest, time.Millisecond by default
+	Unit time.Duration
+	// FlushInterval is the longest a recorded value waits before being sent, 100ms by default
+	FlushInterval time.Duration
+	// MaxValues is the number of buffered values that triggers a flush, 1000 by default
+	MaxValues int
+	// OnError, if set, is called from the flushing goroutine when buffered values could not be sent.
+	// Those values are dropped.
+	OnError func(error)
+}
+
+const (
+	defaultRecorderUnit          = time.Millisecond
+	defaultRecorderFlushInterval = 100 * time.Millisecond
+	defaultRecorderMaxValues     = 1000
+)
+
+// Recorder aggregates latencies in the t-digest stored at a key. Durations passed to Record are buffered and
+// sent in the background with a single TDIGEST.ADD per flush, and the t-digest is created on the first flush
+// if needed, so that several processes recording to the same key share their percentiles.
+type Recorder struct {
+	client *Client
+	key    string
+	config RecorderConfig
+
+	mutex  sync.Mutex
+	values []float64
+	closed bool
+
+	full chan struct{}
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewRecorder - Returns a recorder of latencies in the t-digest stored at key, and starts its flushing goroutine
+func NewRecorder(client *Client, key string, config RecorderConfig) *Recorder {
+	if config.Unit <= 0 {
+		config.Unit = defaultRecorderUnit
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = defaultRecorderFlushInterval
+	}
+	if config.MaxValues <= 0 {
+		config.MaxValues = defaultRecorderMaxValues
+	}
+	r := &Recorder{
+		client: client,
+		key:    key,
+		config: config,
+		values: make([]float64, 0, config.MaxValues),
+		full:   make(chan struct{}, 1),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go r.run()
+	client.register(r, func() { r.Close() })
+	return r
+}
+
+// Record - Buffers d, which is sent with the next flush. Durations recorded once the recorder is closed are dropped.
+func (r *Recorder) Record(d time.Duration) {
+	r.RecordValue(float64(d) / float64(r.config.Unit))
+}
+
+// RecordValue - Buffers a value already expressed in the unit of the recorder
+func (r *Recorder) RecordValue(value float64) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.closed {
+		return
+	}
+	r.values = append(r.values, value)
+	if len(r.values) >= r.config.MaxValues {
+		select {
+		case r.full <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Flush - Sends the buffered values now. Returns ErrRecorderClosed once the recorder is closed.
+func (r *Recorder) Flush() error {
+	r.mutex.Lock()
+	if r.closed {
+		r.mutex.Unlock()
+		return ErrRecorderClosed
+	}
+	r.mutex.Unlock()
+	return r.flush()
+}
+
+// Close - Stops the flushing goroutine and sends the values still buffered
+func (r *Recorder) Close() error {
+	r.mutex.Lock()
+	if r.closed {
+		r.mutex.Unlock()
+		<-r.done
+		return nil
+	}
+	r.closed = true
+	r.mutex.Unlock()
+	close(r.stop)
+	<-r.done
+	r.client.unregister(r)
+	return r.flush()
+}
+
+func (r *Recorder) run() {
+	defer close(r.done)
+	ticker := time.NewTicker(r.config.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+		case <-r.full:
+		}
+		if err := r.flush(); err != nil && r.config.OnError != nil {
+			r.config.OnError(err)
+		}
+	}
+}
+
+// flush sends the buffered values, creating the t-digest if it does not exist
+func (r *Recorder) flush() error {
+	r.mutex.Lock()
+	values := r.values
+	if len(values) == 0 {
+		r.mutex.Unlock()
+		return nil
+	}
+	r.values = make([]float64, 0, r.config.MaxValues)
+	r.mutex.Unlock()
+
+	_, err := r.client.TdAddValues(r.key, values...)
+	if err != nil && strings.Contains(err.Error(), tdigestMissingKeyError) {
+		// losing the race to create the t-digest to another recorder is not an error
+		if _, err = r.client.TdCreateDefault(r.key); err != nil && !strings.Contains(err.Error(), "already exists") {
+			return err
+		}
+		_, err = r.client.TdAddValues(r.key, values...)
+	}
+	return err
+}
+
+// Percentiles - Returns, for each of the given percentiles between 0 and 100, e.g. 50 and 99, an estimate of the
+// recorded duration below which that percentage of the recorded durations fall. Values still buffered are not
+// taken into account, call Flush first to include them.
+func (r *Recorder) Percentiles(p ...float64) ([]time.Duration, error) {
+	quantiles := make([]float64, len(p))
+	for i, percentile := range p {
+		quantiles[i] = percentile / 100
+	}
+	values, err := r.client.TdQuantiles(r.key, quantiles...)
+	if err != nil {
+		return nil, err
+	}
+	durations := make([]time.Duration, len(values))
+	for i, value := range values {
+		durations[i] = time.Duration(value * float64(r.config.Unit))
+	}
+	return durations, nil
+}
@@ -0,0 +1,50 @@
+package redis_bloom_go
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecorder(t *testing.T) {
+	client.FlushAll()
+	key := "test_recorder"
+	r := NewRecorder(client, key, RecorderConfig{FlushInterval: time.Hour, MaxValues: 50})
+	for i := 1; i <= 100; i++ {
+		r.Record(time.Duration(i) * time.Millisecond)
+	}
+	assert.Nil(t, r.Flush())
+	percentiles, err := r.Percentiles(0, 100)
+	assert.Nil(t, err)
+	assert.Equal(t, []time.Duration{time.Millisecond, 100 * time.Millisecond}, percentiles)
+
+	r.Record(time.Second)
+	assert.Nil(t, r.Close())
+	max, err := client.TdMax(key)
+	assert.Nil(t, err)
+	assert.Equal(t, 1000.0, max)
+	assert.Equal(t, ErrRecorderClosed, r.Flush())
+	assert.Nil(t, r.Close())
+}
+
+func TestRecorder_OnError(t *testing.T) {
+	client.FlushAll()
+	key := "test_recorder_wrong_type"
+	client.Reserve(key, 0.01, 100)
+	errs := make(chan error, 1)
+	r := NewRecorder(client, key, RecorderConfig{FlushInterval: time.Millisecond, OnError: func(err error) {
+		select {
+		case errs <- err:
+		default:
+		}
+	}})
+	defer r.Close()
+	r.Record(time.Millisecond)
+	select {
+	case err := <-errs:
+		assert.NotNil(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("OnError was not called")
+	}
+}
@@ -0,0 +1,321 @@
+package redis_bloom_go
+
+import (
+	"encoding/binary"
+	"errors"
+	"sync"
+	"time"
+)
+
+// bloomOptForce64 is the option of the BF.SCANDUMP header telling filters hashed with 64 bit MurmurHash
+const bloomOptForce64 = 4
+
+// bloomDumpHeaderSize is the size of the fixed part of the BF.SCANDUMP header:
+// size (uint64) | number of filters (uint32) | options (uint32) | expansion (uint32)
+const bloomDumpHeaderSize = 20
+
+// ErrUnsupportedDump is returned when a BF.SCANDUMP header cannot be decoded in process
+var ErrUnsupportedDump = errors.New("redisbloom: unsupported bloom filter dump")
+
+// localBloom is an in process copy of a scalable bloom filter rebuilt from its BF.SCANDUMP chunks
+type localBloom struct {
+	force64 bool
+	links   []localBloomLink
+}
+
+type localBloomLink struct {
+	bits   uint64
+	hashes uint32
+	n2     uint8
+	data   []byte
+}
+
+// parseBloomDump rebuilds a filter from the header chunk and the bit array chunks that follow it,
+// which hold the bit arrays of the sub-filters one after the other
+func parseBloomDump(header []byte, chunks [][]byte) (*localBloom, error) {
+	if len(header) < bloomDumpHeaderSize {
+		return nil, ErrUnsupportedDump
+	}
+	filters := binary.LittleEndian.Uint32(header[8:12])
+	options := binary.LittleEndian.Uint32(header[12:16])
+	links := header[bloomDumpHeaderSize:]
+	if filters == 0 || len(links)%int(filters) != 0 {
+		return nil, ErrUnsupportedDump
+	}
+	// bytes (uint64) | bits (uint64) | size (uint64) | error (double) | bpe (double) | hashes (uint32) | ... | n2 (uint8)
+	linkSize := len(links) / int(filters)
+	if linkSize < 45 {
+		return nil, ErrUnsupportedDump
+	}
+	var data []byte
+	for _, chunk := range chunks {
+		data = append(data, chunk...)
+	}
+	filter := &localBloom{force64: options&bloomOptForce64 != 0, links: make([]localBloomLink, filters)}
+	for i := range filter.links {
+		link := links[i*linkSize : (i+1)*linkSize]
+		size := binary.LittleEndian.Uint64(link[0:8])
+		if size > uint64(len(data)) {
+			return nil, ErrUnsupportedDump
+		}
+		filter.links[i] = localBloomLink{
+			bits:   binary.LittleEndian.Uint64(link[8:16]),
+			hashes: binary.LittleEndian.Uint32(link[40:44]),
+			n2:     link[linkSize-1],
+			data:   data[:size],
+		}
+		if filter.links[i].bits == 0 || filter.links[i].bits > size*8 {
+			return nil, ErrUnsupportedDump
+		}
+		data = data[size:]
+	}
+	return filter, nil
+}
+
+// exists reports whether item may be in any of the sub-filters
+func (b *localBloom) exists(item []byte) bool {
+	a, h := b.hash(item)
+	for _, link := range b.links {
+		if link.contains(a, h, b.force64) {
+			return true
+		}
+	}
+	return false
+}
+
+// hash returns the two hashes of item RedisBloom derives the bit positions from
+func (b *localBloom) hash(item []byte) (uint64, uint64) {
+	if b.force64 {
+		a := murmurHash64A(item, 0xc6a4a7935bd1e995)
+		return a, murmurHash64A(item, a)
+	}
+	a := murmurHash2(item, 0x9747b28c)
+	return uint64(a), uint64(murmurHash2(item, a))
+}
+
+// positions returns the bits set for an item with hashes a and h
+func (l *localBloomLink) positions(a uint64, h uint64, force64 bool) []uint64 {
+	mod := l.bits
+	if l.n2 > 0 {
+		mod = uint64(1) << l.n2
+	}
+	positions := make([]uint64, l.hashes)
+	for i := range positions {
+		if force64 {
+			positions[i] = (a + uint64(i)*h) % mod
+		} else {
+			positions[i] = uint64((uint32(a) + uint32(i)*uint32(h)) % uint32(mod))
+		}
+	}
+	return positions
+}
+
+func (l *localBloomLink) contains(a uint64, h uint64, force64 bool) bool {
+	for _, x := range l.positions(a, h, force64) {
+		if l.data[x>>3]&(1<<(x%8)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func murmurHash64A(data []byte, seed uint64) uint64 {
+	const m = 0xc6a4a7935bd1e995
+	const r = 47
+	h := seed ^ (uint64(len(data)) * m)
+	for len(data) >= 8 {
+		k := binary.LittleEndian.Uint64(data)
+		k *= m
+		k ^= k >> r
+		k *= m
+		h ^= k
+		h *= m
+		data = data[8:]
+	}
+	switch len(data) {
+	case 7:
+		h ^= uint64(data[6]) << 48
+		fallthrough
+	case 6:
+		h ^= uint64(data[5]) << 40
+		fallthrough
+	case 5:
+		h ^= uint64(data[4]) << 32
+		fallthrough
+	case 4:
+		h ^= uint64(data[3]) << 24
+		fallthrough
+	case 3:
+		h ^= uint64(data[2]) << 16
+		fallthrough
+	case 2:
+		h ^= uint64(data[1]) << 8
+		fallthrough
+	case 1:
+		h ^= uint64(data[0])
+		h *= m
+	}
+	h ^= h >> r
+	h *= m
+	h ^= h >> r
+	return h
+}
+
+func murmurHash2(data []byte, seed uint32) uint32 {
+	const m = 0x5bd1e995
+	const r = 24
+	h := seed ^ uint32(len(data))
+	for len(data) >= 4 {
+		k := binary.LittleEndian.Uint32(data)
+		k *= m
+		k ^= k >> r
+		k *= m
+		h *= m
+		h ^= k
+		data = data[4:]
+	}
+	switch len(data) {
+	case 3:
+		h ^= uint32(data[2]) << 16
+		fallthrough
+	case 2:
+		h ^= uint32(data[1]) << 8
+		fallthrough
+	case 1:
+		h ^= uint32(data[0])
+		h *= m
+	}
+	h ^= h >> 13
+	h *= m
+	h ^= h >> 15
+	return h
+}
+
+// LocalReplica is an in process copy of a bloom filter, pulled with BF.SCANDUMP, which answers
+// Exists without a round trip to the server. Items added after the last sync are only seen once
+// the replica is synced again, so the allowed staleness bounds how long a new item may be reported
+// missing. When the copy is older than that, or could not be synced at all, lookups fall back to the server.
+type LocalReplica struct {
+	client       *Client
+	key          string
+	maxStaleness time.Duration
+	now          func() time.Time
+
+	mutex  sync.RWMutex
+	filter *localBloom
+	synced time.Time
+
+	lifecycle sync.Mutex
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+// NewLocalReplica - Returns a replica of the bloom filter stored at key, answering locally as long as
+// its last successful sync is at most maxStaleness old. The replica is empty until Sync or Start is called.
+func NewLocalReplica(client *Client, key string, maxStaleness time.Duration) *LocalReplica {
+	return &LocalReplica{
+		client:       client,
+		key:          key,
+		maxStaleness: maxStaleness,
+		now:          time.Now,
+	}
+}
+
+// Sync - Pulls the whole filter from the server and replaces the local copy
+func (r *LocalReplica) Sync() error {
+	it := r.client.BfScanDumpIterator(r.key)
+	var header []byte
+	var chunks [][]byte
+	for it.Next() {
+		_, data := it.Chunk()
+		if header == nil {
+			header = data
+			continue
+		}
+		chunks = append(chunks, data)
+	}
+	if err := it.Err(); err != nil {
+		return err
+	}
+	filter, err := parseBloomDump(header, chunks)
+	if err != nil {
+		return err
+	}
+	r.mutex.Lock()
+	r.filter, r.synced = filter, r.now()
+	r.mutex.Unlock()
+	return nil
+}
+
+// SyncedAt - Returns the time of the last successful sync, or the zero time if the replica was never synced
+func (r *LocalReplica) SyncedAt() time.Time {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.synced
+}
+
+// Exists - Determines whether item may exist in the filter, locally if the replica is fresh
+// and on the server otherwise
+func (r *LocalReplica) Exists(item string) (bool, error) {
+	r.mutex.RLock()
+	filter, synced := r.filter, r.synced
+	r.mutex.RUnlock()
+	if filter != nil && r.now().Sub(synced) <= r.maxStaleness {
+		return filter.exists([]byte(item)), nil
+	}
+	return r.client.Exists(r.key, item)
+}
+
+// defaultReplicaSyncInterval is the delay between two syncs of a started LocalReplica when neither the interval
+// given to Start nor half its maximum staleness is positive
+const defaultReplicaSyncInterval = 30 * time.Second
+
+// Start - Syncs the replica every interval in a background goroutine. Calling Start on a started replica does nothing.
+// Sync errors are not reported: the replica keeps serving the previous copy until it becomes stale.
+// A non-positive interval syncs the replica every half of its maximum staleness, or every 30s.
+func (r *LocalReplica) Start(interval time.Duration) {
+	if interval <= 0 {
+		interval = r.maxStaleness / 2
+	}
+	if interval <= 0 {
+		interval = defaultReplicaSyncInterval
+	}
+	r.lifecycle.Lock()
+	defer r.lifecycle.Unlock()
+	if r.stop != nil {
+		return
+	}
+	r.stop = make(chan struct{})
+	r.done = make(chan struct{})
+	go r.run(interval, r.stop, r.done)
+	r.client.register(r, r.Stop)
+}
+
+// Stop - Stops syncing and waits for the syncing goroutine to exit
+func (r *LocalReplica) Stop() {
+	r.lifecycle.Lock()
+	stop, done := r.stop, r.done
+	r.stop, r.done = nil, nil
+	r.lifecycle.Unlock()
+	if stop == nil {
+		return
+	}
+	r.client.unregister(r)
+	close(stop)
+	<-done
+}
+
+func (r *LocalReplica) run(interval time.Duration, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	r.Sync()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			r.Sync()
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package redis_bloom_go
+
+import (
+	"encoding/binary"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testBloomDump builds a single filter BF.SCANDUMP header and bit array
+func testBloomDump(options uint32, bits uint64, hashes uint32, items ...string) ([]byte, []byte) {
+	size := (bits + 7) / 8
+	header := make([]byte, bloomDumpHeaderSize+53)
+	binary.LittleEndian.PutUint32(header[8:12], 1)
+	binary.LittleEndian.PutUint32(header[12:16], options)
+	link := header[bloomDumpHeaderSize:]
+	binary.LittleEndian.PutUint64(link[0:8], size)
+	binary.LittleEndian.PutUint64(link[8:16], bits)
+	binary.LittleEndian.PutUint32(link[40:44], hashes)
+	data := make([]byte, size)
+	filter := &localBloom{force64: options&bloomOptForce64 != 0, links: []localBloomLink{{bits: bits, hashes: hashes, data: data}}}
+	for _, item := range items {
+		a, h := filter.hash([]byte(item))
+		link := filter.links[0]
+		for _, x := range link.positions(a, h, filter.force64) {
+			data[x>>3] |= 1 << (x % 8)
+		}
+	}
+	return header, data
+}
+
+func TestParseBloomDump(t *testing.T) {
+	for _, options := range []uint32{0, bloomOptForce64} {
+		header, data := testBloomDump(options, 10000, 7, "a", "b", "c")
+		filter, err := parseBloomDump(header, [][]byte{data[:500], data[500:]})
+		assert.Nil(t, err)
+		assert.True(t, filter.exists([]byte("a")))
+		assert.True(t, filter.exists([]byte("c")))
+		falsePositives := 0
+		for i := 0; i < 1000; i++ {
+			if filter.exists([]byte(fmt.Sprintf("missing-%d", i))) {
+				falsePositives++
+			}
+		}
+		assert.True(t, falsePositives < 10)
+	}
+
+	_, err := parseBloomDump([]byte{1, 2, 3}, nil)
+	assert.Equal(t, ErrUnsupportedDump, err)
+	header, data := testBloomDump(0, 10000, 7)
+	_, err = parseBloomDump(header, [][]byte{data[:10]})
+	assert.Equal(t, ErrUnsupportedDump, err)
+}
+
+func TestMurmurHash(t *testing.T) {
+	assert.Equal(t, uint32(0), murmurHash2(nil, 0))
+	assert.NotEqual(t, murmurHash2([]byte("a"), 0x9747b28c), murmurHash2([]byte("b"), 0x9747b28c))
+	assert.NotEqual(t, murmurHash64A([]byte("abcdefghi"), 1), murmurHash64A([]byte("abcdefghi"), 2))
+}
+
+func TestLocalReplica(t *testing.T) {
+	client.FlushAll()
+	key := "test_local_replica"
+	assert.Nil(t, client.Reserve(key, 0.001, 1000))
+	for i := 0; i < 100; i++ {
+		client.Add(key, fmt.Sprintf("item-%d", i))
+	}
+
+	replica := NewLocalReplica(client, key, time.Minute)
+	assert.Nil(t, replica.Sync())
+	assert.False(t, replica.SyncedAt().IsZero())
+	for i := 0; i < 100; i++ {
+		exists, err := replica.Exists(fmt.Sprintf("item-%d", i))
+		assert.Nil(t, err)
+		assert.True(t, exists)
+	}
+
+	// a stale replica asks the server
+	client.Add(key, "late")
+	replica.now = func() time.Time { return time.Now().Add(time.Hour) }
+	exists, err := replica.Exists("late")
+	assert.Nil(t, err)
+	assert.True(t, exists)
+}
+
+func TestLocalReplica_StartDefaultInterval(t *testing.T) {
+	c := NewClientFromPool(nil, "test")
+	c.Pool = &fakePool{conn: &fakeConn{}}
+	// a non-positive interval would panic in the syncing goroutine
+	replica := NewLocalReplica(c, "bf", time.Minute)
+	replica.Start(0)
+	replica.Stop()
+	replica = NewLocalReplica(c, "bf", 0)
+	replica.Start(-time.Second)
+	replica.Stop()
+}
@@ -0,0 +1,153 @@
+package redis_bloom_go
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// ReadPreference selects the server read-only commands are sent to
+type ReadPreference int
+
+const (
+	// ReadPrimary sends every command to the primary
+	ReadPrimary ReadPreference = iota
+	// ReadReplicaPreferred sends read-only commands to a replica, and to the primary if the replica cannot be reached
+	ReadReplicaPreferred
+	// ReadReplicaOnly sends read-only commands to a replica only
+	ReadReplicaOnly
+)
+
+// WithReplicas sends the read-only commands of the client, such as BF.EXISTS, CF.COUNT, CMS.QUERY,
+// TOPK.LIST or TDIGEST.QUANTILE, to the connections of replicas according to preference, e.g. replicas
+// of NewMultiHostPool(replicaAddrs, authPass). Writes, pipelines and transactions go to the primary.
+// Replicas may lag behind the primary: a read following a write may not see it.
+func WithReplicas(replicas ConnPool, preference ReadPreference) ClientOption {
+	return func(client *Client) {
+		client.Pool = &routingPool{ConnPool: client.Pool, replicas: replicas, preference: preference}
+	}
+}
+
+// routingPool hands out connections routing read-only commands to replicas
+type routingPool struct {
+	ConnPool
+	replicas   ConnPool
+	preference ReadPreference
+}
+
+func (p *routingPool) unwrap() ConnPool {
+	return p.ConnPool
+}
+
+func (p *routingPool) Get() redis.Conn {
+	return &routingConn{pool: p}
+}
+
+func (p *routingPool) Close() error {
+	err := p.ConnPool.Close()
+	if replicasErr := p.replicas.Close(); err == nil {
+		err = replicasErr
+	}
+	return err
+}
+
+// routingConn borrows a primary and a replica connection as they are needed
+type routingConn struct {
+	pool    *routingPool
+	primary redis.Conn
+	replica redis.Conn
+	pending int
+	inMulti bool
+}
+
+func (c *routingConn) primaryConn() redis.Conn {
+	if c.primary == nil {
+		c.primary = c.pool.ConnPool.Get()
+	}
+	return c.primary
+}
+
+func (c *routingConn) replicaConn() redis.Conn {
+	if c.replica == nil {
+		c.replica = c.pool.replicas.Get()
+	}
+	return c.replica
+}
+
+// toReplica reports whether command is sent to a replica
+func (c *routingConn) toReplica(command string) bool {
+	return c.pool.preference != ReadPrimary && c.pending == 0 && !c.inMulti && isReadOnlyCommand(command)
+}
+
+func (c *routingConn) Do(command string, args ...interface{}) (interface{}, error) {
+	return c.do(command, doCall(command, args))
+}
+
+func (c *routingConn) DoWithTimeout(timeout time.Duration, command string, args ...interface{}) (interface{}, error) {
+	return c.do(command, doWithTimeoutCall(timeout, command, args))
+}
+
+func (c *routingConn) do(command string, call connCall) (interface{}, error) {
+	switch strings.ToUpper(command) {
+	case "MULTI":
+		c.inMulti = true
+	case "EXEC", "DISCARD":
+		c.inMulti = false
+	}
+	if !c.toReplica(command) {
+		c.pending = 0
+		return call(c.primaryConn())
+	}
+	reply, err := call(c.replicaConn())
+	if _, ok := err.(redis.Error); err != nil && !ok && c.pool.preference == ReadReplicaPreferred {
+		return call(c.primaryConn())
+	}
+	return reply, err
+}
+
+func (c *routingConn) Send(command string, args ...interface{}) error {
+	if strings.EqualFold(command, "MULTI") {
+		c.inMulti = true
+	}
+	c.pending++
+	return c.primaryConn().Send(command, args...)
+}
+
+func (c *routingConn) Flush() error {
+	return c.primaryConn().Flush()
+}
+
+func (c *routingConn) Receive() (interface{}, error) {
+	if c.pending > 0 {
+		c.pending--
+	}
+	return c.primaryConn().Receive()
+}
+
+func (c *routingConn) ReceiveWithTimeout(timeout time.Duration) (interface{}, error) {
+	if c.pending > 0 {
+		c.pending--
+	}
+	return redis.ReceiveWithTimeout(c.primaryConn(), timeout)
+}
+
+func (c *routingConn) Err() error {
+	if c.primary != nil {
+		return c.primary.Err()
+	}
+	return nil
+}
+
+func (c *routingConn) Close() error {
+	var err error
+	if c.primary != nil {
+		err = c.primary.Close()
+	}
+	if c.replica != nil {
+		if replicaErr := c.replica.Close(); err == nil {
+			err = replicaErr
+		}
+	}
+	return err
+}
@@ -0,0 +1,60 @@
+package redis_bloom_go
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newRoutingTestClient(primary *fakeConn, replica *fakeConn, preference ReadPreference) *Client {
+	c := NewClientFromPool(nil, "test")
+	c.Pool = &fakePool{conn: primary}
+	WithReplicas(&fakePool{conn: replica}, preference)(c)
+	return c
+}
+
+func TestWithReplicas(t *testing.T) {
+	primary := &fakeConn{replies: []interface{}{int64(1)}}
+	replica := &fakeConn{replies: []interface{}{int64(0), int64(3)}}
+	c := newRoutingTestClient(primary, replica, ReadReplicaOnly)
+	added, err := c.Add("key", "item")
+	assert.Nil(t, err)
+	assert.True(t, added)
+	exists, err := c.Exists("key", "item")
+	assert.Nil(t, err)
+	assert.False(t, exists)
+	count, err := c.CfCount("key", "item")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), count)
+	assert.Equal(t, []string{"BF.ADD"}, primary.commands)
+	assert.Equal(t, []string{"BF.EXISTS", "CF.COUNT"}, replica.commands)
+
+	// the write setting an expiry stays on the primary
+	primary = &fakeConn{replies: []interface{}{int64(1)}}
+	replica = &fakeConn{}
+	c = newRoutingTestClient(primary, replica, ReadReplicaOnly)
+	c.writeTTL = time.Minute
+	_, err = c.Add("key", "item")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"EVALSHA"}, primary.commands)
+	assert.Nil(t, replica.commands)
+}
+
+func TestWithReplicas_Preferred(t *testing.T) {
+	primary := &fakeConn{replies: []interface{}{int64(1)}}
+	replica := &fakeConn{replies: []interface{}{io.EOF}}
+	c := newRoutingTestClient(primary, replica, ReadReplicaPreferred)
+	exists, err := c.Exists("key", "item")
+	assert.Nil(t, err)
+	assert.True(t, exists)
+	assert.Equal(t, []string{"BF.EXISTS"}, primary.commands)
+
+	primary = &fakeConn{replies: []interface{}{int64(1)}}
+	replica = &fakeConn{}
+	c = newRoutingTestClient(primary, replica, ReadPrimary)
+	c.Exists("key", "item")
+	assert.Equal(t, []string{"BF.EXISTS"}, primary.commands)
+	assert.Nil(t, replica.commands)
+}
@@ -0,0 +1,82 @@
+package redis_bloom_go
+
+import (
+	"strconv"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// The parsers below read the array replies of the multi-item commands in a single pass, converting the
+// element types the server actually sends directly. Any other element makes them fall back to the redigo
+// helpers, so that they accept and reject the same replies with the same errors.
+
+// parseInt64s converts an array reply of integers into int64s, like redis.Int64s
+func parseInt64s(reply interface{}, err error) ([]int64, error) {
+	if err != nil {
+		return nil, err
+	}
+	values, ok := reply.([]interface{})
+	if !ok {
+		return redis.Int64s(reply, nil)
+	}
+	result := make([]int64, len(values))
+	for i, value := range values {
+		n, ok := value.(int64)
+		if !ok {
+			return redis.Int64s(reply, nil)
+		}
+		result[i] = n
+	}
+	return result, nil
+}
+
+// parseStrings converts an array reply of bulk strings and nils into strings, nils being empty strings,
+// like redis.Strings
+func parseStrings(reply interface{}, err error) ([]string, error) {
+	if err != nil {
+		return nil, err
+	}
+	values, ok := reply.([]interface{})
+	if !ok {
+		return redis.Strings(reply, nil)
+	}
+	result := make([]string, len(values))
+	for i, value := range values {
+		switch value := value.(type) {
+		case []byte:
+			result[i] = string(value)
+		case nil:
+		default:
+			return redis.Strings(reply, nil)
+		}
+	}
+	return result, nil
+}
+
+// parseStringInt64Pairs converts an array reply alternating names and integers into a map, skipping the
+// values which are not integers
+func parseStringInt64Pairs(reply interface{}, err error) (map[string]int64, error) {
+	values, err := redis.Values(reply, err)
+	if err != nil {
+		return nil, err
+	}
+	if len(values)%2 != 0 {
+		return parseInfoInt64s(values)
+	}
+	result := make(map[string]int64, len(values)/2)
+	for i := 0; i < len(values); i += 2 {
+		name, ok := values[i].([]byte)
+		if !ok {
+			return parseInfoInt64s(values)
+		}
+		switch value := values[i+1].(type) {
+		case int64:
+			result[string(name)] = value
+		case []byte:
+			if n, err := strconv.ParseInt(string(value), 10, 64); err == nil {
+				result[string(name)] = n
+			}
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,120 @@
+package redis_bloom_go
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseInt64s(t *testing.T) {
+	values, err := parseInt64s([]interface{}{int64(1), int64(0)}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1, 0}, values)
+	values, err = parseInt64s([]interface{}{int64(1), []byte("2")}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1, 2}, values)
+	_, err = parseInt64s([]interface{}{int64(1), redis.Error("ERR boom")}, nil)
+	assert.Equal(t, redis.Error("ERR boom"), err)
+	_, err = parseInt64s(nil, errors.New("dial"))
+	assert.EqualError(t, err, "dial")
+	_, err = parseInt64s(nil, nil)
+	assert.Equal(t, redis.ErrNil, err)
+}
+
+func TestParseStrings(t *testing.T) {
+	values, err := parseStrings([]interface{}{[]byte("a"), nil}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a", ""}, values)
+	values, err = parseStrings([]interface{}{[]byte("a"), "b"}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a", "b"}, values)
+	_, err = parseStrings([]interface{}{int64(1)}, nil)
+	assert.NotNil(t, err)
+}
+
+func TestParseStringInt64Pairs(t *testing.T) {
+	values, err := parseStringInt64Pairs([]interface{}{[]byte("a"), int64(3), []byte("b"), []byte("4"), []byte("c"), []byte("x")}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]int64{"a": 3, "b": 4}, values)
+	values, err = parseStringInt64Pairs([]interface{}{"a", int64(3)}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]int64{"a": 3}, values)
+	_, err = parseStringInt64Pairs([]interface{}{[]byte("a")}, nil)
+	assert.NotNil(t, err)
+}
+
+func benchmarkInt64sReply() []interface{} {
+	reply := make([]interface{}, 1000)
+	for i := range reply {
+		reply[i] = int64(i % 2)
+	}
+	return reply
+}
+
+func benchmarkListReply() []interface{} {
+	reply := make([]interface{}, 2000)
+	for i := 0; i < len(reply); i += 2 {
+		reply[i] = []byte("item" + strconv.Itoa(i))
+		reply[i+1] = int64(i)
+	}
+	return reply
+}
+
+func BenchmarkReply_RedisInt64s(b *testing.B) {
+	reply := benchmarkInt64sReply()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		redis.Int64s(reply, nil)
+	}
+}
+
+func BenchmarkReply_ParseInt64s(b *testing.B) {
+	reply := benchmarkInt64sReply()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		parseInt64s(reply, nil)
+	}
+}
+
+func benchmarkStringsReply() []interface{} {
+	reply := make([]interface{}, 1000)
+	for i := 0; i < len(reply); i += 2 {
+		reply[i] = []byte("item" + strconv.Itoa(i))
+	}
+	return reply
+}
+
+func BenchmarkReply_RedisStrings(b *testing.B) {
+	reply := benchmarkStringsReply()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		redis.Strings(reply, nil)
+	}
+}
+
+func BenchmarkReply_ParseStrings(b *testing.B) {
+	reply := benchmarkStringsReply()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		parseStrings(reply, nil)
+	}
+}
+
+func BenchmarkReply_ParseInfoFields(b *testing.B) {
+	reply := benchmarkListReply()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		parseInfoInt64s(reply)
+	}
+}
+
+func BenchmarkReply_ParseStringInt64Pairs(b *testing.B) {
+	reply := benchmarkListReply()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		parseStringInt64Pairs(reply, nil)
+	}
+}
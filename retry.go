@@ -0,0 +1,204 @@
+package redis_bloom_go
+
+import (
+	"math/rand"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// readOnlyCommands are the commands that do not modify data, and can therefore be sent again
+// after a failure whose outcome is unknown
+var readOnlyCommands = commandSet(
+	"BF.EXISTS", "BF.MEXISTS", "BF.INFO", "BF.CARD", "BF.SCANDUMP",
+	"CF.EXISTS", "CF.MEXISTS", "CF.COUNT", "CF.INFO", "CF.SCANDUMP",
+	"CMS.QUERY", "CMS.INFO",
+	"TOPK.QUERY", "TOPK.COUNT", "TOPK.LIST", "TOPK.INFO",
+	"TDIGEST.QUANTILE", "TDIGEST.CDF", "TDIGEST.MIN", "TDIGEST.MAX", "TDIGEST.INFO",
+	"TDIGEST.RANK", "TDIGEST.REVRANK", "TDIGEST.BYRANK", "TDIGEST.BYREVRANK", "TDIGEST.TRIMMED_MEAN",
+	"EXISTS", "TYPE", "TTL", "PTTL", "DUMP", "SCAN", "MEMORY", "MODULE", "INFO", "PING",
+)
+
+func commandSet(commands ...string) map[string]bool {
+	set := make(map[string]bool, len(commands))
+	for _, command := range commands {
+		set[command] = true
+	}
+	return set
+}
+
+// isReadOnlyCommand reports whether command does not modify data
+func isReadOnlyCommand(command string) bool {
+	return readOnlyCommands[strings.ToUpper(command)]
+}
+
+// notExecutedErrorPrefixes are the error replies of a server refusing a command without executing it
+var notExecutedErrorPrefixes = []string{"LOADING", "READONLY", "TRYAGAIN", "MASTERDOWN"}
+
+// RetryPolicy describes which failed commands are sent again, and when
+type RetryPolicy struct {
+	// MaxAttempts is the number of times a command is sent at most, 3 by default
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry, 10ms by default. It doubles on each retry,
+	// up to MaxDelay (1s by default), and is randomized between half and all of its value.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// RetryWrites also retries commands modifying data after connection failures, although they may have
+	// been executed before the connection failed. By default only read-only commands are retried then.
+	// Commands refused by the server (LOADING, READONLY, TRYAGAIN, MASTERDOWN) are always retried.
+	RetryWrites bool
+	// OnRetry, if set, is called before each retry with the attempt that failed, counting from 1
+	OnRetry func(command string, attempt int, err error)
+}
+
+const (
+	defaultRetryMaxAttempts = 3
+	defaultRetryBaseDelay   = 10 * time.Millisecond
+	defaultRetryMaxDelay    = time.Second
+)
+
+// WithRetry retries the commands of the client that fail transiently, following policy.
+// Commands are not retried inside pipelines and transactions.
+func WithRetry(policy RetryPolicy) ClientOption {
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = defaultRetryMaxAttempts
+	}
+	if policy.BaseDelay <= 0 {
+		policy.BaseDelay = defaultRetryBaseDelay
+	}
+	if policy.MaxDelay <= 0 {
+		policy.MaxDelay = defaultRetryMaxDelay
+	}
+	return func(client *Client) {
+		client.Pool = &retryPool{ConnPool: client.Pool, policy: policy}
+	}
+}
+
+// retryable reports whether command may be sent again after failing with err
+func (policy *RetryPolicy) retryable(command string, err error) bool {
+	if err == nil || err == redis.ErrNil {
+		return false
+	}
+	if replyErr, ok := err.(redis.Error); ok {
+		for _, prefix := range notExecutedErrorPrefixes {
+			if strings.HasPrefix(string(replyErr), prefix) {
+				return true
+			}
+		}
+		return false
+	}
+	return policy.RetryWrites || isReadOnlyCommand(command)
+}
+
+// delay returns the randomized delay before the retry following the given attempt
+func (policy *RetryPolicy) delay(attempt int) time.Duration {
+	delay := policy.BaseDelay << uint(attempt-1)
+	if delay > policy.MaxDelay || delay <= 0 {
+		delay = policy.MaxDelay
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// retryPool hands out connections retrying their failed commands on a fresh connection
+type retryPool struct {
+	// retries counts the retries of the connections of the pool, first for 64-bit alignment
+	retries uint64
+	ConnPool
+	policy RetryPolicy
+}
+
+func (p *retryPool) unwrap() ConnPool {
+	return p.ConnPool
+}
+
+func (p *retryPool) Get() redis.Conn {
+	return &retryConn{Conn: p.ConnPool.Get(), pool: p.ConnPool, policy: &p.policy, retries: &p.retries}
+}
+
+type retryConn struct {
+	redis.Conn
+	pool    ConnPool
+	policy  *RetryPolicy
+	retries *uint64
+	pending int
+	inMulti bool
+}
+
+func (c *retryConn) Do(command string, args ...interface{}) (interface{}, error) {
+	return c.do(command, doCall(command, args))
+}
+
+func (c *retryConn) DoWithTimeout(timeout time.Duration, command string, args ...interface{}) (interface{}, error) {
+	return c.do(command, doWithTimeoutCall(timeout, command, args))
+}
+
+func (c *retryConn) do(command string, call connCall) (interface{}, error) {
+	c.track(command)
+	if command == "" || c.pending > 0 || c.inMulti || strings.EqualFold(command, "EXEC") {
+		c.pending = 0
+		return call(c.Conn)
+	}
+	for attempt := 1; ; attempt++ {
+		reply, err := call(c.Conn)
+		if attempt >= c.policy.MaxAttempts || !c.policy.retryable(command, err) {
+			return reply, err
+		}
+		atomic.AddUint64(c.retries, 1)
+		if c.policy.OnRetry != nil {
+			c.policy.OnRetry(command, attempt, err)
+		}
+		time.Sleep(c.policy.delay(attempt))
+		if _, ok := err.(redis.Error); !ok {
+			// the connection is broken, carry on with a new one
+			c.Conn.Close()
+			c.Conn = c.pool.Get()
+		}
+	}
+}
+
+func (c *retryConn) Send(command string, args ...interface{}) error {
+	c.track(command)
+	c.pending++
+	return c.Conn.Send(command, args...)
+}
+
+func (c *retryConn) Receive() (interface{}, error) {
+	if c.pending > 0 {
+		c.pending--
+	}
+	return c.Conn.Receive()
+}
+
+func (c *retryConn) ReceiveWithTimeout(timeout time.Duration) (interface{}, error) {
+	if c.pending > 0 {
+		c.pending--
+	}
+	return redis.ReceiveWithTimeout(c.Conn, timeout)
+}
+
+// track follows whether the connection is inside a MULTI transaction
+func (c *retryConn) track(command string) {
+	switch strings.ToUpper(command) {
+	case "MULTI":
+		c.inMulti = true
+	case "EXEC", "DISCARD":
+		c.inMulti = false
+	}
+}
+
+// poolRetries returns the number of retries of the retry pools pool is or wraps
+func poolRetries(pool ConnPool) uint64 {
+	retries := uint64(0)
+	for {
+		if p, ok := pool.(*retryPool); ok {
+			retries += atomic.LoadUint64(&p.retries)
+		}
+		wrapping, ok := pool.(wrappingPool)
+		if !ok {
+			return retries
+		}
+		pool = wrapping.unwrap()
+	}
+}
@@ -0,0 +1,73 @@
+package redis_bloom_go
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+func newRetryTestClient(conn *fakeConn, policy RetryPolicy) *Client {
+	c := NewClientFromPool(nil, "test")
+	c.Pool = &fakePool{conn: conn}
+	WithRetry(policy)(c)
+	return c
+}
+
+func TestRetryPolicy_retryable(t *testing.T) {
+	policy := RetryPolicy{}
+	assert.True(t, policy.retryable("BF.EXISTS", io.EOF))
+	assert.False(t, policy.retryable("BF.ADD", io.EOF))
+	assert.True(t, policy.retryable("BF.ADD", redis.Error("LOADING Redis is loading the dataset in memory")))
+	assert.True(t, policy.retryable("BF.ADD", redis.Error("READONLY You can't write against a read only replica.")))
+	assert.False(t, policy.retryable("BF.EXISTS", redis.Error("ERR not found")))
+	assert.False(t, policy.retryable("BF.EXISTS", redis.ErrNil))
+	assert.False(t, policy.retryable("BF.EXISTS", nil))
+	policy.RetryWrites = true
+	assert.True(t, policy.retryable("BF.ADD", io.EOF))
+}
+
+func TestRetryPolicy_delay(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 10 * time.Millisecond, MaxDelay: 30 * time.Millisecond}
+	for i := 0; i < 20; i++ {
+		delay := policy.delay(1)
+		assert.True(t, delay >= 5*time.Millisecond && delay <= 10*time.Millisecond)
+		delay = policy.delay(5)
+		assert.True(t, delay >= 15*time.Millisecond && delay <= 30*time.Millisecond)
+	}
+}
+
+func TestWithRetry(t *testing.T) {
+	var retries []int
+	policy := RetryPolicy{BaseDelay: time.Microsecond, OnRetry: func(command string, attempt int, err error) {
+		retries = append(retries, attempt)
+	}}
+
+	conn := &fakeConn{replies: []interface{}{io.EOF, io.EOF, int64(1)}}
+	exists, err := newRetryTestClient(conn, policy).Exists("key", "item")
+	assert.Nil(t, err)
+	assert.True(t, exists)
+	assert.Equal(t, []int{1, 2}, retries)
+
+	conn = &fakeConn{replies: []interface{}{io.EOF, io.EOF, io.EOF, int64(1)}}
+	_, err = newRetryTestClient(conn, policy).Exists("key", "item")
+	assert.Equal(t, io.EOF, err)
+
+	// writes are only retried when the server did not execute them
+	conn = &fakeConn{replies: []interface{}{io.EOF, int64(1)}}
+	_, err = newRetryTestClient(conn, policy).Add("key", "item")
+	assert.Equal(t, io.EOF, err)
+	conn = &fakeConn{replies: []interface{}{redis.Error("LOADING"), int64(1)}}
+	added, err := newRetryTestClient(conn, policy).Add("key", "item")
+	assert.Nil(t, err)
+	assert.True(t, added)
+
+	// transactions are not retried
+	conn = &fakeConn{replies: []interface{}{"OK", "QUEUED", "QUEUED", io.EOF, "OK"}}
+	c := newRetryTestClient(conn, policy)
+	c.writeTTL = time.Minute
+	_, err = c.Add("key", "item")
+	assert.Equal(t, io.EOF, err)
+}
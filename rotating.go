@@ -0,0 +1,121 @@
+package redis_bloom_go
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// ErrInvalidWindow is returned for a sliding window whose bucket duration or number of buckets is not positive
+var ErrInvalidWindow = errors.New("redisbloom: window bucket duration and number of buckets must be positive")
+
+// RotatingBloom is a bloom filter over a sliding time window, made of one bloom filter per time bucket.
+// Items are added to the bucket of the current time, and lookups check every bucket of the window.
+// Each bucket expires once it leaves the window.
+type RotatingBloom struct {
+	client    *Client
+	name      string
+	bucket    time.Duration
+	buckets   int64
+	errorRate float64
+	capacity  int64
+	now       func() time.Time
+}
+
+// NewRotatingBloom - Returns a rotating bloom filter named name, covering a window of buckets buckets of the
+// given duration, e.g. 24 buckets of one hour. Each bucket is a bloom filter created with errorRate and capacity.
+// Returns ErrInvalidWindow if bucket or buckets is not positive.
+func NewRotatingBloom(client *Client, name string, bucket time.Duration, buckets int64, errorRate float64, capacity int64) (*RotatingBloom, error) {
+	if bucket <= 0 || buckets <= 0 {
+		return nil, ErrInvalidWindow
+	}
+	return &RotatingBloom{
+		client:    client,
+		name:      name,
+		bucket:    bucket,
+		buckets:   buckets,
+		errorRate: errorRate,
+		capacity:  capacity,
+		now:       time.Now,
+	}, nil
+}
+
+// bucketKey returns the key of the bucket with the given index
+func (r *RotatingBloom) bucketKey(index int64) string {
+	return fmt.Sprintf("%s:%d", r.name, index)
+}
+
+// bucketTTL returns the time to live at now of the bucket at index current, in a window of buckets buckets of
+// the given duration, rounded up to the millisecond a TTL requires
+func bucketTTL(current int64, bucket time.Duration, buckets int64, now time.Time) time.Duration {
+	// the bucket leaves the window once buckets more buckets have started
+	expireAt := time.Unix(0, (current+buckets)*int64(bucket))
+	ttl := expireAt.Sub(now)
+	if ttl < time.Millisecond {
+		ttl = time.Millisecond
+	}
+	return ttl
+}
+
+// currentIndex returns the index of the bucket of the current time
+func (r *RotatingBloom) currentIndex() (int64, time.Time) {
+	now := r.now()
+	return now.UnixNano() / int64(r.bucket), now
+}
+
+// Keys - Returns the keys of the buckets in the window, from the oldest to the current one
+func (r *RotatingBloom) Keys() []string {
+	current, _ := r.currentIndex()
+	keys := make([]string, 0, r.buckets)
+	for index := current - r.buckets + 1; index <= current; index++ {
+		keys = append(keys, r.bucketKey(index))
+	}
+	return keys
+}
+
+// Add - Adds item to the bucket of the current time, creating the bucket if needed.
+// Returns false if the item may already be in the current bucket.
+func (r *RotatingBloom) Add(item string) (bool, error) {
+	current, now := r.currentIndex()
+	key := r.bucketKey(current)
+	ttl := bucketTTL(current, r.bucket, r.buckets, now)
+	args := redis.Args{r.client.key(key)}.
+		Add("CAPACITY", r.capacity, "ERROR", r.errorRate, "ITEMS", item)
+	conn := r.client.Pool.Get()
+	defer conn.Close()
+	added, err := redis.Int64s(r.client.doWithTTL(conn, key, ttl, "BF.INSERT", args...))
+	if err != nil {
+		return false, err
+	}
+	return added[0] == 1, nil
+}
+
+// Exists - Determines whether item may have been added to any bucket of the window
+func (r *RotatingBloom) Exists(item string) (bool, error) {
+	keys := r.Keys()
+	conn := r.client.Pool.Get()
+	defer conn.Close()
+	for _, key := range keys {
+		if err := conn.Send("BF.EXISTS", r.client.key(key), item); err != nil {
+			return false, err
+		}
+	}
+	if err := conn.Flush(); err != nil {
+		return false, err
+	}
+	exists := false
+	var outErr error
+	for range keys {
+		found, err := redis.Bool(conn.Receive())
+		if err != nil && outErr == nil {
+			outErr = err
+		}
+		exists = exists || found
+	}
+	if outErr != nil {
+		return false, outErr
+	}
+	return exists, nil
+}
@@ -0,0 +1,62 @@
+package redis_bloom_go
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRotatingBloom_Keys(t *testing.T) {
+	r, err := NewRotatingBloom(client, "seen", time.Hour, 3, 0.01, 1000)
+	assert.Nil(t, err)
+	r.now = func() time.Time { return time.Unix(10*3600+5, 0) }
+	assert.Equal(t, []string{"seen:8", "seen:9", "seen:10"}, r.Keys())
+}
+
+func TestNewRotatingBloom_Invalid(t *testing.T) {
+	_, err := NewRotatingBloom(client, "seen", 0, 3, 0.01, 1000)
+	assert.Equal(t, ErrInvalidWindow, err)
+	_, err = NewRotatingBloom(client, "seen", time.Hour, 0, 0.01, 1000)
+	assert.Equal(t, ErrInvalidWindow, err)
+}
+
+func TestRotatingBloom(t *testing.T) {
+	client.FlushAll()
+	now := time.Unix(10*3600+5, 0)
+	r, err := NewRotatingBloom(client, "seen", time.Hour, 3, 0.01, 1000)
+	assert.Nil(t, err)
+	r.now = func() time.Time { return now }
+
+	added, err := r.Add("a")
+	assert.Nil(t, err)
+	assert.True(t, added)
+	added, err = r.Add("a")
+	assert.Nil(t, err)
+	assert.False(t, added)
+	conn := client.Pool.Get()
+	defer conn.Close()
+	ttl, err := redis.Int64(conn.Do("TTL", "seen:10"))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3*3600-5), ttl)
+
+	// two buckets later the item is still in the window
+	now = now.Add(2 * time.Hour)
+	exists, err := r.Exists("a")
+	assert.Nil(t, err)
+	assert.True(t, exists)
+	r.Add("b")
+	exists, err = r.Exists("b")
+	assert.Nil(t, err)
+	assert.True(t, exists)
+
+	// then the first bucket leaves the window
+	now = now.Add(time.Hour)
+	exists, err = r.Exists("a")
+	assert.Nil(t, err)
+	assert.False(t, exists)
+	exists, err = r.Exists("c")
+	assert.Nil(t, err)
+	assert.False(t, exists)
+}
@@ -0,0 +1,202 @@
+package redis_bloom_go
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"gopkg.in/yaml.v3"
+)
+
+// FilterSpec declares a RedisBloom data structure and the parameters it is created with.
+// Only the parameters of Type are used, zero values standing for the server defaults where there is one.
+type FilterSpec struct {
+	Name string   `yaml:"name"`
+	Type DataType `yaml:"type"`
+	// ErrorRate and Capacity are required for bloom filters, Capacity for cuckoo filters
+	ErrorRate float64 `yaml:"error_rate,omitempty"`
+	Capacity  int64   `yaml:"capacity,omitempty"`
+	// Expansion applies to bloom and cuckoo filters, BucketSize and MaxIterations to cuckoo filters
+	Expansion     int64 `yaml:"expansion,omitempty"`
+	BucketSize    int64 `yaml:"bucket_size,omitempty"`
+	MaxIterations int64 `yaml:"max_iterations,omitempty"`
+	// Width and Depth are required for count-min sketches and top-k, K and Decay for top-k
+	Width int64   `yaml:"width,omitempty"`
+	Depth int64   `yaml:"depth,omitempty"`
+	K     int64   `yaml:"k,omitempty"`
+	Decay float64 `yaml:"decay,omitempty"`
+	// Compression applies to t-digests
+	Compression int64 `yaml:"compression,omitempty"`
+	// TTL, if positive, is the time to live set on the key when it is created or found without one,
+	// e.g. "24h" in YAML
+	TTL time.Duration `yaml:"ttl,omitempty"`
+}
+
+// SchemaAction is what SchemaManager.Apply did, or would need to do, for a FilterSpec
+type SchemaAction int
+
+// Actions reported by SchemaManager.Apply
+const (
+	// SchemaUnchanged is reported for a data structure which exists with the declared parameters
+	SchemaUnchanged SchemaAction = iota + 1
+	// SchemaCreated is reported for a data structure which did not exist and was created
+	SchemaCreated
+	// SchemaTTLSet is reported for a data structure which existed without a time to live and was given the declared one
+	SchemaTTLSet
+	// SchemaDrifted is reported for a data structure which exists with different parameters. It is left untouched:
+	// applying the declared parameters requires deleting and recreating it, which loses its content.
+	SchemaDrifted
+	// SchemaFailed is reported when the data structure could not be checked or created
+	SchemaFailed
+)
+
+// SchemaChange is the outcome of SchemaManager.Apply for a FilterSpec
+type SchemaChange struct {
+	Spec   FilterSpec
+	Action SchemaAction
+	// Mismatches are the parameters which differ from the declared ones, for SchemaDrifted
+	Mismatches []ParamMismatch
+	// Err is the error of SchemaFailed
+	Err error
+}
+
+// Destructive reports whether applying the declared parameters requires recreating the data structure
+func (c SchemaChange) Destructive() bool {
+	return c.Action == SchemaDrifted
+}
+
+// schemaFile is the layout of a YAML schema
+type schemaFile struct {
+	Filters []FilterSpec `yaml:"filters"`
+}
+
+// LoadSchema - Reads filter specs from a YAML document listing them under filters, e.g.
+//
+//	filters:
+//	  - name: users:seen
+//	    type: bloom
+//	    error_rate: 0.001
+//	    capacity: 1000000
+//	    ttl: 720h
+func LoadSchema(r io.Reader) ([]FilterSpec, error) {
+	var file schemaFile
+	if err := yaml.NewDecoder(r).Decode(&file); err != nil && err != io.EOF {
+		return nil, err
+	}
+	for i, spec := range file.Filters {
+		if spec.Name == "" {
+			return nil, fmt.Errorf("redisbloom: filter %d of the schema has no name", i)
+		}
+		if _, ok := specCreators[spec.Type]; !ok {
+			return nil, fmt.Errorf("redisbloom: filter %s of the schema has unknown type %q", spec.Name, spec.Type)
+		}
+	}
+	return file.Filters, nil
+}
+
+// specCreators create the data structure of a FilterSpec if it does not exist and check its parameters otherwise
+var specCreators = map[DataType]func(client *Client, spec FilterSpec) error{
+	DataTypeBloom: func(client *Client, spec FilterSpec) error {
+		var opts []CallOption
+		if spec.Expansion > 0 {
+			opts = append(opts, WithExpansion(spec.Expansion))
+		}
+		return client.EnsureBfReserved(spec.Name, spec.ErrorRate, uint64(spec.Capacity), opts...)
+	},
+	DataTypeCuckoo: func(client *Client, spec FilterSpec) error {
+		var opts []CallOption
+		if spec.BucketSize > 0 {
+			opts = append(opts, WithBucketSize(spec.BucketSize))
+		}
+		if spec.MaxIterations > 0 {
+			opts = append(opts, WithMaxIterations(spec.MaxIterations))
+		}
+		if spec.Expansion > 0 {
+			opts = append(opts, WithExpansion(spec.Expansion))
+		}
+		return client.EnsureCfReserved(spec.Name, spec.Capacity, opts...)
+	},
+	DataTypeCMS: func(client *Client, spec FilterSpec) error {
+		return client.EnsureCmsInitByDim(spec.Name, spec.Width, spec.Depth)
+	},
+	DataTypeTopK: func(client *Client, spec FilterSpec) error {
+		return client.EnsureTopkReserved(spec.Name, spec.K, spec.Width, spec.Depth, spec.Decay)
+	},
+	DataTypeTDigest: func(client *Client, spec FilterSpec) error {
+		var opts []CallOption
+		if spec.Compression > 0 {
+			opts = append(opts, WithCompression(spec.Compression))
+		}
+		return client.EnsureTdCreated(spec.Name, opts...)
+	},
+}
+
+// SchemaManager keeps RedisBloom data structures in line with their declarations
+type SchemaManager struct {
+	client *Client
+	specs  []FilterSpec
+}
+
+// NewSchemaManager - Returns a manager of the data structures declared by specs
+func NewSchemaManager(client *Client, specs ...FilterSpec) *SchemaManager {
+	return &SchemaManager{client: client, specs: specs}
+}
+
+// Apply - Creates the declared data structures which do not exist, sets the declared time to live of those which
+// have none, and reports the ones whose parameters drifted from their declaration, which are left untouched.
+// Returns a change per spec, in order. The error is non-nil only if ctx is done before every spec is applied.
+func (m *SchemaManager) Apply(ctx context.Context) ([]SchemaChange, error) {
+	changes := make([]SchemaChange, 0, len(m.specs))
+	for _, spec := range m.specs {
+		if err := ctx.Err(); err != nil {
+			return changes, err
+		}
+		changes = append(changes, m.apply(spec))
+	}
+	return changes, nil
+}
+
+// apply applies a single spec
+func (m *SchemaManager) apply(spec FilterSpec) SchemaChange {
+	change := SchemaChange{Spec: spec}
+	create, ok := specCreators[spec.Type]
+	if !ok {
+		change.Action, change.Err = SchemaFailed, fmt.Errorf("redisbloom: unknown data type %q", spec.Type)
+		return change
+	}
+	existed, err := m.client.KeyExists(spec.Name)
+	if err == nil {
+		err = create(m.client, spec)
+	}
+	if mismatch, ok := err.(*ParamsMismatchError); ok {
+		change.Action, change.Mismatches = SchemaDrifted, mismatch.Mismatches
+		return change
+	}
+	if err != nil {
+		change.Action, change.Err = SchemaFailed, err
+		return change
+	}
+	change.Action = SchemaUnchanged
+	if !existed {
+		change.Action = SchemaCreated
+	}
+	if spec.TTL <= 0 {
+		return change
+	}
+	conn := m.client.Pool.Get()
+	defer conn.Close()
+	// PTTL replies -1 for a key without a time to live
+	ttl, err := redis.Int64(conn.Do("PTTL", m.client.key(spec.Name)))
+	if err == nil && ttl == -1 {
+		_, err = conn.Do("PEXPIRE", m.client.key(spec.Name), int64(spec.TTL/time.Millisecond))
+		if err == nil && existed {
+			change.Action = SchemaTTLSet
+		}
+	}
+	if err != nil {
+		change.Action, change.Err = SchemaFailed, err
+	}
+	return change
+}
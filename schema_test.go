@@ -0,0 +1,67 @@
+package redis_bloom_go
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadSchema(t *testing.T) {
+	specs, err := LoadSchema(strings.NewReader(`
+filters:
+  - name: users:seen
+    type: bloom
+    error_rate: 0.001
+    capacity: 1000000
+    ttl: 720h
+  - name: pages
+    type: topk
+    k: 10
+    width: 80
+    depth: 7
+    decay: 0.9
+`))
+	assert.Nil(t, err)
+	assert.Equal(t, []FilterSpec{
+		{Name: "users:seen", Type: DataTypeBloom, ErrorRate: 0.001, Capacity: 1000000, TTL: 720 * time.Hour},
+		{Name: "pages", Type: DataTypeTopK, K: 10, Width: 80, Depth: 7, Decay: 0.9},
+	}, specs)
+
+	specs, err = LoadSchema(strings.NewReader(""))
+	assert.Nil(t, err)
+	assert.Empty(t, specs)
+	_, err = LoadSchema(strings.NewReader("filters:\n  - name: x\n    type: quotient\n"))
+	assert.NotNil(t, err)
+	_, err = LoadSchema(strings.NewReader("filters:\n  - type: bloom\n"))
+	assert.NotNil(t, err)
+}
+
+func TestSchemaManager_Apply(t *testing.T) {
+	client.FlushAll()
+	specs := []FilterSpec{
+		{Name: "test_schema_bf", Type: DataTypeBloom, ErrorRate: 0.01, Capacity: 1000, TTL: time.Hour},
+		{Name: "test_schema_cms", Type: DataTypeCMS, Width: 100, Depth: 5},
+	}
+	changes, err := NewSchemaManager(client, specs...).Apply(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, SchemaCreated, changes[0].Action)
+	assert.Equal(t, SchemaCreated, changes[1].Action)
+
+	client.PersistFilter("test_schema_bf")
+	specs[1].Depth = 7
+	changes, err = NewSchemaManager(client, specs...).Apply(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, SchemaTTLSet, changes[0].Action)
+	assert.Equal(t, SchemaDrifted, changes[1].Action)
+	assert.True(t, changes[1].Destructive())
+	assert.Equal(t, []ParamMismatch{{Name: "depth", Expected: int64(7), Actual: int64(5)}}, changes[1].Mismatches)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	changes, err = NewSchemaManager(client, specs...).Apply(ctx)
+	assert.Equal(t, context.Canceled, err)
+	assert.Empty(t, changes)
+}
@@ -0,0 +1,136 @@
+package redis_bloom_go
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// The scripts below combine commands atomically. They are run with EVALSHA, falling back to EVAL, which caches
+// them on the server, the first time a server does not know them. Their keys must hash to the same slot in a
+// cluster, e.g. by sharing a hash tag.
+//
+// The scripts writing a filter take the write-through TTL of the client in milliseconds as their last
+// argument, 0 leaving the TTL alone.
+
+// bfAddIncrScript adds ARGV[1] to the bloom filter KEYS[1] and, if it was newly added, increments the counter
+// KEYS[2] by ARGV[2]. Replies whether the item was added and the value of the counter.
+var bfAddIncrScript = redis.NewScript(2, `
+local added = redis.call('BF.ADD', KEYS[1], ARGV[1])
+if tonumber(ARGV[3]) > 0 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[3])
+end
+local count
+if added == 1 then
+	count = redis.call('INCRBY', KEYS[2], ARGV[2])
+else
+	count = tonumber(redis.call('GET', KEYS[2]) or '0')
+end
+return {added, count}
+`)
+
+// cfAddNxExpireScript adds ARGV[1] to the cuckoo filter KEYS[1] unless it may exist, and sets the TTL of the
+// filter to ARGV[2] milliseconds either way. Replies whether the item was added.
+var cfAddNxExpireScript = redis.NewScript(1, `
+local added = redis.call('CF.ADDNX', KEYS[1], ARGV[1])
+redis.call('PEXPIRE', KEYS[1], ARGV[2])
+return added
+`)
+
+// bfAddIfCountScript adds ARGV[1] to the bloom filter KEYS[1] if its count in the count-min sketch KEYS[2]
+// exceeds ARGV[2]. Replies the count and whether the item was added.
+var bfAddIfCountScript = redis.NewScript(2, `
+local count = redis.call('CMS.QUERY', KEYS[2], ARGV[1])[1]
+if count <= tonumber(ARGV[2]) then
+	return {count, 0}
+end
+local added = redis.call('BF.ADD', KEYS[1], ARGV[1])
+if tonumber(ARGV[3]) > 0 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[3])
+end
+return {count, added}
+`)
+
+// expireOnSuccessSource runs the command ARGV[2] with the arguments ARGV[3..] and, if it succeeds, sets the TTL of
+// KEYS[1] to ARGV[1] milliseconds. Replies the reply of the command, or its error as is.
+const expireOnSuccessSource = `
+local reply = redis.pcall(ARGV[2], unpack(ARGV, 3))
+if type(reply) == 'table' and reply.err then
+	return reply
+end
+redis.call('PEXPIRE', KEYS[1], ARGV[1])
+return reply
+`
+
+var expireOnSuccessScript = redis.NewScript(1, expireOnSuccessSource)
+
+// errUnexpectedScriptReply is returned when a script replies something else than it should
+var errUnexpectedScriptReply = errors.New("redisbloom: unexpected script reply")
+
+var scripts = []*redis.Script{bfAddIncrScript, cfAddNxExpireScript, bfAddIfCountScript, expireOnSuccessScript}
+
+// LoadScripts - Loads the scripts of the composite commands on the server, so that their first calls do not
+// need to send them. Loading is optional: the scripts are sent on first use otherwise.
+func (client *Client) LoadScripts() error {
+	conn := client.Pool.Get()
+	defer conn.Close()
+	for _, script := range scripts {
+		if err := script.Load(conn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BfAddIncr - Adds item to the bloom filter stored at key and, if it was newly added, atomically increments the
+// counter stored at counterKey by increment, e.g. to count distinct items. Returns whether the item was added
+// and the value of the counter.
+func (client *Client) BfAddIncr(key string, item string, counterKey string, increment int64) (bool, int64, error) {
+	conn := client.Pool.Get()
+	defer conn.Close()
+	values, err := parseInt64s(bfAddIncrScript.Do(conn, client.key(key), client.key(counterKey),
+		item, increment, client.writeTTLMillis()))
+	if err != nil {
+		return false, 0, err
+	}
+	if len(values) != 2 {
+		return false, 0, errUnexpectedScriptReply
+	}
+	return values[0] == 1, values[1], nil
+}
+
+// CfAddNxExpire - Adds item to the cuckoo filter stored at key unless it may exist, and atomically sets the time
+// to live of the filter to ttl, e.g. to deduplicate events over a sliding window. Returns whether the item was added.
+// Returns ErrInvalidTTL if ttl is shorter than a millisecond.
+func (client *Client) CfAddNxExpire(key string, item string, ttl time.Duration) (bool, error) {
+	// PEXPIRE 0 would delete the filter
+	if ttl < time.Millisecond {
+		return false, ErrInvalidTTL
+	}
+	conn := client.Pool.Get()
+	defer conn.Close()
+	return redis.Bool(cfAddNxExpireScript.Do(conn, client.key(key), item, int64(ttl/time.Millisecond)))
+}
+
+// BfAddIfCountExceeds - Atomically adds item to the bloom filter stored at key if its count in the count-min
+// sketch stored at cmsKey exceeds threshold, e.g. to remember items once they were seen often enough.
+// Returns the count of the item and whether it was added.
+func (client *Client) BfAddIfCountExceeds(key string, item string, cmsKey string, threshold int64) (int64, bool, error) {
+	conn := client.Pool.Get()
+	defer conn.Close()
+	values, err := parseInt64s(bfAddIfCountScript.Do(conn, client.key(key), client.key(cmsKey),
+		item, threshold, client.writeTTLMillis()))
+	if err != nil {
+		return 0, false, err
+	}
+	if len(values) != 2 {
+		return 0, false, errUnexpectedScriptReply
+	}
+	return values[0], values[1] == 1, nil
+}
+
+// writeTTLMillis returns the write-through TTL of the client in milliseconds, 0 if it has none
+func (client *Client) writeTTLMillis() int64 {
+	return int64(client.writeTTL / time.Millisecond)
+}
@@ -0,0 +1,84 @@
+package redis_bloom_go
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScripts_Fallback(t *testing.T) {
+	conn := &argsConn{fakeConn: &fakeConn{replies: []interface{}{
+		redis.Error("NOSCRIPT No matching script. Please use EVAL."),
+		[]interface{}{int64(1), int64(4)},
+		[]interface{}{int64(1)},
+	}}}
+	c := NewClientFromPool(nil, "test", WithKeyPrefix("app:"))
+	c.Pool = &fakePool{conn: conn}
+	added, count, err := c.BfAddIncr("bf", "a", "counter", 2)
+	assert.Nil(t, err)
+	assert.True(t, added)
+	assert.Equal(t, int64(4), count)
+	assert.Equal(t, []string{"EVALSHA", "EVAL"}, conn.commands)
+	assert.Equal(t, []interface{}{"app:bf", "app:counter", "a", int64(2), int64(0)}, conn.args[1][2:])
+
+	_, _, err = c.BfAddIfCountExceeds("bf", "a", "cms", 10)
+	assert.Equal(t, errUnexpectedScriptReply, err)
+}
+
+func TestClient_CfAddNxExpire_InvalidTTL(t *testing.T) {
+	conn := &fakeConn{}
+	c := NewClientFromPool(nil, "test")
+	c.Pool = &fakePool{conn: conn}
+	// PEXPIRE 0 would delete the filter right after adding the item
+	for _, ttl := range []time.Duration{0, time.Microsecond} {
+		_, err := c.CfAddNxExpire("cf", "a", ttl)
+		assert.Equal(t, ErrInvalidTTL, err)
+	}
+	assert.Nil(t, conn.commands)
+}
+
+func TestClient_BfAddIncr(t *testing.T) {
+	client.FlushAll()
+	added, count, err := client.BfAddIncr("test_script_bf", "a", "test_script_counter", 1)
+	assert.Nil(t, err)
+	assert.True(t, added)
+	assert.Equal(t, int64(1), count)
+	added, count, err = client.BfAddIncr("test_script_bf", "a", "test_script_counter", 1)
+	assert.Nil(t, err)
+	assert.False(t, added)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestClient_CfAddNxExpire(t *testing.T) {
+	client.FlushAll()
+	assert.Nil(t, client.LoadScripts())
+	added, err := client.CfAddNxExpire("test_script_cf", "a", time.Minute)
+	assert.Nil(t, err)
+	assert.True(t, added)
+	added, err = client.CfAddNxExpire("test_script_cf", "a", time.Minute)
+	assert.Nil(t, err)
+	assert.False(t, added)
+	conn := client.Pool.Get()
+	defer conn.Close()
+	ttl, err := redis.Int64(conn.Do("PTTL", "test_script_cf"))
+	assert.Nil(t, err)
+	assert.True(t, ttl > 0)
+}
+
+func TestClient_BfAddIfCountExceeds(t *testing.T) {
+	client.FlushAll()
+	_, err := client.CmsInitByDim("test_script_cms", 100, 5)
+	assert.Nil(t, err)
+	_, err = client.CmsIncrBy("test_script_cms", map[string]int64{"a": 3})
+	assert.Nil(t, err)
+	count, added, err := client.BfAddIfCountExceeds("test_script_bf", "a", "test_script_cms", 5)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), count)
+	assert.False(t, added)
+	count, added, err = client.BfAddIfCountExceeds("test_script_bf", "a", "test_script_cms", 2)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), count)
+	assert.True(t, added)
+}
@@ -0,0 +1,125 @@
+package redis_bloom_go
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// ErrInvalidShards is returned by NewShardedBloom when the number of shards is not positive
+var ErrInvalidShards = errors.New("redisbloom: number of shards must be positive")
+
+// ShardedBloom is a bloom filter spread over several keys, each item being routed to one of them by hash.
+// It overcomes the size limit of a single key and, on a cluster, spreads the load across nodes.
+type ShardedBloom struct {
+	client *Client
+	keys   []string
+}
+
+// NewShardedBloom - Returns a bloom filter named name, spread over shards keys. When hashTags is true the
+// keys are wrapped in cluster hash tags ({name:0}, {name:1}...) so that keys derived from a shard key map
+// to the same slot; otherwise they are named name:0, name:1...
+// Returns ErrInvalidShards if shards is not positive.
+func NewShardedBloom(client *Client, name string, shards int, hashTags bool) (*ShardedBloom, error) {
+	if shards <= 0 {
+		return nil, ErrInvalidShards
+	}
+	keys := make([]string, shards)
+	for i := range keys {
+		if hashTags {
+			keys[i] = fmt.Sprintf("{%s:%d}", name, i)
+		} else {
+			keys[i] = fmt.Sprintf("%s:%d", name, i)
+		}
+	}
+	return &ShardedBloom{client: client, keys: keys}, nil
+}
+
+// Keys - Returns the keys of the shards
+func (s *ShardedBloom) Keys() []string {
+	return s.keys
+}
+
+// shard returns the index of the shard item belongs to
+func (s *ShardedBloom) shard(item string) int {
+	h := fnv.New64a()
+	h.Write([]byte(item))
+	return int(h.Sum64() % uint64(len(s.keys)))
+}
+
+// Reserve - Creates every shard as an empty bloom filter with errorRate and capacityPerShard
+func (s *ShardedBloom) Reserve(errorRate float64, capacityPerShard uint64) error {
+	for _, key := range s.keys {
+		if err := s.client.Reserve(key, errorRate, capacityPerShard); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Add - Adds item to its shard. Returns false if the item may already exist.
+func (s *ShardedBloom) Add(item string) (bool, error) {
+	return s.client.Add(s.keys[s.shard(item)], item)
+}
+
+// Exists - Determines whether item may exist in its shard
+func (s *ShardedBloom) Exists(item string) (bool, error) {
+	return s.client.Exists(s.keys[s.shard(item)], item)
+}
+
+// AddMulti - Adds items to their shards, with one pipelined BF.MADD per shard.
+// The results are aligned with items, 1 meaning the item was newly added.
+func (s *ShardedBloom) AddMulti(items []string) ([]int64, error) {
+	return s.multi("BF.MADD", items)
+}
+
+// ExistsMulti - Determines whether items may exist in their shards, with one pipelined BF.MEXISTS per shard.
+// The results are aligned with items, 1 meaning the item may exist.
+func (s *ShardedBloom) ExistsMulti(items []string) ([]int64, error) {
+	return s.multi("BF.MEXISTS", items)
+}
+
+// multi groups items by shard, sends command once per shard and scatters the replies back in the order of items
+func (s *ShardedBloom) multi(command string, items []string) ([]int64, error) {
+	positions := make(map[int][]int)
+	shardItems := make(map[int][]string)
+	order := make([]int, 0)
+	for i, item := range items {
+		shard := s.shard(item)
+		if _, ok := positions[shard]; !ok {
+			order = append(order, shard)
+		}
+		positions[shard] = append(positions[shard], i)
+		shardItems[shard] = append(shardItems[shard], item)
+	}
+	conn := s.client.Pool.Get()
+	defer conn.Close()
+	for _, shard := range order {
+		if err := conn.Send(command, redis.Args{s.client.key(s.keys[shard])}.AddFlat(shardItems[shard])...); err != nil {
+			return nil, err
+		}
+	}
+	if err := conn.Flush(); err != nil {
+		return nil, err
+	}
+	result := make([]int64, len(items))
+	var outErr error
+	for _, shard := range order {
+		values, err := redis.Int64s(conn.Receive())
+		if err != nil {
+			if outErr == nil {
+				outErr = err
+			}
+			continue
+		}
+		for j, position := range positions[shard] {
+			result[position] = values[j]
+		}
+	}
+	if outErr != nil {
+		return nil, outErr
+	}
+	return result, nil
+}
@@ -0,0 +1,45 @@
+package redis_bloom_go
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShardedBloom_Keys(t *testing.T) {
+	s, err := NewShardedBloom(client, "users", 3, false)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"users:0", "users:1", "users:2"}, s.Keys())
+	s, err = NewShardedBloom(client, "users", 2, true)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"{users:0}", "{users:1}"}, s.Keys())
+	assert.Equal(t, s.shard("item"), s.shard("item"))
+
+	for _, shards := range []int{0, -1} {
+		_, err = NewShardedBloom(client, "users", shards, false)
+		assert.Equal(t, ErrInvalidShards, err)
+	}
+}
+
+func TestShardedBloom(t *testing.T) {
+	client.FlushAll()
+	s, err := NewShardedBloom(client, "users", 4, true)
+	assert.Nil(t, err)
+	err = s.Reserve(0.01, 1000)
+	assert.Nil(t, err)
+
+	added, err := s.Add("a")
+	assert.Nil(t, err)
+	assert.True(t, added)
+	exists, err := s.Exists("a")
+	assert.Nil(t, err)
+	assert.True(t, exists)
+
+	items := []string{"a", "b", "c", "d", "e", "f", "g", "h"}
+	rets, err := s.AddMulti(items)
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{0, 1, 1, 1, 1, 1, 1, 1}, rets)
+	rets, err = s.ExistsMulti([]string{"h", "x", "a"})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1, 0, 1}, rets)
+}
@@ -0,0 +1,327 @@
+package redis_bloom_go
+
+import (
+	"errors"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// defaultVirtualNodes is the number of points of each endpoint on the ring of a ShardedClient
+const defaultVirtualNodes = 160
+
+// Hasher hashes a key, or an endpoint name and virtual node number, onto the ring of a ShardedClient
+type Hasher func(key string) uint64
+
+// FNVHasher is the default Hasher of ShardedClient, 64-bit FNV-1a followed by the MurmurHash3 finalizer: FNV-1a
+// alone hashes strings differing in their last bytes, like the points of an endpoint, too closely to spread them
+// evenly on the ring
+func FNVHasher(key string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	x := h.Sum64()
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return x
+}
+
+// ShardedClientOptions configures a ShardedClient
+type ShardedClientOptions struct {
+	// VirtualNodes is the number of points of each endpoint on the ring, 160 by default. More points spread the
+	// keys more evenly across the endpoints.
+	VirtualNodes int
+	// Hasher hashes the keys and the points of the endpoints, FNVHasher by default
+	Hasher Hasher
+}
+
+// ShardedClient spreads keys over several standalone servers with consistent hashing, for scaling RedisBloom
+// horizontally without Redis Cluster. Each key lives on a single server, chosen by hashing the key, or its hash
+// tag if it has one, onto a ring where each server has many virtual nodes: adding or removing a server only
+// moves the keys of its virtual nodes.
+// Multi-key commands need their keys on the same server: make them share a hash tag, e.g. with HashTagKeys,
+// and run them on the client returned by ClientFor.
+type ShardedClient struct {
+	names   []string
+	clients []*Client
+	hasher  Hasher
+	ring    []ringPoint
+}
+
+// ringPoint is a virtual node of the server clients[shard]
+type ringPoint struct {
+	hash  uint64
+	shard int
+}
+
+var _ Commands = (*ShardedClient)(nil)
+
+// NewShardedClient - Returns a client spreading keys over the servers at endpoints, the endpoints being host:port
+// addresses which also name the servers on the ring
+func NewShardedClient(endpoints []string, authPass *string, opts ShardedClientOptions, clientOpts ...ClientOption) (*ShardedClient, error) {
+	clients := make(map[string]*Client, len(endpoints))
+	for _, endpoint := range endpoints {
+		clients[endpoint] = NewClient(endpoint, endpoint, authPass, clientOpts...)
+	}
+	return NewShardedClientFromClients(clients, opts)
+}
+
+// NewShardedClientFromClients - Returns a client spreading keys over clients, which are named by their map keys
+// on the ring. A server must keep its name for its keys to keep mapping to it.
+func NewShardedClientFromClients(clients map[string]*Client, opts ShardedClientOptions) (*ShardedClient, error) {
+	if len(clients) == 0 {
+		return nil, errors.New("redisbloom: a sharded client needs at least one server")
+	}
+	if opts.VirtualNodes <= 0 {
+		opts.VirtualNodes = defaultVirtualNodes
+	}
+	if opts.Hasher == nil {
+		opts.Hasher = FNVHasher
+	}
+	s := &ShardedClient{hasher: opts.Hasher}
+	for name := range clients {
+		s.names = append(s.names, name)
+	}
+	sort.Strings(s.names)
+	s.ring = make([]ringPoint, 0, len(clients)*opts.VirtualNodes)
+	for shard, name := range s.names {
+		s.clients = append(s.clients, clients[name])
+		for i := 0; i < opts.VirtualNodes; i++ {
+			s.ring = append(s.ring, ringPoint{hash: s.hasher(name + "#" + strconv.Itoa(i)), shard: shard})
+		}
+	}
+	sort.Slice(s.ring, func(i, j int) bool {
+		return s.ring[i].hash < s.ring[j].hash
+	})
+	return s, nil
+}
+
+// hashKey returns the hash tag of key if it has one, key otherwise, like KeySlot
+func hashKey(key string) string {
+	if start := strings.IndexByte(key, '{'); start >= 0 {
+		if end := strings.IndexByte(key[start+1:], '}'); end > 0 {
+			return key[start+1 : start+1+end]
+		}
+	}
+	return key
+}
+
+// shard returns the index of the server key maps to: the one of the first point of the ring from the hash of key
+func (s *ShardedClient) shard(key string) int {
+	h := s.hasher(hashKey(key))
+	i := sort.Search(len(s.ring), func(i int) bool {
+		return s.ring[i].hash >= h
+	})
+	if i == len(s.ring) {
+		i = 0
+	}
+	return s.ring[i].shard
+}
+
+// ClientFor - Returns the client of the server key maps to, e.g. to run commands ShardedClient does not wrap
+func (s *ShardedClient) ClientFor(key string) *Client {
+	return s.clients[s.shard(key)]
+}
+
+// EndpointFor - Returns the name of the server key maps to
+func (s *ShardedClient) EndpointFor(key string) string {
+	return s.names[s.shard(key)]
+}
+
+// Clients - Returns the clients of the servers, by name
+func (s *ShardedClient) Clients() map[string]*Client {
+	clients := make(map[string]*Client, len(s.clients))
+	for i, name := range s.names {
+		clients[name] = s.clients[i]
+	}
+	return clients
+}
+
+// Close - Closes the connection pools of every server, returning the first error
+func (s *ShardedClient) Close() error {
+	var outErr error
+	for _, client := range s.clients {
+		if err := client.Pool.Close(); err != nil && outErr == nil {
+			outErr = err
+		}
+	}
+	return outErr
+}
+
+// Reserve - Same as Client.Reserve, run on the server key maps to
+func (s *ShardedClient) Reserve(key string, error_rate float64, capacity uint64) error {
+	return s.ClientFor(key).Reserve(key, error_rate, capacity)
+}
+
+// Add - Same as Client.Add, run on the server key maps to
+func (s *ShardedClient) Add(key string, item string) (bool, error) {
+	return s.ClientFor(key).Add(key, item)
+}
+
+// Exists - Same as Client.Exists, run on the server key maps to
+func (s *ShardedClient) Exists(key string, item string) (bool, error) {
+	return s.ClientFor(key).Exists(key, item)
+}
+
+// BfAddMulti - Same as Client.BfAddMulti, run on the server key maps to
+func (s *ShardedClient) BfAddMulti(key string, items []string) ([]int64, error) {
+	return s.ClientFor(key).BfAddMulti(key, items)
+}
+
+// BfExistsMulti - Same as Client.BfExistsMulti, run on the server key maps to
+func (s *ShardedClient) BfExistsMulti(key string, items []string) ([]int64, error) {
+	return s.ClientFor(key).BfExistsMulti(key, items)
+}
+
+// Info - Same as Client.Info, run on the server key maps to
+func (s *ShardedClient) Info(key string) (map[string]int64, error) {
+	return s.ClientFor(key).Info(key)
+}
+
+// CfReserve - Same as Client.CfReserve, run on the server key maps to
+func (s *ShardedClient) CfReserve(key string, capacity int64, bucketSize int64, maxIterations int64, expansion int64) (string, error) {
+	return s.ClientFor(key).CfReserve(key, capacity, bucketSize, maxIterations, expansion)
+}
+
+// CfAdd - Same as Client.CfAdd, run on the server key maps to
+func (s *ShardedClient) CfAdd(key string, item string) (bool, error) {
+	return s.ClientFor(key).CfAdd(key, item)
+}
+
+// CfAddNx - Same as Client.CfAddNx, run on the server key maps to
+func (s *ShardedClient) CfAddNx(key string, item string) (bool, error) {
+	return s.ClientFor(key).CfAddNx(key, item)
+}
+
+// CfExists - Same as Client.CfExists, run on the server key maps to
+func (s *ShardedClient) CfExists(key string, item string) (bool, error) {
+	return s.ClientFor(key).CfExists(key, item)
+}
+
+// CfExistsMulti - Same as Client.CfExistsMulti, run on the server key maps to
+func (s *ShardedClient) CfExistsMulti(key string, items ...string) ([]int64, error) {
+	return s.ClientFor(key).CfExistsMulti(key, items...)
+}
+
+// CfDel - Same as Client.CfDel, run on the server key maps to
+func (s *ShardedClient) CfDel(key string, item string) (bool, error) {
+	return s.ClientFor(key).CfDel(key, item)
+}
+
+// CfCount - Same as Client.CfCount, run on the server key maps to
+func (s *ShardedClient) CfCount(key string, item string) (int64, error) {
+	return s.ClientFor(key).CfCount(key, item)
+}
+
+// CfInfo - Same as Client.CfInfo, run on the server key maps to
+func (s *ShardedClient) CfInfo(key string) (map[string]int64, error) {
+	return s.ClientFor(key).CfInfo(key)
+}
+
+// CmsInitByDim - Same as Client.CmsInitByDim, run on the server key maps to
+func (s *ShardedClient) CmsInitByDim(key string, width int64, depth int64) (string, error) {
+	return s.ClientFor(key).CmsInitByDim(key, width, depth)
+}
+
+// CmsInitByProb - Same as Client.CmsInitByProb, run on the server key maps to
+func (s *ShardedClient) CmsInitByProb(key string, error float64, probability float64) (string, error) {
+	return s.ClientFor(key).CmsInitByProb(key, error, probability)
+}
+
+// CmsIncrBy - Same as Client.CmsIncrBy, run on the server key maps to
+func (s *ShardedClient) CmsIncrBy(key string, itemIncrements map[string]int64) ([]int64, error) {
+	return s.ClientFor(key).CmsIncrBy(key, itemIncrements)
+}
+
+// CmsIncrByItems - Same as Client.CmsIncrByItems, run on the server key maps to
+func (s *ShardedClient) CmsIncrByItems(key string, increments []CmsIncrement) ([]int64, error) {
+	return s.ClientFor(key).CmsIncrByItems(key, increments)
+}
+
+// CmsQuery - Same as Client.CmsQuery, run on the server key maps to
+func (s *ShardedClient) CmsQuery(key string, items []string) ([]int64, error) {
+	return s.ClientFor(key).CmsQuery(key, items)
+}
+
+// CmsInfo - Same as Client.CmsInfo, run on the server key maps to
+func (s *ShardedClient) CmsInfo(key string) (map[string]int64, error) {
+	return s.ClientFor(key).CmsInfo(key)
+}
+
+// TopkReserve - Same as Client.TopkReserve, run on the server key maps to
+func (s *ShardedClient) TopkReserve(key string, topk int64, width int64, depth int64, decay float64) (string, error) {
+	return s.ClientFor(key).TopkReserve(key, topk, width, depth, decay)
+}
+
+// TopkAdd - Same as Client.TopkAdd, run on the server key maps to
+func (s *ShardedClient) TopkAdd(key string, items []string) ([]string, error) {
+	return s.ClientFor(key).TopkAdd(key, items)
+}
+
+// TopkQuery - Same as Client.TopkQuery, run on the server key maps to
+func (s *ShardedClient) TopkQuery(key string, items []string) ([]int64, error) {
+	return s.ClientFor(key).TopkQuery(key, items)
+}
+
+// TopkCount - Same as Client.TopkCount, run on the server key maps to
+func (s *ShardedClient) TopkCount(key string, items []string) ([]int64, error) {
+	return s.ClientFor(key).TopkCount(key, items)
+}
+
+// TopkList - Same as Client.TopkList, run on the server key maps to
+func (s *ShardedClient) TopkList(key string) ([]string, error) {
+	return s.ClientFor(key).TopkList(key)
+}
+
+// TopkListWithCount - Same as Client.TopkListWithCount, run on the server key maps to
+func (s *ShardedClient) TopkListWithCount(key string) (map[string]int64, error) {
+	return s.ClientFor(key).TopkListWithCount(key)
+}
+
+// TdCreate - Same as Client.TdCreate, run on the server key maps to
+func (s *ShardedClient) TdCreate(key string, compression int64) (string, error) {
+	return s.ClientFor(key).TdCreate(key, compression)
+}
+
+// TdReset - Same as Client.TdReset, run on the server key maps to
+func (s *ShardedClient) TdReset(key string) (string, error) {
+	return s.ClientFor(key).TdReset(key)
+}
+
+// TdAdd - Same as Client.TdAdd, run on the server key maps to
+func (s *ShardedClient) TdAdd(key string, samples map[float64]float64) (string, error) {
+	return s.ClientFor(key).TdAdd(key, samples)
+}
+
+// TdAddValues - Same as Client.TdAddValues, run on the server key maps to
+func (s *ShardedClient) TdAddValues(key string, values ...float64) (string, error) {
+	return s.ClientFor(key).TdAddValues(key, values...)
+}
+
+// TdMin - Same as Client.TdMin, run on the server key maps to
+func (s *ShardedClient) TdMin(key string) (float64, error) {
+	return s.ClientFor(key).TdMin(key)
+}
+
+// TdMax - Same as Client.TdMax, run on the server key maps to
+func (s *ShardedClient) TdMax(key string) (float64, error) {
+	return s.ClientFor(key).TdMax(key)
+}
+
+// TdQuantile - Same as Client.TdQuantile, run on the server key maps to
+func (s *ShardedClient) TdQuantile(key string, quantile float64) (float64, error) {
+	return s.ClientFor(key).TdQuantile(key, quantile)
+}
+
+// TdQuantiles - Same as Client.TdQuantiles, run on the server key maps to
+func (s *ShardedClient) TdQuantiles(key string, quantiles ...float64) ([]float64, error) {
+	return s.ClientFor(key).TdQuantiles(key, quantiles...)
+}
+
+// TdCdf - Same as Client.TdCdf, run on the server key maps to
+func (s *ShardedClient) TdCdf(key string, value float64) (float64, error) {
+	return s.ClientFor(key).TdCdf(key, value)
+}
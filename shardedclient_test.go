@@ -0,0 +1,99 @@
+package redis_bloom_go
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func shardedTestClient(t *testing.T, names []string, opts ShardedClientOptions) *ShardedClient {
+	clients := make(map[string]*Client, len(names))
+	for _, name := range names {
+		clients[name] = NewClientFromPool(nil, name)
+	}
+	s, err := NewShardedClientFromClients(clients, opts)
+	assert.Nil(t, err)
+	return s
+}
+
+func TestNewShardedClientFromClients(t *testing.T) {
+	_, err := NewShardedClientFromClients(nil, ShardedClientOptions{})
+	assert.NotNil(t, err)
+	s := shardedTestClient(t, []string{"a:6379", "b:6379"}, ShardedClientOptions{VirtualNodes: 10})
+	assert.Len(t, s.ring, 20)
+	assert.Len(t, s.Clients(), 2)
+	assert.Equal(t, s.Clients()[s.EndpointFor("key")], s.ClientFor("key"))
+}
+
+func TestShardedClient_Distribution(t *testing.T) {
+	s := shardedTestClient(t, []string{"a:6379", "b:6379", "c:6379"}, ShardedClientOptions{})
+	counts := map[string]int{}
+	for i := 0; i < 30000; i++ {
+		counts[s.EndpointFor("key:"+strconv.Itoa(i))]++
+	}
+	assert.Len(t, counts, 3)
+	for _, count := range counts {
+		assert.InDelta(t, 10000, count, 2000)
+	}
+}
+
+func TestShardedClient_AddServer(t *testing.T) {
+	before := shardedTestClient(t, []string{"a:6379", "b:6379", "c:6379"}, ShardedClientOptions{})
+	after := shardedTestClient(t, []string{"a:6379", "b:6379", "c:6379", "d:6379"}, ShardedClientOptions{})
+	moved := 0
+	for i := 0; i < 10000; i++ {
+		key := "key:" + strconv.Itoa(i)
+		if endpoint := after.EndpointFor(key); endpoint != before.EndpointFor(key) {
+			// keys only move to the new server
+			assert.Equal(t, "d:6379", endpoint)
+			moved++
+		}
+	}
+	assert.InDelta(t, 2500, moved, 800)
+}
+
+func TestShardedClient_HashTag(t *testing.T) {
+	s := shardedTestClient(t, []string{"a:6379", "b:6379", "c:6379"}, ShardedClientOptions{})
+	for i := 0; i < 100; i++ {
+		tag := "user" + strconv.Itoa(i)
+		keys := HashTagKeys(tag, "bf", "cms", "topk")
+		assert.Equal(t, s.EndpointFor(tag), s.EndpointFor(keys[0]))
+		assert.Equal(t, s.EndpointFor(keys[0]), s.EndpointFor(keys[1]))
+		assert.Equal(t, s.EndpointFor(keys[0]), s.EndpointFor(keys[2]))
+	}
+	assert.Equal(t, "key", hashKey("key"))
+	assert.Equal(t, "{}key", hashKey("{}key"))
+	assert.Equal(t, "tag", hashKey("a{tag}b"))
+}
+
+func TestShardedClient_Hasher(t *testing.T) {
+	var hashed []string
+	s := shardedTestClient(t, []string{"a:6379"}, ShardedClientOptions{VirtualNodes: 2, Hasher: func(key string) uint64 {
+		hashed = append(hashed, key)
+		return uint64(len(key))
+	}})
+	assert.Equal(t, []string{"a:6379#0", "a:6379#1"}, hashed)
+	assert.Equal(t, "a:6379", s.EndpointFor("some key longer than the points"))
+}
+
+func TestShardedClient_Commands(t *testing.T) {
+	conns := map[string]*fakeConn{}
+	clients := map[string]*Client{}
+	for _, name := range []string{"a:6379", "b:6379"} {
+		conns[name] = &fakeConn{replies: []interface{}{int64(1)}}
+		clients[name] = NewClientFromPool(nil, name)
+		clients[name].Pool = &fakePool{conn: conns[name]}
+	}
+	s, err := NewShardedClientFromClients(clients, ShardedClientOptions{})
+	assert.Nil(t, err)
+	added, err := s.Add("bf", "item")
+	assert.Nil(t, err)
+	assert.True(t, added)
+	assert.Len(t, conns[s.EndpointFor("bf")].commands, 1)
+	for name, conn := range conns {
+		if name != s.EndpointFor("bf") {
+			assert.Empty(t, conn.commands)
+		}
+	}
+}
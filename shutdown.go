@@ -0,0 +1,104 @@
+package redis_bloom_go
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// shutdownPollInterval is how often Shutdown checks whether the commands in flight are done
+const shutdownPollInterval = 10 * time.Millisecond
+
+// clientLifecycle keeps the background components running on a client and its views, for Shutdown
+type clientLifecycle struct {
+	mutex sync.Mutex
+	// components are the stop functions of the running background components
+	components map[interface{}]func()
+}
+
+func newClientLifecycle() *clientLifecycle {
+	return &clientLifecycle{components: make(map[interface{}]func())}
+}
+
+// register records the stop function of a background component started on the client, for Shutdown
+func (client *Client) register(component interface{}, stop func()) {
+	if client.lifecycle == nil {
+		return
+	}
+	client.lifecycle.mutex.Lock()
+	defer client.lifecycle.mutex.Unlock()
+	client.lifecycle.components[component] = stop
+}
+
+// unregister forgets a background component once it is stopped
+func (client *Client) unregister(component interface{}) {
+	if client.lifecycle == nil {
+		return
+	}
+	client.lifecycle.mutex.Lock()
+	defer client.lifecycle.mutex.Unlock()
+	delete(client.lifecycle.components, component)
+}
+
+// Shutdown - Shuts the client and its views down for a graceful exit. It first stops the background components
+// started on the client, such as batchers, recorders, watchers, monitors, heavy hitters, local replicas and
+// keyspace listeners, letting them send their buffered work. It then closes the connection pool, so that new
+// commands fail while those in flight carry on, and waits for the connections in use to be returned, when the
+// pool reports them like redis.Pool does. Returns ctx.Err() if ctx is done first, after closing the pool anyway.
+func (client *Client) Shutdown(ctx context.Context) error {
+	var stops []func()
+	if client.lifecycle != nil {
+		client.lifecycle.mutex.Lock()
+		for _, stop := range client.lifecycle.components {
+			stops = append(stops, stop)
+		}
+		client.lifecycle.components = make(map[interface{}]func())
+		client.lifecycle.mutex.Unlock()
+	}
+	err := waitContext(ctx, func() {
+		var wg sync.WaitGroup
+		for _, stop := range stops {
+			wg.Add(1)
+			go func(stop func()) {
+				defer wg.Done()
+				stop()
+			}(stop)
+		}
+		wg.Wait()
+	})
+	if closeErr := client.Pool.Close(); closeErr != nil && err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	ticker := time.NewTicker(shutdownPollInterval)
+	defer ticker.Stop()
+	for {
+		// the connections in use are closed as they are returned to the closed pool
+		stats, ok := poolStats(client.Pool)
+		if !ok || stats.ActiveCount == 0 {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// waitContext runs fn, returning once it returns or, with ctx.Err(), once ctx is done
+func waitContext(ctx context.Context, fn func()) error {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn()
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,60 @@
+package redis_bloom_go
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_Shutdown(t *testing.T) {
+	dials := 0
+	pool := &redis.Pool{Dial: func() (redis.Conn, error) {
+		dials++
+		if dials == 1 {
+			return &fakeConn{replies: []interface{}{"PONG"}}, nil
+		}
+		return &fakeConn{replies: []interface{}{[]interface{}{int64(1)}}}, nil
+	}}
+	c := NewClientFromPool(pool, "test")
+	stopped := 0
+	c.register("component", func() { stopped++ })
+	c.register("stopped", func() { stopped++ })
+	c.unregister("stopped")
+	b := NewBatcher(c, BatcherConfig{MaxDelay: time.Hour})
+	added := b.Add("bf", "a")
+
+	held := c.Pool.Get()
+	_, err := held.Do("PING")
+	assert.Nil(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, c.Shutdown(ctx))
+	assert.Equal(t, 1, stopped)
+	// the batcher sent its queued item before the pool was closed
+	ok, err := added.Get()
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Empty(t, c.lifecycle.components)
+
+	_, err = c.Pool.Get().Do("PING")
+	assert.NotNil(t, err)
+	held.Close()
+	assert.Nil(t, c.Shutdown(context.Background()))
+}
+
+func TestClient_Shutdown_Components(t *testing.T) {
+	c := NewClientFromPool(&redis.Pool{}, "test")
+	w := NewWatcher(c, WatcherConfig{Interval: time.Hour})
+	assert.Empty(t, c.lifecycle.components)
+	w.Start()
+	assert.Len(t, c.lifecycle.components, 1)
+	w.Stop()
+	assert.Empty(t, c.lifecycle.components)
+	w.Start()
+	assert.Nil(t, c.Shutdown(context.Background()))
+	assert.Empty(t, c.lifecycle.components)
+	assert.Nil(t, w.stop)
+}
@@ -0,0 +1,80 @@
+//go:build go1.21
+// +build go1.21
+
+package redis_bloom_go
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// LogHookConfig tunes the records of a LogHook
+type LogHookConfig struct {
+	// CommandLevel is the level of the records of successful commands, slog.LevelDebug by default
+	CommandLevel slog.Leveler
+	// ErrorLevel is the level of the records of failed commands, slog.LevelError by default
+	ErrorLevel slog.Leveler
+	// RetryLevel is the level of the records of retries, slog.LevelWarn by default
+	RetryLevel slog.Leveler
+	// Args adds the arguments of the commands to the records, with AUTH credentials redacted
+	Args bool
+}
+
+// LogHook is a Hook logging every command, its latency and its error to a slog.Logger
+type LogHook struct {
+	logger *slog.Logger
+	config LogHookConfig
+}
+
+// NewLogHook - Returns a hook logging commands to logger
+func NewLogHook(logger *slog.Logger, config LogHookConfig) *LogHook {
+	if config.CommandLevel == nil {
+		config.CommandLevel = slog.LevelDebug
+	}
+	if config.ErrorLevel == nil {
+		config.ErrorLevel = slog.LevelError
+	}
+	if config.RetryLevel == nil {
+		config.RetryLevel = slog.LevelWarn
+	}
+	return &LogHook{logger: logger, config: config}
+}
+
+// WithLogger logs the commands of the client to logger, as a LogHook with config does
+func WithLogger(logger *slog.Logger, config LogHookConfig) ClientOption {
+	return WithHooks(NewLogHook(logger, config))
+}
+
+// BeforeCommand implements Hook
+func (h *LogHook) BeforeCommand(command string, args []interface{}) error {
+	return nil
+}
+
+// AfterCommand implements Hook
+func (h *LogHook) AfterCommand(command string, args []interface{}, duration time.Duration, err error) {
+	level := h.config.CommandLevel.Level()
+	message := "redisbloom command"
+	if err != nil {
+		level = h.config.ErrorLevel.Level()
+		message = "redisbloom command failed"
+	}
+	ctx := context.Background()
+	if !h.logger.Enabled(ctx, level) {
+		return
+	}
+	attrs := []slog.Attr{slog.String("command", command), slog.Duration("duration", duration)}
+	if h.config.Args {
+		attrs = append(attrs, slog.Any("args", redactArgs(command, args)))
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	h.logger.LogAttrs(ctx, level, message, attrs...)
+}
+
+// Retry - Logs the retry of command after the given failed attempt. It can be used as RetryPolicy.OnRetry.
+func (h *LogHook) Retry(command string, attempt int, err error) {
+	h.logger.LogAttrs(context.Background(), h.config.RetryLevel.Level(), "redisbloom command retried",
+		slog.String("command", command), slog.Int("attempt", attempt), slog.String("error", err.Error()))
+}
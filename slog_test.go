@@ -0,0 +1,37 @@
+//go:build go1.21
+// +build go1.21
+
+package redis_bloom_go
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogHook(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	hook := NewLogHook(logger, LogHookConfig{Args: true})
+
+	hook.AfterCommand("BF.ADD", []interface{}{"key", "item"}, time.Millisecond, nil)
+	assert.Equal(t, "", buf.String())
+
+	hook.AfterCommand("AUTH", []interface{}{"secret"}, time.Millisecond, errors.New("WRONGPASS"))
+	out := buf.String()
+	assert.True(t, strings.Contains(out, "level=ERROR"))
+	assert.True(t, strings.Contains(out, "command=AUTH"))
+	assert.True(t, strings.Contains(out, "error=WRONGPASS"))
+	assert.False(t, strings.Contains(out, "secret"))
+
+	buf.Reset()
+	hook = NewLogHook(logger, LogHookConfig{CommandLevel: slog.LevelInfo})
+	hook.AfterCommand("BF.ADD", []interface{}{"key", "item"}, time.Millisecond, nil)
+	assert.True(t, strings.Contains(buf.String(), "command=BF.ADD"))
+	assert.False(t, strings.Contains(buf.String(), "args="))
+}
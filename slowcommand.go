@@ -0,0 +1,148 @@
+package redis_bloom_go
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// redactedArg replaces the credentials in logged arguments
+	redactedArg = "[REDACTED]"
+	// defaultSlowCommandMaxArgs is the number of arguments of a SlowCommand kept when none is configured
+	defaultSlowCommandMaxArgs = 16
+	// defaultSlowCommandMaxArgLength is the length of the arguments of a SlowCommand kept when none is configured
+	defaultSlowCommandMaxArgLength = 64
+)
+
+// SlowCommand is a command which took longer than its threshold
+type SlowCommand struct {
+	Command string
+	// Args are the arguments of the command, with credentials redacted and truncated to the configured limits
+	Args     []interface{}
+	Duration time.Duration
+	// Err is the error returned to the caller, if any
+	Err error
+}
+
+// SlowCommandConfig configures a SlowCommandHook
+type SlowCommandConfig struct {
+	// Threshold is the duration above which a command is slow, unless Thresholds has one for it.
+	// Zero only reports the commands listed in Thresholds.
+	Threshold time.Duration
+	// Thresholds overrides Threshold by command name, e.g. "CF.MEXISTS", or by command family, e.g. "bf", "cf",
+	// "topk" or "redis" for the core commands. Names are not case sensitive, and commands take precedence
+	// over their family. A zero threshold turns the reports off.
+	Thresholds map[string]time.Duration
+	// MaxArgs is the number of arguments reported, 16 by default. The others are replaced by a single
+	// "... N more" argument.
+	MaxArgs int
+	// MaxArgLength is the number of bytes of the string arguments reported, 64 by default
+	MaxArgLength int
+	// OnSlowCommand is called, from the goroutine of the command, for each slow command
+	OnSlowCommand func(SlowCommand)
+}
+
+// SlowCommandHook is a Hook reporting the commands exceeding a duration threshold, such as pathological
+// MEXISTS batches or giant merges
+type SlowCommandHook struct {
+	config     SlowCommandConfig
+	thresholds map[string]time.Duration
+}
+
+// NewSlowCommandHook - Returns a hook calling config.OnSlowCommand for the commands slower than their threshold
+func NewSlowCommandHook(config SlowCommandConfig) *SlowCommandHook {
+	if config.MaxArgs <= 0 {
+		config.MaxArgs = defaultSlowCommandMaxArgs
+	}
+	if config.MaxArgLength <= 0 {
+		config.MaxArgLength = defaultSlowCommandMaxArgLength
+	}
+	thresholds := make(map[string]time.Duration, len(config.Thresholds))
+	for name, threshold := range config.Thresholds {
+		thresholds[strings.ToLower(name)] = threshold
+	}
+	return &SlowCommandHook{config: config, thresholds: thresholds}
+}
+
+// WithSlowCommands reports the slow commands of the client, as a SlowCommandHook with config does
+func WithSlowCommands(config SlowCommandConfig) ClientOption {
+	return WithHooks(NewSlowCommandHook(config))
+}
+
+// threshold returns the threshold of command, zero if it is not watched
+func (h *SlowCommandHook) threshold(command string) time.Duration {
+	if threshold, ok := h.thresholds[strings.ToLower(command)]; ok {
+		return threshold
+	}
+	if threshold, ok := h.thresholds[commandFamily(command)]; ok {
+		return threshold
+	}
+	return h.config.Threshold
+}
+
+// BeforeCommand implements Hook
+func (h *SlowCommandHook) BeforeCommand(command string, args []interface{}) error {
+	return nil
+}
+
+// AfterCommand implements Hook
+func (h *SlowCommandHook) AfterCommand(command string, args []interface{}, duration time.Duration, err error) {
+	threshold := h.threshold(command)
+	if threshold <= 0 || duration < threshold || h.config.OnSlowCommand == nil {
+		return
+	}
+	h.config.OnSlowCommand(SlowCommand{
+		Command:  command,
+		Args:     truncateArgs(redactArgs(command, args), h.config.MaxArgs, h.config.MaxArgLength),
+		Duration: duration,
+		Err:      err,
+	})
+}
+
+// truncateArgs shortens args in place to maxArgs arguments, and its strings to maxLength bytes
+func truncateArgs(args []interface{}, maxArgs int, maxLength int) []interface{} {
+	more := 0
+	if len(args) > maxArgs {
+		more = len(args) - maxArgs
+		args = args[:maxArgs]
+	}
+	for i, arg := range args {
+		switch arg := arg.(type) {
+		case string:
+			if len(arg) > maxLength {
+				args[i] = arg[:maxLength] + "..."
+			}
+		case []byte:
+			if len(arg) > maxLength {
+				args[i] = string(arg[:maxLength]) + "..."
+			}
+		}
+	}
+	if more > 0 {
+		args = append(args, fmt.Sprintf("... %d more", more))
+	}
+	return args
+}
+
+// redactArgs returns a copy of args with the credentials of AUTH and HELLO ... AUTH replaced
+func redactArgs(command string, args []interface{}) []interface{} {
+	switch strings.ToUpper(command) {
+	case "AUTH":
+		redacted := make([]interface{}, len(args))
+		for i := range redacted {
+			redacted[i] = redactedArg
+		}
+		return redacted
+	case "HELLO":
+		redacted := append([]interface{}(nil), args...)
+		for i, arg := range redacted {
+			if s, ok := arg.(string); ok && strings.EqualFold(s, "AUTH") && i+2 < len(redacted) {
+				redacted[i+1], redacted[i+2] = redactedArg, redactedArg
+			}
+		}
+		return redacted
+	}
+	// copied, as handlers may keep the record after AfterCommand returns
+	return append([]interface{}(nil), args...)
+}
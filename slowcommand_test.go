@@ -0,0 +1,53 @@
+package redis_bloom_go
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactArgs(t *testing.T) {
+	assert.Equal(t, []interface{}{redactedArg, redactedArg}, redactArgs("auth", []interface{}{"user", "secret"}))
+	assert.Equal(t, []interface{}{"3", "AUTH", redactedArg, redactedArg, "SETNAME", "x"},
+		redactArgs("HELLO", []interface{}{"3", "AUTH", "user", "secret", "SETNAME", "x"}))
+	args := []interface{}{"key", "item"}
+	assert.Equal(t, args, redactArgs("BF.ADD", args))
+}
+
+func TestTruncateArgs(t *testing.T) {
+	args := []interface{}{"key", strings.Repeat("a", 10), []byte("bytes"), 3, "b", "c"}
+	assert.Equal(t, []interface{}{"key", "aaaa...", "byte...", 3, "... 2 more"}, truncateArgs(args, 4, 4))
+	assert.Equal(t, []interface{}{"key"}, truncateArgs([]interface{}{"key"}, 4, 4))
+}
+
+func TestSlowCommandHook(t *testing.T) {
+	var slow []SlowCommand
+	hook := NewSlowCommandHook(SlowCommandConfig{
+		Threshold:     100 * time.Millisecond,
+		Thresholds:    map[string]time.Duration{"CF": time.Second, "cf.mexists": 10 * time.Millisecond, "ping": 0},
+		MaxArgs:       2,
+		OnSlowCommand: func(command SlowCommand) { slow = append(slow, command) },
+	})
+	assert.Equal(t, 100*time.Millisecond, hook.threshold("BF.ADD"))
+	assert.Equal(t, time.Second, hook.threshold("CF.ADD"))
+	assert.Equal(t, 10*time.Millisecond, hook.threshold("CF.MEXISTS"))
+
+	args := []interface{}{"key", "a", "b", "c"}
+	hook.AfterCommand("BF.MADD", args, 50*time.Millisecond, nil)
+	hook.AfterCommand("CF.ADD", args, 500*time.Millisecond, nil)
+	hook.AfterCommand("PING", nil, time.Hour, nil)
+	assert.Empty(t, slow)
+
+	failed := errors.New("timeout")
+	hook.AfterCommand("CF.MEXISTS", args, 20*time.Millisecond, failed)
+	hook.AfterCommand("AUTH", []interface{}{"secret"}, time.Second, nil)
+	assert.Equal(t, []SlowCommand{
+		{Command: "CF.MEXISTS", Args: []interface{}{"key", "a", "... 2 more"}, Duration: 20 * time.Millisecond, Err: failed},
+		{Command: "AUTH", Args: []interface{}{redactedArg}, Duration: time.Second},
+	}, slow)
+	// the arguments of the command are left untouched
+	assert.Equal(t, []interface{}{"key", "a", "b", "c"}, args)
+}
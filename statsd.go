@@ -0,0 +1,159 @@
+package redis_bloom_go
+
+import (
+	"io"
+	"math/rand"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultStatsdPrefix is the prefix of the metric names of a StatsdExporter when none is configured
+const defaultStatsdPrefix = "redisbloom."
+
+// StatsdConfig configures a StatsdExporter
+type StatsdConfig struct {
+	// Address is the host:port of the StatsD or DogStatsD agent, reached over UDP. Ignored if Writer is set.
+	Address string
+	// Writer, if set, receives the metrics instead of Address, a packet per write
+	Writer io.Writer
+	// Prefix is prepended to the metric names, "redisbloom." by default
+	Prefix string
+	// SampleRate is the fraction of the commands reported, in (0, 1], 1 by default. The rate is sent along
+	// with the metrics so that the agent scales them back.
+	SampleRate float64
+	// Tags are added to every metric, e.g. "env:prod", in the DogStatsD format. The command, its family,
+	// e.g. "bf", and the key prefix of the client are always tagged.
+	Tags []string
+	// NoTags leaves the tags out, for StatsD agents which do not support them
+	NoTags bool
+}
+
+// StatsdExporter is a Hook sending the latency of each command as a timer, and its errors as a counter, to a
+// StatsD or DogStatsD agent: <prefix>command.duration and <prefix>command.errors, tagged with the command,
+// its family and the key prefix of the client. As a PoolHook, it also sends <prefix>pool.wait and
+// <prefix>pool.exhausted.
+type StatsdExporter struct {
+	config StatsdConfig
+	tags   string
+	conn   net.Conn
+
+	mutex sync.Mutex
+	w     io.Writer
+	buf   []byte
+}
+
+// NewStatsdExporter - Returns an exporter registered as a hook of client, sending its metrics to the agent
+// at config.Address, or to config.Writer
+func NewStatsdExporter(client *Client, config StatsdConfig) (*StatsdExporter, error) {
+	if config.Prefix == "" {
+		config.Prefix = defaultStatsdPrefix
+	}
+	if config.SampleRate <= 0 || config.SampleRate > 1 {
+		config.SampleRate = 1
+	}
+	e := &StatsdExporter{config: config, w: config.Writer}
+	if e.w == nil {
+		conn, err := net.Dial("udp", config.Address)
+		if err != nil {
+			return nil, err
+		}
+		e.conn, e.w = conn, conn
+	}
+	tags := append([]string(nil), config.Tags...)
+	if client.keyPrefix != "" {
+		tags = append(tags, "key_prefix:"+client.keyPrefix)
+	}
+	e.tags = strings.Join(tags, ",")
+	client.AddHook(e)
+	return e, nil
+}
+
+// Close closes the connection to the agent, if the exporter opened it
+func (e *StatsdExporter) Close() error {
+	if e.conn == nil {
+		return nil
+	}
+	return e.conn.Close()
+}
+
+// BeforeCommand implements Hook
+func (e *StatsdExporter) BeforeCommand(command string, args []interface{}) error {
+	return nil
+}
+
+// AfterCommand implements Hook, sending the metrics of the command unless it is sampled out
+func (e *StatsdExporter) AfterCommand(command string, args []interface{}, duration time.Duration, err error) {
+	if e.config.SampleRate < 1 && rand.Float64() >= e.config.SampleRate {
+		return
+	}
+	command = strings.ToUpper(command)
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.buf = e.buf[:0]
+	e.appendMetric(command, "command.duration", strconv.FormatFloat(float64(duration)/float64(time.Millisecond), 'f', -1, 64), "ms")
+	if err != nil {
+		e.buf = append(e.buf, '\n')
+		e.appendMetric(command, "command.errors", "1", "c")
+	}
+	// UDP is fire and forget: a lost packet only loses a sample
+	e.w.Write(e.buf)
+}
+
+// AfterGet implements PoolHook, sending the time taken to get a connection from the pool as the pool.wait timer,
+// and the connections refused by an exhausted pool as the pool.exhausted counter
+func (e *StatsdExporter) AfterGet(wait time.Duration, err error) {
+	if e.config.SampleRate < 1 && rand.Float64() >= e.config.SampleRate {
+		return
+	}
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.buf = e.buf[:0]
+	e.appendMetric("", "pool.wait", strconv.FormatFloat(float64(wait)/float64(time.Millisecond), 'f', -1, 64), "ms")
+	if err == ErrPoolExhausted {
+		e.buf = append(e.buf, '\n')
+		e.appendMetric("", "pool.exhausted", "1", "c")
+	}
+	e.w.Write(e.buf)
+}
+
+// appendMetric appends a metric line in the StatsD format, <name>:<value>|<type>[|@<rate>][|#<tags>], tagged
+// with command unless it is empty
+func (e *StatsdExporter) appendMetric(command string, name string, value string, kind string) {
+	e.buf = append(e.buf, e.config.Prefix...)
+	e.buf = append(e.buf, name...)
+	e.buf = append(e.buf, ':')
+	e.buf = append(e.buf, value...)
+	e.buf = append(e.buf, '|')
+	e.buf = append(e.buf, kind...)
+	if e.config.SampleRate < 1 {
+		e.buf = append(e.buf, "|@"...)
+		e.buf = strconv.AppendFloat(e.buf, e.config.SampleRate, 'f', -1, 64)
+	}
+	if e.config.NoTags {
+		return
+	}
+	separator := "|#"
+	if command != "" {
+		e.buf = append(e.buf, "|#command:"...)
+		e.buf = append(e.buf, strings.ToLower(command)...)
+		e.buf = append(e.buf, ",family:"...)
+		e.buf = append(e.buf, commandFamily(command)...)
+		separator = ","
+	}
+	if e.tags != "" {
+		e.buf = append(e.buf, separator...)
+		e.buf = append(e.buf, e.tags...)
+	}
+}
+
+// commandFamily returns the module family of command in lower case, e.g. "bf" for BF.ADD, or "redis" for the
+// core commands
+func commandFamily(command string) string {
+	if dot := strings.IndexByte(command, '.'); dot > 0 {
+		return strings.ToLower(command[:dot])
+	}
+	return "redis"
+}
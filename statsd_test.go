@@ -0,0 +1,80 @@
+package redis_bloom_go
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// packetWriter keeps each write as a packet
+type packetWriter struct {
+	packets []string
+}
+
+func (w *packetWriter) Write(p []byte) (int, error) {
+	w.packets = append(w.packets, string(p))
+	return len(p), nil
+}
+
+func TestStatsdExporter(t *testing.T) {
+	w := &packetWriter{}
+	c := NewClientFromPool(nil, "test", WithKeyPrefix("app:"))
+	e, err := NewStatsdExporter(c, StatsdConfig{Writer: w, Tags: []string{"env:test"}})
+	assert.Nil(t, err)
+	defer e.Close()
+	e.AfterCommand("BF.ADD", nil, 1500*time.Microsecond, nil)
+	e.AfterCommand("del", nil, 2*time.Millisecond, errors.New("boom"))
+	assert.Equal(t, []string{
+		"redisbloom.command.duration:1.5|ms|#command:bf.add,family:bf,env:test,key_prefix:app:",
+		"redisbloom.command.duration:2|ms|#command:del,family:redis,env:test,key_prefix:app:\n" +
+			"redisbloom.command.errors:1|c|#command:del,family:redis,env:test,key_prefix:app:",
+	}, w.packets)
+
+	w.packets = nil
+	e.AfterGet(time.Millisecond, nil)
+	e.AfterGet(3*time.Millisecond, ErrPoolExhausted)
+	assert.Equal(t, []string{
+		"redisbloom.pool.wait:1|ms|#env:test,key_prefix:app:",
+		"redisbloom.pool.wait:3|ms|#env:test,key_prefix:app:\n" +
+			"redisbloom.pool.exhausted:1|c|#env:test,key_prefix:app:",
+	}, w.packets)
+}
+
+func TestStatsdExporter_Sampling(t *testing.T) {
+	w := &packetWriter{}
+	e, err := NewStatsdExporter(NewClientFromPool(nil, "test"), StatsdConfig{Writer: w, Prefix: "bloom.", SampleRate: 0.5, NoTags: true})
+	assert.Nil(t, err)
+	for i := 0; i < 1000; i++ {
+		e.AfterCommand("CF.ADD", nil, time.Millisecond, nil)
+	}
+	assert.InDelta(t, 500, len(w.packets), 100)
+	assert.Equal(t, "bloom.command.duration:1|ms|@0.5", w.packets[0])
+}
+
+func TestStatsdExporter_UDP(t *testing.T) {
+	listener, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer listener.Close()
+	conn := &fakeConn{replies: []interface{}{int64(1)}}
+	c := NewClientFromPool(nil, "test")
+	c.Pool = &fakePool{conn: conn}
+	e, err := NewStatsdExporter(c, StatsdConfig{Address: listener.LocalAddr().String()})
+	assert.Nil(t, err)
+	defer e.Close()
+	_, err = c.Add("bf", "a")
+	assert.Nil(t, err)
+
+	packet := make([]byte, 1024)
+	listener.SetReadDeadline(time.Now().Add(time.Second))
+	n, _, err := listener.ReadFrom(packet)
+	assert.Nil(t, err)
+	assert.True(t, bytes.HasPrefix(packet[:n], []byte("redisbloom.pool.wait:")))
+	n, _, err = listener.ReadFrom(packet)
+	assert.Nil(t, err)
+	assert.True(t, bytes.HasPrefix(packet[:n], []byte("redisbloom.command.duration:")))
+	assert.True(t, bytes.HasSuffix(packet[:n], []byte("|ms|#command:bf.add,family:bf")))
+}
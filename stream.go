@@ -0,0 +1,272 @@
+package redis_bloom_go
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// ErrNoHandler is returned by NewDedupConsumer when its config has no Handler
+var ErrNoHandler = errors.New("redisbloom: dedup consumer config has no handler")
+
+// StreamMessage is a message read from a Redis stream
+type StreamMessage struct {
+	ID string
+	// Values are the fields of the message, nil if it was deleted while pending
+	Values map[string]string
+}
+
+// DedupConsumerConfig describes the stream consumed by a DedupConsumer and how duplicates are detected
+type DedupConsumerConfig struct {
+	// Stream, Group and Consumer identify the consumer in XREADGROUP
+	Stream   string
+	Group    string
+	Consumer string
+	// Filter is the key of the filter remembering the processed messages, a cuckoo filter if Cuckoo is
+	// true and a bloom filter otherwise. It is created on first use unless reserved beforehand.
+	Filter string
+	Cuckoo bool
+	// DedupKey returns the key identifying duplicates of a message. By default it is the message ID.
+	DedupKey func(StreamMessage) string
+	// Handler processes a message that was not seen before. The message is acknowledged and remembered
+	// when it returns nil, and left pending to be retried otherwise. It is required.
+	Handler func(StreamMessage) error
+	// Count is the maximum number of messages read at once, 100 by default
+	Count int64
+	// Block is how long a read waits for new messages, 1s by default
+	Block time.Duration
+	// RetryInterval is how often the messages left pending by the consumer are read again and handed to
+	// Handler, 30s by default. They are first retried by the first Poll, picking up after a restart.
+	RetryInterval time.Duration
+}
+
+// DedupStats counts the messages of one or more reads of a DedupConsumer
+type DedupStats struct {
+	Processed  int
+	Duplicates int
+	Failed     int
+}
+
+// DedupConsumer reads a stream as a member of a consumer group and hands new messages to a handler,
+// acknowledging without processing them the messages whose dedup key is already in a filter.
+// As the filter is only updated after the handler succeeds, a message may still be processed twice if
+// two consumers receive duplicates at the same time, and a bloom filter false positive drops a message.
+type DedupConsumer struct {
+	client *Client
+	config DedupConsumerConfig
+	// retried is when the pending messages were last retried
+	retried time.Time
+}
+
+// defaultDedupRetryInterval is the RetryInterval of a DedupConsumer when none is configured
+const defaultDedupRetryInterval = 30 * time.Second
+
+// NewDedupConsumer - Returns a consumer of the stream described by config.
+// Returns ErrNoHandler if config.Handler is nil.
+func NewDedupConsumer(client *Client, config DedupConsumerConfig) (*DedupConsumer, error) {
+	if config.Handler == nil {
+		return nil, ErrNoHandler
+	}
+	if config.DedupKey == nil {
+		config.DedupKey = func(message StreamMessage) string { return message.ID }
+	}
+	if config.Count <= 0 {
+		config.Count = 100
+	}
+	if config.Block <= 0 {
+		config.Block = time.Second
+	}
+	if config.RetryInterval <= 0 {
+		config.RetryInterval = defaultDedupRetryInterval
+	}
+	return &DedupConsumer{client: client, config: config}, nil
+}
+
+// CreateGroup - Creates the consumer group, and the stream if needed, delivering the messages added from now on.
+// Does nothing if the group already exists.
+func (c *DedupConsumer) CreateGroup() error {
+	conn := c.client.Pool.Get()
+	defer conn.Close()
+	_, err := conn.Do("XGROUP", "CREATE", c.client.key(c.config.Stream), c.config.Group, "$", "MKSTREAM")
+	if err != nil && strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return nil
+	}
+	return err
+}
+
+// Run - Reads and processes messages until ctx is done or a read fails
+func (c *DedupConsumer) Run(ctx context.Context) (DedupStats, error) {
+	var total DedupStats
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+		stats, err := c.Poll()
+		total.Processed += stats.Processed
+		total.Duplicates += stats.Duplicates
+		total.Failed += stats.Failed
+		if err != nil {
+			return total, err
+		}
+	}
+}
+
+// Poll - Reads one batch of new messages, waiting up to Block for them, and processes it.
+// Once every RetryInterval, starting with the first call, the messages left pending are processed again first.
+func (c *DedupConsumer) Poll() (DedupStats, error) {
+	var stats DedupStats
+	if c.retried.IsZero() || time.Since(c.retried) >= c.config.RetryInterval {
+		if err := c.retryPending(&stats); err != nil {
+			return stats, err
+		}
+		c.retried = time.Now()
+	}
+	messages, err := c.read(">")
+	if err != nil {
+		return stats, err
+	}
+	c.processAll(messages, &stats)
+	return stats, nil
+}
+
+// retryPending processes again every message delivered to the consumer and not acknowledged yet
+func (c *DedupConsumer) retryPending(stats *DedupStats) error {
+	id := "0"
+	for {
+		messages, err := c.read(id)
+		if err != nil || len(messages) == 0 {
+			return err
+		}
+		c.processAll(messages, stats)
+		id = messages[len(messages)-1].ID
+	}
+}
+
+// processAll processes messages, counting the outcomes in stats
+func (c *DedupConsumer) processAll(messages []StreamMessage, stats *DedupStats) {
+	for _, message := range messages {
+		if message.Values == nil {
+			// deleted from the stream while pending, there is nothing left to process
+			c.ack(message.ID)
+			continue
+		}
+		duplicate, err := c.process(message)
+		switch {
+		case err != nil:
+			stats.Failed++
+		case duplicate:
+			stats.Duplicates++
+		default:
+			stats.Processed++
+		}
+	}
+}
+
+// process hands message to the handler unless it is a duplicate, and acknowledges it on success
+func (c *DedupConsumer) process(message StreamMessage) (bool, error) {
+	dedupKey := c.config.DedupKey(message)
+	seen, err := c.seen(dedupKey)
+	if err != nil {
+		return false, err
+	}
+	if !seen {
+		if err := c.config.Handler(message); err != nil {
+			return false, err
+		}
+		if err := c.remember(dedupKey); err != nil {
+			return false, err
+		}
+	}
+	return seen, c.ack(message.ID)
+}
+
+func (c *DedupConsumer) seen(dedupKey string) (bool, error) {
+	if c.config.Cuckoo {
+		return c.client.CfExists(c.config.Filter, dedupKey)
+	}
+	return c.client.Exists(c.config.Filter, dedupKey)
+}
+
+func (c *DedupConsumer) remember(dedupKey string) error {
+	var err error
+	if c.config.Cuckoo {
+		_, err = c.client.CfAddNx(c.config.Filter, dedupKey)
+	} else {
+		_, err = c.client.Add(c.config.Filter, dedupKey)
+	}
+	return err
+}
+
+func (c *DedupConsumer) ack(id string) error {
+	conn := c.client.Pool.Get()
+	defer conn.Close()
+	_, err := conn.Do("XACK", c.client.key(c.config.Stream), c.config.Group, id)
+	return err
+}
+
+// read reads the next batch of messages delivered to the consumer, nil if none arrived within Block, when id
+// is ">". Otherwise it reads the messages already delivered to the consumer and still pending after id.
+func (c *DedupConsumer) read(id string) ([]StreamMessage, error) {
+	conn := c.client.Pool.Get()
+	defer conn.Close()
+	reply, err := redis.Values(conn.Do("XREADGROUP", "GROUP", c.config.Group, c.config.Consumer,
+		"COUNT", c.config.Count, "BLOCK", int64(c.config.Block/time.Millisecond),
+		"STREAMS", c.client.key(c.config.Stream), id))
+	if err == redis.ErrNil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var messages []StreamMessage
+	for _, stream := range reply {
+		streamMessages, err := ParseStreamMessages(redis.Values(stream, nil))
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, streamMessages...)
+	}
+	return messages, nil
+}
+
+// ParseStreamMessages - Parses a [stream name, messages] entry of an XREAD or XREADGROUP reply.
+// The messages deleted while pending, which XREADGROUP returns without fields, have nil Values.
+func ParseStreamMessages(stream []interface{}, err error) ([]StreamMessage, error) {
+	if err != nil {
+		return nil, err
+	}
+	if len(stream) != 2 {
+		return nil, errors.New("redisbloom: unexpected stream reply")
+	}
+	entries, err := redis.Values(stream[1], nil)
+	if err != nil {
+		return nil, err
+	}
+	messages := make([]StreamMessage, 0, len(entries))
+	for _, entry := range entries {
+		fields, err := redis.Values(entry, nil)
+		if err != nil {
+			return nil, err
+		}
+		if len(fields) != 2 {
+			return nil, errors.New("redisbloom: unexpected stream message reply")
+		}
+		id, err := redis.String(fields[0], nil)
+		if err != nil {
+			return nil, err
+		}
+		if fields[1] == nil {
+			messages = append(messages, StreamMessage{ID: id})
+			continue
+		}
+		values, err := redis.StringMap(fields[1], nil)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, StreamMessage{ID: id, Values: values})
+	}
+	return messages, nil
+}
@@ -0,0 +1,135 @@
+package redis_bloom_go
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseStreamMessages(t *testing.T) {
+	reply := []interface{}{
+		[]byte("stream"),
+		[]interface{}{
+			[]interface{}{[]byte("1-0"), []interface{}{[]byte("order"), []byte("42")}},
+			[]interface{}{[]byte("2-0"), []interface{}{}},
+		},
+	}
+	messages, err := ParseStreamMessages(reply, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []StreamMessage{
+		{ID: "1-0", Values: map[string]string{"order": "42"}},
+		{ID: "2-0", Values: map[string]string{}},
+	}, messages)
+
+	// a message deleted while pending has no fields
+	messages, err = ParseStreamMessages([]interface{}{[]byte("stream"), []interface{}{[]interface{}{[]byte("3-0"), nil}}}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []StreamMessage{{ID: "3-0"}}, messages)
+
+	_, err = ParseStreamMessages([]interface{}{[]byte("stream")}, nil)
+	assert.NotNil(t, err)
+}
+
+func TestDedupConsumer(t *testing.T) {
+	client.FlushAll()
+	var handled []string
+	consumer, err := NewDedupConsumer(client, DedupConsumerConfig{
+		Stream:   "test_dedup_stream",
+		Group:    "group",
+		Consumer: "consumer",
+		Filter:   "test_dedup_filter",
+		DedupKey: func(message StreamMessage) string { return message.Values["order"] },
+		Handler: func(message StreamMessage) error {
+			if message.Values["fail"] != "" {
+				return errors.New("failed")
+			}
+			handled = append(handled, message.Values["order"])
+			return nil
+		},
+		Block: 10 * time.Millisecond,
+	})
+	assert.Nil(t, err)
+	assert.Nil(t, consumer.CreateGroup())
+	assert.Nil(t, consumer.CreateGroup())
+
+	conn := client.Pool.Get()
+	defer conn.Close()
+	for _, order := range []string{"1", "2", "1", "3", "2"} {
+		_, err := conn.Do("XADD", "test_dedup_stream", "*", "order", order)
+		assert.Nil(t, err)
+	}
+	conn.Do("XADD", "test_dedup_stream", "*", "order", "4", "fail", "1")
+
+	stats, err := consumer.Poll()
+	assert.Nil(t, err)
+	assert.Equal(t, DedupStats{Processed: 3, Duplicates: 2, Failed: 1}, stats)
+	assert.Equal(t, []string{"1", "2", "3"}, handled)
+
+	// only the failed message is left pending
+	pending, err := conn.Do("XPENDING", "test_dedup_stream", "group")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), pending.([]interface{})[0])
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	stats, err = consumer.Run(ctx)
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Equal(t, DedupStats{}, stats)
+}
+
+func TestNewDedupConsumer_NoHandler(t *testing.T) {
+	_, err := NewDedupConsumer(client, DedupConsumerConfig{Stream: "stream", Group: "group", Consumer: "consumer"})
+	assert.Equal(t, ErrNoHandler, err)
+}
+
+func TestDedupConsumer_RetryPending(t *testing.T) {
+	conn := &argsConn{fakeConn: &fakeConn{replies: []interface{}{
+		[]interface{}{[]interface{}{[]byte("stream"), []interface{}{
+			[]interface{}{[]byte("1-0"), []interface{}{[]byte("order"), []byte("1")}},
+			[]interface{}{[]byte("2-0"), nil},
+		}}},
+		int64(0), int64(1), int64(1), // BF.EXISTS, BF.ADD and XACK of 1-0
+		int64(1), // XACK of the deleted 2-0
+		[]interface{}{[]interface{}{[]byte("stream"), []interface{}{}}},
+		nil, // no new message
+		nil,
+	}}}
+	c := NewClientFromPool(nil, "test")
+	c.Pool = &fakePool{conn: conn}
+	var handled []string
+	consumer, err := NewDedupConsumer(c, DedupConsumerConfig{
+		Stream:   "stream",
+		Group:    "group",
+		Consumer: "consumer",
+		Filter:   "filter",
+		Handler: func(message StreamMessage) error {
+			handled = append(handled, message.ID)
+			return nil
+		},
+	})
+	assert.Nil(t, err)
+
+	// the first poll picks up the messages left pending, e.g. before a restart
+	stats, err := consumer.Poll()
+	assert.Nil(t, err)
+	assert.Equal(t, DedupStats{Processed: 1}, stats)
+	assert.Equal(t, []string{"1-0"}, handled)
+	assert.Equal(t, []string{"XREADGROUP", "BF.EXISTS", "BF.ADD", "XACK", "XACK", "XREADGROUP", "XREADGROUP"}, conn.commands)
+	var ids []interface{}
+	for i, command := range conn.commands {
+		if command == "XREADGROUP" {
+			ids = append(ids, conn.args[i][len(conn.args[i])-1])
+		}
+	}
+	assert.Equal(t, []interface{}{"0", "2-0", ">"}, ids)
+
+	// the next ones only read new messages until RetryInterval elapsed
+	_, err = consumer.Poll()
+	assert.Nil(t, err)
+	assert.Equal(t, "XREADGROUP", conn.commands[len(conn.commands)-1])
+	assert.Equal(t, ">", conn.args[len(conn.args)-1][len(conn.args[len(conn.args)-1])-1])
+	assert.Len(t, conn.commands, 8)
+}
@@ -0,0 +1,24 @@
+package redis_bloom_go
+
+import (
+	"errors"
+	"strings"
+)
+
+// tenantKeyPrefix is prepended, along with the tenant id, to the keys of a tenant scoped client
+const tenantKeyPrefix = "tenant:"
+
+// ErrInvalidTenant is returned by ForTenant for an empty tenant id or one containing ':'
+var ErrInvalidTenant = errors.New("redisbloom: tenant id must be non-empty and must not contain ':'")
+
+// ForTenant - Returns a view of the client whose keys are confined to the namespace of tenant id,
+// sharing the connection pool of client. Every command, including ListKeys and Backup,
+// only ever sees the keys of that tenant.
+func (client *Client) ForTenant(id string) (*Client, error) {
+	if id == "" || strings.Contains(id, ":") {
+		return nil, ErrInvalidTenant
+	}
+	scoped := *client
+	scoped.keyPrefix = client.keyPrefix + tenantKeyPrefix + id + ":"
+	return &scoped, nil
+}
@@ -0,0 +1,40 @@
+package redis_bloom_go
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_ForTenant(t *testing.T) {
+	client.FlushAll()
+	_, err := client.ForTenant("")
+	assert.Equal(t, ErrInvalidTenant, err)
+	_, err = client.ForTenant("a:b")
+	assert.Equal(t, ErrInvalidTenant, err)
+
+	tenantA, err := client.ForTenant("a")
+	assert.Nil(t, err)
+	tenantB, err := client.ForTenant("b")
+	assert.Nil(t, err)
+	assert.Equal(t, "tenant:a:filter", tenantA.key("filter"))
+	assert.Equal(t, "", client.keyPrefix)
+
+	tenantA.Add("filter", "x")
+	tenantB.Add("filter", "y")
+	tenantB.Add("other", "y")
+	exists, err := tenantA.Exists("filter", "y")
+	assert.Nil(t, err)
+	assert.False(t, exists)
+
+	listing, err := tenantA.ListKeys("*")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"filter"}, listing.Bloom)
+
+	var buf bytes.Buffer
+	count, err := tenantB.Backup(context.Background(), &buf, "*")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, count)
+}
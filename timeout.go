@@ -0,0 +1,146 @@
+package redis_bloom_go
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// WithCommandTimeouts bounds the time the client waits for the reply of a command with redis.DoWithTimeout,
+// independently of the read timeout of the connections, so that e.g. a slow TDIGEST.MERGE cannot hang a
+// request handler. Timeouts are keyed by command, e.g. "TDIGEST.MERGE", or by family, e.g. "CMS" for every
+// CMS.* command, the command taking precedence over its family; the "" key sets the timeout of the other
+// commands. A command timing out fails with a net.Error whose Timeout method returns true, and its
+// connection is discarded since the reply may still arrive.
+func WithCommandTimeouts(timeouts map[string]time.Duration) ClientOption {
+	return func(client *Client) {
+		normalized := make(map[string]time.Duration, len(timeouts))
+		for command, timeout := range timeouts {
+			normalized[strings.ToUpper(command)] = timeout
+		}
+		client.Pool = &timeoutPool{ConnPool: client.Pool, timeouts: normalized}
+	}
+}
+
+// WithTimeout - Returns a view of the client, sharing its connection pool, whose commands each wait at most
+// timeout for their reply, e.g. client.WithTimeout(time.Second).BfExistsMulti(key, items).
+// The timeout takes precedence over those of WithCommandTimeouts.
+func (client *Client) WithTimeout(timeout time.Duration) *Client {
+	scoped := *client
+	scoped.Pool = &timeoutPool{ConnPool: client.Pool, timeouts: map[string]time.Duration{"": timeout}}
+	return &scoped
+}
+
+// timeoutPool hands out connections applying per command timeouts
+type timeoutPool struct {
+	ConnPool
+	timeouts map[string]time.Duration
+}
+
+func (p *timeoutPool) unwrap() ConnPool {
+	return p.ConnPool
+}
+
+func (p *timeoutPool) Get() redis.Conn {
+	return &timeoutConn{Conn: p.ConnPool.Get(), pool: p}
+}
+
+// timeout returns the timeout of command, zero if it has none
+func (p *timeoutPool) timeout(command string) time.Duration {
+	command = strings.ToUpper(command)
+	if timeout, ok := p.timeouts[command]; ok {
+		return timeout
+	}
+	if dot := strings.IndexByte(command, '.'); dot >= 0 {
+		if timeout, ok := p.timeouts[command[:dot]]; ok {
+			return timeout
+		}
+	}
+	return p.timeouts[""]
+}
+
+// timeoutConn applies the timeouts of its pool to Do, and to the Receive of pipelined commands
+type timeoutConn struct {
+	redis.Conn
+	pool    *timeoutPool
+	pending []time.Duration
+}
+
+func (c *timeoutConn) Do(command string, args ...interface{}) (interface{}, error) {
+	timeout := time.Duration(0)
+	if command == "" {
+		for _, pending := range c.pending {
+			if pending > timeout {
+				timeout = pending
+			}
+		}
+	} else {
+		timeout = c.pool.timeout(command)
+	}
+	c.pending = nil
+	if timeout <= 0 {
+		return c.Conn.Do(command, args...)
+	}
+	return redis.DoWithTimeout(c.Conn, timeout, command, args...)
+}
+
+// DoWithTimeout overrides the timeouts of the pool with timeout
+func (c *timeoutConn) DoWithTimeout(timeout time.Duration, command string, args ...interface{}) (interface{}, error) {
+	c.pending = nil
+	return redis.DoWithTimeout(c.Conn, timeout, command, args...)
+}
+
+func (c *timeoutConn) Send(command string, args ...interface{}) error {
+	if err := c.Conn.Send(command, args...); err != nil {
+		return err
+	}
+	c.pending = append(c.pending, c.pool.timeout(command))
+	return nil
+}
+
+func (c *timeoutConn) Receive() (interface{}, error) {
+	timeout := time.Duration(0)
+	if len(c.pending) > 0 {
+		timeout = c.pending[0]
+		c.pending = c.pending[1:]
+	}
+	if timeout <= 0 {
+		return c.Conn.Receive()
+	}
+	return redis.ReceiveWithTimeout(c.Conn, timeout)
+}
+
+// ReceiveWithTimeout overrides the timeouts of the pool with timeout
+func (c *timeoutConn) ReceiveWithTimeout(timeout time.Duration) (interface{}, error) {
+	if len(c.pending) > 0 {
+		c.pending = c.pending[1:]
+	}
+	return redis.ReceiveWithTimeout(c.Conn, timeout)
+}
+
+// connCall performs a command, or receives a reply, on conn. It lets the connection wrappers share
+// the implementation of Do and DoWithTimeout, and of Receive and ReceiveWithTimeout.
+type connCall func(conn redis.Conn) (interface{}, error)
+
+func doCall(command string, args []interface{}) connCall {
+	return func(conn redis.Conn) (interface{}, error) {
+		return conn.Do(command, args...)
+	}
+}
+
+func doWithTimeoutCall(timeout time.Duration, command string, args []interface{}) connCall {
+	return func(conn redis.Conn) (interface{}, error) {
+		return redis.DoWithTimeout(conn, timeout, command, args...)
+	}
+}
+
+func receiveCall(conn redis.Conn) (interface{}, error) {
+	return conn.Receive()
+}
+
+func receiveWithTimeoutCall(timeout time.Duration) connCall {
+	return func(conn redis.Conn) (interface{}, error) {
+		return redis.ReceiveWithTimeout(conn, timeout)
+	}
+}
@@ -0,0 +1,96 @@
+package redis_bloom_go
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+// timeoutFakeConn is a fakeConn recording the timeout of every command, zero when it has none
+type timeoutFakeConn struct {
+	*fakeConn
+	timeouts []time.Duration
+}
+
+func (c *timeoutFakeConn) Do(command string, args ...interface{}) (interface{}, error) {
+	if command != "" {
+		c.timeouts = append(c.timeouts, 0)
+	}
+	return c.fakeConn.Do(command, args...)
+}
+
+func (c *timeoutFakeConn) DoWithTimeout(timeout time.Duration, command string, args ...interface{}) (interface{}, error) {
+	if command != "" {
+		c.timeouts = append(c.timeouts, timeout)
+	}
+	return c.fakeConn.Do(command, args...)
+}
+
+func (c *timeoutFakeConn) Receive() (interface{}, error) {
+	c.timeouts = append(c.timeouts, 0)
+	return c.fakeConn.Receive()
+}
+
+func (c *timeoutFakeConn) ReceiveWithTimeout(timeout time.Duration) (interface{}, error) {
+	c.timeouts = append(c.timeouts, timeout)
+	return c.fakeConn.Receive()
+}
+
+type timeoutFakePool struct {
+	conn *timeoutFakeConn
+}
+
+func (p *timeoutFakePool) Get() redis.Conn { return p.conn }
+func (p *timeoutFakePool) Close() error    { return nil }
+
+func TestWithCommandTimeouts(t *testing.T) {
+	conn := &timeoutFakeConn{fakeConn: &fakeConn{replies: []interface{}{"OK", []interface{}{int64(3)}, int64(1)}}}
+	hook := &recordingHook{}
+	c := NewClientFromPool(nil, "test", WithCommandTimeouts(map[string]time.Duration{
+		"tdigest.merge": 2 * time.Second,
+		"CMS":           time.Second,
+	}), WithHooks(hook))
+	c.Pool.(*hookedPool).ConnPool.(*timeoutPool).ConnPool = &timeoutFakePool{conn: conn}
+
+	_, err := c.TdMerge("to", "from")
+	assert.Nil(t, err)
+	_, err = c.CmsQuery("sketch", []string{"item"})
+	assert.Nil(t, err)
+	_, err = c.Add("filter", "item")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"TDIGEST.MERGE", "CMS.QUERY", "BF.ADD"}, conn.commands)
+	assert.Equal(t, []time.Duration{2 * time.Second, time.Second, 0}, conn.timeouts)
+	assert.Equal(t, []string{"TDIGEST.MERGE", "CMS.QUERY", "BF.ADD"}, hook.after)
+}
+
+func TestClient_WithTimeout(t *testing.T) {
+	conn := &timeoutFakeConn{fakeConn: &fakeConn{replies: []interface{}{
+		int64(1), []interface{}{int64(1), int64(0)}, []interface{}{int64(1)},
+	}}}
+	c := NewClientFromPool(nil, "test", WithMaxBatchSize(2), WithHooks(&recordingHook{}))
+	c.Pool.(*hookedPool).ConnPool = &timeoutFakePool{conn: conn}
+
+	scoped := c.WithTimeout(500 * time.Millisecond)
+	_, err := scoped.Add("filter", "item")
+	assert.Nil(t, err)
+	// pipelined batches wait at most the timeout for each reply
+	_, err = scoped.BfExistsMulti("filter", []string{"a", "b", "c"})
+	assert.Nil(t, err)
+	assert.Equal(t, []time.Duration{500 * time.Millisecond, 500 * time.Millisecond, 500 * time.Millisecond}, conn.timeouts)
+
+	// the client itself is unaffected
+	conn.replies = []interface{}{int64(1)}
+	conn.timeouts = nil
+	_, err = c.Add("filter", "item")
+	assert.Nil(t, err)
+	assert.Equal(t, []time.Duration{0}, conn.timeouts)
+}
+
+func TestTimeoutPool_Timeout(t *testing.T) {
+	p := &timeoutPool{timeouts: map[string]time.Duration{"": time.Minute, "CF": time.Second, "CF.ADD": time.Millisecond}}
+	assert.Equal(t, time.Millisecond, p.timeout("cf.add"))
+	assert.Equal(t, time.Second, p.timeout("CF.EXISTS"))
+	assert.Equal(t, time.Minute, p.timeout("BF.ADD"))
+}
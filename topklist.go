@@ -0,0 +1,127 @@
+package redis_bloom_go
+
+import (
+	"fmt"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// defaultTopkPageSize is the page size of TopkListIterator when none is given
+const defaultTopkPageSize = 1000
+
+// TopkEntry is an item of a top-k with its estimated count
+type TopkEntry struct {
+	Item  string
+	Count int64
+}
+
+// TopkIterator walks the items of a top-k one page at a time, from the most to the least frequent:
+//
+//	it := client.TopkListIterator(key, 1000, true)
+//	for it.Next() {
+//		for _, entry := range it.Page() {
+//			...
+//		}
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+//
+// TOPK.LIST replies with every item at once, so the reply is still read whole, but the items are converted
+// a page at a time and the reply drops each page once converted: a consumer which does not keep the pages
+// holds the raw reply and a single page instead of the raw reply and its full conversion.
+type TopkIterator struct {
+	list      func() (interface{}, error)
+	pageSize  int
+	withCount bool
+	values    []interface{}
+	page      []TopkEntry
+	fetched   bool
+	err       error
+}
+
+// TopkListIterator - Returns an iterator over the items of the top-k stored at key, in pages of pageSize items,
+// defaultTopkPageSize if pageSize is not positive. withCount fetches the estimated counts of the items,
+// which requires RedisBloom 2.2.5 or newer; otherwise the counts are zero.
+func (client *Client) TopkListIterator(key string, pageSize int, withCount bool) *TopkIterator {
+	if pageSize <= 0 {
+		pageSize = defaultTopkPageSize
+	}
+	return &TopkIterator{
+		list: func() (interface{}, error) {
+			conn := client.Pool.Get()
+			defer conn.Close()
+			if withCount {
+				return conn.Do("TOPK.LIST", client.key(key), "WITHCOUNT")
+			}
+			return conn.Do("TOPK.LIST", client.key(key))
+		},
+		pageSize:  pageSize,
+		withCount: withCount,
+	}
+}
+
+// Next converts the next page of items, returning false once every item was returned or an error occurred.
+// The first call sends TOPK.LIST.
+func (it *TopkIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	if !it.fetched {
+		it.fetched = true
+		it.values, it.err = redis.Values(it.list())
+		if it.err != nil {
+			return false
+		}
+		if it.withCount && len(it.values)%2 != 0 {
+			it.values, it.err = nil, fmt.Errorf("redisbloom: TOPK.LIST WITHCOUNT replied %d values, expected pairs", len(it.values))
+			return false
+		}
+	}
+	stride := 1
+	if it.withCount {
+		stride = 2
+	}
+	n := len(it.values) / stride
+	if n == 0 {
+		it.values, it.page = nil, nil
+		return false
+	}
+	if n > it.pageSize {
+		n = it.pageSize
+	}
+	it.page = it.page[:0]
+	for i := 0; i < n*stride; i += stride {
+		entry := TopkEntry{}
+		// empty slots are nil, like in TopkList
+		if it.values[i] != nil {
+			if entry.Item, it.err = redis.String(it.values[i], nil); it.err != nil {
+				it.values, it.page = nil, nil
+				return false
+			}
+		}
+		if it.withCount {
+			if entry.Count, it.err = redis.Int64(it.values[i+1], nil); it.err != nil {
+				it.values, it.page = nil, nil
+				return false
+			}
+		}
+		it.page = append(it.page, entry)
+		// drop the raw values so that they can be collected while the rest of the reply is walked
+		for j := i; j < i+stride; j++ {
+			it.values[j] = nil
+		}
+	}
+	it.values = it.values[n*stride:]
+	return true
+}
+
+// Page returns the items of the current page. The slice is reused by the next call to Next.
+func (it *TopkIterator) Page() []TopkEntry {
+	return it.page
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *TopkIterator) Err() error {
+	return it.err
+}
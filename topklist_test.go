@@ -0,0 +1,64 @@
+package redis_bloom_go
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTopkIterator(t *testing.T) {
+	calls := 0
+	reply := []interface{}{[]byte("a"), int64(5), []byte("b"), int64(3), []byte("c"), int64(1)}
+	it := &TopkIterator{list: func() (interface{}, error) {
+		calls++
+		return reply, nil
+	}, pageSize: 2, withCount: true}
+	assert.True(t, it.Next())
+	assert.Equal(t, []TopkEntry{{"a", 5}, {"b", 3}}, it.Page())
+	assert.Nil(t, reply[0])
+	assert.True(t, it.Next())
+	assert.Equal(t, []TopkEntry{{"c", 1}}, it.Page())
+	assert.False(t, it.Next())
+	assert.Nil(t, it.Err())
+	assert.Equal(t, 1, calls)
+
+	it = &TopkIterator{list: func() (interface{}, error) {
+		return []interface{}{[]byte("a"), nil}, nil
+	}, pageSize: 10}
+	assert.True(t, it.Next())
+	assert.Equal(t, []TopkEntry{{"a", 0}, {"", 0}}, it.Page())
+	assert.False(t, it.Next())
+
+	listErr := errors.New("list failed")
+	it = &TopkIterator{list: func() (interface{}, error) {
+		return nil, listErr
+	}, pageSize: 10}
+	assert.False(t, it.Next())
+	assert.Equal(t, listErr, it.Err())
+
+	it = &TopkIterator{list: func() (interface{}, error) {
+		return []interface{}{[]byte("a")}, nil
+	}, pageSize: 10, withCount: true}
+	assert.False(t, it.Next())
+	assert.NotNil(t, it.Err())
+}
+
+func TestClient_TopkListIterator(t *testing.T) {
+	client.FlushAll()
+	key := "test_topk_list_iterator"
+	ret, err := client.TopkReserve(key, 10, 50, 5, 0.9)
+	assert.Nil(t, err)
+	assert.Equal(t, "OK", ret)
+	_, err = client.TopkIncrBy(key, map[string]int64{"a": 5, "b": 3, "c": 1})
+	assert.Nil(t, err)
+
+	it := client.TopkListIterator(key, 2, true)
+	entries := make([]TopkEntry, 0)
+	for it.Next() {
+		assert.True(t, len(it.Page()) <= 2)
+		entries = append(entries, it.Page()...)
+	}
+	assert.Nil(t, it.Err())
+	assert.Equal(t, []TopkEntry{{"a", 5}, {"b", 3}, {"c", 1}}, entries)
+}
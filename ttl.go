@@ -0,0 +1,103 @@
+package redis_bloom_go
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// ErrInvalidTTL is returned by the writes given a time to live shorter than a millisecond
+var ErrInvalidTTL = errors.New("redisbloom: ttl must be at least one millisecond")
+
+// ReserveWithTTL - Creates an empty Bloom Filter, like Reserve, that expires after ttl.
+// The expiration is set atomically once the filter was created, so that an existing filter keeps its own.
+func (client *Client) ReserveWithTTL(key string, error_rate float64, capacity uint64, ttl time.Duration) error {
+	if err := client.checkBloomBudget(key, error_rate, capacity); err != nil {
+		return err
+	}
+	conn := client.Pool.Get()
+	defer conn.Close()
+	_, err := client.doWithTTL(conn, key, ttl, "BF.RESERVE", client.key(key), strconv.FormatFloat(error_rate, 'g', 16, 64), capacity)
+	return err
+}
+
+// CfReserveWithTTL - Creates an empty cuckoo filter, like CfReserveWithOptions, that expires after ttl.
+// The expiration is set atomically once the filter was created, so that an existing filter keeps its own.
+func (client *Client) CfReserveWithTTL(key string, capacity int64, ttl time.Duration, opts ...CallOption) (string, error) {
+	if err := client.checkCuckooBudget(key, capacity, opts); err != nil {
+		return "", err
+	}
+	conn := client.Pool.Get()
+	defer conn.Close()
+	return redis.String(client.doWithTTL(conn, key, ttl, "CF.RESERVE", client.cfReserveArgs(key, capacity, opts)...))
+}
+
+// maxExpireOnSuccessArgs is the number of arguments of the commands run by expireOnSuccessScript, above which
+// Lua cannot unpack them
+const maxExpireOnSuccessArgs = 7000
+
+// doWithTTL runs command on conn and, when ttl is positive and command succeeded, atomically sets the time to live
+// of key. Both run in a script, so that a failed command, e.g. reserving a filter which exists or writing to a key
+// of another type, leaves the TTL of key alone, and a successful one cannot be left without TTL. Commands with
+// too many arguments for a script run in a MULTI/EXEC transaction instead, which sets the TTL even if they fail.
+// The reply of command is returned.
+func (client *Client) doWithTTL(conn redis.Conn, key string, ttl time.Duration, command string, args ...interface{}) (interface{}, error) {
+	if ttl <= 0 {
+		return conn.Do(command, args...)
+	}
+	// PEXPIRE 0 would delete the key
+	if ttl < time.Millisecond {
+		return nil, ErrInvalidTTL
+	}
+	millis := int64(ttl / time.Millisecond)
+	if len(args) > maxExpireOnSuccessArgs {
+		return client.doInTransactionWithTTL(conn, key, millis, command, args)
+	}
+	scriptArgs := make([]interface{}, 0, len(args)+3)
+	scriptArgs = append(scriptArgs, client.key(key), millis, command)
+	return expireOnSuccessScript.Do(conn, append(scriptArgs, args...)...)
+}
+
+// doInTransactionWithTTL runs command and sets the time to live of key to millis within the same MULTI/EXEC
+// transaction. The reply of command is returned.
+func (client *Client) doInTransactionWithTTL(conn redis.Conn, key string, millis int64, command string, args []interface{}) (interface{}, error) {
+	if err := conn.Send("MULTI"); err != nil {
+		return nil, err
+	}
+	if err := conn.Send(command, args...); err != nil {
+		return nil, err
+	}
+	if err := conn.Send("PEXPIRE", client.key(key), millis); err != nil {
+		return nil, err
+	}
+	replies, err := redis.Values(conn.Do("EXEC"))
+	if err != nil {
+		return nil, err
+	}
+	if len(replies) == 0 {
+		return nil, redis.ErrNil
+	}
+	if err, ok := replies[0].(redis.Error); ok {
+		return nil, err
+	}
+	return replies[0], nil
+}
+
+// expireOnSuccessCommand returns the command run by an EVALSHA or EVAL of expireOnSuccessScript with args, with its
+// arguments and those of the PEXPIRE following it on success
+func expireOnSuccessCommand(command string, args []interface{}) (string, []interface{}, []interface{}, bool) {
+	if !strings.EqualFold(command, "EVALSHA") && !strings.EqualFold(command, "EVAL") || len(args) < 5 {
+		return "", nil, nil, false
+	}
+	if spec, _ := args[0].(string); spec != expireOnSuccessScript.Hash() && spec != expireOnSuccessSource {
+		return "", nil, nil, false
+	}
+	inner, ok := args[4].(string)
+	if !ok {
+		return "", nil, nil, false
+	}
+	return inner, args[5:], []interface{}{args[2], args[3]}, true
+}
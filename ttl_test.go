@@ -0,0 +1,113 @@
+package redis_bloom_go
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_ReserveWithTTL(t *testing.T) {
+	client.FlushAll()
+	conn := client.Pool.Get()
+	defer conn.Close()
+
+	err := client.ReserveWithTTL("test_bf_ttl", 0.01, 1000, time.Hour)
+	assert.Nil(t, err)
+	ttl, err := redis.Int64(conn.Do("TTL", "test_bf_ttl"))
+	assert.Nil(t, err)
+	assert.Less(t, int64(3500), ttl)
+
+	err = client.ReserveWithTTL("test_bf_ttl", 0.01, 1000, time.Hour)
+	assert.NotNil(t, err)
+
+	ret, err := client.CfReserveWithTTL("test_cf_ttl", 1000, time.Hour, WithBucketSize(4))
+	assert.Nil(t, err)
+	assert.Equal(t, "OK", ret)
+	ttl, err = redis.Int64(conn.Do("TTL", "test_cf_ttl"))
+	assert.Nil(t, err)
+	assert.Less(t, int64(3500), ttl)
+}
+
+func TestClient_WriteTTL(t *testing.T) {
+	client.FlushAll()
+	host, password := getTestConnectionDetails()
+	pool := &redis.Pool{Dial: func() (redis.Conn, error) {
+		return redis.Dial("tcp", host, redis.DialPassword(password))
+	}, MaxIdle: maxConns}
+	ttlClient := NewClientFromPool(pool, "bloom-client-ttl", WithWriteTTL(time.Hour), WithMaxBatchSize(2))
+	defer ttlClient.Pool.Close()
+	conn := client.Pool.Get()
+	defer conn.Close()
+
+	added, err := ttlClient.Add("test_write_ttl_bf", "a")
+	assert.Nil(t, err)
+	assert.True(t, added)
+	added, err = ttlClient.CfAdd("test_write_ttl_cf", "a")
+	assert.Nil(t, err)
+	assert.True(t, added)
+	rets, err := ttlClient.BfAddMulti("test_write_ttl_madd", []string{"a", "b", "c"})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1, 1, 1}, rets)
+	for _, key := range []string{"test_write_ttl_bf", "test_write_ttl_cf", "test_write_ttl_madd"} {
+		ttl, err := redis.Int64(conn.Do("TTL", key))
+		assert.Nil(t, err)
+		assert.Less(t, int64(3500), ttl)
+	}
+
+	// reads leave the TTL alone
+	conn.Do("PERSIST", "test_write_ttl_bf")
+	_, err = ttlClient.Exists("test_write_ttl_bf", "a")
+	assert.Nil(t, err)
+	ttl, err := redis.Int64(conn.Do("TTL", "test_write_ttl_bf"))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(-1), ttl)
+
+	// errors of the write are surfaced
+	conn.Do("SET", "test_write_ttl_plain", "value")
+	_, err = ttlClient.Add("test_write_ttl_plain", "a")
+	assert.NotNil(t, err)
+}
+
+func TestClient_DoWithTTL(t *testing.T) {
+	conn := &argsConn{fakeConn: &fakeConn{replies: []interface{}{
+		"OK",
+		redis.Error("NOSCRIPT No matching script"), redis.Error("ERR item exists"),
+	}}}
+	c := NewClientFromPool(nil, "test", WithKeyPrefix("app:"))
+	c.Pool = &fakePool{conn: conn}
+
+	// the command and its expiry run in a single script
+	assert.Nil(t, c.ReserveWithTTL("bf", 0.01, 1000, 1500*time.Millisecond))
+	assert.Equal(t, []interface{}{expireOnSuccessScript.Hash(), 1, "app:bf", int64(1500), "BF.RESERVE", "app:bf", "0.01", uint64(1000)}, conn.args[0])
+	// failed commands are reported as is
+	assert.Equal(t, redis.Error("ERR item exists"), c.ReserveWithTTL("bf", 0.01, 1000, time.Hour))
+	assert.Equal(t, []string{"EVALSHA", "EVALSHA", "EVAL"}, conn.commands)
+
+	// PEXPIRE 0 would delete the key
+	assert.Equal(t, ErrInvalidTTL, c.ReserveWithTTL("bf", 0.01, 1000, time.Microsecond))
+	assert.Equal(t, 3, len(conn.commands))
+
+	// commands with too many arguments for the script run in a transaction
+	conn = &argsConn{fakeConn: &fakeConn{replies: []interface{}{"OK", "QUEUED", "QUEUED", []interface{}{int64(1), int64(1)}}}}
+	c.Pool = &fakePool{conn: conn}
+	reply, err := c.doWithTTL(conn, "bf", time.Minute, "BF.ADD", make([]interface{}, maxExpireOnSuccessArgs+1)...)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), reply)
+	assert.Equal(t, []string{"MULTI", "BF.ADD", "PEXPIRE", "EXEC"}, conn.commands)
+}
+
+func TestExpireOnSuccessCommand(t *testing.T) {
+	command, args, expireArgs, ok := expireOnSuccessCommand("EVALSHA",
+		[]interface{}{expireOnSuccessScript.Hash(), 1, "bf", int64(1500), "BF.ADD", "bf", "a"})
+	assert.True(t, ok)
+	assert.Equal(t, "BF.ADD", command)
+	assert.Equal(t, []interface{}{"bf", "a"}, args)
+	assert.Equal(t, []interface{}{"bf", int64(1500)}, expireArgs)
+
+	_, _, _, ok = expireOnSuccessCommand("EVALSHA", []interface{}{bfAddIncrScript.Hash(), 2, "bf", "count", "a", 1, 0})
+	assert.False(t, ok)
+	_, _, _, ok = expireOnSuccessCommand("BF.ADD", []interface{}{"bf", "a"})
+	assert.False(t, ok)
+}
@@ -0,0 +1,162 @@
+//go:build go1.18
+// +build go1.18
+
+package redis_bloom_go
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+)
+
+// ErrNotSerializable is returned by the default serializer for values that are not a string, a []byte,
+// a fmt.Stringer or an encoding.BinaryMarshaler
+var ErrNotSerializable = errors.New("redisbloom: value is not serializable, provide a Serializer")
+
+// Serializer turns a value into the item stored in a filter. Equal values must give equal items.
+type Serializer[T any] func(value T) (string, error)
+
+// DefaultSerializer - Returns the serializer of strings, []byte, fmt.Stringer and encoding.BinaryMarshaler
+// values, tried in that order
+func DefaultSerializer[T any]() Serializer[T] {
+	return func(value T) (string, error) {
+		switch v := any(value).(type) {
+		case string:
+			return v, nil
+		case []byte:
+			return string(v), nil
+		case fmt.Stringer:
+			return v.String(), nil
+		case encoding.BinaryMarshaler:
+			data, err := v.MarshalBinary()
+			return string(data), err
+		}
+		return "", ErrNotSerializable
+	}
+}
+
+// serializeAll serializes every value, stopping at the first error
+func serializeAll[T any](serialize Serializer[T], values []T) ([]string, error) {
+	items := make([]string, len(values))
+	for i, value := range values {
+		item, err := serialize(value)
+		if err != nil {
+			return nil, err
+		}
+		items[i] = item
+	}
+	return items, nil
+}
+
+// BloomSet is a bloom filter of values of type T, serialized into items by a Serializer
+type BloomSet[T any] struct {
+	client    *Client
+	key       string
+	serialize Serializer[T]
+}
+
+// NewBloomSet - Returns the typed view of the bloom filter stored at key.
+// A nil serializer selects DefaultSerializer.
+func NewBloomSet[T any](client *Client, key string, serializer Serializer[T]) *BloomSet[T] {
+	if serializer == nil {
+		serializer = DefaultSerializer[T]()
+	}
+	return &BloomSet[T]{client: client, key: key, serialize: serializer}
+}
+
+// Add - Adds value to the filter, returning false if it may already be in it
+func (s *BloomSet[T]) Add(value T) (bool, error) {
+	item, err := s.serialize(value)
+	if err != nil {
+		return false, err
+	}
+	return s.client.Add(s.key, item)
+}
+
+// Exists - Determines whether value may be in the filter
+func (s *BloomSet[T]) Exists(value T) (bool, error) {
+	item, err := s.serialize(value)
+	if err != nil {
+		return false, err
+	}
+	return s.client.Exists(s.key, item)
+}
+
+// AddMulti - Adds values to the filter. Each result is true if the corresponding value was newly added.
+func (s *BloomSet[T]) AddMulti(values []T) ([]bool, error) {
+	items, err := serializeAll(s.serialize, values)
+	if err != nil {
+		return nil, err
+	}
+	return s.client.BfAddMultiBool(s.key, items)
+}
+
+// ExistsMulti - Determines whether each value may be in the filter
+func (s *BloomSet[T]) ExistsMulti(values []T) ([]bool, error) {
+	items, err := serializeAll(s.serialize, values)
+	if err != nil {
+		return nil, err
+	}
+	return s.client.BfExistsMultiBool(s.key, items)
+}
+
+// CuckooSet is a cuckoo filter of values of type T, serialized into items by a Serializer
+type CuckooSet[T any] struct {
+	client    *Client
+	key       string
+	serialize Serializer[T]
+}
+
+// NewCuckooSet - Returns the typed view of the cuckoo filter stored at key.
+// A nil serializer selects DefaultSerializer.
+func NewCuckooSet[T any](client *Client, key string, serializer Serializer[T]) *CuckooSet[T] {
+	if serializer == nil {
+		serializer = DefaultSerializer[T]()
+	}
+	return &CuckooSet[T]{client: client, key: key, serialize: serializer}
+}
+
+// Add - Adds value to the filter, even if it may already be in it
+func (s *CuckooSet[T]) Add(value T) (bool, error) {
+	item, err := s.serialize(value)
+	if err != nil {
+		return false, err
+	}
+	return s.client.CfAdd(s.key, item)
+}
+
+// AddNx - Adds value to the filter only if it may not be in it yet
+func (s *CuckooSet[T]) AddNx(value T) (bool, error) {
+	item, err := s.serialize(value)
+	if err != nil {
+		return false, err
+	}
+	return s.client.CfAddNx(s.key, item)
+}
+
+// Exists - Determines whether value may be in the filter
+func (s *CuckooSet[T]) Exists(value T) (bool, error) {
+	item, err := s.serialize(value)
+	if err != nil {
+		return false, err
+	}
+	return s.client.CfExists(s.key, item)
+}
+
+// Delete - Deletes value once from the filter
+func (s *CuckooSet[T]) Delete(value T) (bool, error) {
+	item, err := s.serialize(value)
+	if err != nil {
+		return false, err
+	}
+	return s.client.CfDel(s.key, item)
+}
+
+// Count - Returns the number of times value may be in the filter
+func (s *CuckooSet[T]) Count(value T) (int64, error) {
+	item, err := s.serialize(value)
+	if err != nil {
+		return 0, err
+	}
+	return s.client.CfCount(s.key, item)
+}
@@ -0,0 +1,78 @@
+//go:build go1.18
+// +build go1.18
+
+package redis_bloom_go
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testStringer int
+
+func (s testStringer) String() string { return "s" + strconv.Itoa(int(s)) }
+
+type testMarshaler struct{ id byte }
+
+func (m testMarshaler) MarshalBinary() ([]byte, error) { return []byte{m.id}, nil }
+
+func TestDefaultSerializer(t *testing.T) {
+	item, err := DefaultSerializer[string]()("a")
+	assert.Nil(t, err)
+	assert.Equal(t, "a", item)
+	item, err = DefaultSerializer[[]byte]()([]byte{1, 2})
+	assert.Nil(t, err)
+	assert.Equal(t, "\x01\x02", item)
+	item, err = DefaultSerializer[testStringer]()(3)
+	assert.Nil(t, err)
+	assert.Equal(t, "s3", item)
+	item, err = DefaultSerializer[testMarshaler]()(testMarshaler{7})
+	assert.Nil(t, err)
+	assert.Equal(t, "\x07", item)
+	_, err = DefaultSerializer[int]()(1)
+	assert.Equal(t, ErrNotSerializable, err)
+}
+
+func TestBloomSet(t *testing.T) {
+	client.FlushAll()
+	set := NewBloomSet(client, "test_bloom_set", func(value int) (string, error) {
+		return strconv.Itoa(value), nil
+	})
+	added, err := set.Add(1)
+	assert.Nil(t, err)
+	assert.True(t, added)
+	results, err := set.AddMulti([]int{1, 2})
+	assert.Nil(t, err)
+	assert.Equal(t, []bool{false, true}, results)
+	exists, err := set.Exists(2)
+	assert.Nil(t, err)
+	assert.True(t, exists)
+	results, err = set.ExistsMulti([]int{2, 3})
+	assert.Nil(t, err)
+	assert.Equal(t, []bool{true, false}, results)
+
+	_, err = NewBloomSet[int](client, "test_bloom_set", nil).Add(1)
+	assert.Equal(t, ErrNotSerializable, err)
+}
+
+func TestCuckooSet(t *testing.T) {
+	client.FlushAll()
+	set := NewCuckooSet[testStringer](client, "test_cuckoo_set", nil)
+	added, err := set.AddNx(1)
+	assert.Nil(t, err)
+	assert.True(t, added)
+	added, err = set.AddNx(1)
+	assert.Nil(t, err)
+	assert.False(t, added)
+	exists, err := set.Exists(1)
+	assert.Nil(t, err)
+	assert.True(t, exists)
+	deleted, err := set.Delete(1)
+	assert.Nil(t, err)
+	assert.True(t, deleted)
+	count, err := set.Count(1)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), count)
+}
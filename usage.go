@@ -0,0 +1,188 @@
+package redis_bloom_go
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// NamespaceUsage is the memory taken by the RedisBloom keys of a namespace
+type NamespaceUsage struct {
+	Namespace string
+	// Keys is the number of RedisBloom keys of the namespace
+	Keys int64
+	// Bytes adds up the MEMORY USAGE of the keys
+	Bytes int64
+	// ByType breaks Bytes down by data structure
+	ByType map[DataType]int64
+	// Quota is the quota of the namespace in bytes, zero if it has none
+	Quota int64
+}
+
+// OverQuota reports whether the namespace has a quota and uses more than it
+func (u NamespaceUsage) OverQuota() bool {
+	return u.Quota > 0 && u.Bytes > u.Quota
+}
+
+// UsageReport is the memory usage of RedisBloom keys per namespace
+type UsageReport struct {
+	// Time is when the scan of the keys started
+	Time time.Time
+	// Namespaces are sorted by name
+	Namespaces []NamespaceUsage
+}
+
+// UsageConfig configures a UsageAccountant
+type UsageConfig struct {
+	// Pattern selects the keys to account for, "*" if empty
+	Pattern string
+	// Namespace returns the namespace a key is charged to, and false for keys to leave out.
+	// TenantNamespace if nil.
+	Namespace func(key string) (string, bool)
+	// Quotas are the maximum numbers of bytes the keys of each namespace may take
+	Quotas map[string]int64
+	// OnOverQuota, if set, is called by Report for each namespace using more than its quota
+	OnOverQuota func(usage NamespaceUsage)
+}
+
+// TenantNamespace charges the keys of a client scoped with ForTenant to the tenant id, and the other keys to
+// the empty namespace
+func TenantNamespace(key string) (string, bool) {
+	if strings.HasPrefix(key, tenantKeyPrefix) {
+		if end := strings.IndexByte(key[len(tenantKeyPrefix):], ':'); end > 0 {
+			return key[len(tenantKeyPrefix) : len(tenantKeyPrefix)+end], true
+		}
+	}
+	return "", true
+}
+
+// PrefixNamespace returns a Namespace function charging each key to the longest of prefixes it starts with,
+// and leaving out the keys matching none
+func PrefixNamespace(prefixes ...string) func(key string) (string, bool) {
+	sorted := append([]string(nil), prefixes...)
+	sort.Slice(sorted, func(i, j int) bool { return len(sorted[i]) > len(sorted[j]) })
+	return func(key string) (string, bool) {
+		for _, prefix := range sorted {
+			if strings.HasPrefix(key, prefix) {
+				return prefix, true
+			}
+		}
+		return "", false
+	}
+}
+
+// UsageAccountant adds up the memory taken by RedisBloom keys per namespace, e.g. per team or tenant,
+// to charge it back and cap it
+type UsageAccountant struct {
+	client *Client
+	config UsageConfig
+}
+
+// NewUsageAccountant - Returns an accountant of the keys of client, relative to its key prefix
+func NewUsageAccountant(client *Client, config UsageConfig) *UsageAccountant {
+	if config.Pattern == "" {
+		config.Pattern = "*"
+	}
+	if config.Namespace == nil {
+		config.Namespace = TenantNamespace
+	}
+	return &UsageAccountant{client: client, config: config}
+}
+
+// Report - Scans the keys, adds up the MEMORY USAGE of the RedisBloom ones per namespace, and calls
+// OnOverQuota for the namespaces over their quota. Namespaces with a quota are reported even without keys.
+// Keys holding other types are left out.
+func (a *UsageAccountant) Report(ctx context.Context) (*UsageReport, error) {
+	report := &UsageReport{Time: time.Now()}
+	usages := make(map[string]*NamespaceUsage)
+	usage := func(namespace string) *NamespaceUsage {
+		u, ok := usages[namespace]
+		if !ok {
+			u = &NamespaceUsage{Namespace: namespace, ByType: make(map[DataType]int64), Quota: a.config.Quotas[namespace]}
+			usages[namespace] = u
+		}
+		return u
+	}
+	for namespace := range a.config.Quotas {
+		usage(namespace)
+	}
+	batch := make([]string, 0, scanBatchSize)
+	account := func() error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		err := a.account(batch, usage)
+		batch = batch[:0]
+		return err
+	}
+	err := a.client.scanKeys(a.config.Pattern, func(key string) error {
+		batch = append(batch, key)
+		if len(batch) < scanBatchSize {
+			return nil
+		}
+		return account()
+	})
+	if err == nil && len(batch) > 0 {
+		err = account()
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range usages {
+		report.Namespaces = append(report.Namespaces, *u)
+	}
+	sort.Slice(report.Namespaces, func(i, j int) bool { return report.Namespaces[i].Namespace < report.Namespaces[j].Namespace })
+	if a.config.OnOverQuota != nil {
+		for _, u := range report.Namespaces {
+			if u.OverQuota() {
+				a.config.OnOverQuota(u)
+			}
+		}
+	}
+	return report, nil
+}
+
+// account pipelines TYPE and MEMORY USAGE for keys and charges the RedisBloom ones to their namespace
+func (a *UsageAccountant) account(keys []string, usage func(namespace string) *NamespaceUsage) error {
+	conn := a.client.Pool.Get()
+	defer conn.Close()
+	for _, key := range keys {
+		if err := conn.Send("TYPE", a.client.key(key)); err != nil {
+			return err
+		}
+		if err := conn.Send("MEMORY", "USAGE", a.client.key(key)); err != nil {
+			return err
+		}
+	}
+	if err := conn.Flush(); err != nil {
+		return err
+	}
+	var outErr error
+	for _, key := range keys {
+		keyType, err := redis.String(conn.Receive())
+		if err != nil && outErr == nil {
+			outErr = err
+		}
+		bytes, err := redis.Int64(conn.Receive())
+		// keys deleted since they were scanned reply nil
+		if err != nil && err != redis.ErrNil && outErr == nil {
+			outErr = err
+		}
+		dataType, ok := dataTypes[keyType]
+		if !ok || outErr != nil {
+			continue
+		}
+		namespace, ok := a.config.Namespace(key)
+		if !ok {
+			continue
+		}
+		u := usage(namespace)
+		u.Keys++
+		u.Bytes += bytes
+		u.ByType[dataType] += bytes
+	}
+	return outErr
+}
@@ -0,0 +1,63 @@
+package redis_bloom_go
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTenantNamespace(t *testing.T) {
+	namespace, ok := TenantNamespace("tenant:acme:seen")
+	assert.True(t, ok)
+	assert.Equal(t, "acme", namespace)
+	namespace, ok = TenantNamespace("seen")
+	assert.True(t, ok)
+	assert.Equal(t, "", namespace)
+	namespace, _ = TenantNamespace("tenant:")
+	assert.Equal(t, "", namespace)
+}
+
+func TestPrefixNamespace(t *testing.T) {
+	namespace := PrefixNamespace("team:", "team:search:")
+	prefix, ok := namespace("team:search:seen")
+	assert.True(t, ok)
+	assert.Equal(t, "team:search:", prefix)
+	prefix, ok = namespace("team:ads:seen")
+	assert.True(t, ok)
+	assert.Equal(t, "team:", prefix)
+	_, ok = namespace("other")
+	assert.False(t, ok)
+}
+
+func TestUsageAccountant_Report(t *testing.T) {
+	client.FlushAll()
+	acme, _ := client.ForTenant("acme")
+	globex, _ := client.ForTenant("globex")
+	acme.Reserve("seen", 0.001, 100000)
+	acme.CmsInitByDim("counts", 1000, 5)
+	globex.Reserve("seen", 0.01, 1000)
+	client.Reserve("shared", 0.01, 1000)
+	conn := client.Pool.Get()
+	conn.Do("SET", "plain", "value")
+	conn.Close()
+
+	var over []NamespaceUsage
+	accountant := NewUsageAccountant(client, UsageConfig{
+		Quotas:      map[string]int64{"acme": 1024, "globex": 1 << 30, "initech": 1 << 20},
+		OnOverQuota: func(usage NamespaceUsage) { over = append(over, usage) },
+	})
+	report, err := accountant.Report(context.Background())
+	assert.Nil(t, err)
+	assert.Len(t, report.Namespaces, 4)
+	assert.Equal(t, "", report.Namespaces[0].Namespace)
+	assert.Equal(t, int64(1), report.Namespaces[0].Keys)
+	assert.Equal(t, "acme", report.Namespaces[1].Namespace)
+	assert.Equal(t, int64(2), report.Namespaces[1].Keys)
+	assert.True(t, report.Namespaces[1].ByType[DataTypeBloom] > 0)
+	assert.True(t, report.Namespaces[1].ByType[DataTypeCMS] > 0)
+	assert.Equal(t, "initech", report.Namespaces[3].Namespace)
+	assert.Equal(t, int64(0), report.Namespaces[3].Keys)
+	assert.Len(t, over, 1)
+	assert.Equal(t, "acme", over[0].Namespace)
+}
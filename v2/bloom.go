@@ -0,0 +1,54 @@
+package redisbloom
+
+import (
+	"context"
+
+	"github.com/mohit-doubtnut/redisbloom-go/v2/bloom"
+)
+
+// BfReserveOptions are the parameters of a bloom filter
+type BfReserveOptions = bloom.ReserveOptions
+
+// BfInsertOptions are the parameters of the bloom filter BfInsert creates if it does not exist
+type BfInsertOptions = bloom.InsertOptions
+
+// BloomInfo is the reply of BF.INFO
+type BloomInfo = bloom.Info
+
+// BfReserve - Creates an empty bloom filter. Fails with ErrKeyExists if key exists.
+func (c *Client) BfReserve(ctx context.Context, key string, opts BfReserveOptions) error {
+	return c.bloom.Reserve(ctx, key, opts)
+}
+
+// BfAdd - Adds item to the bloom filter, creating it with the server defaults if it does not exist.
+// Returns false if the item may have been added before.
+func (c *Client) BfAdd(ctx context.Context, key string, item string) (bool, error) {
+	return c.bloom.Add(ctx, key, item)
+}
+
+// BfMAdd - Adds items to the bloom filter, creating it with the server defaults if it does not exist.
+// Each result is false if the corresponding item may have been added before.
+func (c *Client) BfMAdd(ctx context.Context, key string, items ...string) ([]bool, error) {
+	return c.bloom.MAdd(ctx, key, items...)
+}
+
+// BfInsert - Adds items to the bloom filter, creating it with opts if it does not exist.
+// Each result is false if the corresponding item may have been added before.
+func (c *Client) BfInsert(ctx context.Context, key string, opts BfInsertOptions, items ...string) ([]bool, error) {
+	return c.bloom.Insert(ctx, key, opts, items...)
+}
+
+// BfExists - Reports whether item may have been added to the bloom filter
+func (c *Client) BfExists(ctx context.Context, key string, item string) (bool, error) {
+	return c.bloom.Exists(ctx, key, item)
+}
+
+// BfMExists - Reports, for each of items, whether it may have been added to the bloom filter
+func (c *Client) BfMExists(ctx context.Context, key string, items ...string) ([]bool, error) {
+	return c.bloom.MExists(ctx, key, items...)
+}
+
+// BfInfo - Returns the parameters and the fill of the bloom filter
+func (c *Client) BfInfo(ctx context.Context, key string) (*BloomInfo, error) {
+	return c.bloom.Info(ctx, key)
+}
@@ -0,0 +1,142 @@
+// Package bloom runs the RedisBloom bloom filter commands
+package bloom
+
+import (
+	"context"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/mohit-doubtnut/redisbloom-go/v2/internal/core"
+)
+
+// Pool is the source of the connections of a Client, e.g. a *redis.Pool
+type Pool = core.Pool
+
+// Options configures a Client
+type Options = core.Options
+
+// ReserveOptions are the parameters of a bloom filter
+type ReserveOptions struct {
+	// ErrorRate is the desired probability of false positives, required
+	ErrorRate float64
+	// Capacity is the number of items the filter is sized for, required
+	Capacity int64
+	// Expansion is the growth factor of the sub-filters added once the filter is full, the server default if zero
+	Expansion int64
+	// NonScaling makes the filter fail to add items once full instead of adding a sub-filter
+	NonScaling bool
+	// ExtraArgs are sent after the other optional arguments, e.g. to pass flags of a RedisBloom release newer
+	// than this client
+	ExtraArgs []interface{}
+}
+
+// InsertOptions are the parameters of the bloom filter Insert creates if it does not exist
+type InsertOptions struct {
+	// ErrorRate and Capacity are the server defaults if zero
+	ErrorRate  float64
+	Capacity   int64
+	Expansion  int64
+	NonScaling bool
+	// NoCreate makes Insert fail with ErrKeyNotFound instead of creating the filter
+	NoCreate bool
+	// ExtraArgs are sent after the other optional arguments, e.g. to pass flags of a RedisBloom release newer
+	// than this client
+	ExtraArgs []interface{}
+}
+
+// Info is the reply of BF.INFO
+type Info struct {
+	Capacity      int64
+	Size          int64
+	Filters       int64
+	Items         int64
+	ExpansionRate int64
+}
+
+// Client runs the bloom filter commands. It is safe for concurrent use.
+type Client struct {
+	exec *core.Executor
+}
+
+// NewClient - Returns a client running its commands on the connections of pool
+func NewClient(pool Pool, opts Options) *Client {
+	return New(core.NewExecutor(pool, opts))
+}
+
+// New returns a client running its commands with exec
+func New(exec *core.Executor) *Client {
+	return &Client{exec: exec}
+}
+
+// Reserve - Creates an empty bloom filter. Fails with ErrKeyExists if key exists.
+func (c *Client) Reserve(ctx context.Context, key string, opts ReserveOptions) error {
+	args := []interface{}{core.FormatFloat(opts.ErrorRate), opts.Capacity}
+	if opts.Expansion > 0 {
+		args = append(args, "EXPANSION", opts.Expansion)
+	}
+	if opts.NonScaling {
+		args = append(args, "NONSCALING")
+	}
+	args = append(args, opts.ExtraArgs...)
+	_, err := c.exec.Do(ctx, "BF.RESERVE", key, args...)
+	return err
+}
+
+// Add - Adds item to the bloom filter, creating it with the server defaults if it does not exist.
+// Returns false if the item may have been added before.
+func (c *Client) Add(ctx context.Context, key string, item string) (bool, error) {
+	return redis.Bool(c.exec.Do(ctx, "BF.ADD", key, item))
+}
+
+// MAdd - Adds items to the bloom filter, creating it with the server defaults if it does not exist.
+// Each result is false if the corresponding item may have been added before.
+func (c *Client) MAdd(ctx context.Context, key string, items ...string) ([]bool, error) {
+	return core.Bools(c.exec.Do(ctx, "BF.MADD", key, redis.Args{}.AddFlat(items)...))
+}
+
+// Insert - Adds items to the bloom filter, creating it with opts if it does not exist.
+// Each result is false if the corresponding item may have been added before.
+func (c *Client) Insert(ctx context.Context, key string, opts InsertOptions, items ...string) ([]bool, error) {
+	args := redis.Args{}
+	if opts.Capacity > 0 {
+		args = args.Add("CAPACITY", opts.Capacity)
+	}
+	if opts.ErrorRate > 0 {
+		args = args.Add("ERROR", core.FormatFloat(opts.ErrorRate))
+	}
+	if opts.Expansion > 0 {
+		args = args.Add("EXPANSION", opts.Expansion)
+	}
+	if opts.NoCreate {
+		args = args.Add("NOCREATE")
+	}
+	if opts.NonScaling {
+		args = args.Add("NONSCALING")
+	}
+	args = append(args, opts.ExtraArgs...)
+	return core.Bools(c.exec.Do(ctx, "BF.INSERT", key, args.Add("ITEMS").AddFlat(items)...))
+}
+
+// Exists - Reports whether item may have been added to the bloom filter
+func (c *Client) Exists(ctx context.Context, key string, item string) (bool, error) {
+	return redis.Bool(c.exec.Do(ctx, "BF.EXISTS", key, item))
+}
+
+// MExists - Reports, for each of items, whether it may have been added to the bloom filter
+func (c *Client) MExists(ctx context.Context, key string, items ...string) ([]bool, error) {
+	return core.Bools(c.exec.Do(ctx, "BF.MEXISTS", key, redis.Args{}.AddFlat(items)...))
+}
+
+// Info - Returns the parameters and the fill of the bloom filter
+func (c *Client) Info(ctx context.Context, key string) (*Info, error) {
+	info, err := core.ParseInfo(c.exec.Do(ctx, "BF.INFO", key))
+	if err != nil {
+		return nil, err
+	}
+	return &Info{
+		Capacity:      info.Int("Capacity"),
+		Size:          info.Int("Size"),
+		Filters:       info.Int("Number of filters"),
+		Items:         info.Int("Number of items inserted"),
+		ExpansionRate: info.Int("Expansion rate"),
+	}, nil
+}
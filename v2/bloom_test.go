@@ -0,0 +1,40 @@
+package redisbloom
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_Bloom(t *testing.T) {
+	flushAll()
+	key := "test_bf"
+	assert.Nil(t, client.BfReserve(ctx, key, BfReserveOptions{ErrorRate: 0.01, Capacity: 1000, Expansion: 2}))
+	assert.True(t, errors.Is(client.BfReserve(ctx, key, BfReserveOptions{ErrorRate: 0.01, Capacity: 1000}), ErrKeyExists))
+
+	added, err := client.BfAdd(ctx, key, "a")
+	assert.Nil(t, err)
+	assert.True(t, added)
+	addedMulti, err := client.BfMAdd(ctx, key, "a", "b")
+	assert.Nil(t, err)
+	assert.Equal(t, []bool{false, true}, addedMulti)
+	exists, err := client.BfExists(ctx, key, "b")
+	assert.Nil(t, err)
+	assert.True(t, exists)
+	existsMulti, err := client.BfMExists(ctx, key, "a", "c")
+	assert.Nil(t, err)
+	assert.Equal(t, []bool{true, false}, existsMulti)
+
+	info, err := client.BfInfo(ctx, key)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1000), info.Capacity)
+	assert.Equal(t, int64(2), info.Items)
+	assert.Equal(t, int64(2), info.ExpansionRate)
+
+	_, err = client.BfInsert(ctx, "test_bf_missing", BfInsertOptions{NoCreate: true}, "a")
+	assert.True(t, errors.Is(err, ErrKeyNotFound))
+	inserted, err := client.BfInsert(ctx, "test_bf_insert", BfInsertOptions{Capacity: 100, ErrorRate: 0.001, NonScaling: true}, "a", "a")
+	assert.Nil(t, err)
+	assert.Equal(t, []bool{true, false}, inserted)
+}
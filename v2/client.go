@@ -0,0 +1,73 @@
+// Package redisbloom is a client for the RedisBloom module: bloom and cuckoo filters, count-min sketches,
+// top-k and t-digests.
+//
+// It is the second major version of github.com/mohit-doubtnut/redisbloom-go, which stays available unchanged.
+// Every command is a method of Client prefixed by its data structure (Bf, Cf, Cms, Topk and Td), takes a
+// context, returns typed results, takes its optional arguments as an options struct, and returns errors which
+// can be matched with errors.Is against ErrKeyNotFound, ErrKeyExists and ErrWrongType.
+// The t-digest commands use the syntax of RedisBloom 2.4 and newer.
+//
+// Client gathers the clients of the subpackages bloom, cuckoo, cms, topk and tdigest, which can also be used on
+// their own to depend on a single data structure.
+package redisbloom
+
+import (
+	"github.com/mohit-doubtnut/redisbloom-go/v2/bloom"
+	"github.com/mohit-doubtnut/redisbloom-go/v2/cms"
+	"github.com/mohit-doubtnut/redisbloom-go/v2/cuckoo"
+	"github.com/mohit-doubtnut/redisbloom-go/v2/internal/core"
+	"github.com/mohit-doubtnut/redisbloom-go/v2/tdigest"
+	"github.com/mohit-doubtnut/redisbloom-go/v2/topk"
+)
+
+// Pool is the source of the connections of a Client, e.g. a *redis.Pool
+type Pool = core.Pool
+
+// Options configures a Client
+type Options = core.Options
+
+// Client runs RedisBloom commands on the connections of a pool. It is safe for concurrent use.
+type Client struct {
+	bloom   *bloom.Client
+	cuckoo  *cuckoo.Client
+	cms     *cms.Client
+	topk    *topk.Client
+	tdigest *tdigest.Client
+}
+
+// NewClient - Returns a client running its commands on the connections of pool
+func NewClient(pool Pool, opts Options) *Client {
+	exec := core.NewExecutor(pool, opts)
+	return &Client{
+		bloom:   bloom.New(exec),
+		cuckoo:  cuckoo.New(exec),
+		cms:     cms.New(exec),
+		topk:    topk.New(exec),
+		tdigest: tdigest.New(exec),
+	}
+}
+
+// Bloom returns the client of the bloom filter commands, sharing the pool and options of c
+func (c *Client) Bloom() *bloom.Client {
+	return c.bloom
+}
+
+// Cuckoo returns the client of the cuckoo filter commands, sharing the pool and options of c
+func (c *Client) Cuckoo() *cuckoo.Client {
+	return c.cuckoo
+}
+
+// CMS returns the client of the count-min sketch commands, sharing the pool and options of c
+func (c *Client) CMS() *cms.Client {
+	return c.cms
+}
+
+// TopK returns the client of the top-k commands, sharing the pool and options of c
+func (c *Client) TopK() *topk.Client {
+	return c.topk
+}
+
+// TDigest returns the client of the t-digest commands, sharing the pool and options of c
+func (c *Client) TDigest() *tdigest.Client {
+	return c.tdigest
+}
@@ -0,0 +1,109 @@
+package redisbloom
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+func getTestConnectionDetails() (string, string) {
+	host := "localhost:6379"
+	if value, exists := os.LookupEnv("REDISBLOOM_TEST_HOST"); exists && value != "" {
+		host = value
+	}
+	return host, os.Getenv("REDISBLOOM_TEST_PASSWORD")
+}
+
+var testPool = func() *redis.Pool {
+	host, password := getTestConnectionDetails()
+	return &redis.Pool{Dial: func() (redis.Conn, error) {
+		return redis.Dial("tcp", host, redis.DialPassword(password))
+	}, MaxIdle: 10}
+}()
+
+var client = NewClient(testPool, Options{})
+
+var ctx = context.Background()
+
+func flushAll() {
+	conn := testPool.Get()
+	defer conn.Close()
+	conn.Do("FLUSHALL")
+}
+
+// fakeConn replies to commands with canned replies, in order, recording the arguments it receives
+type fakeConn struct {
+	replies []interface{}
+	args    [][]interface{}
+}
+
+func (c *fakeConn) Close() error                      { return nil }
+func (c *fakeConn) Err() error                        { return nil }
+func (c *fakeConn) Flush() error                      { return nil }
+func (c *fakeConn) Send(string, ...interface{}) error { return nil }
+func (c *fakeConn) Receive() (interface{}, error)     { return nil, nil }
+
+func (c *fakeConn) Do(command string, args ...interface{}) (interface{}, error) {
+	c.args = append(c.args, append([]interface{}{command}, args...))
+	reply := c.replies[0]
+	c.replies = c.replies[1:]
+	if err, ok := reply.(redis.Error); ok {
+		return nil, err
+	}
+	return reply, nil
+}
+
+// fakePool hands out the same fakeConn
+type fakePool struct {
+	conn *fakeConn
+}
+
+func (p *fakePool) GetContext(ctx context.Context) (redis.Conn, error) {
+	return p.conn, nil
+}
+
+func TestClient_KeyPrefix(t *testing.T) {
+	conn := &fakeConn{replies: []interface{}{int64(1), "OK"}}
+	c := NewClient(&fakePool{conn: conn}, Options{KeyPrefix: "svc:"})
+	added, err := c.BfAdd(ctx, "seen", "a")
+	assert.Nil(t, err)
+	assert.True(t, added)
+	assert.Nil(t, c.CmsMerge(ctx, "total", []string{"a", "b"}, CmsMergeOptions{Weights: []int64{1, 2}}))
+	assert.Equal(t, []interface{}{"BF.ADD", "svc:seen", "a"}, conn.args[0])
+	assert.Equal(t, []interface{}{"CMS.MERGE", "svc:total", 2, "svc:a", "svc:b", "WEIGHTS", int64(1), int64(2)}, conn.args[1])
+
+	assert.NotNil(t, c.CmsMerge(ctx, "total", []string{"a", "b"}, CmsMergeOptions{Weights: []int64{1}}))
+	assert.Len(t, conn.args, 2)
+}
+
+func TestClient_ExtraArgs(t *testing.T) {
+	conn := &fakeConn{replies: []interface{}{[]interface{}{int64(1)}, "OK"}}
+	c := NewClient(&fakePool{conn: conn}, Options{})
+	_, err := c.BfInsert(ctx, "seen", BfInsertOptions{Capacity: 100, ExtraArgs: []interface{}{"NEWFLAG", 3}}, "a")
+	assert.Nil(t, err)
+	assert.Nil(t, c.TopkReserve(ctx, "top", TopkReserveOptions{K: 10, ExtraArgs: []interface{}{"NEWFLAG"}}))
+	assert.Equal(t, []interface{}{"BF.INSERT", "seen", "CAPACITY", int64(100), "NEWFLAG", 3, "ITEMS", "a"}, conn.args[0])
+	assert.Equal(t, []interface{}{"TOPK.RESERVE", "top", int64(10), "NEWFLAG"}, conn.args[1])
+}
+
+func TestClient_CanceledContext(t *testing.T) {
+	conn := &fakeConn{}
+	c := NewClient(&fakePool{conn: conn}, Options{})
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err := c.BfExists(canceled, "seen", "a")
+	assert.Equal(t, context.Canceled, err)
+	assert.Empty(t, conn.args)
+}
+
+func TestClient_Subclients(t *testing.T) {
+	conn := &fakeConn{replies: []interface{}{[]interface{}{int64(1)}}}
+	c := NewClient(&fakePool{conn: conn}, Options{KeyPrefix: "svc:"})
+	exists, err := c.Cuckoo().MExists(ctx, "seen", "a")
+	assert.Nil(t, err)
+	assert.Equal(t, []bool{true}, exists)
+	assert.Equal(t, []interface{}{"CF.MEXISTS", "svc:seen", "a"}, conn.args[0])
+}
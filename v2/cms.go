@@ -0,0 +1,49 @@
+package redisbloom
+
+import (
+	"context"
+
+	"github.com/mohit-doubtnut/redisbloom-go/v2/cms"
+)
+
+// CmsIncrement is an item and the amount its count is increased by
+type CmsIncrement = cms.Increment
+
+// CmsMergeOptions are the optional arguments of CmsMerge
+type CmsMergeOptions = cms.MergeOptions
+
+// CMSInfo is the reply of CMS.INFO
+type CMSInfo = cms.Info
+
+// CmsInitByDim - Creates a count-min sketch of width counters per row and depth rows.
+// Fails with ErrKeyExists if key exists.
+func (c *Client) CmsInitByDim(ctx context.Context, key string, width int64, depth int64) error {
+	return c.cms.InitByDim(ctx, key, width, depth)
+}
+
+// CmsInitByProb - Creates a count-min sketch whose estimates exceed the true counts by more than errorRate times
+// the total count with a probability of at most probability. Fails with ErrKeyExists if key exists.
+func (c *Client) CmsInitByProb(ctx context.Context, key string, errorRate float64, probability float64) error {
+	return c.cms.InitByProb(ctx, key, errorRate, probability)
+}
+
+// CmsIncrBy - Increases the counts of items and returns their new estimates, in the order of increments
+func (c *Client) CmsIncrBy(ctx context.Context, key string, increments ...CmsIncrement) ([]int64, error) {
+	return c.cms.IncrBy(ctx, key, increments...)
+}
+
+// CmsQuery - Returns the estimated counts of items, in their order
+func (c *Client) CmsQuery(ctx context.Context, key string, items ...string) ([]int64, error) {
+	return c.cms.Query(ctx, key, items...)
+}
+
+// CmsMerge - Overwrites the count-min sketch dest, which must exist, with the sum of the sources, which must have
+// its dimensions
+func (c *Client) CmsMerge(ctx context.Context, dest string, sources []string, opts CmsMergeOptions) error {
+	return c.cms.Merge(ctx, dest, sources, opts)
+}
+
+// CmsInfo - Returns the dimensions and the total count of the count-min sketch
+func (c *Client) CmsInfo(ctx context.Context, key string) (*CMSInfo, error) {
+	return c.cms.Info(ctx, key)
+}
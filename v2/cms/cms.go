@@ -0,0 +1,106 @@
+// Package cms runs the RedisBloom count-min sketch commands
+package cms
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/mohit-doubtnut/redisbloom-go/v2/internal/core"
+)
+
+// Pool is the source of the connections of a Client, e.g. a *redis.Pool
+type Pool = core.Pool
+
+// Options configures a Client
+type Options = core.Options
+
+// Increment is an item and the amount its count is increased by
+type Increment struct {
+	Item  string
+	Count int64
+}
+
+// MergeOptions are the optional arguments of Merge
+type MergeOptions struct {
+	// Weights multiply the counts of the corresponding sources, 1 for all of them if nil
+	Weights []int64
+	// ExtraArgs are sent after the other optional arguments, e.g. to pass flags of a RedisBloom release newer
+	// than this client
+	ExtraArgs []interface{}
+}
+
+// Info is the reply of CMS.INFO
+type Info struct {
+	Width int64
+	Depth int64
+	// Count is the total of the increments
+	Count int64
+}
+
+// Client runs the count-min sketch commands. It is safe for concurrent use.
+type Client struct {
+	exec *core.Executor
+}
+
+// NewClient - Returns a client running its commands on the connections of pool
+func NewClient(pool Pool, opts Options) *Client {
+	return New(core.NewExecutor(pool, opts))
+}
+
+// New returns a client running its commands with exec
+func New(exec *core.Executor) *Client {
+	return &Client{exec: exec}
+}
+
+// InitByDim - Creates a count-min sketch of width counters per row and depth rows.
+// Fails with ErrKeyExists if key exists.
+func (c *Client) InitByDim(ctx context.Context, key string, width int64, depth int64) error {
+	_, err := c.exec.Do(ctx, "CMS.INITBYDIM", key, width, depth)
+	return err
+}
+
+// InitByProb - Creates a count-min sketch whose estimates exceed the true counts by more than errorRate times
+// the total count with a probability of at most probability. Fails with ErrKeyExists if key exists.
+func (c *Client) InitByProb(ctx context.Context, key string, errorRate float64, probability float64) error {
+	_, err := c.exec.Do(ctx, "CMS.INITBYPROB", key, core.FormatFloat(errorRate), core.FormatFloat(probability))
+	return err
+}
+
+// IncrBy - Increases the counts of items and returns their new estimates, in the order of increments
+func (c *Client) IncrBy(ctx context.Context, key string, increments ...Increment) ([]int64, error) {
+	args := make([]interface{}, 0, 2*len(increments))
+	for _, increment := range increments {
+		args = append(args, increment.Item, increment.Count)
+	}
+	return redis.Int64s(c.exec.Do(ctx, "CMS.INCRBY", key, args...))
+}
+
+// Query - Returns the estimated counts of items, in their order
+func (c *Client) Query(ctx context.Context, key string, items ...string) ([]int64, error) {
+	return redis.Int64s(c.exec.Do(ctx, "CMS.QUERY", key, redis.Args{}.AddFlat(items)...))
+}
+
+// Merge - Overwrites the count-min sketch dest, which must exist, with the sum of the sources, which must have
+// its dimensions
+func (c *Client) Merge(ctx context.Context, dest string, sources []string, opts MergeOptions) error {
+	args := redis.Args{len(sources)}.AddFlat(c.exec.Keys(sources))
+	if opts.Weights != nil {
+		if len(opts.Weights) != len(sources) {
+			return errors.New("redisbloom: CMS.MERGE expects a weight per source")
+		}
+		args = args.Add("WEIGHTS").AddFlat(opts.Weights)
+	}
+	args = append(args, opts.ExtraArgs...)
+	_, err := c.exec.Do(ctx, "CMS.MERGE", dest, args...)
+	return err
+}
+
+// Info - Returns the dimensions and the total count of the count-min sketch
+func (c *Client) Info(ctx context.Context, key string) (*Info, error) {
+	info, err := core.ParseInfo(c.exec.Do(ctx, "CMS.INFO", key))
+	if err != nil {
+		return nil, err
+	}
+	return &Info{Width: info.Int("width"), Depth: info.Int("depth"), Count: info.Int("count")}, nil
+}
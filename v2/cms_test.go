@@ -0,0 +1,33 @@
+package redisbloom
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_CMS(t *testing.T) {
+	flushAll()
+	assert.Nil(t, client.CmsInitByDim(ctx, "test_cms_a", 1000, 5))
+	assert.Nil(t, client.CmsInitByProb(ctx, "test_cms_b", 0.002, 0.01))
+	assert.True(t, errors.Is(client.CmsInitByDim(ctx, "test_cms_a", 1000, 5), ErrKeyExists))
+
+	counts, err := client.CmsIncrBy(ctx, "test_cms_a", CmsIncrement{Item: "x", Count: 3}, CmsIncrement{Item: "y", Count: 1})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{3, 1}, counts)
+	_, err = client.CmsIncrBy(ctx, "test_cms_b", CmsIncrement{Item: "x", Count: 2})
+	assert.Nil(t, err)
+
+	assert.Nil(t, client.CmsInitByDim(ctx, "test_cms_total", 1000, 5))
+	// test_cms_b has a depth of 7
+	assert.NotNil(t, client.CmsMerge(ctx, "test_cms_total", []string{"test_cms_a", "test_cms_b"}, CmsMergeOptions{}))
+	assert.Nil(t, client.CmsMerge(ctx, "test_cms_total", []string{"test_cms_a", "test_cms_a"}, CmsMergeOptions{Weights: []int64{1, 2}}))
+	counts, err = client.CmsQuery(ctx, "test_cms_total", "x", "z")
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{9, 0}, counts)
+
+	info, err := client.CmsInfo(ctx, "test_cms_total")
+	assert.Nil(t, err)
+	assert.Equal(t, CMSInfo{Width: 1000, Depth: 5, Count: 12}, *info)
+}
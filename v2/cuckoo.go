@@ -0,0 +1,82 @@
+package redisbloom
+
+import (
+	"context"
+
+	"github.com/mohit-doubtnut/redisbloom-go/v2/cuckoo"
+)
+
+// InsertResult is the outcome of inserting an item in a cuckoo filter
+type InsertResult = cuckoo.InsertResult
+
+// Outcomes of CfInsert and CfInsertNx
+const (
+	// InsertFull is returned when the filter is full and cannot take the item
+	InsertFull = cuckoo.InsertFull
+	// InsertExists is returned by CfInsertNx when the item may have been added before
+	InsertExists = cuckoo.InsertExists
+	// InsertAdded is returned when the item was added
+	InsertAdded = cuckoo.InsertAdded
+)
+
+// CfReserveOptions are the parameters of a cuckoo filter
+type CfReserveOptions = cuckoo.ReserveOptions
+
+// CfInsertOptions are the parameters of the cuckoo filter CfInsert and CfInsertNx create if it does not exist
+type CfInsertOptions = cuckoo.InsertOptions
+
+// CuckooInfo is the reply of CF.INFO
+type CuckooInfo = cuckoo.Info
+
+// CfReserve - Creates an empty cuckoo filter. Fails with ErrKeyExists if key exists.
+func (c *Client) CfReserve(ctx context.Context, key string, opts CfReserveOptions) error {
+	return c.cuckoo.Reserve(ctx, key, opts)
+}
+
+// CfAdd - Adds item to the cuckoo filter, creating it with the server defaults if it does not exist.
+// An item can be added several times.
+func (c *Client) CfAdd(ctx context.Context, key string, item string) error {
+	return c.cuckoo.Add(ctx, key, item)
+}
+
+// CfAddNx - Adds item to the cuckoo filter, creating it with the server defaults if it does not exist,
+// unless it may have been added before, in which case it returns false
+func (c *Client) CfAddNx(ctx context.Context, key string, item string) (bool, error) {
+	return c.cuckoo.AddNx(ctx, key, item)
+}
+
+// CfInsert - Adds items to the cuckoo filter, creating it with opts if it does not exist
+func (c *Client) CfInsert(ctx context.Context, key string, opts CfInsertOptions, items ...string) ([]InsertResult, error) {
+	return c.cuckoo.Insert(ctx, key, opts, items...)
+}
+
+// CfInsertNx - Adds the items which may not have been added before to the cuckoo filter, creating it
+// with opts if it does not exist
+func (c *Client) CfInsertNx(ctx context.Context, key string, opts CfInsertOptions, items ...string) ([]InsertResult, error) {
+	return c.cuckoo.InsertNx(ctx, key, opts, items...)
+}
+
+// CfExists - Reports whether item may have been added to the cuckoo filter
+func (c *Client) CfExists(ctx context.Context, key string, item string) (bool, error) {
+	return c.cuckoo.Exists(ctx, key, item)
+}
+
+// CfMExists - Reports, for each of items, whether it may have been added to the cuckoo filter
+func (c *Client) CfMExists(ctx context.Context, key string, items ...string) ([]bool, error) {
+	return c.cuckoo.MExists(ctx, key, items...)
+}
+
+// CfDel - Deletes an occurrence of item from the cuckoo filter. Returns false if it was not found.
+func (c *Client) CfDel(ctx context.Context, key string, item string) (bool, error) {
+	return c.cuckoo.Del(ctx, key, item)
+}
+
+// CfCount - Returns an estimate of the number of times item was added to the cuckoo filter
+func (c *Client) CfCount(ctx context.Context, key string, item string) (int64, error) {
+	return c.cuckoo.Count(ctx, key, item)
+}
+
+// CfInfo - Returns the parameters and the fill of the cuckoo filter
+func (c *Client) CfInfo(ctx context.Context, key string) (*CuckooInfo, error) {
+	return c.cuckoo.Info(ctx, key)
+}
@@ -0,0 +1,178 @@
+// Package cuckoo runs the RedisBloom cuckoo filter commands
+package cuckoo
+
+import (
+	"context"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/mohit-doubtnut/redisbloom-go/v2/internal/core"
+)
+
+// Pool is the source of the connections of a Client, e.g. a *redis.Pool
+type Pool = core.Pool
+
+// Options configures a Client
+type Options = core.Options
+
+// InsertResult is the outcome of inserting an item in a cuckoo filter
+type InsertResult int64
+
+// Outcomes of Insert and InsertNx
+const (
+	// InsertFull is returned when the filter is full and cannot take the item
+	InsertFull InsertResult = -1
+	// InsertExists is returned by InsertNx when the item may have been added before
+	InsertExists InsertResult = 0
+	// InsertAdded is returned when the item was added
+	InsertAdded InsertResult = 1
+)
+
+// ReserveOptions are the parameters of a cuckoo filter
+type ReserveOptions struct {
+	// Capacity is the number of items the filter is sized for, required
+	Capacity int64
+	// BucketSize, MaxIterations and Expansion are the server defaults if zero
+	BucketSize    int64
+	MaxIterations int64
+	Expansion     int64
+	// ExtraArgs are sent after the other optional arguments, e.g. to pass flags of a RedisBloom release newer
+	// than this client
+	ExtraArgs []interface{}
+}
+
+// InsertOptions are the parameters of the cuckoo filter Insert and InsertNx create if it does not exist
+type InsertOptions struct {
+	// Capacity is the server default if zero
+	Capacity int64
+	// NoCreate makes the insert fail with ErrKeyNotFound instead of creating the filter
+	NoCreate bool
+	// ExtraArgs are sent after the other optional arguments, e.g. to pass flags of a RedisBloom release newer
+	// than this client
+	ExtraArgs []interface{}
+}
+
+// Info is the reply of CF.INFO
+type Info struct {
+	Size          int64
+	Buckets       int64
+	Filters       int64
+	Inserted      int64
+	Deleted       int64
+	BucketSize    int64
+	ExpansionRate int64
+	MaxIterations int64
+}
+
+// Client runs the cuckoo filter commands. It is safe for concurrent use.
+type Client struct {
+	exec *core.Executor
+}
+
+// NewClient - Returns a client running its commands on the connections of pool
+func NewClient(pool Pool, opts Options) *Client {
+	return New(core.NewExecutor(pool, opts))
+}
+
+// New returns a client running its commands with exec
+func New(exec *core.Executor) *Client {
+	return &Client{exec: exec}
+}
+
+// Reserve - Creates an empty cuckoo filter. Fails with ErrKeyExists if key exists.
+func (c *Client) Reserve(ctx context.Context, key string, opts ReserveOptions) error {
+	args := []interface{}{opts.Capacity}
+	if opts.BucketSize > 0 {
+		args = append(args, "BUCKETSIZE", opts.BucketSize)
+	}
+	if opts.MaxIterations > 0 {
+		args = append(args, "MAXITERATIONS", opts.MaxIterations)
+	}
+	if opts.Expansion > 0 {
+		args = append(args, "EXPANSION", opts.Expansion)
+	}
+	args = append(args, opts.ExtraArgs...)
+	_, err := c.exec.Do(ctx, "CF.RESERVE", key, args...)
+	return err
+}
+
+// Add - Adds item to the cuckoo filter, creating it with the server defaults if it does not exist.
+// An item can be added several times.
+func (c *Client) Add(ctx context.Context, key string, item string) error {
+	_, err := c.exec.Do(ctx, "CF.ADD", key, item)
+	return err
+}
+
+// AddNx - Adds item to the cuckoo filter, creating it with the server defaults if it does not exist,
+// unless it may have been added before, in which case it returns false
+func (c *Client) AddNx(ctx context.Context, key string, item string) (bool, error) {
+	return redis.Bool(c.exec.Do(ctx, "CF.ADDNX", key, item))
+}
+
+// Insert - Adds items to the cuckoo filter, creating it with opts if it does not exist
+func (c *Client) Insert(ctx context.Context, key string, opts InsertOptions, items ...string) ([]InsertResult, error) {
+	return c.insert(ctx, "CF.INSERT", key, opts, items)
+}
+
+// InsertNx - Adds the items which may not have been added before to the cuckoo filter, creating it
+// with opts if it does not exist
+func (c *Client) InsertNx(ctx context.Context, key string, opts InsertOptions, items ...string) ([]InsertResult, error) {
+	return c.insert(ctx, "CF.INSERTNX", key, opts, items)
+}
+
+func (c *Client) insert(ctx context.Context, command string, key string, opts InsertOptions, items []string) ([]InsertResult, error) {
+	args := redis.Args{}
+	if opts.Capacity > 0 {
+		args = args.Add("CAPACITY", opts.Capacity)
+	}
+	if opts.NoCreate {
+		args = args.Add("NOCREATE")
+	}
+	args = append(args, opts.ExtraArgs...)
+	ints, err := redis.Int64s(c.exec.Do(ctx, command, key, args.Add("ITEMS").AddFlat(items)...))
+	if err != nil {
+		return nil, err
+	}
+	results := make([]InsertResult, len(ints))
+	for i, n := range ints {
+		results[i] = InsertResult(n)
+	}
+	return results, nil
+}
+
+// Exists - Reports whether item may have been added to the cuckoo filter
+func (c *Client) Exists(ctx context.Context, key string, item string) (bool, error) {
+	return redis.Bool(c.exec.Do(ctx, "CF.EXISTS", key, item))
+}
+
+// MExists - Reports, for each of items, whether it may have been added to the cuckoo filter
+func (c *Client) MExists(ctx context.Context, key string, items ...string) ([]bool, error) {
+	return core.Bools(c.exec.Do(ctx, "CF.MEXISTS", key, redis.Args{}.AddFlat(items)...))
+}
+
+// Del - Deletes an occurrence of item from the cuckoo filter. Returns false if it was not found.
+func (c *Client) Del(ctx context.Context, key string, item string) (bool, error) {
+	return redis.Bool(c.exec.Do(ctx, "CF.DEL", key, item))
+}
+
+// Count - Returns an estimate of the number of times item was added to the cuckoo filter
+func (c *Client) Count(ctx context.Context, key string, item string) (int64, error) {
+	return redis.Int64(c.exec.Do(ctx, "CF.COUNT", key, item))
+}
+
+// Info - Returns the parameters and the fill of the cuckoo filter
+func (c *Client) Info(ctx context.Context, key string) (*Info, error) {
+	info, err := core.ParseInfo(c.exec.Do(ctx, "CF.INFO", key))
+	if err != nil {
+		return nil, err
+	}
+	return &Info{
+		Size:          info.Int("Size"),
+		Buckets:       info.Int("Number of buckets"),
+		Filters:       info.Int("Number of filters"),
+		Inserted:      info.Int("Number of items inserted"),
+		Deleted:       info.Int("Number of items deleted"),
+		BucketSize:    info.Int("Bucket size"),
+		ExpansionRate: info.Int("Expansion rate"),
+		MaxIterations: info.Int("Max iterations"),
+	}, nil
+}
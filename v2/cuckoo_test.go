@@ -0,0 +1,44 @@
+package redisbloom
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_Cuckoo(t *testing.T) {
+	flushAll()
+	key := "test_cf"
+	assert.Nil(t, client.CfReserve(ctx, key, CfReserveOptions{Capacity: 1000, BucketSize: 4}))
+	assert.True(t, errors.Is(client.CfReserve(ctx, key, CfReserveOptions{Capacity: 1000}), ErrKeyExists))
+
+	assert.Nil(t, client.CfAdd(ctx, key, "a"))
+	added, err := client.CfAddNx(ctx, key, "a")
+	assert.Nil(t, err)
+	assert.False(t, added)
+	results, err := client.CfInsertNx(ctx, key, CfInsertOptions{NoCreate: true}, "a", "b")
+	assert.Nil(t, err)
+	assert.Equal(t, []InsertResult{InsertExists, InsertAdded}, results)
+	results, err = client.CfInsert(ctx, key, CfInsertOptions{}, "b")
+	assert.Nil(t, err)
+	assert.Equal(t, []InsertResult{InsertAdded}, results)
+
+	count, err := client.CfCount(ctx, key, "b")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), count)
+	deleted, err := client.CfDel(ctx, key, "b")
+	assert.Nil(t, err)
+	assert.True(t, deleted)
+	exists, err := client.CfMExists(ctx, key, "a", "b", "c")
+	assert.Nil(t, err)
+	assert.Equal(t, []bool{true, true, false}, exists)
+
+	info, err := client.CfInfo(ctx, key)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(4), info.BucketSize)
+	assert.Equal(t, int64(1), info.Deleted)
+
+	_, err = client.CfInsert(ctx, "test_cf_missing", CfInsertOptions{NoCreate: true}, "a")
+	assert.True(t, errors.Is(err, ErrKeyNotFound))
+}
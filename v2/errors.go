@@ -0,0 +1,16 @@
+package redisbloom
+
+import "github.com/mohit-doubtnut/redisbloom-go/v2/internal/core"
+
+// Errors a *CommandError matches with errors.Is, depending on the error replied by the server
+var (
+	// ErrKeyNotFound is matched when the command needs a data structure which does not exist
+	ErrKeyNotFound = core.ErrKeyNotFound
+	// ErrKeyExists is matched when the command creates a data structure which already exists
+	ErrKeyExists = core.ErrKeyExists
+	// ErrWrongType is matched when the key holds another kind of value
+	ErrWrongType = core.ErrWrongType
+)
+
+// CommandError is an error replied by the server to a command
+type CommandError = core.CommandError
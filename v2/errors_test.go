@@ -0,0 +1,33 @@
+package redisbloom
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommandError_Is(t *testing.T) {
+	conn := &fakeConn{replies: []interface{}{
+		redis.Error("ERR not found"),
+		redis.Error("CMS: key already exists"),
+		redis.Error("WRONGTYPE Operation against a key holding the wrong kind of value"),
+	}}
+	c := NewClient(&fakePool{conn: conn}, Options{KeyPrefix: "svc:"})
+
+	_, err := c.BfInfo(ctx, "seen")
+	assert.True(t, errors.Is(err, ErrKeyNotFound))
+	assert.False(t, errors.Is(err, ErrKeyExists))
+	assert.Equal(t, "redisbloom: BF.INFO seen: ERR not found", err.Error())
+	var commandErr *CommandError
+	assert.True(t, errors.As(err, &commandErr))
+	assert.Equal(t, "seen", commandErr.Key)
+
+	err = c.CmsInitByDim(ctx, "counts", 10, 2)
+	assert.True(t, errors.Is(err, ErrKeyExists))
+
+	_, err = c.CfExists(ctx, "seen", "a")
+	assert.True(t, errors.Is(err, ErrWrongType))
+	assert.False(t, errors.Is(err, ErrKeyNotFound))
+}
@@ -0,0 +1,8 @@
+module github.com/mohit-doubtnut/redisbloom-go/v2
+
+go 1.13
+
+require (
+	github.com/gomodule/redigo v1.8.8
+	github.com/stretchr/testify v1.7.0
+)
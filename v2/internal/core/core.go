@@ -0,0 +1,154 @@
+// Package core runs the commands of the data structure packages: it applies the key prefix, bounds commands by
+// their context, converts server errors into *CommandError and parses the replies shared by several commands.
+package core
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// Pool is the source of the connections of an Executor, e.g. a *redis.Pool
+type Pool interface {
+	GetContext(ctx context.Context) (redis.Conn, error)
+}
+
+// Options configures an Executor
+type Options struct {
+	// KeyPrefix is prepended to every key sent to the server
+	KeyPrefix string
+}
+
+// Executor runs commands on the connections of a pool. It is safe for concurrent use.
+type Executor struct {
+	pool   Pool
+	prefix string
+}
+
+// NewExecutor returns an executor running its commands on the connections of pool
+func NewExecutor(pool Pool, opts Options) *Executor {
+	return &Executor{pool: pool, prefix: opts.KeyPrefix}
+}
+
+// Key returns the name under which key is stored on the server
+func (e *Executor) Key(key string) string {
+	return e.prefix + key
+}
+
+// Keys returns the names under which keys are stored on the server
+func (e *Executor) Keys(keys []string) []string {
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = e.Key(key)
+	}
+	return prefixed
+}
+
+// Do runs command on a connection of the pool, key being the first argument, and returns its reply.
+// Errors replied by the server are returned as *CommandError.
+func (e *Executor) Do(ctx context.Context, command string, key string, args ...interface{}) (interface{}, error) {
+	conn, err := e.pool.GetContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	reply, err := doContext(ctx, conn, command, append([]interface{}{e.Key(key)}, args...)...)
+	if serverErr, ok := err.(redis.Error); ok {
+		return nil, &CommandError{Command: command, Key: key, Err: serverErr}
+	}
+	return reply, err
+}
+
+// doContext runs command on conn, bounded by ctx if conn supports it
+func doContext(ctx context.Context, conn redis.Conn, command string, args ...interface{}) (interface{}, error) {
+	if cwc, ok := conn.(redis.ConnWithContext); ok {
+		return cwc.DoContext(ctx, command, args...)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return conn.Do(command, args...)
+}
+
+// FormatFloat formats f the way RedisBloom parses it, without losing precision
+func FormatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// FormatFloats formats each of floats with FormatFloat
+func FormatFloats(floats []float64) []interface{} {
+	args := make([]interface{}, len(floats))
+	for i, f := range floats {
+		args[i] = FormatFloat(f)
+	}
+	return args
+}
+
+// Float converts an integer or bulk string reply into a float64
+func Float(reply interface{}, err error) (float64, error) {
+	if n, ok := reply.(int64); ok && err == nil {
+		return float64(n), nil
+	}
+	return redis.Float64(reply, err)
+}
+
+// Floats converts an array reply of integers or bulk strings into float64s
+func Floats(reply interface{}, err error) ([]float64, error) {
+	values, err := redis.Values(reply, err)
+	if err != nil {
+		return nil, err
+	}
+	floats := make([]float64, len(values))
+	for i, value := range values {
+		if floats[i], err = Float(value, nil); err != nil {
+			return nil, err
+		}
+	}
+	return floats, nil
+}
+
+// Bools converts an array reply of 0 and 1 integers into bools
+func Bools(reply interface{}, err error) ([]bool, error) {
+	ints, err := redis.Int64s(reply, err)
+	if err != nil {
+		return nil, err
+	}
+	bools := make([]bool, len(ints))
+	for i, n := range ints {
+		bools[i] = n == 1
+	}
+	return bools, nil
+}
+
+// Info holds the fields of an INFO reply, which alternates field names and values
+type Info map[string]interface{}
+
+// ParseInfo converts an INFO reply into an Info
+func ParseInfo(reply interface{}, err error) (Info, error) {
+	values, err := redis.Values(reply, err)
+	if err != nil {
+		return nil, err
+	}
+	info := make(Info, len(values)/2)
+	for i := 0; i+1 < len(values); i += 2 {
+		name, err := redis.String(values[i], nil)
+		if err != nil {
+			return nil, err
+		}
+		info[name] = values[i+1]
+	}
+	return info, nil
+}
+
+// Int returns the integer field name, 0 if the reply does not have it
+func (info Info) Int(name string) int64 {
+	n, _ := redis.Int64(info[name], nil)
+	return n
+}
+
+// Float returns the number field name, 0 if the reply does not have it
+func (info Info) Float(name string) float64 {
+	f, _ := Float(info[name], nil)
+	return f
+}
@@ -0,0 +1,71 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeConn replies to commands with canned replies, in order, recording the arguments it receives
+type fakeConn struct {
+	replies []interface{}
+	args    [][]interface{}
+}
+
+func (c *fakeConn) Close() error                      { return nil }
+func (c *fakeConn) Err() error                        { return nil }
+func (c *fakeConn) Flush() error                      { return nil }
+func (c *fakeConn) Send(string, ...interface{}) error { return nil }
+func (c *fakeConn) Receive() (interface{}, error)     { return nil, nil }
+
+func (c *fakeConn) Do(command string, args ...interface{}) (interface{}, error) {
+	c.args = append(c.args, append([]interface{}{command}, args...))
+	reply := c.replies[0]
+	c.replies = c.replies[1:]
+	if err, ok := reply.(redis.Error); ok {
+		return nil, err
+	}
+	return reply, nil
+}
+
+type fakePool struct {
+	conn *fakeConn
+}
+
+func (p *fakePool) GetContext(ctx context.Context) (redis.Conn, error) {
+	return p.conn, nil
+}
+
+func TestExecutor_Do(t *testing.T) {
+	conn := &fakeConn{replies: []interface{}{"OK", redis.Error("ERR not found")}}
+	exec := NewExecutor(&fakePool{conn: conn}, Options{KeyPrefix: "svc:"})
+	reply, err := exec.Do(context.Background(), "CMS.MERGE", "total", 1, exec.Key("a"))
+	assert.Nil(t, err)
+	assert.Equal(t, "OK", reply)
+	assert.Equal(t, []interface{}{"CMS.MERGE", "svc:total", 1, "svc:a"}, conn.args[0])
+
+	_, err = exec.Do(context.Background(), "BF.INFO", "seen")
+	assert.True(t, errors.Is(err, ErrKeyNotFound))
+	assert.Equal(t, "redisbloom: BF.INFO seen: ERR not found", err.Error())
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = exec.Do(canceled, "BF.EXISTS", "seen", "a")
+	assert.Equal(t, context.Canceled, err)
+	assert.Len(t, conn.args, 2)
+}
+
+func TestParseInfo(t *testing.T) {
+	info, err := ParseInfo([]interface{}{[]byte("width"), int64(10), []byte("decay"), []byte("0.9")}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(10), info.Int("width"))
+	assert.Equal(t, 0.9, info.Float("decay"))
+	assert.Equal(t, int64(0), info.Int("missing"))
+	floats, err := Floats([]interface{}{int64(1), []byte("nan"), []byte("2.5")}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 1.0, floats[0])
+	assert.Equal(t, 2.5, floats[2])
+}
@@ -0,0 +1,50 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// Errors a *CommandError matches with errors.Is, depending on the error replied by the server
+var (
+	// ErrKeyNotFound is matched when the command needs a data structure which does not exist
+	ErrKeyNotFound = errors.New("redisbloom: key does not exist")
+	// ErrKeyExists is matched when the command creates a data structure which already exists
+	ErrKeyExists = errors.New("redisbloom: key already exists")
+	// ErrWrongType is matched when the key holds another kind of value
+	ErrWrongType = errors.New("redisbloom: key holds the wrong kind of value")
+)
+
+// CommandError is an error replied by the server to a command
+type CommandError struct {
+	Command string
+	// Key is the key of the command, relative to Options.KeyPrefix
+	Key string
+	Err redis.Error
+}
+
+func (e *CommandError) Error() string {
+	return fmt.Sprintf("redisbloom: %s %s: %s", e.Command, e.Key, e.Err)
+}
+
+// Unwrap returns the error replied by the server
+func (e *CommandError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is the sentinel error matching the error replied by the server
+func (e *CommandError) Is(target error) bool {
+	message := strings.ToLower(string(e.Err))
+	switch target {
+	case ErrKeyNotFound:
+		return strings.Contains(message, "not found") || strings.Contains(message, "does not exist")
+	case ErrKeyExists:
+		return strings.Contains(message, "item exists") || strings.Contains(message, "already exists")
+	case ErrWrongType:
+		return strings.HasPrefix(message, "wrongtype")
+	}
+	return false
+}
@@ -0,0 +1,19 @@
+module github.com/mohit-doubtnut/redisbloom-go/v2/rueidispool
+
+go 1.20
+
+require (
+	github.com/gomodule/redigo v1.8.8
+	github.com/mohit-doubtnut/redisbloom-go/v2 v2.0.0-00010101000000-000000000000
+	github.com/redis/rueidis v1.0.34
+	github.com/stretchr/testify v1.7.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/sys v0.17.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/mohit-doubtnut/redisbloom-go/v2 => ../
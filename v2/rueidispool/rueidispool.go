@@ -0,0 +1,274 @@
+// Package rueidispool runs the commands of the redisbloom clients on a rueidis client, which pipelines the
+// commands of concurrent callers on shared connections and can cache the replies of read commands on the client.
+//
+// Pool implements the Pool of the v2 clients and the ConnPool of the v1 client:
+//
+//	rc, err := rueidis.NewClient(rueidis.ClientOption{InitAddress: []string{"localhost:6379"}})
+//	client := redisbloom.NewClient(rueidispool.New(rc, rueidispool.Options{}), redisbloom.Options{})
+//
+// The connections it hands out are views on the rueidis client: Do runs a single command, and Send, Flush and
+// Receive run the queued commands as one pipeline.
+package rueidispool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/redis/rueidis"
+)
+
+// cacheableCommands are the read commands whose replies Pool can cache on the client
+var cacheableCommands = map[string]bool{
+	"BF.EXISTS":   true,
+	"BF.MEXISTS":  true,
+	"BF.INFO":     true,
+	"CF.EXISTS":   true,
+	"CF.MEXISTS":  true,
+	"CF.COUNT":    true,
+	"CF.INFO":     true,
+	"CMS.QUERY":   true,
+	"CMS.INFO":    true,
+	"TOPK.QUERY":  true,
+	"TOPK.LIST":   true,
+	"TOPK.INFO":   true,
+	"TDIGEST.MIN": true,
+	"TDIGEST.MAX": true,
+}
+
+// Options configures a Pool
+type Options struct {
+	// CacheTTL is how long the replies of read commands are cached on the client, tracked by the server so that
+	// they are invalidated when their key changes. Zero disables client side caching.
+	CacheTTL time.Duration
+}
+
+// Pool hands out connections running their commands on a rueidis client. It is safe for concurrent use.
+type Pool struct {
+	client rueidis.Client
+	opts   Options
+}
+
+// New - Returns a pool running its commands on client
+func New(client rueidis.Client, opts Options) *Pool {
+	return &Pool{client: client, opts: opts}
+}
+
+// Get returns a connection running its commands on the rueidis client
+func (p *Pool) Get() redis.Conn {
+	return &conn{pool: p}
+}
+
+// GetContext returns a connection running its commands on the rueidis client
+func (p *Pool) GetContext(ctx context.Context) (redis.Conn, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return &conn{pool: p}, nil
+}
+
+// Close closes the rueidis client
+func (p *Pool) Close() error {
+	p.client.Close()
+	return nil
+}
+
+// build returns the rueidis command of a redigo command, the first argument of module commands being their key
+func (p *Pool) build(command string, args []interface{}) rueidis.Completed {
+	strs := formatArgs(args)
+	cmd := p.client.B().Arbitrary(strings.Fields(command)...)
+	if strings.Contains(command, ".") && len(strs) > 0 {
+		cmd = cmd.Keys(strs[0])
+		strs = strs[1:]
+	}
+	return cmd.Args(strs...).Build()
+}
+
+// do runs a command, from the client side cache if it is cacheable
+func (p *Pool) do(ctx context.Context, command string, args []interface{}) (interface{}, error) {
+	cmd := p.build(command, args)
+	if p.opts.CacheTTL > 0 && cacheableCommands[strings.ToUpper(command)] {
+		return convertResult(p.client.DoCache(ctx, rueidis.Cacheable(cmd), p.opts.CacheTTL))
+	}
+	return convertResult(p.client.Do(ctx, cmd))
+}
+
+// conn is a redis.Conn running its commands on the rueidis client of a pool
+type conn struct {
+	pool    *Pool
+	pending []rueidis.Completed
+	results []rueidis.RedisResult
+	err     error
+}
+
+func (c *conn) Close() error {
+	c.pending, c.results = nil, nil
+	return nil
+}
+
+func (c *conn) Err() error {
+	return c.err
+}
+
+func (c *conn) Do(command string, args ...interface{}) (interface{}, error) {
+	return c.DoContext(context.Background(), command, args...)
+}
+
+// DoContext runs the queued commands, if any, discarding their replies like redigo, then command
+func (c *conn) DoContext(ctx context.Context, command string, args ...interface{}) (interface{}, error) {
+	if len(c.pending) > 0 {
+		if err := c.flush(ctx); err != nil {
+			return nil, err
+		}
+		c.results = nil
+	}
+	if command == "" {
+		return nil, nil
+	}
+	return c.pool.do(ctx, command, args)
+}
+
+func (c *conn) Send(command string, args ...interface{}) error {
+	c.pending = append(c.pending, c.pool.build(command, args))
+	return nil
+}
+
+func (c *conn) Flush() error {
+	return c.flush(context.Background())
+}
+
+func (c *conn) flush(ctx context.Context) error {
+	if len(c.pending) == 0 {
+		return nil
+	}
+	results := c.pool.client.DoMulti(ctx, c.pending...)
+	c.pending = nil
+	for _, result := range results {
+		if err := result.NonRedisError(); err != nil {
+			c.err = err
+			return err
+		}
+	}
+	c.results = append(c.results, results...)
+	return nil
+}
+
+func (c *conn) Receive() (interface{}, error) {
+	return c.ReceiveContext(context.Background())
+}
+
+// ReceiveContext returns the reply of the oldest command sent, flushing the queued commands if needed
+func (c *conn) ReceiveContext(ctx context.Context) (interface{}, error) {
+	if len(c.results) == 0 {
+		if err := c.flush(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if len(c.results) == 0 {
+		return nil, errors.New("rueidispool: Receive called without a pending command")
+	}
+	result := c.results[0]
+	c.results = c.results[1:]
+	return convertResult(result)
+}
+
+// formatArgs formats args the way redigo writes them on the wire
+func formatArgs(args []interface{}) []string {
+	strs := make([]string, len(args))
+	for i, arg := range args {
+		switch arg := arg.(type) {
+		case string:
+			strs[i] = arg
+		case []byte:
+			strs[i] = string(arg)
+		case int:
+			strs[i] = strconv.Itoa(arg)
+		case int64:
+			strs[i] = strconv.FormatInt(arg, 10)
+		case float64:
+			strs[i] = strconv.FormatFloat(arg, 'g', -1, 64)
+		case bool:
+			if arg {
+				strs[i] = "1"
+			} else {
+				strs[i] = "0"
+			}
+		case nil:
+			strs[i] = ""
+		case redis.Argument:
+			strs[i] = formatArgs([]interface{}{arg.RedisArg()})[0]
+		default:
+			strs[i] = fmt.Sprint(arg)
+		}
+	}
+	return strs
+}
+
+// convertResult converts a rueidis result into the reply redigo would return
+func convertResult(result rueidis.RedisResult) (interface{}, error) {
+	if err := result.NonRedisError(); err != nil {
+		return nil, err
+	}
+	msg, err := result.ToMessage()
+	if err != nil && !rueidis.IsRedisNil(err) {
+		return nil, redis.Error(err.Error())
+	}
+	return convertMessage(&msg)
+}
+
+// convertMessage converts a RESP3 message into the RESP2 shaped reply of redigo: integers and booleans are
+// int64, strings and doubles are []byte, arrays, sets and maps are []interface{}, the latter alternating keys
+// and values
+func convertMessage(msg *rueidis.RedisMessage) (interface{}, error) {
+	switch {
+	case msg.IsNil():
+		return nil, nil
+	case msg.Error() != nil:
+		return redis.Error(msg.Error().Error()), nil
+	case msg.IsInt64():
+		return msg.AsInt64()
+	case msg.IsBool():
+		b, err := msg.AsBool()
+		if b {
+			return int64(1), err
+		}
+		return int64(0), err
+	case msg.IsFloat64():
+		f, err := msg.AsFloat64()
+		return []byte(strconv.FormatFloat(f, 'g', -1, 64)), err
+	case msg.IsString():
+		s, err := msg.ToString()
+		return []byte(s), err
+	case msg.IsMap():
+		entries, err := msg.AsMap()
+		if err != nil {
+			return nil, err
+		}
+		values := make([]interface{}, 0, 2*len(entries))
+		for key, entry := range entries {
+			value, err := convertMessage(&entry)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, []byte(key), value)
+		}
+		return values, nil
+	case msg.IsArray():
+		elements, err := msg.ToArray()
+		if err != nil {
+			return nil, err
+		}
+		values := make([]interface{}, len(elements))
+		for i := range elements {
+			if values[i], err = convertMessage(&elements[i]); err != nil {
+				return nil, err
+			}
+		}
+		return values, nil
+	}
+	return msg.ToAny()
+}
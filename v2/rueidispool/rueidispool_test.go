@@ -0,0 +1,98 @@
+package rueidispool
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/mohit-doubtnut/redisbloom-go/v2/bloom"
+	"github.com/redis/rueidis"
+	"github.com/stretchr/testify/assert"
+)
+
+type redisArg string
+
+func (a redisArg) RedisArg() interface{} {
+	return string(a) + "!"
+}
+
+func TestFormatArgs(t *testing.T) {
+	args := formatArgs([]interface{}{"key", []byte("item"), 3, int64(-4), 0.5, true, false, nil, redisArg("arg"), uint8(7)})
+	assert.Equal(t, []string{"key", "item", "3", "-4", "0.5", "1", "0", "", "arg!", "7"}, args)
+}
+
+func getTestConnectionDetails() (string, string) {
+	host := "localhost:6379"
+	if value, exists := os.LookupEnv("REDISBLOOM_TEST_HOST"); exists && value != "" {
+		host = value
+	}
+	return host, os.Getenv("REDISBLOOM_TEST_PASSWORD")
+}
+
+// benchmarkPools returns the redigo and rueidis pools compared by the benchmarks, skipping b if the server is
+// not reachable
+func benchmarkPools(b *testing.B) map[string]bloom.Pool {
+	host, password := getTestConnectionDetails()
+	rc, err := rueidis.NewClient(rueidis.ClientOption{InitAddress: []string{host}, Password: password})
+	if err != nil {
+		b.Skipf("server not reachable: %v", err)
+	}
+	redigoPool := &redis.Pool{Dial: func() (redis.Conn, error) {
+		return redis.Dial("tcp", host, redis.DialPassword(password))
+	}, MaxIdle: 64}
+	b.Cleanup(func() {
+		redigoPool.Close()
+		rc.Close()
+	})
+	return map[string]bloom.Pool{"redigo": redigoPool, "rueidis": New(rc, Options{})}
+}
+
+func benchmarkItems(n int) []string {
+	items := make([]string, n)
+	for i := range items {
+		items[i] = "item" + strconv.Itoa(i)
+	}
+	return items
+}
+
+func BenchmarkMAdd(b *testing.B) {
+	items := benchmarkItems(10)
+	for name, pool := range benchmarkPools(b) {
+		client := bloom.NewClient(pool, bloom.Options{KeyPrefix: "bench:" + name + ":"})
+		var n int64
+		b.Run(name, func(b *testing.B) {
+			b.RunParallel(func(pb *testing.PB) {
+				key := "madd" + strconv.FormatInt(atomic.AddInt64(&n, 1)%16, 10)
+				for pb.Next() {
+					if _, err := client.MAdd(context.Background(), key, items...); err != nil {
+						b.Error(err)
+						return
+					}
+				}
+			})
+		})
+	}
+}
+
+func BenchmarkMExists(b *testing.B) {
+	items := benchmarkItems(10)
+	for name, pool := range benchmarkPools(b) {
+		client := bloom.NewClient(pool, bloom.Options{KeyPrefix: "bench:" + name + ":"})
+		if _, err := client.MAdd(context.Background(), "mexists", items[:5]...); err != nil {
+			b.Fatal(err)
+		}
+		b.Run(name, func(b *testing.B) {
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					if _, err := client.MExists(context.Background(), "mexists", items...); err != nil {
+						b.Error(err)
+						return
+					}
+				}
+			})
+		})
+	}
+}
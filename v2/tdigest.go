@@ -0,0 +1,69 @@
+package redisbloom
+
+import (
+	"context"
+
+	"github.com/mohit-doubtnut/redisbloom-go/v2/tdigest"
+)
+
+// TdCreateOptions are the parameters of a t-digest
+type TdCreateOptions = tdigest.CreateOptions
+
+// TdMergeOptions are the optional arguments of TdMerge
+type TdMergeOptions = tdigest.MergeOptions
+
+// TDigestInfo is the reply of TDIGEST.INFO
+type TDigestInfo = tdigest.Info
+
+// TdCreate - Creates an empty t-digest. Fails with ErrKeyExists if key exists.
+func (c *Client) TdCreate(ctx context.Context, key string, opts TdCreateOptions) error {
+	return c.tdigest.Create(ctx, key, opts)
+}
+
+// TdAdd - Adds values to the t-digest
+func (c *Client) TdAdd(ctx context.Context, key string, values ...float64) error {
+	return c.tdigest.Add(ctx, key, values...)
+}
+
+// TdReset - Empties the t-digest, keeping its compression
+func (c *Client) TdReset(ctx context.Context, key string) error {
+	return c.tdigest.Reset(ctx, key)
+}
+
+// TdMerge - Merges the sources into the t-digest dest, creating it if it does not exist
+func (c *Client) TdMerge(ctx context.Context, dest string, sources []string, opts TdMergeOptions) error {
+	return c.tdigest.Merge(ctx, dest, sources, opts)
+}
+
+// TdMin - Returns the smallest value added to the t-digest, NaN if it is empty
+func (c *Client) TdMin(ctx context.Context, key string) (float64, error) {
+	return c.tdigest.Min(ctx, key)
+}
+
+// TdMax - Returns the largest value added to the t-digest, NaN if it is empty
+func (c *Client) TdMax(ctx context.Context, key string) (float64, error) {
+	return c.tdigest.Max(ctx, key)
+}
+
+// TdQuantile - Returns, for each of quantiles, an estimate of the value below which that fraction of the values
+// added to the t-digest fall
+func (c *Client) TdQuantile(ctx context.Context, key string, quantiles ...float64) ([]float64, error) {
+	return c.tdigest.Quantile(ctx, key, quantiles...)
+}
+
+// TdCdf - Returns, for each of values, an estimate of the fraction of the values added to the t-digest
+// which are lower than or equal to it
+func (c *Client) TdCdf(ctx context.Context, key string, values ...float64) ([]float64, error) {
+	return c.tdigest.Cdf(ctx, key, values...)
+}
+
+// TdTrimmedMean - Returns an estimate of the mean of the values added to the t-digest between the low and high
+// quantiles
+func (c *Client) TdTrimmedMean(ctx context.Context, key string, low float64, high float64) (float64, error) {
+	return c.tdigest.TrimmedMean(ctx, key, low, high)
+}
+
+// TdInfo - Returns the parameters and the fill of the t-digest
+func (c *Client) TdInfo(ctx context.Context, key string) (*TDigestInfo, error) {
+	return c.tdigest.Info(ctx, key)
+}
@@ -0,0 +1,149 @@
+// Package tdigest runs the RedisBloom t-digest commands, with the syntax of RedisBloom 2.4 and newer
+package tdigest
+
+import (
+	"context"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/mohit-doubtnut/redisbloom-go/v2/internal/core"
+)
+
+// Pool is the source of the connections of a Client, e.g. a *redis.Pool
+type Pool = core.Pool
+
+// Options configures a Client
+type Options = core.Options
+
+// CreateOptions are the parameters of a t-digest
+type CreateOptions struct {
+	// Compression trades accuracy for memory, the server default if zero
+	Compression int64
+	// ExtraArgs are sent after the other optional arguments, e.g. to pass flags of a RedisBloom release newer
+	// than this client
+	ExtraArgs []interface{}
+}
+
+// MergeOptions are the optional arguments of Merge
+type MergeOptions struct {
+	// Compression is the compression of dest if it is created or overridden, the largest compression of the
+	// sources if zero
+	Compression int64
+	// Override replaces the content of dest instead of merging the sources into it
+	Override bool
+	// ExtraArgs are sent after the other optional arguments, e.g. to pass flags of a RedisBloom release newer
+	// than this client
+	ExtraArgs []interface{}
+}
+
+// Info is the reply of TDIGEST.INFO
+type Info struct {
+	Compression       int64
+	Capacity          int64
+	MergedNodes       int64
+	UnmergedNodes     int64
+	MergedWeight      float64
+	UnmergedWeight    float64
+	TotalCompressions int64
+}
+
+// Observations returns the total weight of the values added to the t-digest
+func (info *Info) Observations() float64 {
+	return info.MergedWeight + info.UnmergedWeight
+}
+
+// Client runs the t-digest commands. It is safe for concurrent use.
+type Client struct {
+	exec *core.Executor
+}
+
+// NewClient - Returns a client running its commands on the connections of pool
+func NewClient(pool Pool, opts Options) *Client {
+	return New(core.NewExecutor(pool, opts))
+}
+
+// New returns a client running its commands with exec
+func New(exec *core.Executor) *Client {
+	return &Client{exec: exec}
+}
+
+// Create - Creates an empty t-digest. Fails with ErrKeyExists if key exists.
+func (c *Client) Create(ctx context.Context, key string, opts CreateOptions) error {
+	var args []interface{}
+	if opts.Compression > 0 {
+		args = append(args, "COMPRESSION", opts.Compression)
+	}
+	args = append(args, opts.ExtraArgs...)
+	_, err := c.exec.Do(ctx, "TDIGEST.CREATE", key, args...)
+	return err
+}
+
+// Add - Adds values to the t-digest
+func (c *Client) Add(ctx context.Context, key string, values ...float64) error {
+	_, err := c.exec.Do(ctx, "TDIGEST.ADD", key, core.FormatFloats(values)...)
+	return err
+}
+
+// Reset - Empties the t-digest, keeping its compression
+func (c *Client) Reset(ctx context.Context, key string) error {
+	_, err := c.exec.Do(ctx, "TDIGEST.RESET", key)
+	return err
+}
+
+// Merge - Merges the sources into the t-digest dest, creating it if it does not exist
+func (c *Client) Merge(ctx context.Context, dest string, sources []string, opts MergeOptions) error {
+	args := redis.Args{len(sources)}.AddFlat(c.exec.Keys(sources))
+	if opts.Compression > 0 {
+		args = args.Add("COMPRESSION", opts.Compression)
+	}
+	if opts.Override {
+		args = args.Add("OVERRIDE")
+	}
+	args = append(args, opts.ExtraArgs...)
+	_, err := c.exec.Do(ctx, "TDIGEST.MERGE", dest, args...)
+	return err
+}
+
+// Min - Returns the smallest value added to the t-digest, NaN if it is empty
+func (c *Client) Min(ctx context.Context, key string) (float64, error) {
+	return core.Float(c.exec.Do(ctx, "TDIGEST.MIN", key))
+}
+
+// Max - Returns the largest value added to the t-digest, NaN if it is empty
+func (c *Client) Max(ctx context.Context, key string) (float64, error) {
+	return core.Float(c.exec.Do(ctx, "TDIGEST.MAX", key))
+}
+
+// Quantile - Returns, for each of quantiles, an estimate of the value below which that fraction of the values
+// added to the t-digest fall
+func (c *Client) Quantile(ctx context.Context, key string, quantiles ...float64) ([]float64, error) {
+	return core.Floats(c.exec.Do(ctx, "TDIGEST.QUANTILE", key, core.FormatFloats(quantiles)...))
+}
+
+// Cdf - Returns, for each of values, an estimate of the fraction of the values added to the t-digest
+// which are lower than or equal to it
+func (c *Client) Cdf(ctx context.Context, key string, values ...float64) ([]float64, error) {
+	return core.Floats(c.exec.Do(ctx, "TDIGEST.CDF", key, core.FormatFloats(values)...))
+}
+
+// TrimmedMean - Returns an estimate of the mean of the values added to the t-digest between the low and high
+// quantiles
+func (c *Client) TrimmedMean(ctx context.Context, key string, low float64, high float64) (float64, error) {
+	return core.Float(c.exec.Do(ctx, "TDIGEST.TRIMMED_MEAN", key, core.FormatFloat(low), core.FormatFloat(high)))
+}
+
+// Info - Returns the parameters and the fill of the t-digest
+func (c *Client) Info(ctx context.Context, key string) (*Info, error) {
+	info, err := core.ParseInfo(c.exec.Do(ctx, "TDIGEST.INFO", key))
+	if err != nil {
+		return nil, err
+	}
+	return &Info{
+		Compression:       info.Int("Compression"),
+		Capacity:          info.Int("Capacity"),
+		MergedNodes:       info.Int("Merged nodes"),
+		UnmergedNodes:     info.Int("Unmerged nodes"),
+		MergedWeight:      info.Float("Merged weight"),
+		UnmergedWeight:    info.Float("Unmerged weight"),
+		TotalCompressions: info.Int("Total compressions"),
+	}, nil
+}
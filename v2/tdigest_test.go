@@ -0,0 +1,52 @@
+package redisbloom
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_TDigest(t *testing.T) {
+	flushAll()
+	key := "test_td"
+	assert.Nil(t, client.TdCreate(ctx, key, TdCreateOptions{Compression: 100}))
+	assert.True(t, errors.Is(client.TdCreate(ctx, key, TdCreateOptions{}), ErrKeyExists))
+	values := make([]float64, 100)
+	for i := range values {
+		values[i] = float64(i + 1)
+	}
+	assert.Nil(t, client.TdAdd(ctx, key, values...))
+
+	min, err := client.TdMin(ctx, key)
+	assert.Nil(t, err)
+	assert.Equal(t, 1.0, min)
+	max, err := client.TdMax(ctx, key)
+	assert.Nil(t, err)
+	assert.Equal(t, 100.0, max)
+	quantiles, err := client.TdQuantile(ctx, key, 0, 0.5, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, 1.0, quantiles[0])
+	assert.InDelta(t, 50, quantiles[1], 2)
+	assert.Equal(t, 100.0, quantiles[2])
+	cdfs, err := client.TdCdf(ctx, key, 0, 200)
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{0, 1}, cdfs)
+	mean, err := client.TdTrimmedMean(ctx, key, 0.1, 0.9)
+	assert.Nil(t, err)
+	assert.InDelta(t, 50.5, mean, 1)
+
+	assert.Nil(t, client.TdMerge(ctx, "test_td_merged", []string{key, key}, TdMergeOptions{}))
+	info, err := client.TdInfo(ctx, "test_td_merged")
+	assert.Nil(t, err)
+	assert.Equal(t, 200.0, info.Observations())
+
+	assert.Nil(t, client.TdReset(ctx, key))
+	info, err = client.TdInfo(ctx, key)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(100), info.Compression)
+	assert.Equal(t, 0.0, info.Observations())
+
+	_, err = client.TdMin(ctx, "test_td_missing")
+	assert.True(t, errors.Is(err, ErrKeyNotFound))
+}
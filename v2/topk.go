@@ -0,0 +1,57 @@
+package redisbloom
+
+import (
+	"context"
+
+	"github.com/mohit-doubtnut/redisbloom-go/v2/topk"
+)
+
+// TopkReserveOptions are the parameters of a top-k
+type TopkReserveOptions = topk.ReserveOptions
+
+// TopkIncrement is an item and the amount its count is increased by
+type TopkIncrement = topk.Increment
+
+// TopkItem is an item of a top-k and its estimated count
+type TopkItem = topk.Item
+
+// TopKInfo is the reply of TOPK.INFO
+type TopKInfo = topk.Info
+
+// TopkReserve - Creates an empty top-k. Fails with ErrKeyExists if key exists.
+func (c *Client) TopkReserve(ctx context.Context, key string, opts TopkReserveOptions) error {
+	return c.topk.Reserve(ctx, key, opts)
+}
+
+// TopkAdd - Adds items to the top-k. Returns, for each item, the item it expelled from the top-k,
+// or an empty string if it expelled none.
+func (c *Client) TopkAdd(ctx context.Context, key string, items ...string) ([]string, error) {
+	return c.topk.Add(ctx, key, items...)
+}
+
+// TopkIncrBy - Increases the counts of items in the top-k. Returns, for each increment, the item it expelled
+// from the top-k, or an empty string if it expelled none.
+func (c *Client) TopkIncrBy(ctx context.Context, key string, increments ...TopkIncrement) ([]string, error) {
+	return c.topk.IncrBy(ctx, key, increments...)
+}
+
+// TopkQuery - Reports, for each of items, whether it is in the top-k
+func (c *Client) TopkQuery(ctx context.Context, key string, items ...string) ([]bool, error) {
+	return c.topk.Query(ctx, key, items...)
+}
+
+// TopkList - Returns the items of the top-k, from the most to the least frequent
+func (c *Client) TopkList(ctx context.Context, key string) ([]string, error) {
+	return c.topk.List(ctx, key)
+}
+
+// TopkListWithCount - Returns the items of the top-k with their estimated counts, from the most to the least
+// frequent. Requires RedisBloom 2.2.5 or newer.
+func (c *Client) TopkListWithCount(ctx context.Context, key string) ([]TopkItem, error) {
+	return c.topk.ListWithCount(ctx, key)
+}
+
+// TopkInfo - Returns the parameters of the top-k
+func (c *Client) TopkInfo(ctx context.Context, key string) (*TopKInfo, error) {
+	return c.topk.Info(ctx, key)
+}
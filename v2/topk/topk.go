@@ -0,0 +1,131 @@
+// Package topk runs the RedisBloom top-k commands
+package topk
+
+import (
+	"context"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/mohit-doubtnut/redisbloom-go/v2/internal/core"
+)
+
+// Pool is the source of the connections of a Client, e.g. a *redis.Pool
+type Pool = core.Pool
+
+// Options configures a Client
+type Options = core.Options
+
+// ReserveOptions are the parameters of a top-k
+type ReserveOptions struct {
+	// K is the number of top items kept, required
+	K int64
+	// Width, Depth and Decay are the server defaults if Width is zero
+	Width int64
+	Depth int64
+	Decay float64
+	// ExtraArgs are sent after the other optional arguments, e.g. to pass flags of a RedisBloom release newer
+	// than this client
+	ExtraArgs []interface{}
+}
+
+// Increment is an item and the amount its count is increased by
+type Increment struct {
+	Item      string
+	Increment int64
+}
+
+// Item is an item of a top-k and its estimated count
+type Item struct {
+	Item  string
+	Count int64
+}
+
+// Info is the reply of TOPK.INFO
+type Info struct {
+	K     int64
+	Width int64
+	Depth int64
+	Decay float64
+}
+
+// Client runs the top-k commands. It is safe for concurrent use.
+type Client struct {
+	exec *core.Executor
+}
+
+// NewClient - Returns a client running its commands on the connections of pool
+func NewClient(pool Pool, opts Options) *Client {
+	return New(core.NewExecutor(pool, opts))
+}
+
+// New returns a client running its commands with exec
+func New(exec *core.Executor) *Client {
+	return &Client{exec: exec}
+}
+
+// Reserve - Creates an empty top-k. Fails with ErrKeyExists if key exists.
+func (c *Client) Reserve(ctx context.Context, key string, opts ReserveOptions) error {
+	args := []interface{}{opts.K}
+	if opts.Width > 0 {
+		args = append(args, opts.Width, opts.Depth, core.FormatFloat(opts.Decay))
+	}
+	args = append(args, opts.ExtraArgs...)
+	_, err := c.exec.Do(ctx, "TOPK.RESERVE", key, args...)
+	return err
+}
+
+// Add - Adds items to the top-k. Returns, for each item, the item it expelled from the top-k,
+// or an empty string if it expelled none.
+func (c *Client) Add(ctx context.Context, key string, items ...string) ([]string, error) {
+	return redis.Strings(c.exec.Do(ctx, "TOPK.ADD", key, redis.Args{}.AddFlat(items)...))
+}
+
+// IncrBy - Increases the counts of items in the top-k. Returns, for each increment, the item it expelled
+// from the top-k, or an empty string if it expelled none.
+func (c *Client) IncrBy(ctx context.Context, key string, increments ...Increment) ([]string, error) {
+	args := make([]interface{}, 0, 2*len(increments))
+	for _, increment := range increments {
+		args = append(args, increment.Item, increment.Increment)
+	}
+	return redis.Strings(c.exec.Do(ctx, "TOPK.INCRBY", key, args...))
+}
+
+// Query - Reports, for each of items, whether it is in the top-k
+func (c *Client) Query(ctx context.Context, key string, items ...string) ([]bool, error) {
+	return core.Bools(c.exec.Do(ctx, "TOPK.QUERY", key, redis.Args{}.AddFlat(items)...))
+}
+
+// List - Returns the items of the top-k, from the most to the least frequent
+func (c *Client) List(ctx context.Context, key string) ([]string, error) {
+	return redis.Strings(c.exec.Do(ctx, "TOPK.LIST", key))
+}
+
+// ListWithCount - Returns the items of the top-k with their estimated counts, from the most to the least
+// frequent. Requires RedisBloom 2.2.5 or newer.
+func (c *Client) ListWithCount(ctx context.Context, key string) ([]Item, error) {
+	values, err := redis.Values(c.exec.Do(ctx, "TOPK.LIST", key, "WITHCOUNT"))
+	if err != nil {
+		return nil, err
+	}
+	items := make([]Item, 0, len(values)/2)
+	for i := 0; i+1 < len(values); i += 2 {
+		item, err := redis.String(values[i], nil)
+		if err != nil {
+			return nil, err
+		}
+		count, err := redis.Int64(values[i+1], nil)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, Item{Item: item, Count: count})
+	}
+	return items, nil
+}
+
+// Info - Returns the parameters of the top-k
+func (c *Client) Info(ctx context.Context, key string) (*Info, error) {
+	info, err := core.ParseInfo(c.exec.Do(ctx, "TOPK.INFO", key))
+	if err != nil {
+		return nil, err
+	}
+	return &Info{K: info.Int("k"), Width: info.Int("width"), Depth: info.Int("depth"), Decay: info.Float("decay")}, nil
+}
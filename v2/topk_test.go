@@ -0,0 +1,35 @@
+package redisbloom
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_TopK(t *testing.T) {
+	flushAll()
+	key := "test_topk"
+	assert.Nil(t, client.TopkReserve(ctx, key, TopkReserveOptions{K: 2, Width: 50, Depth: 4, Decay: 0.9}))
+	assert.True(t, errors.Is(client.TopkReserve(ctx, key, TopkReserveOptions{K: 2}), ErrKeyExists))
+
+	expelled, err := client.TopkAdd(ctx, key, "a", "b")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"", ""}, expelled)
+	_, err = client.TopkIncrBy(ctx, key, TopkIncrement{Item: "a", Increment: 5}, TopkIncrement{Item: "c", Increment: 3})
+	assert.Nil(t, err)
+
+	inTop, err := client.TopkQuery(ctx, key, "a", "b")
+	assert.Nil(t, err)
+	assert.Equal(t, []bool{true, false}, inTop)
+	list, err := client.TopkList(ctx, key)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a", "c"}, list)
+	items, err := client.TopkListWithCount(ctx, key)
+	assert.Nil(t, err)
+	assert.Equal(t, []TopkItem{{Item: "a", Count: 6}, {Item: "c", Count: 3}}, items)
+
+	info, err := client.TopkInfo(ctx, key)
+	assert.Nil(t, err)
+	assert.Equal(t, TopKInfo{K: 2, Width: 50, Depth: 4, Decay: 0.9}, *info)
+}
@@ -0,0 +1,124 @@
+package redis_bloom_go
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// bloomModuleName is the name RedisBloom registers with in MODULE LIST
+const bloomModuleName = "bf"
+
+// tdigestModernVersion is the first RedisBloom version accepting the
+// values-only TDIGEST.ADD and the COMPRESSION keyword of TDIGEST.CREATE
+const tdigestModernVersion = 20400
+
+// moduleVersionRetryDelay is how long a failure to read the module version is returned before MODULE LIST is sent again
+const moduleVersionRetryDelay = 30 * time.Second
+
+// TDigestSyntax is the syntax of the t-digest commands sent by a client
+type TDigestSyntax int
+
+const (
+	// TDigestSyntaxAuto picks the syntax from the RedisBloom version reported by MODULE LIST
+	TDigestSyntaxAuto TDigestSyntax = iota
+	// TDigestSyntaxLegacy is the syntax of the servers prior to RedisBloom 2.4, e.g. TDIGEST.ADD key value weight
+	TDigestSyntaxLegacy
+	// TDigestSyntaxModern is the syntax of RedisBloom 2.4 and newer, e.g. TDIGEST.ADD key value
+	TDigestSyntaxModern
+)
+
+// WithTDigestSyntax pins the syntax of the t-digest commands, e.g. when MODULE LIST is denied by an ACL
+// or hidden by a proxy, instead of detecting it from the module version
+func WithTDigestSyntax(syntax TDigestSyntax) ClientOption {
+	return func(client *Client) {
+		client.tdigestSyntax = syntax
+	}
+}
+
+// moduleVersionCache memoizes the RedisBloom version reported by the server, or the failure to read it
+type moduleVersionCache struct {
+	sync.Mutex
+	version  int64
+	loaded   bool
+	err      error
+	failedAt time.Time
+}
+
+// ModuleVersion - Returns the RedisBloom module version loaded on the server, as reported
+// by MODULE LIST (e.g. 20400 for v2.4.0). The value is cached for the lifetime of the client,
+// and a failure to read it for 30 seconds.
+func (client *Client) ModuleVersion() (int64, error) {
+	if client.versionCache == nil {
+		return client.fetchModuleVersion()
+	}
+	client.versionCache.Lock()
+	defer client.versionCache.Unlock()
+	if client.versionCache.loaded {
+		return client.versionCache.version, nil
+	}
+	if client.versionCache.err != nil && time.Since(client.versionCache.failedAt) < moduleVersionRetryDelay {
+		return 0, client.versionCache.err
+	}
+	version, err := client.fetchModuleVersion()
+	if err != nil {
+		client.versionCache.err, client.versionCache.failedAt = err, time.Now()
+		return 0, err
+	}
+	client.versionCache.version, client.versionCache.loaded, client.versionCache.err = version, true, nil
+	return version, nil
+}
+
+func (client *Client) fetchModuleVersion() (int64, error) {
+	conn := client.Pool.Get()
+	defer conn.Close()
+	modules, err := redis.Values(conn.Do("MODULE", "LIST"))
+	if err != nil {
+		return 0, err
+	}
+	for _, module := range modules {
+		fields, err := redis.Values(module, nil)
+		if err != nil {
+			return 0, err
+		}
+		var name string
+		var version int64
+		for i := 0; i+1 < len(fields); i += 2 {
+			field, err := redis.String(fields[i], nil)
+			if err != nil {
+				return 0, err
+			}
+			switch field {
+			case "name":
+				name, err = redis.String(fields[i+1], nil)
+			case "ver":
+				version, err = redis.Int64(fields[i+1], nil)
+			}
+			if err != nil {
+				return 0, err
+			}
+		}
+		if name == bloomModuleName {
+			return version, nil
+		}
+	}
+	return 0, nil
+}
+
+// isModernTDigest reports whether the server speaks the RedisBloom 2.4+ t-digest syntax, unless pinned by
+// WithTDigestSyntax. When the version cannot be determined, the error is returned rather than guessing a
+// syntax: the legacy TDIGEST.ADD key value weight would add each weight as an observation on newer servers.
+func (client *Client) isModernTDigest() (bool, error) {
+	switch client.tdigestSyntax {
+	case TDigestSyntaxLegacy:
+		return false, nil
+	case TDigestSyntaxModern:
+		return true, nil
+	}
+	version, err := client.ModuleVersion()
+	if err != nil {
+		return false, err
+	}
+	return version >= tdigestModernVersion, nil
+}
@@ -0,0 +1,189 @@
+package redis_bloom_go
+
+import (
+	"sync"
+	"time"
+)
+
+// WatchEventType is the kind of change reported by a Watcher
+type WatchEventType int
+
+// Kinds of changes reported by a Watcher
+const (
+	// EventScaled is reported when a filter added one or more sub-filters
+	EventScaled WatchEventType = iota + 1
+	// EventThresholdCrossed is reported when the fill ratio of the current sub-filter rises above a threshold
+	EventThresholdCrossed
+	// EventNearlyFull is reported when a non-scaling filter rises above the nearly full ratio
+	EventNearlyFull
+	// EventError is reported when the information of a filter could not be read
+	EventError
+)
+
+// defaultNearlyFullRatio is the fill ratio above which a non-scaling filter is reported as nearly full
+const defaultNearlyFullRatio = 0.9
+
+// defaultWatcherInterval is the delay between two polls of a Watcher when none is configured
+const defaultWatcherInterval = 30 * time.Second
+
+// WatchedFilter is a filter polled by a Watcher
+type WatchedFilter struct {
+	Key string
+	// Type is DataTypeBloom or DataTypeCuckoo
+	Type DataType
+	// ErrorRate is the error rate the bloom filter was created with, used to estimate its false positive rate
+	ErrorRate float64
+	// NonScaling enables EventNearlyFull for filters that cannot add sub-filters
+	NonScaling bool
+}
+
+// WatchEvent is a change in the state of a watched filter
+type WatchEvent struct {
+	Key  string
+	Type WatchEventType
+	// Health is the state of the filter at the time of the event, nil for EventError
+	Health *FilterHealth
+	// Previous is the state of the filter at the previous poll, nil on the first poll
+	Previous *FilterHealth
+	// Threshold is the crossed threshold of EventThresholdCrossed and EventNearlyFull
+	Threshold float64
+	// Err is the error of EventError
+	Err error
+}
+
+// WatcherConfig configures a Watcher
+type WatcherConfig struct {
+	// Interval is the delay between two polls, 30s by default
+	Interval time.Duration
+	// Thresholds are the fill ratios of the current sub-filter reported by EventThresholdCrossed
+	Thresholds []float64
+	// NearlyFullRatio is the fill ratio reported by EventNearlyFull, 0.9 when zero
+	NearlyFullRatio float64
+	// OnEvent is called, from the polling goroutine, for every event
+	OnEvent func(WatchEvent)
+}
+
+// Watcher periodically polls the information of filters and reports scaling and saturation events
+type Watcher struct {
+	client  *Client
+	config  WatcherConfig
+	filters []WatchedFilter
+	last    map[string]*FilterHealth
+	stop    chan struct{}
+	done    chan struct{}
+	mutex   sync.Mutex
+	polling sync.Mutex
+}
+
+// NewWatcher - Returns a watcher of filters, which starts polling once Start is called
+func NewWatcher(client *Client, config WatcherConfig, filters ...WatchedFilter) *Watcher {
+	if config.NearlyFullRatio <= 0 {
+		config.NearlyFullRatio = defaultNearlyFullRatio
+	}
+	if config.Interval <= 0 {
+		config.Interval = defaultWatcherInterval
+	}
+	return &Watcher{
+		client:  client,
+		config:  config,
+		filters: filters,
+		last:    make(map[string]*FilterHealth, len(filters)),
+	}
+}
+
+// Start - Starts polling in a background goroutine. Calling Start on a started watcher does nothing.
+func (w *Watcher) Start() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.stop != nil {
+		return
+	}
+	w.stop = make(chan struct{})
+	w.done = make(chan struct{})
+	go w.run(w.stop, w.done)
+	w.client.register(w, w.Stop)
+}
+
+// Stop - Stops polling and waits for the polling goroutine to exit
+func (w *Watcher) Stop() {
+	w.mutex.Lock()
+	stop, done := w.stop, w.done
+	w.stop, w.done = nil, nil
+	w.mutex.Unlock()
+	if stop == nil {
+		return
+	}
+	w.client.unregister(w)
+	close(stop)
+	<-done
+}
+
+func (w *Watcher) run(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+	w.Poll()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			w.Poll()
+		}
+	}
+}
+
+// Poll - Reads the information of every watched filter once and reports the resulting events
+func (w *Watcher) Poll() {
+	w.polling.Lock()
+	defer w.polling.Unlock()
+	for _, filter := range w.filters {
+		var health *FilterHealth
+		var err error
+		if filter.Type == DataTypeCuckoo {
+			health, err = w.client.CfHealth(filter.Key)
+		} else {
+			health, err = w.client.BfHealth(filter.Key, filter.ErrorRate)
+		}
+		if err != nil {
+			w.emit(WatchEvent{Key: filter.Key, Type: EventError, Err: err})
+			continue
+		}
+		for _, event := range w.changes(filter, w.last[filter.Key], health) {
+			w.emit(event)
+		}
+		w.last[filter.Key] = health
+	}
+}
+
+// changes returns the events between two consecutive states of filter
+func (w *Watcher) changes(filter WatchedFilter, previous *FilterHealth, current *FilterHealth) []WatchEvent {
+	var events []WatchEvent
+	event := func(eventType WatchEventType, threshold float64) WatchEvent {
+		return WatchEvent{Key: filter.Key, Type: eventType, Health: current, Previous: previous, Threshold: threshold}
+	}
+	previousScaleOuts, previousFill := int64(0), 0.0
+	if previous != nil {
+		previousScaleOuts, previousFill = previous.ScaleOuts, previous.CurrentFillRatio
+	}
+	if previous != nil && current.ScaleOuts > previousScaleOuts {
+		events = append(events, event(EventScaled, 0))
+		// the current sub-filter is a new one
+		previousFill = 0
+	}
+	for _, threshold := range w.config.Thresholds {
+		if previousFill < threshold && current.CurrentFillRatio >= threshold {
+			events = append(events, event(EventThresholdCrossed, threshold))
+		}
+	}
+	if filter.NonScaling && previousFill < w.config.NearlyFullRatio && current.CurrentFillRatio >= w.config.NearlyFullRatio {
+		events = append(events, event(EventNearlyFull, w.config.NearlyFullRatio))
+	}
+	return events
+}
+
+func (w *Watcher) emit(event WatchEvent) {
+	if w.config.OnEvent != nil {
+		w.config.OnEvent(event)
+	}
+}
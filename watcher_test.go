@@ -0,0 +1,65 @@
+package redis_bloom_go
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatcher_changes(t *testing.T) {
+	w := NewWatcher(client, WatcherConfig{Thresholds: []float64{0.5, 0.8}})
+	filter := WatchedFilter{Key: "f", Type: DataTypeBloom, NonScaling: true}
+	types := func(events []WatchEvent) []WatchEventType {
+		result := []WatchEventType{}
+		for _, event := range events {
+			result = append(result, event.Type)
+		}
+		return result
+	}
+
+	empty := &FilterHealth{CurrentFillRatio: 0.1}
+	assert.Equal(t, []WatchEventType{}, types(w.changes(filter, nil, empty)))
+	half := &FilterHealth{CurrentFillRatio: 0.6}
+	events := w.changes(filter, empty, half)
+	assert.Equal(t, []WatchEventType{EventThresholdCrossed}, types(events))
+	assert.Equal(t, 0.5, events[0].Threshold)
+	assert.Equal(t, empty, events[0].Previous)
+	full := &FilterHealth{CurrentFillRatio: 0.95}
+	assert.Equal(t, []WatchEventType{EventThresholdCrossed, EventNearlyFull}, types(w.changes(filter, half, full)))
+	assert.Equal(t, []WatchEventType{}, types(w.changes(filter, full, full)))
+
+	scaling := WatchedFilter{Key: "s", Type: DataTypeBloom}
+	scaled := &FilterHealth{ScaleOuts: 1, CurrentFillRatio: 0.6}
+	assert.Equal(t, []WatchEventType{EventScaled, EventThresholdCrossed}, types(w.changes(scaling, full, scaled)))
+}
+
+func TestWatcher_Start(t *testing.T) {
+	client.FlushAll()
+	client.Reserve("test_watcher", 0.01, 10)
+	client.BfAddMulti("test_watcher", []string{"a", "b", "c", "d", "e", "f"})
+	events := make(chan WatchEvent, 10)
+	w := NewWatcher(client, WatcherConfig{
+		Interval:   10 * time.Millisecond,
+		Thresholds: []float64{0.5},
+		OnEvent: func(event WatchEvent) {
+			events <- event
+		},
+	}, WatchedFilter{Key: "test_watcher", Type: DataTypeBloom, ErrorRate: 0.01})
+	w.Start()
+	defer w.Stop()
+	event := <-events
+	assert.Equal(t, EventThresholdCrossed, event.Type)
+	assert.Equal(t, "test_watcher", event.Key)
+
+	client.BfAddMulti("test_watcher", []string{"g", "h", "i", "j", "k", "l"})
+	event = <-events
+	assert.Equal(t, EventScaled, event.Type)
+}
+
+func TestNewWatcher_DefaultInterval(t *testing.T) {
+	w := NewWatcher(NewClientFromPool(nil, "test"), WatcherConfig{})
+	assert.Equal(t, defaultWatcherInterval, w.config.Interval)
+	w.Start()
+	w.Stop()
+}
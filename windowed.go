@@ -0,0 +1,236 @@
+package redis_bloom_go
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// WindowedCMS is a count-min sketch over a sliding time window, made of one sketch per time bucket.
+// Increments go to the bucket of the current time, and queries sum the counts of every bucket of the window.
+// Each bucket expires once it leaves the window.
+type WindowedCMS struct {
+	client  *Client
+	name    string
+	bucket  time.Duration
+	buckets int64
+	width   int64
+	depth   int64
+	now     func() time.Time
+}
+
+// NewWindowedCMS - Returns a windowed count-min sketch named name, covering a window of buckets buckets of the
+// given duration, e.g. 60 buckets of one minute. Each bucket is a sketch created with width and depth.
+// Returns ErrInvalidWindow if bucket or buckets is not positive.
+func NewWindowedCMS(client *Client, name string, bucket time.Duration, buckets int64, width int64, depth int64) (*WindowedCMS, error) {
+	if bucket <= 0 || buckets <= 0 {
+		return nil, ErrInvalidWindow
+	}
+	return &WindowedCMS{
+		client:  client,
+		name:    name,
+		bucket:  bucket,
+		buckets: buckets,
+		width:   width,
+		depth:   depth,
+		now:     time.Now,
+	}, nil
+}
+
+// bucketKey returns the key of the bucket with the given index
+func (w *WindowedCMS) bucketKey(index int64) string {
+	return fmt.Sprintf("%s:%d", w.name, index)
+}
+
+// currentIndex returns the index of the bucket of the current time
+func (w *WindowedCMS) currentIndex() (int64, time.Time) {
+	now := w.now()
+	return now.UnixNano() / int64(w.bucket), now
+}
+
+// Keys - Returns the keys of the buckets in the window, from the oldest to the current one
+func (w *WindowedCMS) Keys() []string {
+	current, _ := w.currentIndex()
+	keys := make([]string, 0, w.buckets)
+	for index := current - w.buckets + 1; index <= current; index++ {
+		keys = append(keys, w.bucketKey(index))
+	}
+	return keys
+}
+
+// IncrBy - Increments the counts of items in the bucket of the current time, creating the bucket if needed.
+// Returns the counts of the items in the current bucket.
+func (w *WindowedCMS) IncrBy(increments []CmsIncrement) ([]int64, error) {
+	current, now := w.currentIndex()
+	key := w.bucketKey(current)
+	counts, err := w.client.CmsIncrByItems(key, increments)
+	if err != nil && strings.Contains(err.Error(), cmsMissingKeyError) {
+		if err = w.create(key, bucketTTL(current, w.bucket, w.buckets, now)); err != nil {
+			return nil, err
+		}
+		counts, err = w.client.CmsIncrByItems(key, increments)
+	}
+	return counts, err
+}
+
+// create creates the sketch of a bucket expiring after ttl. Losing the race to create it is not an error.
+func (w *WindowedCMS) create(key string, ttl time.Duration) error {
+	conn := w.client.Pool.Get()
+	defer conn.Close()
+	_, err := w.client.doWithTTL(conn, key, ttl, "CMS.INITBYDIM", w.client.key(key), w.width, w.depth)
+	if err != nil && strings.Contains(err.Error(), "already exists") {
+		return nil
+	}
+	return err
+}
+
+// Query - Returns the counts of items over the window, summing the counts of every bucket with one pipelined
+// CMS.QUERY per bucket. Buckets which were never incremented count as zero.
+func (w *WindowedCMS) Query(items ...string) ([]int64, error) {
+	return w.client.cmsQueryAcross(w.Keys(), items, nil, true)
+}
+
+// MergeInto - Merges the buckets of the window into the sketch at dest with CMS.MERGE, so that dest can be
+// queried like a single sketch. dest must exist with the width and depth of the buckets. Buckets which were
+// never incremented are skipped; if none was, dest is left untouched.
+func (w *WindowedCMS) MergeInto(dest string) error {
+	keys := w.Keys()
+	conn := w.client.Pool.Get()
+	for _, key := range keys {
+		if err := conn.Send("EXISTS", w.client.key(key)); err != nil {
+			conn.Close()
+			return err
+		}
+	}
+	if err := conn.Flush(); err != nil {
+		conn.Close()
+		return err
+	}
+	live := make([]string, 0, len(keys))
+	var outErr error
+	for _, key := range keys {
+		exists, err := redis.Bool(conn.Receive())
+		if err != nil && outErr == nil {
+			outErr = err
+		}
+		if exists {
+			live = append(live, key)
+		}
+	}
+	conn.Close()
+	if outErr != nil || len(live) == 0 {
+		return outErr
+	}
+	_, err := w.client.CmsMerge(dest, live, nil)
+	return err
+}
+
+// WindowedTopK tracks the most frequent items over a sliding time window, made of one top-k sketch per time
+// bucket. Items are added to the bucket of the current time, and the top items of the window are computed
+// client-side by summing the counts listed by every bucket. Each bucket expires once it leaves the window.
+type WindowedTopK struct {
+	client  *Client
+	name    string
+	bucket  time.Duration
+	buckets int64
+	topk    int64
+	width   int64
+	depth   int64
+	decay   float64
+	now     func() time.Time
+}
+
+// NewWindowedTopK - Returns a windowed top-k named name, covering a window of buckets buckets of the given
+// duration. Each bucket is a sketch created by TOPK.RESERVE with topk, width, depth and decay.
+// Returns ErrInvalidWindow if bucket or buckets is not positive.
+func NewWindowedTopK(client *Client, name string, bucket time.Duration, buckets int64, topk int64, width int64, depth int64, decay float64) (*WindowedTopK, error) {
+	if bucket <= 0 || buckets <= 0 {
+		return nil, ErrInvalidWindow
+	}
+	return newWindowedTopK(client, name, bucket, buckets, topk, width, depth, decay), nil
+}
+
+// newWindowedTopK returns a windowed top-k sketch, bucket and buckets being positive
+func newWindowedTopK(client *Client, name string, bucket time.Duration, buckets int64, topk int64, width int64, depth int64, decay float64) *WindowedTopK {
+	return &WindowedTopK{
+		client:  client,
+		name:    name,
+		bucket:  bucket,
+		buckets: buckets,
+		topk:    topk,
+		width:   width,
+		depth:   depth,
+		decay:   decay,
+		now:     time.Now,
+	}
+}
+
+// bucketKey returns the key of the bucket with the given index
+func (w *WindowedTopK) bucketKey(index int64) string {
+	return fmt.Sprintf("%s:%d", w.name, index)
+}
+
+// Keys - Returns the keys of the buckets in the window, from the oldest to the current one
+func (w *WindowedTopK) Keys() []string {
+	current := w.now().UnixNano() / int64(w.bucket)
+	keys := make([]string, 0, w.buckets)
+	for index := current - w.buckets + 1; index <= current; index++ {
+		keys = append(keys, w.bucketKey(index))
+	}
+	return keys
+}
+
+// Add - Adds items to the bucket of the current time, creating the bucket if needed.
+// Returns the items expelled from the current bucket, as TopkAdd does.
+func (w *WindowedTopK) Add(items ...string) ([]string, error) {
+	return w.write(func(key string) ([]string, error) {
+		return w.client.TopkAdd(key, items)
+	})
+}
+
+// IncrBy - Increases the scores of items in the bucket of the current time, creating the bucket if needed.
+// Returns the items expelled from the current bucket, as TopkIncrByItems does.
+func (w *WindowedTopK) IncrBy(increments []TopkIncrement) ([]string, error) {
+	return w.write(func(key string) ([]string, error) {
+		return w.client.TopkIncrByItems(key, increments)
+	})
+}
+
+// write runs command on the bucket of the current time, creating the bucket if it does not exist
+func (w *WindowedTopK) write(command func(key string) ([]string, error)) ([]string, error) {
+	now := w.now()
+	current := now.UnixNano() / int64(w.bucket)
+	key := w.bucketKey(current)
+	expelled, err := command(key)
+	if err != nil && strings.Contains(err.Error(), topkMissingKeyError) {
+		if err = w.reserve(key, bucketTTL(current, w.bucket, w.buckets, now)); err != nil {
+			return nil, err
+		}
+		expelled, err = command(key)
+	}
+	return expelled, err
+}
+
+// reserve creates the sketch of a bucket expiring after ttl. Losing the race to create it is not an error.
+func (w *WindowedTopK) reserve(key string, ttl time.Duration) error {
+	conn := w.client.Pool.Get()
+	defer conn.Close()
+	_, err := w.client.doWithTTL(conn, key, ttl, "TOPK.RESERVE", w.client.key(key), w.topk, w.width, w.depth, w.decay)
+	if err != nil && strings.Contains(err.Error(), "already exists") {
+		return nil
+	}
+	return err
+}
+
+// List - Returns the topk most frequent items over the window, from the most to the least frequent, with one
+// pipelined TOPK.LIST WITHCOUNT per bucket. The count of an item is the sum of its counts in the buckets listing
+// it, so an item that was not among the top items of a bucket misses the occurrences it had in that bucket.
+func (w *WindowedTopK) List() ([]HeavyHitter, error) {
+	counts, err := w.client.topkListMerged(w.Keys(), true)
+	if err != nil {
+		return nil, err
+	}
+	return topHeavyHitters(counts, w.topk), nil
+}
@@ -0,0 +1,124 @@
+package redis_bloom_go
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWindowedCMS_Keys(t *testing.T) {
+	w, err := NewWindowedCMS(client, "hits", time.Minute, 3, 1000, 5)
+	assert.Nil(t, err)
+	w.now = func() time.Time { return time.Unix(10*60+5, 0) }
+	assert.Equal(t, []string{"hits:8", "hits:9", "hits:10"}, w.Keys())
+}
+
+func TestNewWindowedCMS_Invalid(t *testing.T) {
+	_, err := NewWindowedCMS(client, "hits", 0, 3, 1000, 5)
+	assert.Equal(t, ErrInvalidWindow, err)
+	_, err = NewWindowedCMS(client, "hits", time.Minute, -1, 1000, 5)
+	assert.Equal(t, ErrInvalidWindow, err)
+}
+
+func TestWindowedCMS(t *testing.T) {
+	client.FlushAll()
+	now := time.Unix(10*60+5, 0)
+	w, err := NewWindowedCMS(client, "hits", time.Minute, 3, 1000, 5)
+	assert.Nil(t, err)
+	w.now = func() time.Time { return now }
+
+	counts, err := w.IncrBy([]CmsIncrement{{Item: "a", Count: 2}, {Item: "b", Count: 1}})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{2, 1}, counts)
+	conn := client.Pool.Get()
+	defer conn.Close()
+	ttl, err := redis.Int64(conn.Do("TTL", "hits:10"))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3*60-5), ttl)
+
+	// two buckets later the counts add up over the window
+	now = now.Add(2 * time.Minute)
+	counts, err = w.IncrBy([]CmsIncrement{{Item: "a", Count: 3}})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{3}, counts)
+	counts, err = w.Query("a", "b", "c")
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{5, 1, 0}, counts)
+
+	_, err = client.CmsInitByDim("merged", 1000, 5)
+	assert.Nil(t, err)
+	assert.Nil(t, w.MergeInto("merged"))
+	counts, err = client.CmsQuery("merged", []string{"a", "b"})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{5, 1}, counts)
+
+	// then the first bucket leaves the window
+	now = now.Add(time.Minute)
+	counts, err = w.Query("a", "b")
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{3, 0}, counts)
+}
+
+func TestWindowedTopK_Keys(t *testing.T) {
+	w, err := NewWindowedTopK(client, "top", time.Minute, 3, 2, 16, 7, 0.9)
+	assert.Nil(t, err)
+	w.now = func() time.Time { return time.Unix(10*60+5, 0) }
+	assert.Equal(t, []string{"top:8", "top:9", "top:10"}, w.Keys())
+}
+
+func TestNewWindowedTopK_Invalid(t *testing.T) {
+	_, err := NewWindowedTopK(client, "top", -time.Minute, 3, 2, 16, 7, 0.9)
+	assert.Equal(t, ErrInvalidWindow, err)
+	_, err = NewWindowedTopK(client, "top", time.Minute, 0, 2, 16, 7, 0.9)
+	assert.Equal(t, ErrInvalidWindow, err)
+	h, err := NewHeavyHitters(client, "top", HeavyHittersConfig{TopK: 2})
+	assert.Nil(t, err)
+	assert.Equal(t, time.Minute, h.window.bucket)
+}
+
+func TestWindowedTopK_BucketEnd(t *testing.T) {
+	conn := &argsConn{fakeConn: &fakeConn{replies: []interface{}{
+		redis.Error("TopK: key does not exist"), "OK", []interface{}{nil},
+	}}}
+	c := NewClientFromPool(nil, "test")
+	c.Pool = &fakePool{conn: conn}
+	w, err := NewWindowedTopK(c, "top", time.Minute, 1, 2, 16, 7, 0.9)
+	assert.Nil(t, err)
+	// a bucket created in its last microsecond still gets the shortest TTL rather than failing
+	w.now = func() time.Time { return time.Unix(11*60, 0).Add(-time.Microsecond) }
+	_, err = w.Add("a")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"TOPK.ADD", "EVALSHA", "TOPK.ADD"}, conn.commands)
+	assert.Equal(t, int64(1), conn.args[1][3])
+}
+
+func TestWindowedTopK(t *testing.T) {
+	client.FlushAll()
+	now := time.Unix(10*60+5, 0)
+	w, err := NewWindowedTopK(client, "top", time.Minute, 2, 2, 16, 7, 0.9)
+	assert.Nil(t, err)
+	w.now = func() time.Time { return now }
+
+	_, err = w.Add("a", "a", "a", "b", "b", "c")
+	assert.Nil(t, err)
+	conn := client.Pool.Get()
+	defer conn.Close()
+	ttl, err := redis.Int64(conn.Do("TTL", "top:10"))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2*60-5), ttl)
+
+	now = now.Add(time.Minute)
+	_, err = w.IncrBy([]TopkIncrement{{Item: "c", Increment: 4}})
+	assert.Nil(t, err)
+	top, err := w.List()
+	assert.Nil(t, err)
+	assert.Equal(t, []HeavyHitter{{"c", 5}, {"a", 3}}, top)
+
+	// the first bucket has left the window
+	now = now.Add(time.Minute)
+	top, err = w.List()
+	assert.Nil(t, err)
+	assert.Equal(t, []HeavyHitter{{"c", 4}}, top)
+}